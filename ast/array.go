@@ -0,0 +1,143 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+package ast
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// ReadArray creates a new Node tree from the compressed JSON array
+// form produced by other PEGN implementations. Every node is an array
+// with the type first followed by either a string value or by an array
+// of the nodes under it:
+//
+//	["Grammar",[["Comment","some"],["Rule",[["Name","Foo"]]]]]
+//
+// The type may be an integer (used as is) or a rule name which is
+// resolved into an integer type with the id function (see
+// gr.Grammar.ID). An error is returned if a name cannot be resolved or
+// if anything in the data does not match this form.
+func ReadArray(data []byte, id func(name string) (int, bool)) (*Node, error) {
+	var v any
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	return fromArray(v, id)
+}
+
+func fromArray(v any, id func(name string) (int, bool)) (*Node, error) {
+	a, is := v.([]any)
+	if !is || len(a) == 0 || len(a) > 2 {
+		return nil, fmt.Errorf(`node must be array of type and value or nodes: %v`, v)
+	}
+
+	n := new(Node)
+	switch t := a[0].(type) {
+	case json.Number:
+		i, err := t.Int64()
+		if err != nil {
+			return nil, fmt.Errorf(`invalid node type: %v`, t)
+		}
+		n.T = int(i)
+	case string:
+		if id == nil {
+			return nil, fmt.Errorf(`unable to resolve node type name: %q`, t)
+		}
+		i, has := id(t)
+		if !has {
+			return nil, fmt.Errorf(`unknown node type name: %q`, t)
+		}
+		n.T = i
+	default:
+		return nil, fmt.Errorf(`invalid node type: %v`, t)
+	}
+
+	if len(a) == 1 {
+		return n, nil
+	}
+
+	switch u := a[1].(type) {
+	case string:
+		n.V = u
+	case []any:
+		for _, i := range u {
+			c, err := fromArray(i, id)
+			if err != nil {
+				return nil, err
+			}
+			c.P = n
+			n.Append(c)
+		}
+	default:
+		return nil, fmt.Errorf(`node must have string value or array of nodes: %v`, u)
+	}
+
+	return n, nil
+}
+
+// MarshalShort returns the compressed JSON array form (see ReadArray)
+// of the node and every node under it with integer types:
+//
+//	[1,[[2,"some"],[3,[[4,"Foo"]]]]]
+//
+// Nodes with neither a value nor nodes under them are written with the
+// type alone ([5]). Like MarshalJSON, HTML is never escaped.
+func (n *Node) MarshalShort() ([]byte, error) {
+	buf := new(bytes.Buffer)
+	if err := n.writeShort(buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (n *Node) writeShort(buf *bytes.Buffer) error {
+	fmt.Fprintf(buf, `[%d`, n.T)
+	nodes := n.Nodes()
+	switch {
+	case len(nodes) > 0:
+		buf.WriteString(`,[`)
+		for i, c := range nodes {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := c.writeShort(buf); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+	case n.V != ``:
+		buf.WriteByte(',')
+		enc := json.NewEncoder(buf)
+		enc.SetEscapeHTML(false)
+		if err := enc.Encode(n.V); err != nil {
+			return err
+		}
+		buf.Truncate(buf.Len() - 1) // newline from Encode
+	}
+	buf.WriteByte(']')
+	return nil
+}
+
+// UnmarshalShort reads the compressed JSON array form written by
+// MarshalShort replacing the node completely (including every node
+// under it). Types must be integers. Use ReadArray for types given by
+// name.
+func (n *Node) UnmarshalShort(data []byte) error {
+	u, err := ReadArray(data, nil)
+	if err != nil {
+		return err
+	}
+	n.Init()
+	n.Count = 0
+	n.T, n.V = u.T, u.V
+	for _, c := range u.Nodes() {
+		c.P = n
+		n.Append(c)
+	}
+	return nil
+}
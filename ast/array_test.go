@@ -0,0 +1,65 @@
+package ast_test
+
+import (
+	"fmt"
+
+	"github.com/rwxrob/pegn/ast"
+)
+
+func ExampleReadArray() {
+
+	ids := map[string]int{`Grammar`: 1, `Comment`: 2, `Rule`: 3, `Name`: 4}
+	id := func(name string) (int, bool) { i, has := ids[name]; return i, has }
+
+	n, err := ast.ReadArray([]byte(`
+	["Grammar",[
+	  ["Comment","some"],
+	  ["Rule",[["Name","Foo"],[9,"numeric type"]]]
+	]]`), id)
+
+	fmt.Println(err)
+	n.Println()
+
+	_, err = ast.ReadArray([]byte(`["Grammar",[["Unknown","x"]]]`), id)
+	fmt.Println(err)
+
+	_, err = ast.ReadArray([]byte(`["Grammar","some",[]]`), id)
+	fmt.Println(err)
+
+	// Output:
+	// <nil>
+	// {"T":1,"N":[{"T":2,"V":"some"},{"T":3,"N":[{"T":4,"V":"Foo"},{"T":9,"V":"numeric type"}]}]}
+	// unknown node type name: "Unknown"
+	// node must be array of type and value or nodes: [Grammar some []]
+}
+
+func ExampleNode_MarshalShort() {
+
+	n := new(ast.Node)
+	n.T = 1
+	n.Add(2, `some <"quoted">`)
+	r := n.Add(3, ``)
+	r.Add(4, `Foo`)
+	r.Add(5, ``)
+
+	byt, err := n.MarshalShort()
+	fmt.Println(err)
+	fmt.Println(string(byt))
+
+	u := new(ast.Node)
+	fmt.Println(u.UnmarshalShort(byt))
+	u.Println()
+	n.Println()
+	fmt.Println(u.Nodes()[1].P == u)
+
+	fmt.Println(u.UnmarshalShort([]byte(`["Grammar"]`)))
+
+	// Output:
+	// <nil>
+	// [1,[[2,"some <\"quoted\">"],[3,[[4,"Foo"],[5]]]]]
+	// <nil>
+	// {"T":1,"N":[{"T":2,"V":"some <\"quoted\">"},{"T":3,"N":[{"T":4,"V":"Foo"},{"T":5}]}]}
+	// {"T":1,"N":[{"T":2,"V":"some <\"quoted\">"},{"T":3,"N":[{"T":4,"V":"Foo"},{"T":5}]}]}
+	// true
+	// unable to resolve node type name: "Grammar"
+}
@@ -0,0 +1,34 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+package ast
+
+// Capture returns the first node under this one (depth-first, preorder)
+// with the given Tag or nil if there is none. Tagged nodes are created
+// by grammars with named captures (<:tag ... >) so that what matters
+// can be retrieved by name instead of by position among the nodes
+// under another (which changes whenever the grammar does).
+func (n *Node) Capture(tag string) *Node {
+	for _, u := range n.Nodes() {
+		if u.Tag == tag {
+			return u
+		}
+		if c := u.Capture(tag); c != nil {
+			return c
+		}
+	}
+	return nil
+}
+
+// Captures returns every node under this one with the given Tag in
+// depth-first, preorder order (see Capture).
+func (n *Node) Captures(tag string) []*Node {
+	var list []*Node
+	for _, u := range n.Nodes() {
+		if u.Tag == tag {
+			list = append(list, u)
+		}
+		list = append(list, u.Captures(tag)...)
+	}
+	return list
+}
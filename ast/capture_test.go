@@ -0,0 +1,26 @@
+package ast_test
+
+import (
+	"fmt"
+
+	"github.com/rwxrob/pegn/ast"
+)
+
+func ExampleNode_Capture() {
+	n := new(ast.Node)
+	n.Add(1, `a`).Tag = `first`
+	u := n.Add(2, ``)
+	u.Add(3, `b`).Tag = `item`
+	u.Add(3, `c`).Tag = `item`
+	fmt.Println(n.Capture(`first`).V)
+	fmt.Println(n.Capture(`item`).V)
+	fmt.Println(len(n.Captures(`item`)))
+	fmt.Println(n.Capture(`none`) == nil)
+	fmt.Println(n)
+	// Output:
+	// a
+	// b
+	// 2
+	// true
+	// {"T":0,"N":[{"T":1,"V":"a","Tag":"first"},{"T":2,"N":[{"T":3,"V":"b","Tag":"item"},{"T":3,"V":"c","Tag":"item"}]}]}
+}
@@ -0,0 +1,115 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+package ast
+
+import (
+	"fmt"
+	"strings"
+)
+
+// EditOp is the operation of a single Edit.
+type EditOp int
+
+const (
+	Insert EditOp = iota + 1 // B added
+	Delete                   // A removed
+	Change                   // A (value, tag, or type) changed to B
+)
+
+var editOps = []string{``, `insert`, `delete`, `change`}
+
+// String fulfills fmt.Stringer with the lower case name.
+func (o EditOp) String() string {
+	if o < Insert || o > Change {
+		return fmt.Sprintf(`editop(%d)`, int(o))
+	}
+	return editOps[o]
+}
+
+// Edit is a single difference between two trees (see Diff). Path is
+// the index of every node above (starting under the root) in the first
+// tree followed by the index of the node itself in the first tree for
+// Delete and Change or in the second tree for Insert. The nodes of the
+// edit include everything under them.
+type Edit struct {
+	Op   EditOp
+	Path []int
+	A    *Node // nil for Insert
+	B    *Node // nil for Delete
+}
+
+// String returns the edit as a single line with the path as indexes
+// separated by slashes (/ alone for the root) followed by the nodes.
+//
+//	change /0/1: {"T":3,"V":"1"} => {"T":3,"V":"2"}
+func (e Edit) String() string {
+	p := make([]string, len(e.Path))
+	for i, n := range e.Path {
+		p[i] = fmt.Sprint(n)
+	}
+	path := `/` + strings.Join(p, `/`)
+	switch e.Op {
+	case Insert:
+		return fmt.Sprintf(`%v %v: %v`, e.Op, path, e.B)
+	case Delete:
+		return fmt.Sprintf(`%v %v: %v`, e.Op, path, e.A)
+	}
+	return fmt.Sprintf(`%v %v: %v => %v`, e.Op, path, e.A, e.B)
+}
+
+// Diff returns the edits that turn tree a into tree b (or nil if they
+// are the same) in order of path so that tests can show exactly how
+// a tree changed rather than two entire trees. Nodes under the same
+// node are matched by type (in order, keeping as many as possible)
+// so a node added or removed among others is a single Insert or
+// Delete. Matched nodes with different values or tags (or with values
+// instead of nodes under them) are a Change. Roots of different types
+// are always a single Change.
+func Diff(a, b *Node) []Edit {
+	return diff(a, b, nil, nil)
+}
+
+func diff(a, b *Node, path []int, list []Edit) []Edit {
+	if a.T != b.T {
+		return append(list, Edit{Op: Change, Path: path, A: a, B: b})
+	}
+	if a.V != b.V || a.Tag != b.Tag || (a.Count == 0) != (b.Count == 0) {
+		return append(list, Edit{Op: Change, Path: path, A: a, B: b})
+	}
+	an, bn := a.Nodes(), b.Nodes()
+	// longest common subsequence of types
+	lcs := make([][]int, len(an)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(bn)+1)
+	}
+	for i := len(an) - 1; i >= 0; i-- {
+		for j := len(bn) - 1; j >= 0; j-- {
+			switch {
+			case an[i].T == bn[j].T:
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			case lcs[i+1][j] >= lcs[i][j+1]:
+				lcs[i][j] = lcs[i+1][j]
+			default:
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+	sub := func(i int) []int { return append(append([]int{}, path...), i) }
+	i, j := 0, 0
+	for i < len(an) || j < len(bn) {
+		switch {
+		case i < len(an) && j < len(bn) && an[i].T == bn[j].T:
+			list = diff(an[i], bn[j], sub(i), list)
+			i++
+			j++
+		case j == len(bn) || i < len(an) && lcs[i+1][j] >= lcs[i][j+1]:
+			list = append(list, Edit{Op: Delete, Path: sub(i), A: an[i]})
+			i++
+		default:
+			list = append(list, Edit{Op: Insert, Path: sub(j), B: bn[j]})
+			j++
+		}
+	}
+	return list
+}
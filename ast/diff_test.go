@@ -0,0 +1,24 @@
+package ast_test
+
+import (
+	"fmt"
+
+	"github.com/rwxrob/pegn/ast"
+)
+
+func ExampleDiff() {
+	a := short(`[1,[[2,"x"],[3,[[4,"1"],[4,"2"]]],[5,"end"]]]`)
+	b := short(`[1,[[3,[[4,"1"],[4,"3"],[6,"new"]]],[5,"fin"]]]`)
+	for _, e := range ast.Diff(a, b) {
+		fmt.Println(e)
+	}
+	fmt.Println(ast.Diff(a, a.Copy()) == nil)
+	fmt.Println(ast.Diff(a, short(`[9,"other"]`))[0].Op)
+	// Output:
+	// delete /0: {"T":2,"V":"x"}
+	// change /1/1: {"T":4,"V":"2"} => {"T":4,"V":"3"}
+	// insert /1/2: {"T":6,"V":"new"}
+	// change /2: {"T":5,"V":"end"} => {"T":5,"V":"fin"}
+	// true
+	// change
+}
@@ -0,0 +1,40 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+package ast
+
+import "math"
+
+// ErrorNode is the reserved type of nodes standing in for input that
+// could not be parsed and was skipped in order to continue (see
+// NewError). Types from math.MinInt32 up to Reserved are set aside for
+// nodes like these (see TriviaNode) and never used for rules (even the
+// negative ones of pegng) so error nodes can be placed anywhere within
+// a partial tree and still be told apart from everything else. The
+// range is that of int32 so trees written as JSON read back the same on
+// every platform.
+const ErrorNode = math.MinInt32
+
+// Reserved is the last of the node types set aside for nodes that are
+// not of any rule (see ErrorNode).
+const Reserved = math.MinInt32 + 255
+
+// NewError returns a new ErrorNode with the text skipped as its value
+// and the error explaining why it could not be parsed (usually
+// a pegn.Error with the position and what was expected).
+func NewError(skipped string, err error) *Node {
+	return &Node{T: ErrorNode, V: skipped, Err: err}
+}
+
+// Errors returns every ErrorNode of the tree (including this one) in
+// depth-first, preorder order so that problem regions of partial trees
+// can be counted or rendered without walking the tree by hand.
+func (n *Node) Errors() []*Node {
+	var list []*Node
+	n.WalkDeepPre(func(u *Node) {
+		if u.T == ErrorNode {
+			list = append(list, u)
+		}
+	})
+	return list
+}
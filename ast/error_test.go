@@ -0,0 +1,28 @@
+package ast_test
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/rwxrob/pegn/ast"
+)
+
+func ExampleNode_Errors() {
+	n := new(ast.Node)
+	n.Add(1, `ok`)
+	n.Append(ast.NewError("bad line\n", errors.New(`expecting '='`)))
+	n.Add(1, `ok`)
+	for _, e := range n.Errors() {
+		fmt.Printf("%q %v\n", e.V, e.Err)
+	}
+	fmt.Println(n)
+
+	u := new(ast.Node)
+	fmt.Println(u.UnmarshalJSON([]byte(n.String())))
+	fmt.Println(len(u.Errors()), u.Errors()[0].Err)
+	// Output:
+	// "bad line\n" expecting '='
+	// {"T":0,"N":[{"T":1,"V":"ok"},{"T":-2147483648,"V":"bad line\n","Err":"expecting '='"},{"T":1,"V":"ok"}]}
+	// <nil>
+	// 1 expecting '='
+}
@@ -0,0 +1,88 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+package ast
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io"
+)
+
+// UnmarshalJSON fulfills the json.Unmarshaler interface by reading the
+// same form written by MarshalJSON replacing the node completely
+// (including every node under it).
+func (n *Node) UnmarshalJSON(data []byte) error {
+	var v struct {
+		T   int
+		V   string
+		Tag string
+		Err string
+		N   []*Node
+	}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	n.Init()
+	n.Count = 0
+	n.T, n.V, n.Tag = v.T, v.V, v.Tag
+	if v.Err != "" {
+		n.Err = errors.New(v.Err)
+	}
+	for _, u := range v.N {
+		u.P = n
+		n.Append(u)
+	}
+	return nil
+}
+
+// JSONLWriter writes node trees as JSON Lines (one MarshalJSON tree per
+// line) so that trees can be streamed to disk (or a pipe) as soon as
+// they are complete rather than held in memory. See ReadJSONL.
+type JSONLWriter struct {
+	w   *bufio.Writer
+	Len int // number of trees written
+}
+
+// NewJSONLWriter returns a JSONLWriter buffering output to w. Call
+// Flush when done.
+func NewJSONLWriter(w io.Writer) *JSONLWriter {
+	return &JSONLWriter{w: bufio.NewWriter(w)}
+}
+
+// Write writes the tree as a single line.
+func (j *JSONLWriter) Write(n *Node) error {
+	byt, err := n.MarshalJSON()
+	if err != nil {
+		return err
+	}
+	if _, err := j.w.Write(append(byt, '\n')); err != nil {
+		return err
+	}
+	j.Len++
+	return nil
+}
+
+// Flush writes anything still buffered.
+func (j *JSONLWriter) Flush() error { return j.w.Flush() }
+
+// ReadJSONL reads JSON Lines written by JSONLWriter calling fn with
+// each tree in order (holding only one in memory at a time). Blank
+// lines are skipped. Stops at the first error (from reading or fn).
+func ReadJSONL(r io.Reader, fn func(n *Node) error) error {
+	dec := json.NewDecoder(r)
+	for {
+		n := new(Node)
+		err := dec.Decode(n)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := fn(n); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,32 @@
+package ast_test
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rwxrob/pegn/ast"
+)
+
+func ExampleReadJSONL() {
+
+	in := `{"T":1,"N":[{"T":2,"V":"a"},{"T":3,"V":"1"}]}
+
+{"T":1,"N":[{"T":2,"V":"b"}]}
+`
+	err := ast.ReadJSONL(strings.NewReader(in), func(n *ast.Node) error {
+		fmt.Println(n, n.Count, n.Nodes()[0].P == n)
+		return nil
+	})
+	fmt.Println(err)
+
+	err = ast.ReadJSONL(strings.NewReader(`{"T":"x"}`), func(n *ast.Node) error {
+		return nil
+	})
+	fmt.Println(err != nil)
+
+	// Output:
+	// {"T":1,"N":[{"T":2,"V":"a"},{"T":3,"V":"1"}]} 2 true
+	// {"T":1,"N":[{"T":2,"V":"b"}]} 1 true
+	// <nil>
+	// true
+}
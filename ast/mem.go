@@ -0,0 +1,21 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+package ast
+
+import "unsafe"
+
+// NodeSize is the number of bytes of every Node (without its value).
+const NodeSize = int(unsafe.Sizeof(Node{}))
+
+// MemSize returns an estimate of the bytes of memory held by the node
+// and every node under it: NodeSize for every node plus the bytes of
+// every value. Values sharing memory (such as those sliced from the
+// same input) are counted every time and Origins (which are interned)
+// never, so the estimate is an upper bound best used for capacity
+// planning and comparing grammars rather than exact accounting.
+func (n *Node) MemSize() int {
+	var size int
+	n.WalkDeepPre(func(n *Node) { size += NodeSize + len(n.V) })
+	return size
+}
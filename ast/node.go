@@ -18,12 +18,20 @@ import (
 // instead. While there is nothing preventing a Node from having both
 // a value and other nodes under it, such use is unsupported by the
 // MarshalJSON/UnmarshalJSON methods. All nodes have a specific integer
-// type (T).
+// type (T). Nodes from parsers (see pegn.ParserFor and gr) also have the
+// byte offsets of the source they were parsed from (B to E) so that
+// editors and linters can map them back to lines and columns (see
+// scanner.S.Positions and scanner.S.Span).
 type Node struct {
-	T     int    `json:"T"`          // type
-	V     string `json:",omitempty"` // value
-	P     *Node  `json:"-"`          // up/parent
-	Count int    `json:"-"`          // node count
+	T     int     `json:"T"`          // type
+	V     string  `json:",omitempty"` // value
+	P     *Node   `json:"-"`          // up/parent
+	Count int     `json:"-"`          // node count
+	O     *Origin `json:"-"`          // grammar that produced (optional)
+	Tag   string  `json:",omitempty"` // name of capture (see Capture)
+	Err   error   `json:"-"`          // why not parsed (see ErrorNode)
+	B     int     `json:"-"`          // byte offset of beginning in source (if known)
+	E     int     `json:"-"`          // byte offset of end in source (0 if unknown)
 
 	left  *Node
 	right *Node
@@ -36,6 +44,11 @@ type Node struct {
 func (n *Node) Init() {
 	n.T = 0
 	n.V = ""
+	n.O = nil
+	n.Tag = ""
+	n.Err = nil
+	n.B = 0
+	n.E = 0
 	n.first = nil
 	n.last = nil
 	n.left = nil
@@ -96,11 +109,84 @@ func (n *Node) Cut() *Node {
 	return n
 }
 
+// InsertBefore adds u (cut from wherever it was) under the same node
+// as this one just before it. Like every method changing a tree, it can
+// be called while walking it (see WalkDeepPre) but nodes already
+// visited (or queued to be visited) are not visited again. Panics if
+// this node is not under another.
+func (n *Node) InsertBefore(u *Node) {
+	p := n.parent()
+	if u == n {
+		return
+	}
+	u.Cut()
+	u.P, u.left, u.right = p, n.left, n
+	if n.left != nil {
+		n.left.right = u
+	} else {
+		p.first = u
+	}
+	n.left = u
+	p.Count++
+}
+
+// InsertAfter adds u (cut from wherever it was) under the same node as
+// this one just after it (see InsertBefore).
+func (n *Node) InsertAfter(u *Node) {
+	p := n.parent()
+	if u == n {
+		return
+	}
+	u.Cut()
+	u.P, u.left, u.right = p, n, n.right
+	if n.right != nil {
+		n.right.left = u
+	} else {
+		p.last = u
+	}
+	n.right = u
+	p.Count++
+}
+
+// ReplaceWith puts u (cut from wherever it was) in place of this node
+// which is removed (see Cut) and returned. Panics if this node is not
+// under another.
+func (n *Node) ReplaceWith(u *Node) *Node {
+	if u == n {
+		return n
+	}
+	n.InsertBefore(u)
+	return n.Cut()
+}
+
+// Wrap puts a new node of the given type in place of this one (if
+// under another) with this one under it and returns it (ex: adding
+// an implied Group node while desugaring).
+func (n *Node) Wrap(t int) *Node {
+	w := &Node{T: t}
+	if n.P != nil {
+		n.ReplaceWith(w)
+	}
+	w.Append(n)
+	return w
+}
+
+// parent returns the node this one is under or panics if none.
+func (n *Node) parent() *Node {
+	if n.P == nil {
+		panic(`ast: node is not under another`)
+	}
+	return n.P
+}
+
 // Take moves all nodes from another under itself.
 func (n *Node) Take(from *Node) {
 	if from.first == nil {
 		return
 	}
+	for u := from.first; u != nil; u = u.right {
+		u.P = n
+	}
 	if n.first == nil {
 		n.first = from.first
 		n.last = from.last
@@ -119,6 +205,7 @@ func (n *Node) Take(from *Node) {
 // Append adds an existing Node under this one as if Add had been
 // called.
 func (n *Node) Append(u *Node) {
+	u.P = n
 	n.Count++
 	if n.first == nil {
 		n.first = u
@@ -138,6 +225,11 @@ func (n *Node) Morph(c *Node) {
 	n.Init()
 	n.T = c.T
 	n.V = c.V
+	n.O = c.O
+	n.Tag = c.Tag
+	n.Err = c.Err
+	n.B = c.B
+	n.E = c.E
 	n.P = c.P
 	n.left = c.left
 	n.right = c.right
@@ -216,9 +308,11 @@ func (n *Node) WalkDeepPre(do func(n *Node)) {
 // ------------------------------ Printer -----------------------------
 // just for marshaling
 type jsnode struct {
-	T int     `json:"T"`
-	V string  `json:"V,omitempty"`
-	N []*Node `json:"N,omitempty"`
+	T   int     `json:"T"`
+	V   string  `json:"V,omitempty"`
+	Tag string  `json:"Tag,omitempty"`
+	Err string  `json:"Err,omitempty"`
+	N   []*Node `json:"N,omitempty"`
 }
 
 // MarshalJSON fulfills the json.Marshaler interface by first creating
@@ -229,6 +323,10 @@ func (s Node) MarshalJSON() ([]byte, error) {
 	n := new(jsnode)
 	n.T = s.T
 	n.V = s.V
+	n.Tag = s.Tag
+	if s.Err != nil {
+		n.Err = s.Err.Error()
+	}
 	n.N = s.Nodes()
 	buf := new(bytes.Buffer)
 	enc := json.NewEncoder(buf)
@@ -220,3 +220,40 @@ func ExampleNode_Copy() {
 	// {"T":0,"N":[{"T":2,"V":"some"},{"T":3,"V":"new","N":[{"T":4,"V":"deep"}]}]}
 
 }
+
+func ExampleNode_MemSize() {
+	n := new(ast.Node)
+	n.Add(1, `hello`)
+	n.Add(2, `world!`)
+	fmt.Println(n.MemSize() == 3*ast.NodeSize+11)
+	// Output:
+	// true
+}
+
+func ExampleNode_InsertBefore() {
+	n := new(ast.Node)
+	b := n.Add(2, "b")
+	b.InsertBefore(&ast.Node{T: 1, V: "a"})
+	b.InsertAfter(&ast.Node{T: 3, V: "c"})
+	fmt.Println(n, n.Count)
+	// Output:
+	// {"T":0,"N":[{"T":1,"V":"a"},{"T":2,"V":"b"},{"T":3,"V":"c"}]} 3
+}
+
+func ExampleNode_ReplaceWith() {
+	n := new(ast.Node)
+	n.Add(1, "a")
+	n.Add(9, "-")
+	n.Add(1, "b")
+	n.WalkDeepPre(func(u *ast.Node) {
+		switch u.T {
+		case 9:
+			u.ReplaceWith(&ast.Node{T: 2, V: "+"})
+		case 1:
+			u.Wrap(5)
+		}
+	})
+	fmt.Println(n, n.Count)
+	// Output:
+	// {"T":0,"N":[{"T":5,"N":[{"T":1,"V":"a"}]},{"T":2,"V":"+"},{"T":5,"N":[{"T":1,"V":"b"}]}]} 3
+}
@@ -0,0 +1,61 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+package ast
+
+import "sync"
+
+// Origin identifies the grammar that produced a node (see Node.O) so
+// that trees assembled from the output of several grammars can be
+// traced back to where each node came from while debugging. Origins
+// are always interned (see Intern) so that every node from the same
+// grammar points to the same Origin and adding one costs only
+// a pointer. Origin is never marshaled.
+type Origin struct {
+	Name    string // name of grammar (ex: kegml)
+	Version string // version of grammar (ex: v0.3.0)
+}
+
+// String returns the name and version joined with an at sign
+// (kegml@v0.3.0) or just the name if version is empty.
+func (o *Origin) String() string {
+	if o == nil {
+		return `<nil>`
+	}
+	if o.Version == "" {
+		return o.Name
+	}
+	return o.Name + `@` + o.Version
+}
+
+var origins = map[Origin]*Origin{}
+var originsmu sync.Mutex
+
+// Intern returns the one Origin for the name and version creating it
+// the first time. It is safe for concurrent use. Grammar packages
+// usually call it once when initialized.
+//
+//	var origin = ast.Intern(`kegml`, `v0.3.0`)
+func Intern(name, version string) *Origin {
+	originsmu.Lock()
+	defer originsmu.Unlock()
+	k := Origin{name, version}
+	if o, has := origins[k]; has {
+		return o
+	}
+	o := &k
+	origins[k] = o
+	return o
+}
+
+// SetOrigin sets the Origin (O) of the node and of every node under it
+// that does not already have one. Since nodes from other grammars keep
+// their own, it is safe to call on a tree after any number of subtrees
+// from other grammars have been added.
+func (n *Node) SetOrigin(o *Origin) {
+	n.WalkDeepPre(func(c *Node) {
+		if c.O == nil {
+			c.O = o
+		}
+	})
+}
@@ -0,0 +1,36 @@
+package ast_test
+
+import (
+	"fmt"
+
+	"github.com/rwxrob/pegn/ast"
+)
+
+func ExampleNode_SetOrigin() {
+
+	doc := ast.Intern(`kegml`, `v0.3.0`)
+	math := ast.Intern(`math`, ``)
+	fmt.Println(doc == ast.Intern(`kegml`, `v0.3.0`))
+
+	eq := new(ast.Node)
+	eq.T = 10
+	eq.Add(11, `x`)
+	eq.SetOrigin(math)
+
+	n := new(ast.Node)
+	n.T = 1
+	n.Add(2, `some`)
+	n.Append(eq)
+	n.SetOrigin(doc)
+
+	n.WalkDeepPre(func(c *ast.Node) { fmt.Println(c.T, c.O) })
+	fmt.Println(n)
+
+	// Output:
+	// true
+	// 1 kegml@v0.3.0
+	// 2 kegml@v0.3.0
+	// 10 math
+	// 11 math
+	// {"T":1,"N":[{"T":2,"V":"some"},{"T":10,"N":[{"T":11,"V":"x"}]}]}
+}
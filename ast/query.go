@@ -0,0 +1,148 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+package ast
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Selector is a compiled path of steps (see ParseSelector) selecting
+// nodes from a tree the way XPath selects elements from a document.
+type Selector struct {
+	abs   bool // first step matches the node itself (leading /)
+	steps []step
+}
+
+// step matches nodes directly under (or anywhere under if deep) those
+// matched by the step before it.
+type step struct {
+	deep bool
+	any  bool   // *
+	tag  string // :tag (see Node.Tag)
+	t    int
+}
+
+func (s step) match(n *Node) bool {
+	switch {
+	case s.any:
+		return true
+	case s.tag != "":
+		return n.Tag == s.tag
+	}
+	return n.T == s.t
+}
+
+// ParseSelector compiles the path expression into a Selector. Steps
+// are separated by slash (/) for nodes directly under those of the
+// step before or by two (//) for nodes anywhere under them. A leading
+// slash matches the first step against the node itself rather than
+// the nodes under it. Each step is one of the following:
+//
+//	Name   type with the name (resolved with id, see gr.Grammar.ID)
+//	12     type (integer)
+//	:tag   named capture with the tag (see Node.Capture)
+//	*      any node
+//
+// For example, "Rule/Name" selects the Name nodes directly under Rule
+// nodes directly under the node, "//Comment" every Comment node
+// anywhere under it, and "/Grammar//:key" every key capture within it
+// if it is a Grammar node. The id function may be nil if no names are
+// used.
+func ParseSelector(expr string, id func(name string) (int, bool)) (*Selector, error) {
+	sel := new(Selector)
+	rest := expr
+	if strings.HasPrefix(rest, `/`) && !strings.HasPrefix(rest, `//`) {
+		sel.abs = true
+		rest = rest[1:]
+	}
+	for len(rest) > 0 {
+		var st step
+		if strings.HasPrefix(rest, `//`) {
+			st.deep = true
+			rest = rest[2:]
+		} else if len(sel.steps) > 0 {
+			if rest[0] != '/' {
+				return nil, fmt.Errorf(`invalid selector: %q`, expr)
+			}
+			rest = rest[1:]
+		}
+		a := rest
+		if i := strings.IndexByte(rest, '/'); i >= 0 {
+			a, rest = rest[:i], rest[i:]
+		} else {
+			rest = ""
+		}
+		switch {
+		case a == `*`:
+			st.any = true
+		case len(a) > 1 && a[0] == ':':
+			st.tag = a[1:]
+		case a == "":
+			return nil, fmt.Errorf(`empty step in selector: %q`, expr)
+		default:
+			t, err := strconv.Atoi(a)
+			if err != nil {
+				var has bool
+				if id != nil {
+					t, has = id(a)
+				}
+				if !has {
+					return nil, fmt.Errorf(`unknown type in selector: %q`, a)
+				}
+			}
+			st.t = t
+		}
+		sel.steps = append(sel.steps, st)
+	}
+	if len(sel.steps) == 0 {
+		return nil, fmt.Errorf(`empty selector`)
+	}
+	return sel, nil
+}
+
+// Select returns every node selected from the tree of n in the order
+// first found (depth-first, preorder) without duplicates.
+func (s *Selector) Select(n *Node) []*Node {
+	list := []*Node{n}
+	for i, st := range s.steps {
+		var next []*Node
+		seen := map[*Node]bool{}
+		add := func(u *Node) {
+			if !seen[u] && st.match(u) {
+				seen[u] = true
+				next = append(next, u)
+			}
+		}
+		for _, c := range list {
+			switch {
+			case i == 0 && s.abs:
+				add(c)
+			case st.deep:
+				for _, u := range c.Nodes() {
+					u.WalkDeepPre(add)
+				}
+			default:
+				for _, u := range c.Nodes() {
+					add(u)
+				}
+			}
+		}
+		list = next
+	}
+	return list
+}
+
+// Query returns the nodes selected from the tree of n by the path
+// expression (see ParseSelector) or nil if the expression is invalid.
+// Since nodes only have integer types, names cannot be used (see
+// gr.Grammar.Query which resolves them).
+func (n *Node) Query(expr string) []*Node {
+	sel, err := ParseSelector(expr, nil)
+	if err != nil {
+		return nil
+	}
+	return sel.Select(n)
+}
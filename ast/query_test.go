@@ -0,0 +1,21 @@
+package ast_test
+
+import "fmt"
+
+func ExampleNode_Query() {
+	n := short(`[1,[[2,[[3,"a"],[4,[[3,"b"]]]]],[2,[[3,"c"]]]]]`)
+	for _, q := range []string{`2/3`, `//3`, `2//3`, `/1/2/4/3`, `*`, `bad`} {
+		fmt.Print(q)
+		for _, u := range n.Query(q) {
+			fmt.Printf(" %q", u.V)
+		}
+		fmt.Println()
+	}
+	// Output:
+	// 2/3 "a" "c"
+	// //3 "a" "b" "c"
+	// 2//3 "a" "b" "c"
+	// /1/2/4/3 "b"
+	// * "" ""
+	// bad
+}
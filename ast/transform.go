@@ -0,0 +1,75 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+package ast
+
+// Prune removes every node under this one for which fn returns true
+// (along with everything under it). Nodes under those removed are
+// never passed to fn.
+func (n *Node) Prune(fn func(u *Node) bool) {
+	for _, u := range n.Nodes() {
+		if fn(u) {
+			u.P = n
+			u.Cut()
+			continue
+		}
+		u.Prune(fn)
+	}
+}
+
+// Lift replaces every node under this one of any of the given types
+// with the nodes under it (in the same place) removing insignificant
+// wrapper nodes (ex: grouping rules) from the tree. Nodes of those
+// types with nothing under them are kept since they have values.
+func (n *Node) Lift(types ...int) {
+	for _, u := range n.Nodes() {
+		u.Lift(types...)
+		if u.Count > 0 && hasType(types, u.T) {
+			n.unwrap(u)
+		}
+	}
+}
+
+// Flatten collapses every chain of nodes with a single node under them
+// into the last of the chain (ex: Expr > Term > Factor > Num becomes
+// just Num). Only nodes of the given types are collapsed (or of any if
+// none are given). This node itself is never collapsed.
+func (n *Node) Flatten(types ...int) {
+	for _, u := range n.Nodes() {
+		u.Flatten(types...)
+		if u.Count == 1 && (len(types) == 0 || hasType(types, u.T)) {
+			n.unwrap(u)
+		}
+	}
+}
+
+// unwrap replaces u (under n) with the nodes under u.
+func (n *Node) unwrap(u *Node) {
+	for _, i := range u.Nodes() {
+		i.P = n
+	}
+	u.first.left = u.left
+	if u.left != nil {
+		u.left.right = u.first
+	} else {
+		n.first = u.first
+	}
+	u.last.right = u.right
+	if u.right != nil {
+		u.right.left = u.last
+	} else {
+		n.last = u.last
+	}
+	n.Count += u.Count - 1
+	u.P, u.left, u.right, u.first, u.last, u.Count = nil, nil, nil, nil, nil, 0
+}
+
+// hasType returns true if t is one of the types.
+func hasType(types []int, t int) bool {
+	for _, i := range types {
+		if i == t {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,43 @@
+package ast_test
+
+import (
+	"fmt"
+
+	"github.com/rwxrob/pegn/ast"
+)
+
+func short(data string) *ast.Node {
+	n := new(ast.Node)
+	if err := n.UnmarshalShort([]byte(data)); err != nil {
+		panic(err)
+	}
+	return n
+}
+
+func ExampleNode_Prune() {
+	n := short(`[1,[[2,"a"],[9," "],[3,[[9," "],[2,"b"]]]]]`)
+	n.Prune(func(u *ast.Node) bool { return u.T == 9 })
+	fmt.Println(n, n.Count)
+	// Output:
+	// {"T":1,"N":[{"T":2,"V":"a"},{"T":3,"N":[{"T":2,"V":"b"}]}]} 2
+}
+
+func ExampleNode_Lift() {
+	n := short(`[1,[[5,[[2,"a"],[2,"b"]]],[5,"kept"],[3,[[5,[[2,"c"]]]]]]]`)
+	n.Lift(5)
+	fmt.Println(n, n.Count)
+	// Output:
+	// {"T":1,"N":[{"T":2,"V":"a"},{"T":2,"V":"b"},{"T":5,"V":"kept"},{"T":3,"N":[{"T":2,"V":"c"}]}]} 4
+}
+
+func ExampleNode_Flatten() {
+	n := short(`[1,[[4,[[5,[[6,"1"]]]]],[7,"+"],[4,[[5,[[6,"2"],[8,"!"]]]]]]]`)
+	c := n.Copy()
+	n.Flatten()
+	fmt.Println(n)
+	c.Flatten(4)
+	fmt.Println(c)
+	// Output:
+	// {"T":1,"N":[{"T":6,"V":"1"},{"T":7,"V":"+"},{"T":5,"N":[{"T":6,"V":"2"},{"T":8,"V":"!"}]}]}
+	// {"T":1,"N":[{"T":5,"N":[{"T":6,"V":"1"}]},{"T":7,"V":"+"},{"T":5,"N":[{"T":6,"V":"2"},{"T":8,"V":"!"}]}]}
+}
@@ -0,0 +1,29 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+package ast
+
+import "strings"
+
+// TriviaNode is the reserved type of nodes holding source text matched
+// between (or before or after) the nodes of a concrete syntax tree
+// (whitespace, comments, delimiters, and such) so that the tree keeps
+// every byte it was parsed from (see Source). Like ErrorNode it is
+// reserved and never the type of a grammar node. Remove them with Prune
+// when only the abstract tree is wanted.
+const TriviaNode = ErrorNode + 2
+
+// Source returns the values of every node of the tree without nodes
+// under it (trivia included) in order joined together. For concrete
+// syntax trees (see TriviaNode) this is exactly the source parsed so
+// formatters can change only the nodes they care about and write the
+// rest back out unchanged.
+func (n *Node) Source() string {
+	var buf strings.Builder
+	n.WalkDeepPre(func(u *Node) {
+		if u.Count == 0 {
+			buf.WriteString(u.V)
+		}
+	})
+	return buf.String()
+}
@@ -0,0 +1,17 @@
+package ast_test
+
+import (
+	"fmt"
+
+	"github.com/rwxrob/pegn/ast"
+)
+
+func ExampleNode_Source() {
+	n := short(`[1,[[2,"a"],[-2147483646,", "],[2,"b"]]]`)
+	fmt.Printf("%q\n", n.Source())
+	n.Prune(func(u *ast.Node) bool { return u.T == ast.TriviaNode })
+	fmt.Printf("%q\n", n.Source())
+	// Output:
+	// "a, b"
+	// "ab"
+}
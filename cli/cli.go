@@ -0,0 +1,146 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+/*
+Package cli contains helpers for command line applications that embed
+PEGN grammars so that they all present the same options in the same
+way. Every flag value type here fulfills both flag.Value and the
+pflag.Value interface (with the additional Type method) used by
+spf13/cobra so that they can be passed directly to cmd.Flags().Var
+(see Flags and PFlags). Applications built with cobra can add the
+parse, scan, and check commands of the pegn command as their own (see
+ParseCommand, ScanCommand, and CheckCommand).
+*/
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/rwxrob/pegn/gr"
+)
+
+// Grammar is a flag value accepting either the path to a PEGN grammar
+// file or the inline PEGN itself. A value is considered inline only if
+// no file exists with that name and it contains an arrow (<-).
+type Grammar struct {
+	Grammar  *gr.Grammar // last grammar successfully set
+	Source   string      // the path or inline PEGN as given
+	Err      error       // from the last Set (nil if successful)
+	Warnings []string    // of the last grammar set (see gr.Grammar.Warnings)
+	Register bool        // register rules of grammars set (see Set)
+}
+
+// String fulfills flag.Value.
+func (f *Grammar) String() string { return f.Source }
+
+// Type fulfills pflag.Value.
+func (f *Grammar) Type() string { return `grammar` }
+
+// Set fulfills flag.Value by compiling the grammar from the file or
+// inline PEGN (see gr.Compile) so that grammars that could never be
+// executed (undefined rules and such) are rejected then and there
+// rather than failing later as if the input did not match. Any warnings (see gr.Grammar.Warnings) are kept in
+// Warnings for the application to report as it sees fit. The rules of
+// the grammar are only registered (so that errors name them, see
+// gr.Grammar.Register) if Register is set since doing so replaces any
+// rules the application has registered with the same IDs or names.
+func (f *Grammar) Set(v string) error {
+	var g *gr.Grammar
+	var err error
+	if _, serr := os.Stat(v); serr != nil && strings.Contains(v, `<-`) {
+		g, err = gr.Compile(v)
+	} else {
+		var byt []byte
+		if byt, err = os.ReadFile(v); err == nil {
+			g, err = gr.Compile(string(byt))
+		}
+	}
+	if err == nil && f.Register {
+		err = g.Register()
+	}
+	f.Err = err
+	if err != nil {
+		return err
+	}
+	f.Grammar, f.Source, f.Warnings = g, v, g.Warnings()
+	return nil
+}
+
+// Rule is a flag value accepting the name of a single rule. If Grammar
+// is set, and has already been loaded, the name must be a rule of that
+// grammar (or a builtin). Otherwise, since flags may be given in any
+// order, use Rule to look it up after the flags have been parsed.
+type Rule struct {
+	Name    string
+	Grammar *Grammar
+}
+
+// String fulfills flag.Value.
+func (f *Rule) String() string { return f.Name }
+
+// Type fulfills pflag.Value.
+func (f *Rule) Type() string { return `rule` }
+
+// Set fulfills flag.Value.
+func (f *Rule) Set(v string) error {
+	if !validName(v) {
+		return fmt.Errorf(`invalid rule name: %q`, v)
+	}
+	if f.Grammar != nil && f.Grammar.Grammar != nil {
+		if f.Grammar.Grammar.Lookup(v) == nil {
+			return fmt.Errorf(`rule not found: %v`, v)
+		}
+	}
+	f.Name = v
+	return nil
+}
+
+// Rule returns the named rule from the Grammar. If no name has been
+// set the first rule of the grammar is returned.
+func (f *Rule) Rule() (*gr.Rule, error) {
+	if f.Grammar == nil || f.Grammar.Grammar == nil {
+		return nil, fmt.Errorf(`no grammar`)
+	}
+	g := f.Grammar.Grammar
+	if f.Name == "" {
+		if len(g.Rules) == 0 {
+			return nil, fmt.Errorf(`grammar has no rules`)
+		}
+		return g.Rules[0], nil
+	}
+	if r := g.Lookup(f.Name); r != nil {
+		return r, nil
+	}
+	return nil, fmt.Errorf(`rule not found: %v`, f.Name)
+}
+
+// validName returns true if the name could be a PEGN rule, token, or
+// class name.
+func validName(a string) bool {
+	if len(a) == 0 || !(a[0] >= 'A' && a[0] <= 'Z' || a[0] >= 'a' && a[0] <= 'z') {
+		return false
+	}
+	for _, r := range a {
+		if !(r == '_' || r >= 'A' && r <= 'Z' || r >= 'a' && r <= 'z' || r >= '0' && r <= '9') {
+			return false
+		}
+	}
+	return true
+}
+
+// Flags adds the standard -g (grammar) and -r (rule) options to the
+// flag set (or flag.CommandLine if nil) and returns their values with
+// the rule already associated with the grammar.
+func Flags(fs *flag.FlagSet) (*Grammar, *Rule) {
+	if fs == nil {
+		fs = flag.CommandLine
+	}
+	g := new(Grammar)
+	r := &Rule{Grammar: g}
+	fs.Var(g, `g`, `grammar file or inline PEGN`)
+	fs.Var(r, `r`, `name of rule (default first rule of grammar)`)
+	return g, r
+}
@@ -0,0 +1,83 @@
+package cli_test
+
+import (
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/rwxrob/pegn/cli"
+	"github.com/rwxrob/pegn/model"
+	"github.com/rwxrob/pegn/rule"
+)
+
+func ExampleFlags() {
+
+	fs := flag.NewFlagSet(`app`, flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	g, r := cli.Flags(fs)
+
+	// rule before grammar is looked up later with Rule
+	err := fs.Parse([]string{`-r`, `Name`, `-g`, `Greet <-- 'hi' SP Name
+Name <-- upper lower+`})
+	fmt.Println(err)
+	fmt.Println(g.Grammar.Rules[0])
+	fmt.Println(r.Rule())
+
+	// grammar before rule is checked immediately
+	err = fs.Parse([]string{`-r`, `Missing`})
+	fmt.Println(err)
+
+	// Output:
+	// <nil>
+	// Greet <-- 'hi' SP Name
+	// Name <-- upper lower+ <nil>
+	// invalid value "Missing" for flag -r: rule not found: Missing
+}
+
+func ExampleGrammar_Set() {
+
+	// rule the application registered itself
+	rule.Replace(model.Rule{ID: 1, Name: `Host`, PEGN: `Host <-- 'host'`})
+
+	g := new(cli.Grammar)
+	g.Set(`Greet <-- 'hi'`)
+	r, _ := rule.ByID(1)
+	fmt.Println(r.Name)
+
+	g.Register = true
+	g.Set(`Greet <-- 'hi'`)
+	r, _ = rule.ByID(1)
+	fmt.Println(r.Name)
+
+	// Output:
+	// Host
+	// Greet
+}
+
+func ExampleGrammar_Set_undefined() {
+	g := new(cli.Grammar)
+	fmt.Println(g.Set(`Aa <-- Bb`))
+	fmt.Println(g.Err != nil, g.Grammar == nil)
+	// Output:
+	// line 1: undefined: Bb
+	// true true
+}
+
+func ExampleRule_Set() {
+	g := new(cli.Grammar)
+	g.Set(`Greet <-- 'hi'`)
+	r := &cli.Rule{Grammar: g}
+	fmt.Println(r.Set(`Greet`))
+	fmt.Println(r.Set(`SP`))
+	fmt.Println(r.Set(`Other`))
+	fmt.Println(r.Set(`1nvalid`))
+
+	g.Set("Old <- 'x'\n# Deprecated: use Old.\nNew <- 'y'\nUse <-- New")
+	fmt.Println(g.Warnings)
+	// Output:
+	// <nil>
+	// <nil>
+	// rule not found: Other
+	// invalid rule name: "1nvalid"
+	// [line 4: Use refers to deprecated New: use Old.]
+}
@@ -0,0 +1,159 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/rwxrob/pegn/gr"
+	"github.com/rwxrob/pegn/scanner"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// PFlags adds the standard --grammar (-g) and --rule (-r) options to the
+// pflag.FlagSet (of a cobra.Command) and returns their values with the
+// rule already associated with the grammar (see Flags).
+func PFlags(fs *pflag.FlagSet) (*Grammar, *Rule) {
+	g := new(Grammar)
+	r := &Rule{Grammar: g}
+	fs.VarP(g, `grammar`, `g`, `grammar file or inline PEGN`)
+	fs.VarP(r, `rule`, `r`, `name of rule (default first rule of grammar)`)
+	return g, r
+}
+
+// ParseCommand returns a new parse command that parses the input file
+// (or standard input) with the rule and writes the node tree as JSON
+// (see gr.Grammar.ParseRule) just as the pegn parse command does. The
+// rule must match all of the input. If not, the explanation of the
+// farthest failure is returned (see gr.Grammar.Explain).
+func ParseCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   `parse -g grammar [-r rule] [file]`,
+		Short: `Parse input and print the node tree as JSON`,
+		Args:  cobra.MaximumNArgs(1),
+	}
+	g, r := PFlags(cmd.Flags())
+	cmd.MarkFlagRequired(`grammar`)
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		rule, s, err := scanInput(cmd, g, r, args)
+		if err != nil {
+			return err
+		}
+		n := g.Grammar.ParseRule(rule.Name, s)
+		if n == nil || !s.Finished() {
+			return unmatched(g.Grammar, rule, s)
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), n)
+		return nil
+	}
+	return cmd
+}
+
+// ScanCommand returns a new scan command that matches the input file
+// (or standard input) completely against the rule writing nothing
+// unless it does not match (see gr.Grammar.ScanRule) just as the pegn
+// scan command does.
+func ScanCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   `scan -g grammar [-r rule] [file]`,
+		Short: `Check that input matches a rule`,
+		Args:  cobra.MaximumNArgs(1),
+	}
+	g, r := PFlags(cmd.Flags())
+	cmd.MarkFlagRequired(`grammar`)
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		rule, s, err := scanInput(cmd, g, r, args)
+		if err != nil {
+			return err
+		}
+		if !g.Grammar.ScanRule(rule.Name, s, nil) || !s.Finished() {
+			return unmatched(g.Grammar, rule, s)
+		}
+		return nil
+	}
+	return cmd
+}
+
+// CheckCommand returns a new check command that compiles every grammar
+// file given (or standard input) writing the errors and warnings of
+// each (see gr.Compile and gr.Grammar.Warnings) to standard error just
+// as the pegn check command does. An error is returned if any grammar
+// failed.
+func CheckCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   `check [grammar...]`,
+		Short: `Check grammars for errors and warnings`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				args = []string{`-`}
+			}
+			var failed bool
+			for _, path := range args {
+				var byt []byte
+				var err error
+				if path == `-` {
+					byt, err = io.ReadAll(cmd.InOrStdin())
+				} else {
+					byt, err = os.ReadFile(path)
+				}
+				if err != nil {
+					return err
+				}
+				g, err := gr.Compile(string(byt))
+				if err != nil {
+					fmt.Fprintf(cmd.ErrOrStderr(), "%v: %v\n", path, err)
+					failed = true
+					continue
+				}
+				for _, w := range g.Warnings() {
+					fmt.Fprintf(cmd.ErrOrStderr(), "%v: warning: %v\n", path, w)
+				}
+			}
+			if failed {
+				return errors.New(`check: grammar failed`)
+			}
+			return nil
+		},
+	}
+}
+
+// scanInput writes the warnings of the grammar to standard error and
+// returns the rule with a scanner of the input file (or standard input
+// if none).
+func scanInput(cmd *cobra.Command, g *Grammar, r *Rule, args []string) (*gr.Rule, *scanner.S, error) {
+	for _, w := range g.Warnings {
+		fmt.Fprintln(cmd.ErrOrStderr(), `warning: `+w)
+	}
+	rule, err := r.Rule()
+	if err != nil {
+		return nil, nil, err
+	}
+	s := scanner.New()
+	if len(args) == 0 {
+		err = s.Buffer(cmd.InOrStdin())
+	} else {
+		err = s.Open(args[0])
+	}
+	return rule, s, err
+}
+
+// unmatched returns the explanation of why the rule did not match all
+// of the input (see gr.Grammar.Explain).
+func unmatched(g *gr.Grammar, rule *gr.Rule, s *scanner.S) error {
+	x, err := g.Explain(rule.Name, s.Buf)
+	if err != nil {
+		return err
+	}
+	if x == nil {
+		return fmt.Errorf(`%v: failed to match`, rule.Name)
+	}
+	if s.Name != "" {
+		return fmt.Errorf(`%v: %w`, s.Name, x)
+	}
+	return x
+}
@@ -0,0 +1,43 @@
+package cli_test
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/rwxrob/pegn/cli"
+	"github.com/spf13/cobra"
+)
+
+func ExampleParseCommand() {
+
+	root := &cobra.Command{Use: `app`, SilenceUsage: true, SilenceErrors: true}
+	root.AddCommand(cli.ParseCommand(), cli.ScanCommand(), cli.CheckCommand())
+
+	run := func(in string, args ...string) {
+		out := new(bytes.Buffer)
+		root.SetArgs(args)
+		root.SetIn(strings.NewReader(in))
+		root.SetOut(out)
+		root.SetErr(out)
+		err := root.Execute()
+		fmt.Print(out)
+		fmt.Println(err)
+	}
+
+	list := "List <-- Item (',' Item)*\nItem <-- lower+"
+	run(`ab,cd`, `parse`, `-g`, list)
+	run(`ab,cd;`, `parse`, `--grammar`, list)
+	run(`ab`, `scan`, `-g`, list, `--rule`, `Item`)
+	run(`ab`, `scan`, `-g`, list, `-r`, `Nope`)
+	run("Expr <-- Expr '-' Num / Num\nNum <-- digit+", `check`)
+
+	// Output:
+	// {"T":1,"N":[{"T":2,"V":"ab"},{"T":2,"V":"cd"}]}
+	// <nil>
+	// line 1, column 6: expected lower or ',' but found ';'
+	// <nil>
+	// invalid argument "Nope" for "-r, --rule" flag: rule not found: Nope
+	// -: warning: line 1: left recursive: Expr -> Expr
+	// <nil>
+}
@@ -0,0 +1,198 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"time"
+
+	"github.com/rwxrob/pegn/ast"
+	"github.com/rwxrob/pegn/gr"
+	"github.com/rwxrob/pegn/scanner"
+)
+
+// benchResult is the JSON written by bench and read back as its
+// baseline.
+type benchResult struct {
+	Rule   string       `json:"rule"`
+	Inputs []benchInput `json:"inputs"`
+}
+
+// benchInput is the measurement of parsing a single input.
+type benchInput struct {
+	Path        string  `json:"path"`
+	Bytes       int     `json:"bytes"`
+	Runs        int     `json:"runs"`
+	NsPerOp     float64 `json:"ns_per_op"`
+	MBPerSec    float64 `json:"mb_per_sec"`
+	AllocsPerOp float64 `json:"allocs_per_op"`
+	BytesPerOp  float64 `json:"bytes_per_op"`
+	Nodes       int     `json:"nodes"`      // in tree parsed
+	TreeBytes   int     `json:"tree_bytes"` // see ast.Node.MemSize
+}
+
+// bench parses every file of the corpus (directories are walked) again
+// and again for at least the -t duration each and writes the throughput
+// and allocation per input as JSON along with the size of the tree
+// parsed (see ast.Node.MemSize). With -baseline (the JSON of an
+// earlier run) every input that is slower or allocates more than the
+// -threshold fraction allows is reported to standard error and
+// bench exits with ExitRegress.
+func bench(args []string) error {
+	fs, g, r := flags(`bench`)
+	baseline := fs.String(`baseline`, ``, `JSON of earlier run to compare with`)
+	threshold := fs.Float64(`threshold`, 0.1, `largest increase allowed (0.1 is 10%)`)
+	dur := fs.Duration(`t`, 100*time.Millisecond, `least time for each input`)
+	if err := parseFlags(fs, g, args); err != nil {
+		return err
+	}
+	rule, err := r.Rule()
+	if err != nil {
+		return usageErr(`bench: %v`, err)
+	}
+	if fs.NArg() == 0 {
+		return usageErr(`bench: corpus required`)
+	}
+	paths, err := corpus(fs.Args())
+	if err != nil {
+		return ioErr(err)
+	}
+
+	res := benchResult{Rule: rule.Name}
+	for _, path := range paths {
+		in, err := benchFile(g.Grammar, rule.Name, path, *dur)
+		if err != nil {
+			return err
+		}
+		res.Inputs = append(res.Inputs, in)
+	}
+	byt, err := json.MarshalIndent(res, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(byt))
+
+	if *baseline == `` {
+		return nil
+	}
+	byt, err = os.ReadFile(*baseline)
+	if err != nil {
+		return ioErr(err)
+	}
+	var old benchResult
+	if err := json.Unmarshal(byt, &old); err != nil {
+		return fmt.Errorf(`bench: %v: %w`, *baseline, err)
+	}
+	regressions := compare(old, res, *threshold)
+	for _, i := range regressions {
+		fmt.Fprintln(os.Stderr, i)
+	}
+	if len(regressions) > 0 {
+		return exitError{code: ExitRegress, err: fmt.Errorf(`bench: %v regressions`, len(regressions))}
+	}
+	return nil
+}
+
+// corpus returns every file of the paths walking directories (in
+// lexical order).
+func corpus(paths []string) ([]string, error) {
+	var files []string
+	for _, path := range paths {
+		err := filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !d.IsDir() {
+				files = append(files, p)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+// benchFile parses the file with the rule until at least the duration
+// has passed. Inputs that do not parse completely (as for parse) are
+// errors since measuring a failure says nothing about the grammar.
+func benchFile(g *gr.Grammar, rule, path string, dur time.Duration) (benchInput, error) {
+	in := benchInput{Path: path}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return in, ioErr(err)
+	}
+	in.Bytes = len(data)
+
+	s := scanner.New()
+	var tree *ast.Node
+	parse := func() bool {
+		*s.Errors() = (*s.Errors())[:0]
+		s.Buffer(data)
+		tree = g.ParseRule(rule, s)
+		return tree != nil
+	}
+	if !parse() || !s.Finished() {
+		return in, fmt.Errorf(`%v: %w`, path, unparsed(g, rule, s))
+	}
+	tree.WalkDeepPre(func(*ast.Node) { in.Nodes++ })
+	in.TreeBytes = tree.MemSize()
+
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	start := time.Now()
+	var took time.Duration
+	for in.Runs == 0 || took < dur {
+		parse()
+		in.Runs++
+		took = time.Since(start)
+	}
+	runtime.ReadMemStats(&after)
+
+	runs := float64(in.Runs)
+	in.NsPerOp = float64(took.Nanoseconds()) / runs
+	in.MBPerSec = float64(in.Bytes) * runs / 1e6 / took.Seconds()
+	in.AllocsPerOp = float64(after.Mallocs-before.Mallocs) / runs
+	in.BytesPerOp = float64(after.TotalAlloc-before.TotalAlloc) / runs
+	return in, nil
+}
+
+// compare returns a line for every input of the result (by path) that
+// takes more time or allocates more (count or bytes) per parse than in
+// the baseline by more than the threshold fraction. Inputs not in both
+// are ignored.
+func compare(old, res benchResult, threshold float64) []string {
+	base := map[string]benchInput{}
+	for _, in := range old.Inputs {
+		base[in.Path] = in
+	}
+	var lines []string
+	for _, in := range res.Inputs {
+		o, has := base[in.Path]
+		if !has {
+			continue
+		}
+		for _, m := range []struct {
+			name     string
+			old, new float64
+		}{
+			{`ns/op`, o.NsPerOp, in.NsPerOp},
+			{`allocs/op`, o.AllocsPerOp, in.AllocsPerOp},
+			{`B/op`, o.BytesPerOp, in.BytesPerOp},
+		} {
+			if m.old > 0 && (m.new-m.old)/m.old > threshold {
+				lines = append(lines, fmt.Sprintf(`%v: %v %.0f -> %.0f (%+.1f%%)`,
+					in.Path, m.name, m.old, m.new, (m.new-m.old)/m.old*100))
+			}
+		}
+	}
+	return lines
+}
@@ -0,0 +1,57 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/rwxrob/pegn/gr"
+)
+
+// check compiles every grammar file (or standard input) reporting any
+// that cannot be read or executed (see gr.Compile) along with warnings
+// (see gr.Grammar.Warnings). Nothing is printed to standard output.
+// Every file is checked even after one fails.
+func check(args []string) error {
+	fs := flag.NewFlagSet(`check`, flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+	if err := fs.Parse(args); err != nil {
+		return exitError{ExitUsage, err, true}
+	}
+
+	paths := fs.Args()
+	if len(paths) == 0 {
+		paths = []string{`-`}
+	}
+	var failed error
+	for _, path := range paths {
+		var byt []byte
+		var err error
+		if path == `-` {
+			byt, err = io.ReadAll(os.Stdin)
+		} else {
+			byt, err = os.ReadFile(path)
+		}
+		if err != nil {
+			return err
+		}
+		g, err := gr.Compile(string(byt))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%v: %v\n", path, err)
+			failed = errors.New(`check: grammar failed`)
+			continue
+		}
+		for _, w := range g.Warnings() {
+			fmt.Fprintf(os.Stderr, "%v: warning: %v\n", path, w)
+		}
+	}
+	if failed != nil {
+		return exitError{ExitGrammar, failed, true}
+	}
+	return nil
+}
@@ -0,0 +1,32 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import "fmt"
+
+// explain matches the input file (or standard input) against the rule
+// and prints where and why it failed (see gr.Grammar.Explain).
+func explain(args []string) error {
+	fs, g, r := flags(`explain`)
+	if err := parseFlags(fs, g, args); err != nil {
+		return err
+	}
+
+	in, err := input(fs)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	x, err := g.Grammar.Explain(r.Name, in)
+	if err != nil {
+		return err
+	}
+	if x == nil {
+		fmt.Println(`ok`)
+		return nil
+	}
+	fmt.Println(x.String())
+	return parseErr(fmt.Errorf(`explain: input does not match %v`, x.Rule))
+}
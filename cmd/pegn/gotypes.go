@@ -0,0 +1,20 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import "fmt"
+
+// gotypes prints Go source with typed wrappers for every node rule of
+// the grammar (see gr.Grammar.GoTypes). It is meant for go generate:
+//
+//	//go:generate sh -c "pegn gotypes -g calc.pegn -p calc > nodes.go"
+func gotypes(args []string) error {
+	fs, g, _ := flags(`gotypes`)
+	pkg := fs.String(`p`, `main`, `package name`)
+	if err := parseFlags(fs, g, args); err != nil {
+		return err
+	}
+	fmt.Print(g.Grammar.GoTypes(*pkg))
+	return nil
+}
@@ -0,0 +1,185 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/rwxrob/pegn/gr"
+	"github.com/rwxrob/pegn/gr/grtest"
+)
+
+// lesson is a single step of the tutorial: a small grammar with one
+// rule that does not yet match its examples (see gr.ValidPrefix) and
+// must be fixed by redefining it.
+type lesson struct {
+	Title   string
+	Text    string // what the lesson teaches and asks
+	Grammar string // PEGN with Valid and Invalid examples
+	Hint    string // a definition that passes
+}
+
+// lessons are given in order, each building on the last.
+var lessons = []lesson{
+	{
+		Title: `Literals and ordered choice`,
+		Text: `Quoted text is matched exactly. Alternatives are separated by / and
+tried in order. Make Greeting match both "hello" and "hi".`,
+		Grammar: `
+# Valid: hello
+# Valid: hi
+# Invalid: hey
+Greeting <- 'hello'`,
+		Hint: `Greeting <- 'hello' / 'hi'`,
+	},
+	{
+		Title: `Classes and repetition`,
+		Text: `Lower case names (upper, lower, digit) are classes matching a single
+rune. A suffix of + repeats one or more times, * zero or more, and
+? makes it optional. Make Name match a capitalized word of any length.`,
+		Grammar: `
+# Valid: Rob
+# Valid: Al
+# Invalid: rob
+Name <- upper lower`,
+		Hint: `Name <- upper lower+`,
+	},
+	{
+		Title: `Counts`,
+		Text: `A count in braces repeats exactly {n}, at least {n,}, or between
+{n,m} times. Make Year match exactly four digits.`,
+		Grammar: `
+# Valid: 2022
+# Invalid: 22
+# Invalid: 20222
+Year <- digit+`,
+		Hint: `Year <- digit{4}`,
+	},
+	{
+		Title: `The first match wins`,
+		Text: `Unlike regular expressions, once an alternative matches the others
+are never tried, even if the rest of the input then fails. Fix Keyword
+so that "int" is matched completely.`,
+		Grammar: `
+# Valid: in
+# Valid: int
+Keyword <- 'in' / 'int'`,
+		Hint: `Keyword <- 'int' / 'in'`,
+	},
+	{
+		Title: `Repetition never gives back`,
+		Text: `Repetition matches as much as it can and never backtracks, so
+unipoint* consumes the line ending Line needs. Use a negative
+lookahead (!) to stop Comment before LF.`,
+		Grammar: `
+# Valid: "# hello\n"
+# Invalid: "# hello"
+Line    <- Comment LF
+
+# Valid: # hello
+Comment <- '#' unipoint*`,
+		Hint: `Comment <- '#' (!LF unipoint)*`,
+	},
+}
+
+// learn runs the interactive tutorial on standard input and output.
+func learn(args []string) error {
+	fs := flag.NewFlagSet(`learn`, flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+	from := fs.Int(`l`, 1, `lesson to start from`)
+	if err := fs.Parse(args); err != nil {
+		return exitError{ExitUsage, err, true}
+	}
+	if *from < 1 || *from > len(lessons) {
+		return usageErr(`learn: no lesson %v (1-%v)`, *from, len(lessons))
+	}
+	return tutor(os.Stdin, os.Stdout, lessons[*from-1:])
+}
+
+const learnHelp = `Enter a new definition (Name <- ...) for any rule. Commands:
+
+  :show  show the grammar again
+  :hint  show a definition that passes
+  :skip  go on to the next lesson
+  :quit  stop the tutorial
+`
+
+// tutor gives every lesson in turn reading new definitions from in
+// until the examples of the lesson all pass.
+func tutor(in io.Reader, out io.Writer, lessons []lesson) error {
+	lines := bufio.NewScanner(in)
+	fmt.Fprint(out, learnHelp)
+	for i, l := range lessons {
+		g, err := gr.Read(l.Grammar)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "\n%v. %v\n\n%v\n\n%v\n", i+1, l.Title, l.Text, g)
+		for {
+			fmt.Fprint(out, `> `)
+			if !lines.Scan() {
+				fmt.Fprintln(out)
+				return lines.Err()
+			}
+			line := strings.TrimSpace(lines.Text())
+			switch line {
+			case ``:
+				continue
+			case `:show`:
+				fmt.Fprint(out, g)
+				continue
+			case `:hint`:
+				fmt.Fprintln(out, l.Hint)
+				continue
+			case `:quit`:
+				return nil
+			case `:skip`:
+			default:
+				n, err := redefine(g, line)
+				if err != nil {
+					fmt.Fprintln(out, err)
+					continue
+				}
+				g = n
+				if err := grtest.Examples(g); err != nil {
+					fmt.Fprintln(out, `not yet:`, err)
+					continue
+				}
+				fmt.Fprintln(out, `correct!`)
+			}
+			break
+		}
+	}
+	fmt.Fprintln(out, "\nThat is every lesson. See pegn.dev for the rest of PEGN.")
+	return nil
+}
+
+// redefine returns a new grammar with the rule of the definition
+// replaced (keeping its Doc and examples) or added if not yet defined.
+func redefine(g *gr.Grammar, def string) (*gr.Grammar, error) {
+	var r gr.Rule
+	if err := r.UnmarshalText([]byte(def)); err != nil {
+		return nil, err
+	}
+	n := *g
+	n.Rules = make([]*gr.Rule, 0, len(g.Rules)+1)
+	found := false
+	for _, o := range g.Rules {
+		if o.Name == r.Name {
+			c := *o
+			c.Node, c.Expr = r.Node, r.Expr
+			o, found = &c, true
+		}
+		n.Rules = append(n.Rules, o)
+	}
+	if !found {
+		n.Rules = append(n.Rules, &r)
+	}
+	return gr.Compile(n.String())
+}
@@ -0,0 +1,172 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+/*
+Command pegn provides tooling for working with PEGN grammars from the
+command line.
+
+	pegn bench -g grammar.pegn [-r Rule] [-baseline old.json] corpus...
+	pegn check [grammar.pegn...]
+	pegn explain -g grammar.pegn [-r Rule] [file]
+	pegn gotypes -g grammar.pegn [-p package]
+	pegn learn [-l lesson]
+	pegn parse -g grammar.pegn [-r Rule] [-events] [-profile] [-progress N]
+	           [-lossless] [file]
+	pegn profile -g grammar.pegn [-r Rule] [-w] [file...]
+	pegn rename -g grammar.pegn [-w] OldName NewName
+	pegn scan -g grammar.pegn [-r Rule] [file]
+
+Input is read from standard input unless a file is given. Results
+(trees, grammars, reports) are written to standard output and
+diagnostics (errors, warnings, notes) to standard error so that pegn
+composes with other commands in scripts and Makefiles. The exit codes
+are stable:
+
+	0  ok
+	1  input does not match the grammar
+	2  grammar could not be read
+	3  usage (unknown command, invalid flags or arguments, unreadable input)
+	4  performance regressed (bench)
+*/
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/rwxrob/pegn/cli"
+)
+
+// Exit codes (see package documentation).
+const (
+	ExitOK      = 0
+	ExitParse   = 1
+	ExitGrammar = 2
+	ExitUsage   = 3
+	ExitRegress = 4
+)
+
+// exitError is an error with the exit code it should produce.
+type exitError struct {
+	code  int
+	err   error
+	shown bool // already written to standard error (by flag)
+}
+
+func (e exitError) Error() string { return e.err.Error() }
+func (e exitError) Unwrap() error { return e.err }
+
+func usageErr(format string, args ...any) error {
+	return exitError{code: ExitUsage, err: fmt.Errorf(format, args...)}
+}
+
+func parseErr(err error) error { return exitError{code: ExitParse, err: err} }
+
+// ioErr is for input that cannot be read at all (rather than input that
+// does not match the grammar) such as files that do not exist.
+func ioErr(err error) error { return exitError{code: ExitUsage, err: err} }
+
+// commands contains every subcommand by name.
+var commands = map[string]func(args []string) error{
+	`bench`:   bench,
+	`check`:   check,
+	`explain`: explain,
+	`gotypes`: gotypes,
+	`learn`:   learn,
+	`parse`:   parse,
+	`profile`: profile,
+	`rename`:  rename,
+	`scan`:    scan,
+}
+
+func usage() {
+	var names []string
+	for n := range commands {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	fmt.Fprintf(os.Stderr, "usage: pegn <command> [options]\n\ncommands: %v\n", names)
+}
+
+// flags returns a new flag set for the command that returns errors
+// rather than exiting along with the standard grammar and rule flags
+// (see cli.Flags). The rules of the grammar are registered so that
+// errors (and profiles) name them. Usage is not printed for a grammar
+// that fails since the error says all there is to say.
+func flags(name string) (*flag.FlagSet, *cli.Grammar, *cli.Rule) {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+	g, r := cli.Flags(fs)
+	g.Register = true
+	usage := fs.Usage
+	fs.Usage = func() {
+		if g.Err == nil {
+			usage()
+		}
+	}
+	return fs, g, r
+}
+
+// parseFlags parses the arguments and requires a grammar returning
+// errors with the exit code for a grammar that cannot be read or
+// invalid usage. Warnings about the grammar are written to standard
+// error.
+func parseFlags(fs *flag.FlagSet, g *cli.Grammar, args []string) error {
+	if err := fs.Parse(args); err != nil {
+		if g.Err != nil {
+			return exitError{ExitGrammar, g.Err, true}
+		}
+		return exitError{ExitUsage, err, true}
+	}
+	if g.Grammar == nil {
+		return usageErr(`%v: grammar (-g) required`, fs.Name())
+	}
+	for _, w := range g.Warnings {
+		fmt.Fprintln(os.Stderr, `warning: `+w)
+	}
+	return nil
+}
+
+// input returns the first file argument opened (or standard input if
+// none). Close is a no-op for standard input.
+func input(fs *flag.FlagSet) (io.ReadCloser, error) {
+	if fs.NArg() == 0 {
+		return io.NopCloser(os.Stdin), nil
+	}
+	f, err := os.Open(fs.Arg(0))
+	if err != nil {
+		return nil, ioErr(err)
+	}
+	return f, nil
+}
+
+func run(args []string) int {
+	if len(args) < 1 {
+		usage()
+		return ExitUsage
+	}
+	cmd, has := commands[args[0]]
+	if !has {
+		usage()
+		return ExitUsage
+	}
+	err := cmd(args[1:])
+	if err == nil {
+		return ExitOK
+	}
+	var e exitError
+	if errors.As(err, &e) {
+		if !e.shown {
+			fmt.Fprintln(os.Stderr, err)
+		}
+		return e.code
+	}
+	fmt.Fprintln(os.Stderr, err)
+	return ExitParse
+}
+
+func main() { os.Exit(run(os.Args[1:])) }
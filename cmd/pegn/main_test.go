@@ -0,0 +1,303 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/rwxrob/pegn/gr"
+	"github.com/rwxrob/pegn/scanner"
+)
+
+func Example_run_parse() {
+
+	file, dir := fixture()
+	defer os.RemoveAll(dir)
+	g := file(`list.pegn`, "List <-- Item (',' Item)*\nItem <-- lower+\n")
+
+	defer func(f *os.File) { os.Stderr = f }(os.Stderr)
+	os.Stderr, _ = os.Open(os.DevNull)
+
+	fmt.Println(run([]string{`parse`, `-g`, g, file(`ok`, `ab,cd`)}))
+	fmt.Println(run([]string{`parse`, `-g`, g, file(`rest`, `ab,cd;ef`)}))
+	fmt.Println(run([]string{`parse`, `-g`, g, `-lossless`, file(`rest`, `ab,cd;ef`)}))
+	fmt.Println(run([]string{`parse`, `-g`, g, `-events`, file(`rest`, `ab,cd;ef`)}))
+	fmt.Println(run([]string{`parse`, `-g`, g, filepath.Join(dir, `missing`)}))
+	fmt.Println(run([]string{`scan`, `-g`, g, filepath.Join(dir, `missing`)}))
+	fmt.Println(run([]string{`parse`, `-g`, `Aa <-- Bb`, file(`ok`, `ab,cd`)}))
+
+	// Output:
+	// {"T":1,"N":[{"T":2,"V":"ab"},{"T":2,"V":"cd"}]}
+	// 0
+	// 1
+	// 1
+	// {"rule":"List","type":1,"depth":0,"b":0,"e":5}
+	// {"rule":"Item","type":2,"depth":1,"b":0,"e":2,"value":"ab"}
+	// {"rule":"Item","type":2,"depth":1,"b":3,"e":5,"value":"cd"}
+	// 1
+	// 3
+	// 3
+	// 2
+}
+
+func Example_unparsed() {
+
+	g := gr.MustRead("List <-- Item (',' Item)*\nItem <-- lower+\n")
+
+	// stopped before the end without pushing an error
+	s := scanner.New(`ab`)
+	s.Scan()
+	fmt.Println(unparsed(g, `Item`, s))
+
+	s = scanner.New(``)
+	fmt.Println(failed(s, `List`))
+
+	// Output:
+	// 1:2: unparsed input at 'b' after Item
+	// 1:1: failed to match List
+}
+
+func Example_run_bench() {
+
+	file, dir := fixture()
+	defer os.RemoveAll(dir)
+	g := file(`list.pegn`, "List <-- Item (',' Item)*\nItem <-- lower+\n")
+	file(`corpus/a`, `ab,cd`)
+	file(`corpus/b`, `ab,cd,ef`)
+	bad := file(`bad/c`, `ab;cd`)
+
+	defer func(f *os.File) { os.Stderr = f }(os.Stderr)
+	os.Stderr, _ = os.Open(os.DevNull)
+	// runs (and so times) vary so only what does not is printed
+	var out string
+	bench := func(args ...string) int {
+		var code int
+		out = stdout(func() {
+			code = run(append([]string{`bench`, `-g`, g, `-t`, `1ms`}, args...))
+		})
+		return code
+	}
+	fmt.Println(bench(filepath.Join(dir, `corpus`)))
+	var res benchResult
+	json.Unmarshal([]byte(out), &res)
+	for _, in := range res.Inputs {
+		fmt.Println(res.Rule, filepath.Base(in.Path), in.Bytes, in.Nodes, in.Runs > 0, in.NsPerOp > 0)
+	}
+
+	// every input regresses against a baseline taking no time at all
+	for i := range res.Inputs {
+		res.Inputs[i].NsPerOp = 1
+	}
+	byt, _ := json.Marshal(res)
+	base := file(`base.json`, string(byt))
+	fmt.Println(bench(`-baseline`, base, filepath.Join(dir, `corpus`)))
+
+	fmt.Println(bench(bad))
+	fmt.Println(bench())
+
+	// Output:
+	// 0
+	// List a 5 3 true true
+	// List b 8 4 true true
+	// 4
+	// 1
+	// 3
+}
+
+// fixture returns a function writing files (and any parent
+// directories) into a new temporary directory and the directory itself
+// for examples of the commands.
+func fixture() (func(name, data string) string, string) {
+	dir, _ := os.MkdirTemp("", `pegn`)
+	return func(name, data string) string {
+		path := filepath.Join(dir, name)
+		os.MkdirAll(filepath.Dir(path), 0700)
+		os.WriteFile(path, []byte(data), 0600)
+		return path
+	}, dir
+}
+
+// stdin replaces standard input with the data until the function
+// returned is called.
+func stdin(data string) func() {
+	f, _ := os.CreateTemp("", `stdin`)
+	f.WriteString(data)
+	f.Seek(0, 0)
+	old := os.Stdin
+	os.Stdin = f
+	return func() { os.Stdin = old; f.Close(); os.Remove(f.Name()) }
+}
+
+// stdout returns everything the function writes to standard output.
+func stdout(fn func()) string {
+	f, _ := os.CreateTemp("", `stdout`)
+	defer os.Remove(f.Name())
+	old := os.Stdout
+	os.Stdout = f
+	fn()
+	os.Stdout = old
+	f.Close()
+	byt, _ := os.ReadFile(f.Name())
+	return string(byt)
+}
+
+func Example_run_check() {
+
+	file, dir := fixture()
+	defer os.RemoveAll(dir)
+
+	defer func(f *os.File) { os.Stderr = f }(os.Stderr)
+	os.Stderr, _ = os.Open(os.DevNull)
+
+	fmt.Println(run([]string{`check`, file(`ok.pegn`, "List <-- Item (',' Item)*\nItem <-- lower+\n")}))
+	fmt.Println(run([]string{`check`, file(`bad.pegn`, "List <-- Item (',' Item)*\n")}))
+	defer stdin("Item <-- lower+\n")()
+	fmt.Println(run([]string{`check`}))
+
+	// Output:
+	// 0
+	// 2
+	// 0
+}
+
+func Example_run_explain() {
+
+	file, dir := fixture()
+	defer os.RemoveAll(dir)
+	g := file(`list.pegn`, "List <-- Item (',' Item)*\nItem <-- lower+\n")
+
+	defer func(f *os.File) { os.Stderr = f }(os.Stderr)
+	os.Stderr, _ = os.Open(os.DevNull)
+
+	fmt.Println(run([]string{`explain`, `-g`, g, file(`ok`, `ab,cd`)}))
+	fmt.Println(run([]string{`explain`, `-g`, g, file(`bad`, `ab,`)}))
+
+	// Output:
+	// ok
+	// 0
+	// line 1, column 4: expected lower but found end of data
+	//   in List > Item
+	//   ruled out Item
+	// 1
+}
+
+func Example_run_gotypes() {
+
+	file, dir := fixture()
+	defer os.RemoveAll(dir)
+	g := file(`list.pegn`, "List <-- Item (',' Item)*\nItem <-- lower+\n")
+
+	defer func(f *os.File) { os.Stderr = f }(os.Stderr)
+	os.Stderr, _ = os.Open(os.DevNull)
+
+	var code int
+	out := stdout(func() { code = run([]string{`gotypes`, `-g`, g, `-p`, `list`}) })
+	for _, line := range strings.Split(out, "\n") {
+		if strings.HasPrefix(line, `package `) || strings.HasPrefix(line, `type `) {
+			fmt.Println(line)
+		}
+	}
+	fmt.Println(code)
+	fmt.Println(run([]string{`gotypes`, `-p`, `list`}))
+
+	// Output:
+	// package list
+	// type ListNode struct{ *ast.Node }
+	// type ItemNode struct{ *ast.Node }
+	// 0
+	// 3
+}
+
+func Example_run_learn() {
+
+	defer func(f *os.File) { os.Stderr = f }(os.Stderr)
+	os.Stderr, _ = os.Open(os.DevNull)
+
+	defer stdin("Greeting <- 'hi'\n:hint\nGreeting <- 'hello' / 'hi'\n:quit\n")()
+	fmt.Println(run([]string{`learn`}))
+	fmt.Println(run([]string{`learn`, `-l`, `99`}))
+
+	// Output:
+	// Enter a new definition (Name <- ...) for any rule. Commands:
+	//
+	//   :show  show the grammar again
+	//   :hint  show a definition that passes
+	//   :skip  go on to the next lesson
+	//   :quit  stop the tutorial
+	//
+	// 1. Literals and ordered choice
+	//
+	// Quoted text is matched exactly. Alternatives are separated by / and
+	// tried in order. Make Greeting match both "hello" and "hi".
+	//
+	// # Valid: hello
+	// # Valid: hi
+	// # Invalid: hey
+	// Greeting <- 'hello'
+	//
+	// > not yet: Greeting: valid example "hello": line 1, column 1: expected 'hi' but found 'h'
+	// > Greeting <- 'hello' / 'hi'
+	// > correct!
+	//
+	// 2. Classes and repetition
+	//
+	// Lower case names (upper, lower, digit) are classes matching a single
+	// rune. A suffix of + repeats one or more times, * zero or more, and
+	// ? makes it optional. Make Name match a capitalized word of any length.
+	//
+	// # Valid: Rob
+	// # Valid: Al
+	// # Invalid: rob
+	// Name <- upper lower
+	//
+	// > 0
+	// 3
+}
+
+func Example_run_profile() {
+
+	file, dir := fixture()
+	defer os.RemoveAll(dir)
+	g := file(`kw.pegn`, "Words <-- Word (SP Word)*\nWord <-- 'if' / 'else' / 'for'\n")
+	in := file(`in`, `for for else for`)
+
+	defer func(f *os.File) { os.Stderr = f }(os.Stderr)
+	os.Stderr, _ = os.Open(os.DevNull)
+
+	fmt.Println(run([]string{`profile`, `-g`, g, in}))
+	fmt.Println(run([]string{`profile`, `-g`, g, `-w`, in}))
+
+	// Output:
+	// Word: 'if' (0/4) / 'else' (1/4) / 'for' (3/3)
+	// 0
+	// Words <-- Word (SP Word)*
+	// Word  <-- 'for' / 'else' / 'if'
+	// 0
+}
+
+func Example_run_rename() {
+
+	file, dir := fixture()
+	defer os.RemoveAll(dir)
+	g := file(`list.pegn`, "List <-- Item (',' Item)*\nItem <-- lower+\n")
+
+	defer func(f *os.File) { os.Stderr = f }(os.Stderr)
+	os.Stderr, _ = os.Open(os.DevNull)
+
+	fmt.Println(run([]string{`rename`, `-g`, g, `Item`, `Entry`}))
+	fmt.Println(run([]string{`rename`, `-g`, g, `Item`, `List`}))
+	fmt.Println(run([]string{`rename`, `-g`, g, `-w`, `Item`, `Entry`}))
+	byt, _ := os.ReadFile(g)
+	fmt.Print(string(byt))
+
+	// Output:
+	// List  <-- Entry (',' Entry)*
+	// Entry <-- lower+
+	// 0
+	// 3
+	// 0
+	// List  <-- Entry (',' Entry)*
+	// Entry <-- lower+
+}
@@ -0,0 +1,134 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/rwxrob/pegn"
+	"github.com/rwxrob/pegn/gr"
+	"github.com/rwxrob/pegn/scanner"
+)
+
+// parse parses the input file (or standard input) with the rule and
+// prints the node tree as JSON (see gr.Grammar.ParseRule). As with scan
+// the rule must match all of the input. If not, the farthest failure
+// is reported (see gr.Grammar.Explain). With -events
+// one JSON object is printed per line for every node rule matched
+// instead (see gr.Grammar.Events) for post-processing with jq, grep,
+// and such. With -profile the calls, failures, and time (total and
+// self) of every rule are printed to standard error after the parse
+// (see scanner.S.EnableProfile) along with the stats of the scanner
+// (see scanner.S.Stats) to find the rules worth optimizing. With
+// -progress N the bytes and percentage of the input parsed so far are
+// printed to standard error every N bytes (see scanner.S.OnProgress).
+// With -lossless the tree keeps all text matched between nodes as
+// trivia nodes (see gr.Grammar.ParseLossless).
+func parse(args []string) error {
+	fs, g, r := flags(`parse`)
+	events := fs.Bool(`events`, false, `print one JSON event per rule matched`)
+	prof := fs.Bool(`profile`, false, `print calls and time per rule to stderr`)
+	lossless := fs.Bool(`lossless`, false, `keep text between nodes as trivia nodes`)
+	every := fs.Int(`progress`, 0, `print progress to stderr every `+"`N`"+` bytes`)
+	if err := parseFlags(fs, g, args); err != nil {
+		return err
+	}
+	rule, err := r.Rule()
+	if err != nil {
+		return usageErr(`parse: %v`, err)
+	}
+
+	in, err := input(fs)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	s := scanner.New()
+	if err := s.Buffer(in); err != nil {
+		return ioErr(err)
+	}
+	if *prof {
+		s.EnableProfile()
+		defer printProfile(s)
+	}
+	if *every > 0 {
+		s.OnProgress(*every, func(pos int, pct float64) {
+			fmt.Fprintf(os.Stderr, "progress: %v bytes (%.1f%%)\n", pos, pct)
+		})
+	}
+
+	if *events {
+		out := bufio.NewWriter(os.Stdout)
+		defer out.Flush()
+		enc := json.NewEncoder(out)
+		enc.SetEscapeHTML(false)
+		err := g.Grammar.Events(rule.Name, s, func(e gr.Event) error {
+			return enc.Encode(e)
+		})
+		if errors.As(err, new(pegn.Error)) || err == nil && !s.Finished() {
+			return unparsed(g.Grammar, rule.Name, s)
+		}
+		return err
+	}
+
+	parseRule := g.Grammar.ParseRule
+	if *lossless {
+		parseRule = g.Grammar.ParseLossless
+	}
+	n := parseRule(rule.Name, s)
+	if n == nil || !s.Finished() {
+		return unparsed(g.Grammar, rule.Name, s)
+	}
+	n.Println()
+	return nil
+}
+
+// unparsed returns the error for input the rule failed to match or did
+// not match all of explaining the farthest failure (see
+// gr.Grammar.Explain) with the last error pushed as a fallback.
+func unparsed(g *gr.Grammar, rule string, s *scanner.S) error {
+	x, err := g.Explain(rule, s.Buf)
+	if err != nil {
+		return err
+	}
+	if x != nil {
+		return parseErr(x)
+	}
+	return failed(s, rule)
+}
+
+// failed returns the last error pushed to the scanner (see posErr) or,
+// if none was, an error for the input after the cursor not parsed by
+// the rule.
+func failed(s *scanner.S, rule string) error {
+	errs := *s.Errors()
+	if len(errs) > 0 {
+		return posErr(s, errs[len(errs)-1])
+	}
+	if !s.Scan() {
+		return parseErr(fmt.Errorf(`%v: failed to match %v`, s.Pos().Short(), rule))
+	}
+	return parseErr(fmt.Errorf(`%v: unparsed input at %q after %v`, s.Pos().Short(), s.Rune(), rule))
+}
+
+// printProfile prints the profile of the scanner to standard error
+// (with the rules named since the grammar is registered, see flags).
+func printProfile(s *scanner.S) {
+	fmt.Fprint(os.Stderr, s.Profile())
+	fmt.Fprintln(os.Stderr, s.Stats())
+}
+
+// posErr prefixes scanner errors with their position in the input
+// (with the exit code for input not matching the grammar).
+func posErr(s *scanner.S, err error) error {
+	if !errors.As(err, new(pegn.Error)) {
+		return err
+	}
+	return parseErr(errors.New(strings.TrimSpace(s.FormatErr(err))))
+}
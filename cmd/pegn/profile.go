@@ -0,0 +1,54 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// profile matches the rule against every file of a corpus (or standard
+// input as the only file) and prints how often each alternative of
+// every choice was tried and matched (see gr.Grammar.Profile). With -w the grammar with its alternatives
+// reordered (most matched first) is printed instead with notes about
+// each reordering going to standard error.
+func profile(args []string) error {
+	fs, g, r := flags(`profile`)
+	write := fs.Bool(`w`, false, `print reordered grammar`)
+	if err := parseFlags(fs, g, args); err != nil {
+		return err
+	}
+
+	var corpus []string
+	if fs.NArg() == 0 {
+		byt, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return ioErr(err)
+		}
+		corpus = append(corpus, string(byt))
+	}
+	for _, path := range fs.Args() {
+		byt, err := os.ReadFile(path)
+		if err != nil {
+			return ioErr(err)
+		}
+		corpus = append(corpus, string(byt))
+	}
+
+	p, err := g.Grammar.Profile(r.Name, corpus...)
+	if err != nil {
+		return err
+	}
+	if !*write {
+		fmt.Print(p)
+		return nil
+	}
+	n, notes := p.Reorder()
+	for _, i := range notes {
+		fmt.Fprintln(os.Stderr, i)
+	}
+	fmt.Print(n)
+	return nil
+}
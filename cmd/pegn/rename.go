@@ -0,0 +1,37 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+// rename renames a rule of the grammar along with every reference to
+// it (see gr.Grammar.Rename) and prints the grammar in canonical PEGN
+// form. With -w the grammar file is overwritten instead. Conflicts with
+// existing names (case-insensitive) are usage errors and change
+// nothing.
+func rename(args []string) error {
+	fs, g, _ := flags(`rename`)
+	write := fs.Bool(`w`, false, `overwrite grammar file`)
+	if err := parseFlags(fs, g, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 2 {
+		return usageErr(`rename: old and new rule names required`)
+	}
+	if err := g.Grammar.Rename(fs.Arg(0), fs.Arg(1)); err != nil {
+		return exitError{code: ExitUsage, err: err}
+	}
+	if !*write {
+		fmt.Print(g.Grammar)
+		return nil
+	}
+	info, err := os.Stat(g.Source)
+	if err != nil {
+		return usageErr(`rename: -w requires a grammar file`)
+	}
+	return os.WriteFile(g.Source, []byte(g.Grammar.String()), info.Mode())
+}
@@ -0,0 +1,44 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/rwxrob/pegn/scanner"
+)
+
+// scan matches the input file (or standard input) completely against
+// the rule printing nothing unless it does not match (see
+// gr.Grammar.ScanRule) so that it can be used for validation where only
+// the exit code matters. Use explain to find out why input does not
+// match.
+func scan(args []string) error {
+	fs, g, r := flags(`scan`)
+	if err := parseFlags(fs, g, args); err != nil {
+		return err
+	}
+	rule, err := r.Rule()
+	if err != nil {
+		return usageErr(`scan: %v`, err)
+	}
+
+	in, err := input(fs)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	s := scanner.New()
+	if err := s.Buffer(in); err != nil {
+		return ioErr(err)
+	}
+
+	if !g.Grammar.ScanRule(rule.Name, s, nil) {
+		return failed(s, rule.Name)
+	}
+	if s.Scan() {
+		return parseErr(fmt.Errorf(`%v: unexpected %q after %v`, s.Pos().Short(), s.Rune(), rule.Name))
+	}
+	return nil
+}
@@ -0,0 +1,223 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+package pegn
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/rwxrob/pegn/curs"
+)
+
+// CheckNode calls MarshalJSON on the node and passes the output to
+// CheckNodeJSON. Implementations of Node (or anything else that
+// marshals node trees) should call this from their own tests to
+// certify they produce the mandated JSON (see Node).
+func CheckNode(n json.Marshaler) error {
+	byt, err := n.MarshalJSON()
+	if err != nil {
+		return err
+	}
+	return CheckNodeJSON(byt)
+}
+
+// CheckNodeJSON returns an error describing the first way in which
+// the data does not conform to the JSON mandated for all Node
+// implementations (see Node):
+//
+//   - compact (no insignificant whitespace)
+//   - no escaped HTML characters (<, >, &)
+//   - only the T, V, Tag, Err, and N keys and always in that order
+//   - T is always present and an integer
+//   - V, Tag, Err, and N are omitted when empty
+//   - V and N are never both present in the same node
+//
+// A single JSON string beginning with "error: " also conforms since it
+// is the mandated form for errors.
+func CheckNodeJSON(data []byte) error {
+	buf := new(bytes.Buffer)
+	if err := json.Compact(buf, data); err != nil {
+		return err
+	}
+	if !bytes.Equal(buf.Bytes(), data) {
+		return fmt.Errorf(`node json: not compact`)
+	}
+	for _, esc := range []string{`\u003c`, `\u003e`, `\u0026`} {
+		if bytes.Contains(data, []byte(esc)) {
+			return fmt.Errorf(`node json: must not escape HTML (%v)`, esc)
+		}
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	t, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if s, is := t.(string); is {
+		if !strings.HasPrefix(s, `error: `) {
+			return fmt.Errorf(`node json: string without "error: " prefix`)
+		}
+		return nil
+	}
+	if t != json.Delim('{') {
+		return fmt.Errorf(`node json: must be object or error string`)
+	}
+	return checkNode(dec, `.`)
+}
+
+// checkNode checks the rest of an object after the opening brace.
+func checkNode(dec *json.Decoder, path string) error {
+	fail := func(format string, a ...any) error {
+		return fmt.Errorf(`node json: %v: `+format, append([]any{path}, a...)...)
+	}
+	var last string
+	var value bool
+
+	for dec.More() {
+		t, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		key, _ := t.(string)
+		if t, err = dec.Token(); err != nil {
+			return err
+		}
+
+		switch key {
+
+		case `T`:
+			if last != "" {
+				return fail(`T must be first`)
+			}
+			n, is := t.(json.Number)
+			if _, err := n.Int64(); !is || err != nil {
+				return fail(`T must be an integer`)
+			}
+
+		case `V`:
+			if last != `T` {
+				return fail(`V must follow T`)
+			}
+			v, is := t.(string)
+			if !is {
+				return fail(`V must be a string`)
+			}
+			if len(v) == 0 {
+				return fail(`V must be omitted when empty`)
+			}
+			value = true
+
+		case `Tag`, `Err`:
+			after := map[string]string{`Tag`: `T or V`, `Err`: `T, V, or Tag`}
+			if last == "" || last == key || last == `Err` || last == `N` {
+				return fail(`%v must follow %v`, key, after[key])
+			}
+			v, is := t.(string)
+			if !is {
+				return fail(`%v must be a string`, key)
+			}
+			if len(v) == 0 {
+				return fail(`%v must be omitted when empty`, key)
+			}
+
+		case `N`:
+			switch last {
+			case `V`:
+				return fail(`must not have both V and N`)
+			case `T`, `Tag`, `Err`:
+			default:
+				return fail(`N must follow T`)
+			}
+			if value {
+				return fail(`must not have both V and N`)
+			}
+			if t != json.Delim('[') {
+				return fail(`N must be an array`)
+			}
+			var i int
+			for ; dec.More(); i++ {
+				if t, err = dec.Token(); err != nil {
+					return err
+				}
+				if t != json.Delim('{') {
+					return fail(`N[%v] must be object`, i)
+				}
+				if err := checkNode(dec, fmt.Sprintf(`%vN[%v].`, path, i)); err != nil {
+					return err
+				}
+			}
+			if i == 0 {
+				return fail(`N must be omitted when empty`)
+			}
+			if _, err := dec.Token(); err != nil {
+				return err
+			}
+
+		default:
+			return fail(`unknown key %q`, key)
+		}
+
+		last = key
+	}
+
+	if last == "" {
+		return fail(`missing T`)
+	}
+	_, err := dec.Token()
+	return err
+}
+
+// CheckScanFunc calls the ScanFunc twice from the current position of
+// the Scanner, once with a nil buffer and once with a non-nil one, and
+// returns an error describing the first way in which it breaks the
+// contract mandated for all ScanFuncs (see ScanFunc):
+//
+//   - the position is restored when the scan fails
+//   - at least one error is pushed when the scan fails
+//   - the buffer is appended to (never replaced) when not nil
+//   - something is buffered when anything is scanned
+//   - the result and position do not depend on the buffer
+//
+// Grammar packages should call this from their own tests for every
+// ScanFunc against inputs that both match and do not match since
+// these are the most common mistakes when writing one by hand. The
+// Scanner is left where the second call left it.
+func CheckScanFunc(s Scanner, fn ScanFunc) error {
+	errs := s.Errors()
+	m, n := s.Mark(), len(*errs)
+
+	ok := fn(s, nil)
+	e := s.Mark()
+	if !ok {
+		if !samePos(e, m) {
+			return fmt.Errorf(`scan func: failed without restoring %v (left at %v)`, m, e)
+		}
+		if len(*errs) <= n {
+			return fmt.Errorf(`scan func: failed without pushing an error at %v`, m)
+		}
+	}
+
+	s.Goto(m)
+	*errs = (*errs)[:n]
+	const mark = '\uFFFF'
+	buf := []rune{mark}
+	if fn(s, &buf) != ok {
+		return fmt.Errorf(`scan func: result at %v depends on buffer`, m)
+	}
+	if end := s.Mark(); !samePos(end, e) {
+		return fmt.Errorf(`scan func: end at %v depends on buffer (%v and %v)`, m, e, end)
+	}
+	if len(buf) == 0 || buf[0] != mark {
+		return fmt.Errorf(`scan func: buffer replaced rather than appended at %v`, m)
+	}
+	if ok && e.E > m.E && len(buf) == 1 {
+		return fmt.Errorf(`scan func: scanned %v to %v without buffering`, m, e)
+	}
+	return nil
+}
+
+func samePos(a, b curs.R) bool { return a.R == b.R && a.B == b.B && a.E == b.E }
@@ -0,0 +1,118 @@
+package pegn_test
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/rwxrob/pegn"
+	"github.com/rwxrob/pegn/ast"
+	"github.com/rwxrob/pegn/scanner"
+)
+
+func ExampleCheckNode() {
+
+	n := new(ast.Node)
+	n.Add(1, "<some>")
+	n.Add(2, "").Add(3, "other")
+	fmt.Println(pegn.CheckNode(n))
+
+	n.Add(4, "first").Tag = "key"
+	n.Append(ast.NewError("bad", errors.New(`expecting '='`)))
+	fmt.Println(pegn.CheckNode(n))
+
+	// Output:
+	// <nil>
+	// <nil>
+}
+
+func ExampleCheckNodeJSON() {
+
+	for _, data := range []string{
+		`{"T":1,"N":[{"T":2,"V":"some"}]}`,
+		`"error: something bad"`,
+		`{"T":1, "V":"some"}`,
+		`{"V":"some","T":1}`,
+		`{"T":1,"V":"some","N":[{"T":2}]}`,
+		`{"T":1,"N":[{"T":2,"V":""}]}`,
+		`{"T":1,"N":[]}`,
+		`{"T":1,"V":"\u003c"}`,
+		`{"t":1}`,
+		`{"T":2,"V":"x","Tag":"first"}`,
+		`{"T":3,"Tag":"list","N":[{"T":2,"V":"x"}]}`,
+		`{"T":-1,"V":"bad","Err":"expecting '='"}`,
+		`{"T":2,"Tag":"first","V":"x"}`,
+		`{"T":2,"V":"x","Tag":"first","N":[{"T":2}]}`,
+		`{"T":2,"Err":""}`,
+		`"something bad"`,
+	} {
+		fmt.Println(pegn.CheckNodeJSON([]byte(data)))
+	}
+
+	// Output:
+	// <nil>
+	// <nil>
+	// node json: not compact
+	// node json: .: V must follow T
+	// node json: .: must not have both V and N
+	// node json: .N[0].: V must be omitted when empty
+	// node json: .: N must be omitted when empty
+	// node json: must not escape HTML (\u003c)
+	// node json: .: unknown key "t"
+	// <nil>
+	// <nil>
+	// <nil>
+	// node json: .: V must follow T
+	// node json: .: must not have both V and N
+	// node json: .: Err must be omitted when empty
+	// node json: string without "error: " prefix
+}
+
+func ExampleCheckScanFunc() {
+
+	// a correct ScanFunc for one or more digits
+	digits := func(s pegn.Scanner, buf *[]rune) bool {
+		b := s.Mark()
+		m := b
+		for s.Scan() && s.Rune() >= '0' && s.Rune() <= '9' {
+			m = s.Mark()
+			if buf != nil {
+				*buf = append(*buf, s.Rune())
+			}
+		}
+		s.Goto(m)
+		if m == b {
+			return s.Expected(1)
+		}
+		return true
+	}
+
+	// the most common mistakes
+	norevert := func(s pegn.Scanner, buf *[]rune) bool {
+		s.Scan()
+		return s.Expected(1)
+	}
+	noerror := func(s pegn.Scanner, buf *[]rune) bool {
+		return false
+	}
+	replaced := func(s pegn.Scanner, buf *[]rune) bool {
+		if !s.Scan() {
+			return s.Expected(1)
+		}
+		if buf != nil {
+			*buf = []rune{s.Rune()}
+		}
+		return true
+	}
+
+	for _, fn := range []pegn.ScanFunc{digits, norevert, noerror, replaced} {
+		fmt.Println(pegn.CheckScanFunc(scanner.New(`x`), fn))
+	}
+	fmt.Println(pegn.CheckScanFunc(scanner.New(`123x`), digits))
+
+	// Output:
+	// <nil>
+	// scan func: failed without restoring '\x00' 0-0 (left at 'x' 0-1)
+	// scan func: failed without pushing an error at '\x00' 0-0
+	// scan func: buffer replaced rather than appended at '\x00' 0-0
+	// <nil>
+}
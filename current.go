@@ -0,0 +1,46 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+package pegn
+
+import "sync"
+
+// Grammar packages (such as kegml) commonly provide package-level
+// convenience functions (Parse(input), Check(input)) that create their
+// own Scanner. When a document mixes grammars, however, all of them must
+// share a single Scanner so that the position and error stack are
+// unified and errors from every grammar are reported together. By
+// convention, therefore, grammar packages must use CurrentScanner when
+// it is not nil rather than creating their own. Callers use With to set
+// it for the duration of a parse.
+
+var current Scanner
+var currentmu sync.Mutex
+
+// CurrentScanner returns the Scanner currently shared by all grammar
+// packages or nil if none has been set (see With).
+func CurrentScanner() Scanner {
+	currentmu.Lock()
+	defer currentmu.Unlock()
+	return current
+}
+
+// SetCurrentScanner sets the Scanner to be shared by all grammar
+// packages returning the previous one. Prefer With.
+func SetCurrentScanner(s Scanner) Scanner {
+	currentmu.Lock()
+	defer currentmu.Unlock()
+	prev := current
+	current = s
+	return prev
+}
+
+// With sets the CurrentScanner for the duration of fn restoring the
+// previous one when done (even if fn panics). Calls may be nested.
+// Since the current scanner is shared by the entire program With must
+// not be called concurrently with different scanners.
+func With(s Scanner, fn func()) {
+	prev := SetCurrentScanner(s)
+	defer SetCurrentScanner(prev)
+	fn()
+}
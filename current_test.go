@@ -0,0 +1,43 @@
+package pegn_test
+
+import (
+	"fmt"
+
+	"github.com/rwxrob/pegn"
+	"github.com/rwxrob/pegn/pegng"
+	"github.com/rwxrob/pegn/scanner"
+)
+
+// Check would be the convenience function of some other grammar
+// package (ex: kegml) respecting the shared scanner convention.
+func Check(input string) bool {
+	s := pegn.CurrentScanner()
+	if s == nil {
+		s = scanner.New()
+	}
+	s.Buffer(input)
+	for s.Scan() {
+		if s.Rune() == '!' {
+			return s.Expected(42)
+		}
+	}
+	return true
+}
+
+func ExampleWith() {
+
+	s := scanner.New()
+
+	pegn.With(s, func() {
+		Check(`oops!x`)
+		pegng.Scan_ws(pegn.CurrentScanner(), nil)
+	})
+
+	fmt.Print(s.Error())
+	fmt.Println(pegn.CurrentScanner())
+
+	// Output:
+	// 1:6: expecting type 42 at '!' 4-5
+	// 1:7: expecting type -1 at 'x' 5-6
+	// <nil>
+}
@@ -0,0 +1,41 @@
+package pegn_test
+
+import (
+	"fmt"
+
+	"github.com/rwxrob/pegn"
+	"github.com/rwxrob/pegn/lang"
+	"github.com/rwxrob/pegn/model"
+	"github.com/rwxrob/pegn/rule"
+	"github.com/rwxrob/pegn/scanner"
+)
+
+func ExampleError_registered() {
+
+	rule.Register(model.Rule{
+		ID: 1001, Name: `Field`, PEGN: `Field <-- uprint+`,
+		Desc: model.LangMap{
+			`en`: `one or more printable runes except space`,
+			`de`: `ein oder mehrere druckbare Zeichen außer Leerzeichen`,
+		},
+	})
+	rule.Register(model.Rule{ID: 1002, Name: `Sep`, PEGN: `Sep <- SP+`})
+
+	s := scanner.New("name age\nrob  \t")
+	for i := 0; i < 14; i++ {
+		s.Scan()
+	}
+
+	lang.Lang = `en_US`
+	fmt.Println(pegn.Error{T: 1001, C: s.Mark()})
+	lang.Lang = `de`
+	fmt.Println(pegn.Error{T: 1001, C: s.Mark()})
+	fmt.Println(pegn.Error{T: 1002})
+	fmt.Println(pegn.Error{T: 1003, C: s.Mark()})
+
+	// Output:
+	// expecting Field (one or more printable runes except space) at line 2, column 6
+	// erwartet Field (ein oder mehrere druckbare Zeichen außer Leerzeichen) bei Zeile 2, Spalte 6
+	// erwartet Sep bei '\x00' 0-0
+	// expecting type 1003 at ' ' 13-14
+}
@@ -0,0 +1,86 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+/*
+Package eval evaluates attribute grammars over ast.Node trees so that
+calculators, type checkers, and such can be declared rule by rule (by
+node type) on top of parse output rather than written as hand-made
+tree walks.
+
+Every node has inherited attributes (passed down from the node above
+it) and synthesized attributes (computed from those of the nodes under
+it). Both are declared for each node type with a Rule. Evaluation is
+a single depth-first, left-to-right pass so the attributes inherited
+by a node may depend on those synthesized by the nodes before it
+(L-attributed grammars), which is enough to thread an environment
+through a list of statements.
+*/
+package eval
+
+import (
+	"fmt"
+
+	"github.com/rwxrob/pegn/ast"
+)
+
+// Attrs are attribute values by name.
+type Attrs map[string]any
+
+// Rule declares how the attributes of nodes of a given type are
+// computed. Either function may be nil.
+//
+// Inherit returns the attributes inherited by the node under n at index
+// i given the attributes inherited by n itself and those synthesized by
+// the nodes before i (left). When nil, every node under n inherits
+// exactly what n inherited.
+//
+// Synth returns the attributes synthesized by n given the attributes it
+// inherited and those synthesized by each node under it (in order).
+// When nil, n synthesizes all the attributes of the nodes under it
+// merged in order (later replacing earlier) or nothing for a leaf.
+type Rule struct {
+	Inherit func(n *ast.Node, inh Attrs, i int, left []Attrs) Attrs
+	Synth   func(n *ast.Node, inh Attrs, under []Attrs) (Attrs, error)
+}
+
+// Grammar is the set of Rules by node type (ast.Node.T).
+type Grammar map[int]Rule
+
+// Eval evaluates every node of the tree beginning with the attributes
+// inherited by its root and returns the attributes synthesized by the
+// root. The first error returned by a Synth stops evaluation and is
+// returned with the type of the node that failed.
+func (g Grammar) Eval(n *ast.Node, inh Attrs) (Attrs, error) {
+	r := g[n.T]
+	nodes := n.Nodes()
+	under := make([]Attrs, 0, len(nodes))
+	for i, c := range nodes {
+		cinh := inh
+		if r.Inherit != nil {
+			cinh = r.Inherit(n, inh, i, under)
+		}
+		a, err := g.Eval(c, cinh)
+		if err != nil {
+			return nil, err
+		}
+		under = append(under, a)
+	}
+
+	if r.Synth != nil {
+		a, err := r.Synth(n, inh, under)
+		if err != nil {
+			return nil, fmt.Errorf(`eval: node type %v: %w`, n.T, err)
+		}
+		return a, nil
+	}
+	if len(under) == 0 {
+		return nil, nil
+	}
+	merged := Attrs{}
+	for _, a := range under {
+		for k, v := range a {
+			merged[k] = v
+		}
+	}
+	return merged, nil
+}
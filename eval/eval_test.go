@@ -0,0 +1,96 @@
+package eval_test
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/rwxrob/pegn/ast"
+	"github.com/rwxrob/pegn/eval"
+)
+
+const (
+	Program = iota + 1
+	Let
+	Print
+	Sum
+	Name
+	Num
+	Target
+)
+
+func Example() {
+
+	// let x = 1 + 2
+	// print x + 40
+	// print y
+	tree, _ := ast.ReadArray([]byte(`[1,[
+	  [2,[[7,"x"],[4,[[6,"1"],[6,"2"]]]]],
+	  [3,[[4,[[5,"x"],[6,"40"]]]]],
+	  [3,[[5,"y"]]]
+	]]`), nil)
+
+	g := eval.Grammar{
+
+		// each statement inherits the environment left by the last
+		Program: {
+			Inherit: func(n *ast.Node, inh eval.Attrs, i int, left []eval.Attrs) eval.Attrs {
+				if i == 0 {
+					return eval.Attrs{`env`: map[string]int{}}
+				}
+				return left[i-1]
+			},
+		},
+
+		Let: {
+			Synth: func(n *ast.Node, inh eval.Attrs, under []eval.Attrs) (eval.Attrs, error) {
+				env := map[string]int{}
+				for k, v := range inh[`env`].(map[string]int) {
+					env[k] = v
+				}
+				env[n.Nodes()[0].V] = under[1][`value`].(int)
+				return eval.Attrs{`env`: env}, nil
+			},
+		},
+
+		Print: {
+			Synth: func(n *ast.Node, inh eval.Attrs, under []eval.Attrs) (eval.Attrs, error) {
+				fmt.Println(under[0][`value`])
+				return inh, nil
+			},
+		},
+
+		Sum: {
+			Synth: func(n *ast.Node, inh eval.Attrs, under []eval.Attrs) (eval.Attrs, error) {
+				var sum int
+				for _, a := range under {
+					sum += a[`value`].(int)
+				}
+				return eval.Attrs{`value`: sum}, nil
+			},
+		},
+
+		Name: {
+			Synth: func(n *ast.Node, inh eval.Attrs, under []eval.Attrs) (eval.Attrs, error) {
+				v, has := inh[`env`].(map[string]int)[n.V]
+				if !has {
+					return nil, fmt.Errorf(`undefined: %v`, n.V)
+				}
+				return eval.Attrs{`value`: v}, nil
+			},
+		},
+
+		Num: {
+			Synth: func(n *ast.Node, inh eval.Attrs, under []eval.Attrs) (eval.Attrs, error) {
+				v, err := strconv.Atoi(n.V)
+				return eval.Attrs{`value`: v}, err
+			},
+		},
+	}
+
+	_, err := g.Eval(tree, nil)
+	fmt.Println(err)
+
+	// Output:
+	// 43
+	// eval: node type 5: undefined: y
+}
@@ -0,0 +1,80 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+package gr
+
+import (
+	"github.com/rwxrob/pegn"
+	"github.com/rwxrob/pegn/curs"
+)
+
+// Action is called with the scanner and a cursor to what was matched
+// (from B to E, same as Mark) once for every match of the rule it is
+// attached to (see OnMatch). Returning an error fails the scan or parse
+// with the error pushed to the scanner (instead of the usual
+// pegn.Error) and no other action called so that actions can reject
+// what they cannot evaluate (ex: a number too large).
+type Action func(s pegn.Scanner, m curs.R) error
+
+// OnMatch attaches the action to the named rule (replacing any
+// attached before) or removes it if nil. Actions are called by Scan,
+// ScanRule, Parse, ParseRule, ParseEach, and Events (never by Explain
+// or any of the analysis methods) so that values can be evaluated (ex:
+// calculators, configuration loaders) without creating a node tree at
+// all. Since only what is part of the final match counts, actions are
+// called once the outermost rule has matched (with the scanner at its
+// end) in the order rules completed (inner before outer). Rules matched
+// while trying alternatives that are later abandoned (backtracking) or
+// while growing a left recursive rule (see ScanRule) never have their
+// actions called more than once, nor do those within lookahead (&, !),
+// tokens, and classes.
+func (g *Grammar) OnMatch(name string, fn Action) {
+	if fn == nil {
+		delete(g.Actions, name)
+		return
+	}
+	if g.Actions == nil {
+		g.Actions = map[string]Action{}
+	}
+	g.Actions[name] = fn
+}
+
+// pending is an action to be called with the cursor to what its rule
+// matched once the outermost rule has matched (see match).
+type pending struct {
+	act Action
+	c   curs.R
+}
+
+// match matches the rule as the first (outermost) and then calls the
+// actions pending in order but fails anyway (back where it began) if
+// any returned an error (or a limiter did) since that does not always
+// fail every rule outside of it.
+func (m *machine) match(r *Rule) bool {
+	st := m.save()
+	if m.rule(r) && m.acterr == nil && m.call() {
+		return true
+	}
+	m.restore(st)
+	return false
+}
+
+// call calls every action pending until one returns an error.
+func (m *machine) call() bool {
+	for _, p := range m.pending {
+		if err := p.act(m.s, p.c); err != nil {
+			m.acterr = err
+			return false
+		}
+	}
+	return true
+}
+
+// action returns the action to call if the rule matches or nil if the
+// machine does not call actions or is quiet.
+func (m *machine) action(r *Rule) Action {
+	if !m.acts || m.quiet > 0 {
+		return nil
+	}
+	return m.g.Actions[r.Name]
+}
@@ -0,0 +1,65 @@
+package gr_test
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/rwxrob/pegn"
+	"github.com/rwxrob/pegn/curs"
+	"github.com/rwxrob/pegn/gr"
+	"github.com/rwxrob/pegn/scanner"
+)
+
+func ExampleGrammar_OnMatch() {
+
+	g := gr.MustCompile(`
+Sum <- Num ('+' Num)*
+Num <- digit+`)
+
+	var total int
+	g.OnMatch(`Num`, func(s pegn.Scanner, m curs.R) error {
+		n, err := strconv.ParseInt(string((*m.Buf)[m.B:m.E]), 10, 8)
+		total += int(n)
+		return err
+	})
+
+	s := scanner.New("1+20+3")
+	fmt.Println(g.Scan(s, nil), total)
+
+	total = 0
+	s = scanner.New("1+2000+3")
+	fmt.Println(g.Scan(s, nil), total, s.RuneE())
+	fmt.Println((*s.Errors())[0])
+
+	// Output:
+	// true 24
+	// false 128 0
+	// strconv.ParseInt: parsing "2000": value out of range
+}
+
+func ExampleGrammar_OnMatch_leftRecursive() {
+
+	g := gr.MustRead(`
+Expr <- Expr '+' Num / Num
+Num  <- digit+`)
+
+	for _, name := range []string{`Expr`, `Num`} {
+		name := name
+		g.OnMatch(name, func(s pegn.Scanner, m curs.R) error {
+			fmt.Println(name, m.B, m.E)
+			return nil
+		})
+	}
+
+	s := scanner.New("1+2+3")
+	fmt.Println(g.Scan(s, nil))
+
+	// Output:
+	// Num 0 1
+	// Expr 0 1
+	// Num 2 3
+	// Expr 0 3
+	// Num 4 5
+	// Expr 0 5
+	// true
+}
@@ -0,0 +1,202 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+package gr
+
+import (
+	"fmt"
+
+	"github.com/rwxrob/pegn/scanner"
+)
+
+// Ambiguity is a choice within a rule for which swapping two of its
+// alternatives changes the result for a given input.
+type Ambiguity struct {
+	Rule   string // name of rule containing the choice
+	First  string // alternative tried first (PEGN)
+	Second string // alternative immediately after First (PEGN)
+	Input  string // first sample input with a different result
+}
+
+// String fulfills the fmt.Stringer interface.
+func (a Ambiguity) String() string {
+	return fmt.Sprintf(`%v: swapping %v and %v changes result for %q`,
+		a.Rule, a.First, a.Second, a.Input)
+}
+
+// Ambiguities matches the named rule (or the first if empty) against
+// every sample input again and again, each time with two adjacent
+// alternatives of one of the choices of the grammar swapped, and
+// returns every swap that changes the result (success, amount of input
+// matched, or the node rules matched and where). This flags grammars
+// that accidentally depend on the order of alternatives that the
+// author intended to be interchangeable. Choices with any alternative
+// that can match nothing (x?, x*, !x) are skipped since reordering them
+// is never safe and such choices are obviously intentionally ordered.
+func (g *Grammar) Ambiguities(rule string, inputs ...string) ([]Ambiguity, error) {
+	if rule == "" && len(g.Rules) > 0 {
+		rule = g.Rules[0].Name
+	}
+	if g.Lookup(rule) == nil {
+		return nil, fmt.Errorf(`rule not found: %q`, rule)
+	}
+
+	want := make([]string, len(inputs))
+	for i, in := range inputs {
+		want[i] = g.result(rule, in)
+	}
+
+	var found []Ambiguity
+	for ri, r := range g.Rules {
+		var choices []Choice
+		Walk(r.Expr, func(e Expr) bool {
+			if c, is := e.(Choice); is {
+				choices = append(choices, c)
+			}
+			return true
+		})
+
+		for ci, c := range choices {
+			if !g.safeChoice(c) {
+				continue
+			}
+			for ai := 0; ai < len(c)-1; ai++ {
+				v := *g
+				v.Rules = append([]*Rule{}, g.Rules...)
+				vr := *r
+				vr.Expr = swapAlt(r.Expr, ci, ai)
+				v.Rules[ri] = &vr
+				for i, in := range inputs {
+					if v.result(rule, in) != want[i] {
+						found = append(found, Ambiguity{
+							Rule:   r.Name,
+							First:  c[ai].String(),
+							Second: c[ai+1].String(),
+							Input:  in,
+						})
+						break
+					}
+				}
+			}
+		}
+	}
+
+	return found, nil
+}
+
+// result returns a summary of everything about matching the rule
+// against the input that could be changed by the order of choices.
+func (g *Grammar) result(rule, input string) string {
+	s := scanner.New(input)
+	m := newMachine(g, s, nil)
+	m.record = true
+	ok := m.rule(g.Lookup(rule))
+	out := fmt.Sprint(ok, s.RuneE())
+	for _, i := range m.spans {
+		out += fmt.Sprintf(` %v:%v-%v:%v`, i.rule.Name, i.b, i.e, i.depth)
+	}
+	return out
+}
+
+// safeChoice returns false if any alternative can match nothing.
+func (g *Grammar) safeChoice(c Choice) bool {
+	for _, e := range c {
+		if g.nullable(e, map[string]bool{}) {
+			return false
+		}
+	}
+	return true
+}
+
+// nullable returns true if the expression can succeed without
+// consuming anything.
+func (g *Grammar) nullable(e Expr, seen map[string]bool) bool {
+	switch v := e.(type) {
+	case Seq:
+		for _, x := range v {
+			if !g.nullable(x, seen) {
+				return false
+			}
+		}
+		return true
+	case Choice:
+		for _, x := range v {
+			if g.nullable(x, seen) {
+				return true
+			}
+		}
+		return false
+	case Quant:
+		return v.Min == 0 || g.nullable(v.E, seen)
+	case Look:
+		return true
+	case Capture:
+		return g.nullable(v.E, seen)
+	case Replace:
+		return g.nullable(v.E, seen)
+	case Inject:
+		return true
+	case Ref:
+		r := g.Lookup(string(v))
+		if r == nil || seen[r.Name] {
+			return false
+		}
+		seen[r.Name] = true
+		defer delete(seen, r.Name)
+		return g.nullable(r.Expr, seen)
+	}
+	return false
+}
+
+// swapAlt returns a copy of the expression with alternatives i and i+1
+// swapped within the choice at the given index (counting choices in
+// the same order as Walk).
+func swapAlt(e Expr, choice, i int) Expr {
+	return withChoice(e, choice, func(c Choice) Choice {
+		c[i], c[i+1] = c[i+1], c[i]
+		return c
+	})
+}
+
+// withChoice returns a copy of the expression with the choice at the
+// given index (counting choices in the same order as Walk) replaced by
+// whatever fn returns when passed a copy of it.
+func withChoice(e Expr, choice int, fn func(c Choice) Choice) Expr {
+	var n int
+	var walk func(e Expr) Expr
+	walk = func(e Expr) Expr {
+		switch v := e.(type) {
+		case Choice:
+			k := n
+			n++
+			c := make(Choice, len(v))
+			for j, x := range v {
+				c[j] = walk(x)
+			}
+			if k == choice {
+				c = fn(c)
+			}
+			return c
+		case Seq:
+			s := make(Seq, len(v))
+			for j, x := range v {
+				s[j] = walk(x)
+			}
+			return s
+		case Look:
+			v.E = walk(v.E)
+			return v
+		case Quant:
+			v.E = walk(v.E)
+			return v
+		case Capture:
+			v.E = walk(v.E)
+			return v
+		case Replace:
+			v.E = walk(v.E)
+			return v
+		}
+		return e
+	}
+	return walk(e)
+}
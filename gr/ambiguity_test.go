@@ -0,0 +1,28 @@
+package gr_test
+
+import (
+	"fmt"
+
+	"github.com/rwxrob/pegn/gr"
+)
+
+func ExampleGrammar_Ambiguities() {
+
+	g := gr.MustRead(`
+Decl    <-- Type SP Name (SP Value)?
+Type    <-- 'in' / 'int' / 'str'
+Name    <-- lower+
+Value   <-- Float / Int / Word
+Float   <-- digit+ '.' digit+
+Int     <-- digit+
+Word    <-- alpha+ / digit`)
+
+	list, _ := g.Ambiguities(``, `str x 1.5`, `int y 42`, `str z`)
+	for _, a := range list {
+		fmt.Println(a)
+	}
+
+	// Output:
+	// Type: swapping 'in' and 'int' changes result for "int y 42"
+	// Value: swapping Float and Int changes result for "str x 1.5"
+}
@@ -0,0 +1,385 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+package gr
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/rwxrob/pegn/model"
+)
+
+// ANTLR returns the grammar as an ANTLR4 combined grammar with the
+// given name (which ANTLR requires to match the file name). If name is
+// empty the Name of the Grammar is used (or PEGN if also empty).
+// Rules (Mixed) become parser rules with a lowercase first letter.
+// Tokens (CAPS) become lexer rules of the same name and classes (lower)
+// become lexer rules with the C_ prefix (ex: C_digit). Any builtin
+// token or class referenced is included as well. The header includes
+// the BuildInfo stamp line.
+//
+// # Caveats
+//
+// PEGN (like all PEG) and ANTLR do not share the same semantics so the
+// output must be considered a starting point to be verified with ANTLR
+// tooling rather than an equivalent grammar. Any construct that cannot
+// be converted is noted in a comment before the rule with the line
+// number of the original PEGN definition.
+//
+//   - Ordered choice (/) becomes unordered alternation (|). PEG commits
+//     to the first alternative that matches while ANTLR predicts the
+//     alternative using as much of the remaining input as needed. When
+//     an earlier alternative is a prefix of a later one the results
+//     will differ (PEG never tries the later one).
+//
+//   - PEGN is scannerless but ANTLR tokenizes all input with the lexer
+//     rules (longest match, then first defined) before any parser rule
+//     is tried. Overlapping tokens and classes (ex: C_upper and
+//     C_alpha) therefore compete with one another and may need to be
+//     refactored into fragments by hand.
+//
+//   - Lookahead (& and !) has no ANTLR4 equivalent and is dropped. The
+//     very common negated set pattern (!ws rune) is the exception and
+//     becomes a negated set (~[ \t\n\r]).
+//
+//   - Ranges, code points, and any (.) are not allowed in parser rules
+//     so they are moved into generated lexer rules (R_1, R_2, ...).
+//
+//   - Lexer rules cannot refer to parser rules at all.
+func (g *Grammar) ANTLR(name string) string {
+	if name == "" {
+		name = g.Name
+	}
+	if name == "" {
+		name = `PEGN`
+	}
+	a := &antlr{g: g, hoisted: map[string]string{}}
+
+	var parser, lexer []*Rule
+	for _, r := range g.Rules {
+		if r.Type == model.RuleType {
+			parser = append(parser, r)
+			continue
+		}
+		lexer = append(lexer, r)
+	}
+	lexer = append(lexer, a.builtins()...)
+
+	var out strings.Builder
+	out.WriteString("// Generated from PEGN. See gr.Grammar.ANTLR for caveats.\n")
+	out.WriteString(`// ` + g.BuildInfo().String() + "\n\n")
+	out.WriteString(`grammar ` + name + ";\n")
+	for _, r := range parser {
+		a.rule(&out, r, false)
+	}
+	for _, r := range lexer {
+		a.rule(&out, r, true)
+	}
+	for i, v := range a.hoist {
+		fmt.Fprintf(&out, "\nR_%v : %v ;\n", i+1, v)
+	}
+	return out.String()
+}
+
+type antlr struct {
+	g       *Grammar
+	lexer   bool              // currently converting a lexer rule
+	notes   []string          // notes for the current rule
+	hoist   []string          // lexer expressions moved out of parser rules
+	hoisted map[string]string // name of each hoisted expression
+}
+
+// builtins returns every builtin rule referenced (directly or
+// indirectly) and not defined by the grammar itself.
+func (a *antlr) builtins() []*Rule {
+	var list []*Rule
+	seen := map[string]bool{}
+	var visit func(e Expr)
+	visit = func(e Expr) {
+		for _, n := range Refs(e) {
+			if seen[n] || a.g.Rule(n) != nil {
+				continue
+			}
+			seen[n] = true
+			if r := Builtins.Rule(n); r != nil {
+				list = append(list, r)
+				visit(r.Expr)
+			}
+		}
+	}
+	for _, r := range a.g.Rules {
+		visit(r.Expr)
+	}
+	return list
+}
+
+var antlrReserved = map[string]bool{
+	`catch`: true, `channels`: true, `finally`: true, `fragment`: true,
+	`grammar`: true, `import`: true, `lexer`: true, `locals`: true,
+	`mode`: true, `options`: true, `parser`: true, `returns`: true,
+	`throws`: true, `tokens`: true,
+}
+
+// name returns the ANTLR name for the PEGN name.
+func (a *antlr) name(n string) string {
+	switch model.TypeOf(n) {
+	case model.ClassType:
+		return `C_` + n
+	case model.TokenType:
+		if n == `EOF` {
+			return `EOF_`
+		}
+		return n
+	}
+	r := []rune(n)
+	r[0] = unicode.ToLower(r[0])
+	n = string(r)
+	if antlrReserved[n] {
+		n += `_`
+	}
+	return n
+}
+
+func (a *antlr) note(format string, args ...any) {
+	a.notes = append(a.notes, fmt.Sprintf(format, args...))
+}
+
+func (a *antlr) rule(out *strings.Builder, r *Rule, lexer bool) {
+	a.lexer = lexer
+	a.notes = nil
+
+	var alts []string
+	if c, is := r.Expr.(Choice); is {
+		for _, e := range c {
+			alts = append(alts, a.expr(e, precSeq))
+		}
+	} else {
+		alts = []string{a.expr(r.Expr, precChoice)}
+	}
+
+	out.WriteString("\n")
+	for _, d := range r.Doc {
+		if strings.HasPrefix(d, `#`) && a.g.Rule(r.Name) == r {
+			out.WriteString(`//` + strings.TrimPrefix(d, `#`) + "\n")
+		}
+	}
+	for _, n := range a.notes {
+		if r.Line > 0 {
+			fmt.Fprintf(out, "// PEGN line %v: %v\n", r.Line, n)
+			continue
+		}
+		fmt.Fprintf(out, "// PEGN %v: %v\n", r.Name, n)
+	}
+	if len(alts) == 1 {
+		fmt.Fprintf(out, "%v : %v ;\n", a.name(r.Name), alts[0])
+		return
+	}
+	fmt.Fprintf(out, "%v\n  : %v\n  ;\n",
+		a.name(r.Name), strings.Join(alts, "\n  | "))
+}
+
+// expr returns the ANTLR form of the expression wrapping it in
+// parenthesis if the precedence is lower than min.
+func (a *antlr) expr(e Expr, min int) string {
+	var s string
+
+	switch v := e.(type) {
+
+	case Choice:
+		list := make([]string, len(v))
+		for i, x := range v {
+			list[i] = a.expr(x, precSeq)
+		}
+		s = strings.Join(list, ` | `)
+
+	case Seq:
+		var list []string
+		for i := 0; i < len(v); i++ {
+			if l, is := v[i].(Look); is && l.Not && i+1 < len(v) && a.isAny(v[i+1]) {
+				if set, is := a.set(l.E, 0); is {
+					list = append(list, a.lexset(`~[`+set+`]`))
+					i++
+					continue
+				}
+			}
+			if x := a.expr(v[i], precPrefix); x != "" {
+				list = append(list, x)
+			}
+		}
+		if len(list) == 1 {
+			return list[0]
+		}
+		s = strings.Join(list, ` `)
+
+	case Look:
+		a.note(`lookahead dropped: %v`, v)
+		return ""
+
+	case Quant:
+		x := a.expr(v.E, precPrimary)
+		switch {
+		case v.Min == 0 && v.Max == 1:
+			return x + `?`
+		case v.Min == 0 && v.Max < 0:
+			return x + `*`
+		case v.Min == 1 && v.Max < 0:
+			return x + `+`
+		}
+		list := make([]string, v.Min)
+		for i := range list {
+			list[i] = x
+		}
+		if v.Max < 0 {
+			list = append(list, x+`*`)
+		}
+		for i := v.Min; i < v.Max; i++ {
+			list = append(list, x+`?`)
+		}
+		s = strings.Join(list, ` `)
+
+	case Ref:
+		n := string(v)
+		if a.g.Lookup(n) == nil {
+			a.note(`undefined: %v`, n)
+		}
+		if a.lexer && model.TypeOf(n) == model.RuleType {
+			a.note(`lexer rule cannot refer to parser rule: %v`, n)
+		}
+		return a.name(n)
+
+	case Lit:
+		return antlrLit(string(v))
+
+	case Point:
+		return a.lexset(`[` + antlrSetRune(v.R) + `]`)
+
+	case Range:
+		return a.lexset(`[` + antlrSetRune(v.Lo) + `-` + antlrSetRune(v.Hi) + `]`)
+
+	case Any:
+		return a.lexset(`.`)
+
+	case Capture:
+		return a.expr(v.E, min)
+
+	case Replace:
+		a.note(`replacement dropped: %v`, v)
+		return a.expr(v.E, min)
+
+	case Inject:
+		a.note(`injection dropped: %v`, v)
+		return ""
+	}
+
+	if e.prec() < min {
+		return `(` + s + `)`
+	}
+	return s
+}
+
+// lexset returns the set unchanged when converting a lexer rule but
+// hoists it into its own lexer rule otherwise returning that name.
+func (a *antlr) lexset(set string) string {
+	if a.lexer {
+		return set
+	}
+	if n, has := a.hoisted[set]; has {
+		return n
+	}
+	a.hoist = append(a.hoist, set)
+	n := fmt.Sprintf(`R_%v`, len(a.hoist))
+	a.hoisted[set] = n
+	return n
+}
+
+// isAny returns true if the expression matches any single rune.
+func (a *antlr) isAny(e Expr) bool {
+	switch v := e.(type) {
+	case Any:
+		return true
+	case Range:
+		return v.Lo == 0 && v.Hi >= unicode.MaxRune
+	case Ref:
+		if r := a.g.Lookup(string(v)); r != nil {
+			return a.isAny(r.Expr)
+		}
+	}
+	return false
+}
+
+// set returns the inside of an ANTLR set ([...]) if the expression can
+// be expressed as one.
+func (a *antlr) set(e Expr, depth int) (string, bool) {
+	if depth > 16 {
+		return "", false
+	}
+	switch v := e.(type) {
+	case Point:
+		return antlrSetRune(v.R), true
+	case Range:
+		return antlrSetRune(v.Lo) + `-` + antlrSetRune(v.Hi), true
+	case Lit:
+		if r := []rune(string(v)); len(r) == 1 {
+			return antlrSetRune(r[0]), true
+		}
+	case Choice:
+		var s string
+		for _, x := range v {
+			i, is := a.set(x, depth+1)
+			if !is {
+				return "", false
+			}
+			s += i
+		}
+		return s, true
+	case Ref:
+		if r := a.g.Lookup(string(v)); r != nil {
+			return a.set(r.Expr, depth+1)
+		}
+	}
+	return "", false
+}
+
+func antlrEscape(r rune) string {
+	switch r {
+	case '\n':
+		return `\n`
+	case '\r':
+		return `\r`
+	case '\t':
+		return `\t`
+	case '\\':
+		return `\\`
+	}
+	if r < 0x20 || r > 0x7E {
+		if r > 0xFFFF {
+			return fmt.Sprintf(`\u{%X}`, r)
+		}
+		return fmt.Sprintf(`\u%04X`, r)
+	}
+	return string(r)
+}
+
+func antlrSetRune(r rune) string {
+	switch r {
+	case ']', '-':
+		return `\` + string(r)
+	}
+	return antlrEscape(r)
+}
+
+func antlrLit(a string) string {
+	var s strings.Builder
+	s.WriteString(`'`)
+	for _, r := range a {
+		if r == '\'' {
+			s.WriteString(`\'`)
+			continue
+		}
+		s.WriteString(antlrEscape(r))
+	}
+	s.WriteString(`'`)
+	return s.String()
+}
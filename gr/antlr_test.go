@@ -0,0 +1,57 @@
+package gr_test
+
+import (
+	"fmt"
+
+	"github.com/rwxrob/pegn/gr"
+)
+
+func ExampleGrammar_ANTLR() {
+
+	g := gr.MustRead(`
+# list of words
+List  <-- Word (COMMA SP? Word)*
+Word  <-- (!(ws / COMMA) .)+ / Quoted
+Quoted <- '"' [x20-x21]+ '"' &SP
+`)
+
+	fmt.Print(g.ANTLR(`List`))
+
+	// Output:
+	// // Generated from PEGN. See gr.Grammar.ANTLR for caveats.
+	// // pegn:build version=(devel) spec=2023-01 grammar=sha256:d7eaebd2fb3c21b44991ca85b60615fd53118a6064a6be8a4e378fd7816f0330
+	//
+	// grammar List;
+	//
+	// // list of words
+	// list : word (COMMA SP? word)* ;
+	//
+	// word
+	//   : R_1+
+	//   | quoted
+	//   ;
+	//
+	// // PEGN line 5: lookahead dropped: &SP
+	// quoted : '"' R_2+ '"' ;
+	//
+	// COMMA : [,] ;
+	//
+	// SP : [ ] ;
+	//
+	// C_ws
+	//   : SP
+	//   | TAB
+	//   | LF
+	//   | CR
+	//   ;
+	//
+	// TAB : [\t] ;
+	//
+	// LF : [\n] ;
+	//
+	// CR : [\r] ;
+	//
+	// R_1 : ~[ \t\n\r,] ;
+	//
+	// R_2 : [ -!] ;
+}
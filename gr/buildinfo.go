@@ -0,0 +1,114 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+package gr
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"strings"
+
+	"github.com/rwxrob/pegn"
+	"github.com/rwxrob/pegn/ast"
+)
+
+// BuildInfo identifies exactly what produced a generated artifact
+// (code, ANTLR, compiled tables) from a grammar so that mismatches
+// between the generator and the runtime can be detected when the
+// artifact is loaded (see Check). It is written into every generated
+// header as a single stamp line (see String).
+type BuildInfo struct {
+	Version string // pegn module version (see pegn.Version)
+	Spec    string // PEGN specification version (see pegn.Spec)
+	Hash    string // hash of the grammar (see Grammar.Hash)
+}
+
+// BuildInfoPrefix begins every stamp line (following any comment
+// prefix required by the generated language).
+const BuildInfoPrefix = `pegn:build `
+
+// Hash returns the SHA-256 of the canonical PEGN of the grammar (see
+// String) in the form sha256:<hex>. Since the canonical form is used,
+// changes to comments and alignment also change the hash.
+func (g *Grammar) Hash() string {
+	return fmt.Sprintf(`sha256:%x`, sha256.Sum256([]byte(g.String())))
+}
+
+// Origin returns the interned ast.Origin to set on nodes produced by
+// the grammar (see ast.Node.SetOrigin). Grammars have no version of
+// their own so the beginning of the Hash is used instead, which changes
+// whenever the grammar does.
+func (g *Grammar) Origin() *ast.Origin {
+	return ast.Intern(g.Name, g.Hash()[:19])
+}
+
+// BuildInfo returns the BuildInfo for the grammar as compiled into the
+// currently running binary.
+func (g *Grammar) BuildInfo() BuildInfo {
+	return BuildInfo{Version: pegn.Version(), Spec: pegn.Spec, Hash: g.Hash()}
+}
+
+// String returns the single stamp line for use in generated headers:
+//
+//	pegn:build version=v0.3.0 spec=2023-01 grammar=sha256:...
+func (b BuildInfo) String() string {
+	return fmt.Sprintf(`%vversion=%v spec=%v grammar=%v`,
+		BuildInfoPrefix, b.Version, b.Spec, b.Hash)
+}
+
+// ParseBuildInfo returns the BuildInfo from the first stamp line found
+// anywhere within text (usually the header of a generated file). An
+// error is returned if there is none or it cannot be parsed.
+func ParseBuildInfo(text string) (BuildInfo, error) {
+	var b BuildInfo
+	i := strings.Index(text, BuildInfoPrefix)
+	if i < 0 {
+		return b, fmt.Errorf(`no build info found`)
+	}
+	line := text[i+len(BuildInfoPrefix):]
+	if n := strings.IndexByte(line, '\n'); n >= 0 {
+		line = line[:n]
+	}
+	for _, f := range strings.Fields(line) {
+		k, v, found := strings.Cut(f, `=`)
+		if !found {
+			return b, fmt.Errorf(`invalid build info field: %q`, f)
+		}
+		switch k {
+		case `version`:
+			b.Version = v
+		case `spec`:
+			b.Spec = v
+		case `grammar`:
+			b.Hash = v
+		}
+	}
+	if b.Spec == "" || b.Hash == "" {
+		return b, fmt.Errorf(`incomplete build info: %q`, line)
+	}
+	return b, nil
+}
+
+// Check returns an error describing the first difference between the
+// BuildInfo (usually from ParseBuildInfo) and that of the grammar in
+// the current binary (see Grammar.BuildInfo). Versions are only
+// compared when both are known (not "(devel)").
+func (b BuildInfo) Check(g *Grammar) error {
+	cur := g.BuildInfo()
+	switch {
+	case b.Spec != cur.Spec:
+		return fmt.Errorf(`generated for PEGN spec %v but runtime implements %v`,
+			b.Spec, cur.Spec)
+	case b.Hash != cur.Hash:
+		return fmt.Errorf(`generated from different grammar (%v) than current (%v)`,
+			b.Hash, cur.Hash)
+	case known(b.Version) && known(cur.Version) && b.Version != cur.Version:
+		return fmt.Errorf(`generated with pegn %v but runtime is %v`,
+			b.Version, cur.Version)
+	}
+	return nil
+}
+
+func known(version string) bool {
+	return version != "" && version != `(devel)`
+}
@@ -0,0 +1,46 @@
+package gr_test
+
+import (
+	"fmt"
+
+	"github.com/rwxrob/pegn/gr"
+)
+
+func ExampleGrammar_BuildInfo() {
+
+	g := gr.MustRead(`Greeting <-- 'hello' / 'hi'`)
+
+	stamp := g.BuildInfo().String()
+	fmt.Println(stamp)
+
+	b, err := gr.ParseBuildInfo("// Generated.\n// " + stamp + "\n")
+	fmt.Println(err)
+	fmt.Println(b.Check(g))
+
+	b.Spec = `2022-01`
+	fmt.Println(b.Check(g))
+
+	g = gr.MustRead(`Greeting <-- 'hello' / 'hi' / 'hey'`)
+	b.Spec = `2023-01`
+	fmt.Println(b.Check(g))
+
+	_, err = gr.ParseBuildInfo(`nothing here`)
+	fmt.Println(err)
+
+	// Output:
+	// pegn:build version=(devel) spec=2023-01 grammar=sha256:e8032da0d2e84f540415c1f356efef86df635ff26855305b3d491aa71e22bb64
+	// <nil>
+	// <nil>
+	// generated for PEGN spec 2022-01 but runtime implements 2023-01
+	// generated from different grammar (sha256:e8032da0d2e84f540415c1f356efef86df635ff26855305b3d491aa71e22bb64) than current (sha256:493fa5f75064416e9f9e064a47b6e5d32107e7757ed7b85cf46ead112b425200)
+	// no build info found
+}
+
+func ExampleGrammar_Origin() {
+	g := gr.MustRead("# GREET example.com/greet\n\nGreeting <-- 'hello' / 'hi'")
+	fmt.Println(g.Origin())
+	fmt.Println(g.Origin() == g.Origin())
+	// Output:
+	// GREET@sha256:e780b04a65ab
+	// true
+}
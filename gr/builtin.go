@@ -0,0 +1,34 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+package gr
+
+import "github.com/rwxrob/pegn/model"
+
+// Builtins contains the tokens and classes from the PEGN specification
+// (see model.Tokens and model.Classes) that any grammar may reference
+// without defining them itself.
+var Builtins = MustRead(model.Tokens + "\n" + model.Classes)
+
+// builtin contains every rule of Builtins (which have IDs of their own
+// that overlap those of any grammar).
+var builtin = func() map[*Rule]bool {
+	set := map[*Rule]bool{}
+	for _, r := range Builtins.Rules {
+		set[r] = true
+	}
+	return set
+}()
+
+// Lookup returns the rule with the given name from the grammar itself
+// or from Builtins if not defined by the grammar. Returns nil if
+// neither has it.
+func (g *Grammar) Lookup(name string) *Rule {
+	if r := g.Rule(name); r != nil {
+		return r
+	}
+	if g != Builtins {
+		return Builtins.Rule(name)
+	}
+	return nil
+}
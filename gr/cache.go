@@ -0,0 +1,397 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+package gr
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/gob"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Cache holds compiled grammars keyed by the SHA-256 hash of their PEGN
+// source so that compiling the same text again (as services receiving
+// grammars with every request do) returns the grammar already compiled.
+// The zero value is ready to use and safe for concurrent use.
+//
+// Grammars returned from a Cache are shared and must not be changed
+// (including Delegates). Use Select or Lenient (which return new
+// grammars) or Compile directly when changes are needed.
+type Cache struct {
+	Max int    // most grammars kept (oldest dropped first, 0 for no limit)
+	Dir string // optional directory where compiled grammars are also stored
+
+	mu     sync.Mutex
+	m      map[[sha256.Size]byte]*Grammar
+	order  [][sha256.Size]byte
+	hits   int
+	misses int
+}
+
+// Compile returns the grammar compiled from the source (see Compile)
+// from the cache if it has been compiled before. Errors are never
+// cached.
+//
+// If Dir is set every grammar compiled is also stored there (in a file
+// named after the hash) and those already stored are loaded from there
+// rather than compiled again so that other processes (and the same one
+// after a restart) sharing the directory never read (see Read) or check
+// (see Check) the same grammar twice. Problems with the directory
+// (including files stored by other versions) are ignored since it is
+// only ever an optimization.
+func (c *Cache) Compile(src string) (*Grammar, error) {
+	key := sha256.Sum256([]byte(src))
+
+	c.mu.Lock()
+	if g, has := c.m[key]; has {
+		c.hits++
+		c.mu.Unlock()
+		return g, nil
+	}
+	c.misses++
+	c.mu.Unlock()
+
+	g := c.load(key)
+	if g == nil {
+		var err error
+		if g, err = Compile(src); err != nil {
+			return nil, err
+		}
+		c.store(key, g)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if had, has := c.m[key]; has {
+		return had, nil
+	}
+	if c.m == nil {
+		c.m = map[[sha256.Size]byte]*Grammar{}
+	}
+	c.m[key] = g
+	c.order = append(c.order, key)
+	for c.Max > 0 && len(c.order) > c.Max {
+		delete(c.m, c.order[0])
+		c.order = c.order[1:]
+	}
+	return g, nil
+}
+
+// path returns the path of the file in Dir storing the grammar with
+// the hash (or empty if no Dir).
+func (c *Cache) path(key [sha256.Size]byte) string {
+	if c.Dir == `` {
+		return ``
+	}
+	return filepath.Join(c.Dir, hex.EncodeToString(key[:])+`.gob`)
+}
+
+// load returns the grammar with the hash stored in Dir (or nil if none
+// or it cannot be loaded).
+func (c *Cache) load(key [sha256.Size]byte) *Grammar {
+	path := c.path(key)
+	if path == `` {
+		return nil
+	}
+	byt, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	var sg storedGrammar
+	if gob.NewDecoder(bytes.NewReader(byt)).Decode(&sg) != nil {
+		return nil
+	}
+	if sg.Version != storeVersion {
+		return nil
+	}
+	return sg.grammar()
+}
+
+// store stores the grammar with the hash in Dir (if set) writing to
+// a temporary file first so that no other process ever loads half of
+// it.
+func (c *Cache) store(key [sha256.Size]byte, g *Grammar) {
+	path := c.path(key)
+	if path == `` {
+		return
+	}
+	var buf bytes.Buffer
+	if gob.NewEncoder(&buf).Encode(storeGrammar(g)) != nil {
+		return
+	}
+	if os.MkdirAll(c.Dir, 0755) != nil {
+		return
+	}
+	f, err := os.CreateTemp(c.Dir, `.tmp-*`)
+	if err != nil {
+		return
+	}
+	_, err = f.Write(buf.Bytes())
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), path)
+	}
+	if err != nil {
+		os.Remove(f.Name())
+	}
+}
+
+// Len returns the number of grammars in the cache.
+func (c *Cache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.m)
+}
+
+// Stats returns the number of calls to Compile answered from the cache
+// (hits) and those that had to compile (misses).
+func (c *Cache) Stats() (hits, misses int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.hits, c.misses
+}
+
+// Reset empties the cache (but not Dir) and zeroes the stats.
+func (c *Cache) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.m, c.order, c.hits, c.misses = nil, nil, 0, 0
+}
+
+// storeVersion changes whenever storedGrammar (or the encoding of
+// expressions) does so that grammars stored by other versions are
+// compiled again instead.
+const storeVersion = 1
+
+// storedGrammar is a compiled grammar as stored in the Dir of a Cache.
+// Everything is kept except what is never read (see Delegates).
+type storedGrammar struct {
+	Version   int
+	Name      string
+	Home      string
+	Copyright string
+	License   string
+	Includes  []string
+	Rules     []storedRule
+	Trailer   []string
+}
+
+// storedRule is a rule (without its Expr) and its expression encoded
+// (see appendExpr) since gob only encodes interfaces of registered
+// types (with exported fields) and is far slower at decoding trees of
+// small values than reading the PEGN would be.
+type storedRule struct {
+	Rule Rule
+	Expr []byte
+}
+
+// kinds of expressions encoded by appendExpr
+const (
+	storedChoice byte = iota + 1
+	storedSeq
+	storedLook
+	storedQuant
+	storedRef
+	storedLit
+	storedPoint
+	storedRange
+	storedAny
+	storedCapture
+	storedReplace
+	storedInject
+)
+
+func storeGrammar(g *Grammar) storedGrammar {
+	sg := storedGrammar{
+		Version:   storeVersion,
+		Name:      g.Name,
+		Home:      g.Home,
+		Copyright: g.Copyright,
+		License:   g.License,
+		Includes:  g.Includes,
+		Trailer:   g.Trailer,
+	}
+	for _, r := range g.Rules {
+		sr := storedRule{Rule: *r, Expr: appendExpr(nil, r.Expr)}
+		sr.Rule.Expr = nil
+		sg.Rules = append(sg.Rules, sr)
+	}
+	return sg
+}
+
+// grammar returns a new grammar from what was stored (or nil if any
+// expression cannot be decoded).
+func (sg storedGrammar) grammar() *Grammar {
+	g := &Grammar{
+		Name:      sg.Name,
+		Home:      sg.Home,
+		Copyright: sg.Copyright,
+		License:   sg.License,
+		Includes:  sg.Includes,
+		Trailer:   sg.Trailer,
+	}
+	in := interner{}
+	for _, sr := range sg.Rules {
+		r := sr.Rule
+		d := exprDecoder{buf: sr.Expr, in: in}
+		if r.Expr = d.expr(); r.Expr == nil || d.bad || len(d.buf) > 0 {
+			return nil
+		}
+		g.Rules = append(g.Rules, &r)
+	}
+	return g
+}
+
+// appendExpr appends the expression encoded as its kind followed by its
+// fields (as varints and length prefixed strings) and then those of the
+// expressions within it.
+func appendExpr(buf []byte, e Expr) []byte {
+	switch v := e.(type) {
+	case Choice:
+		buf = appendInt(append(buf, storedChoice), len(v))
+		for _, x := range v {
+			buf = appendExpr(buf, x)
+		}
+	case Seq:
+		buf = appendInt(append(buf, storedSeq), len(v))
+		for _, x := range v {
+			buf = appendExpr(buf, x)
+		}
+	case Look:
+		var not int
+		if v.Not {
+			not = 1
+		}
+		buf = appendExpr(appendInt(append(buf, storedLook), not), v.E)
+	case Quant:
+		buf = appendInt(appendInt(append(buf, storedQuant), v.Min), v.Max)
+		buf = appendExpr(buf, v.E)
+	case Ref:
+		buf = appendString(append(buf, storedRef), string(v))
+	case Lit:
+		buf = appendString(append(buf, storedLit), string(v))
+	case Point:
+		buf = appendInt(append(buf, storedPoint, v.Form), int(v.R))
+	case Range:
+		buf = appendInt(appendInt(append(buf, storedRange, v.Form), int(v.Lo)), int(v.Hi))
+	case Any:
+		buf = append(buf, storedAny)
+	case Capture:
+		buf = appendExpr(appendString(append(buf, storedCapture), v.Tag), v.E)
+	case Replace:
+		buf = appendExpr(appendString(append(buf, storedReplace), v.With), v.E)
+	case Inject:
+		buf = appendString(append(buf, storedInject), string(v))
+	}
+	return buf
+}
+
+func appendInt(buf []byte, n int) []byte {
+	var b [binary.MaxVarintLen64]byte
+	return append(buf, b[:binary.PutVarint(b[:], int64(n))]...)
+}
+
+func appendString(buf []byte, a string) []byte {
+	return append(appendInt(buf, len(a)), a...)
+}
+
+// exprDecoder decodes expressions encoded by appendExpr interning the
+// names and literals (see interner). Once bad, everything decoded is
+// nil.
+type exprDecoder struct {
+	buf []byte
+	in  interner
+	bad bool
+}
+
+func (d *exprDecoder) expr() Expr {
+	if d.bad || len(d.buf) == 0 {
+		d.bad = true
+		return nil
+	}
+	k := d.buf[0]
+	d.buf = d.buf[1:]
+	switch k {
+	case storedChoice, storedSeq:
+		n := d.int()
+		if n < 0 || n > len(d.buf) {
+			d.bad = true
+			return nil
+		}
+		x := make([]Expr, n)
+		for i := range x {
+			x[i] = d.expr()
+		}
+		if k == storedChoice {
+			return Choice(x)
+		}
+		return Seq(x)
+	case storedLook:
+		not := d.int() == 1
+		return Look{Not: not, E: d.expr()}
+	case storedQuant:
+		min, max := d.int(), d.int()
+		return Quant{E: d.expr(), Min: min, Max: max}
+	case storedRef:
+		return Ref(d.in.intern(d.string()))
+	case storedLit:
+		return Lit(d.in.intern(d.string()))
+	case storedPoint:
+		form := d.byte()
+		return Point{R: rune(d.int()), Form: form}
+	case storedRange:
+		form := d.byte()
+		lo, hi := d.int(), d.int()
+		return Range{Lo: rune(lo), Hi: rune(hi), Form: form}
+	case storedAny:
+		return Any{}
+	case storedCapture:
+		tag := d.string()
+		return Capture{E: d.expr(), Tag: tag}
+	case storedReplace:
+		with := d.string()
+		return Replace{E: d.expr(), With: with}
+	case storedInject:
+		return Inject(d.string())
+	}
+	d.bad = true
+	return nil
+}
+
+func (d *exprDecoder) int() int {
+	n, i := binary.Varint(d.buf)
+	if i <= 0 {
+		d.bad = true
+		return 0
+	}
+	d.buf = d.buf[i:]
+	return int(n)
+}
+
+func (d *exprDecoder) byte() byte {
+	if len(d.buf) == 0 {
+		d.bad = true
+		return 0
+	}
+	b := d.buf[0]
+	d.buf = d.buf[1:]
+	return b
+}
+
+func (d *exprDecoder) string() string {
+	n := d.int()
+	if n < 0 || n > len(d.buf) {
+		d.bad = true
+		return ``
+	}
+	a := string(d.buf[:n])
+	d.buf = d.buf[n:]
+	return a
+}
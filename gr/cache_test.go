@@ -0,0 +1,110 @@
+package gr_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/rwxrob/pegn/gr"
+)
+
+func ExampleCache() {
+
+	c := gr.Cache{Max: 2}
+	src := `Greeting <-- 'hello' / 'hi'`
+
+	a, _ := c.Compile(src)
+	b, _ := c.Compile(src)
+	fmt.Println(a == b, c.Len())
+	fmt.Println(c.Stats())
+
+	_, err := c.Compile(`Bad <-- Nope`)
+	fmt.Println(err, c.Len())
+
+	c.Compile(`One <-- '1'`)
+	c.Compile(`Two <-- '2'`)
+	d, _ := c.Compile(src)
+	fmt.Println(a == d, c.Len())
+
+	// Output:
+	// true 1
+	// 1 1
+	// line 1: undefined: Nope 1
+	// false 2
+}
+
+func ExampleCache_Dir() {
+
+	dir, _ := os.MkdirTemp(``, `gr`)
+	defer os.RemoveAll(dir)
+	src := "# PAIRS example.com/pairs\n\n" +
+		"Pairs <-- Pair (SP+ Pair)* !.\n" +
+		"Pair  <-- <:key lower+> '=' Value ^';'\n" +
+		"Value <- &digit [0-9]{1,3} / x41 / 'on' => 'yes' / < (!SP .)+ >\n"
+
+	a := gr.Cache{Dir: dir}
+	g, _ := a.Compile(src)
+	a.Compile(`Bad <-- Nope`)
+	files, _ := os.ReadDir(dir)
+	fmt.Println(len(files))
+
+	// another process loads what was compiled instead of compiling it
+	b := gr.Cache{Dir: dir}
+	h, err := b.Compile(src)
+	fmt.Println(reflect.DeepEqual(g.Rules, h.Rules), g != h, err)
+	fmt.Println(h.Name, h.Home)
+	fmt.Print(h)
+
+	// files that cannot be loaded are compiled again
+	os.WriteFile(filepath.Join(dir, files[0].Name()), []byte(`junk`), 0644)
+	c := gr.Cache{Dir: dir}
+	i, err := c.Compile(src)
+	fmt.Println(reflect.DeepEqual(g.Rules, i.Rules), err)
+
+	// Output:
+	// 1
+	// true true <nil>
+	// PAIRS example.com/pairs
+	// # PAIRS example.com/pairs
+	//
+	// Pairs <-- Pair (SP+ Pair)* !.
+	// Pair  <-- <:key lower+ > '=' Value ^';'
+	// Value <- &digit [0-9]{1,3} / x41 / 'on' => 'yes' / < (!SP .)+ >
+	// true <nil>
+}
+
+// rules returns the PEGN of a grammar with n rules much like one
+// another.
+func rules(n int) string {
+	var b strings.Builder
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&b, "Rule%d <-- 'kw%d' SP+ (Name / [0-9]{1,3} / x41) (',' Rule%d)* !.\n", i, i, (i+1)%n)
+	}
+	b.WriteString("Name <- upper lower+\n")
+	return b.String()
+}
+
+// compiling every time (as with a new process and no Dir)
+func BenchmarkCache_compile(b *testing.B) {
+	src := rules(300)
+	for i := 0; i < b.N; i++ {
+		c := gr.Cache{}
+		c.Compile(src)
+	}
+}
+
+// loading every time (as with a new process sharing Dir)
+func BenchmarkCache_Dir(b *testing.B) {
+	src := rules(300)
+	dir := b.TempDir()
+	c := gr.Cache{Dir: dir}
+	c.Compile(src)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		c := gr.Cache{Dir: dir}
+		c.Compile(src)
+	}
+}
@@ -0,0 +1,41 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+package gr
+
+import (
+	"strings"
+
+	"github.com/rwxrob/pegn/ast"
+)
+
+// CaptureNode is the type of the nodes of named captures (<:tag a >)
+// which have the tag as their Tag (see ast.Node.Capture) and everything
+// matched within as their value or nodes. Like ErrorNode, it is one of
+// the types reserved by ast (see ast.Reserved) and never that of a rule.
+const CaptureNode = ast.ErrorNode + 1
+
+// tagged returns the stand-in rule of the named capture so that spans
+// of captures are recorded (and reported by Events) like those of node
+// rules. The rule is named as the capture is written (<:tag>).
+func (m *machine) tagged(tag string) *Rule {
+	if r, has := m.tags[tag]; has {
+		return r
+	}
+	if m.tags == nil {
+		m.tags = map[string]*Rule{}
+	}
+	r := &Rule{Node: true}
+	r.ID, r.Name = CaptureNode, `<:`+tag+`>`
+	m.tags[tag] = r
+	return r
+}
+
+// tagOf returns the tag of the stand-in rule of a named capture (see
+// tagged) or nothing if not one.
+func tagOf(r *Rule) string {
+	if r.ID != CaptureNode {
+		return ""
+	}
+	return strings.TrimSuffix(strings.TrimPrefix(r.Name, `<:`), `>`)
+}
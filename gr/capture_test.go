@@ -0,0 +1,27 @@
+package gr_test
+
+import (
+	"fmt"
+
+	"github.com/rwxrob/pegn/gr"
+	"github.com/rwxrob/pegn/scanner"
+)
+
+func ExampleCapture() {
+
+	g := gr.MustCompile(`Record <-- <:key lower+> '=' <:val digit+>`)
+	fmt.Println(g.Rules[0])
+	fmt.Println(g.Rules[0].Regex())
+
+	n := g.Parse(scanner.New("port=8080"))
+	fmt.Println(n)
+	fmt.Println(n.Capture(`key`).V, n.Capture(`val`).V)
+	fmt.Println(n.Capture(`missing`))
+
+	// Output:
+	// Record <-- <:key lower+ > '=' <:val digit+ >
+	// ^(?:(?P<key>[a-z]+)=(?P<val>[0-9]+))$ <nil>
+	// {"T":1,"N":[{"T":-2147483647,"V":"port","Tag":"key"},{"T":-2147483647,"V":"8080","Tag":"val"}]}
+	// port 8080
+	// <nil>
+}
@@ -0,0 +1,135 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+package gr
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Compile reads the PEGN source (see Read) and checks that the
+// resulting grammar can be executed (see Check) so that its Scan,
+// ScanRule, Parse, and ParseRule methods can be used against any
+// pegn.Scanner without surprises. Prefer Compile over Read whenever
+// the grammar is to be used for scanning or parsing rather than
+// written back out.
+func Compile(src string) (*Grammar, error) {
+	g, err := Read(src)
+	if err != nil {
+		return nil, err
+	}
+	if err := g.Check(); err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+// MustCompile calls Compile and panics on error.
+func MustCompile(src string) *Grammar {
+	g, err := Compile(src)
+	if err != nil {
+		panic(err)
+	}
+	return g
+}
+
+// Check returns an error for the first rule (in order of definition)
+// that could never be executed because it refers to an undefined rule
+// (that is not a builtin). Left recursive rules (that call themselves
+// again without consuming anything) are supported by the interpreter
+// (see ScanRule) and only warned about (see Warnings) since most PEG
+// tools (and code generated for any of them) do not support them and
+// would never end.
+func (g *Grammar) Check() error {
+	for _, r := range g.Rules {
+		for _, n := range Refs(r.Expr) {
+			if g.Lookup(n) == nil {
+				return g.ruleErr(r, `undefined: %v`, n)
+			}
+		}
+	}
+	return nil
+}
+
+// leftWarnings returns a line for every left recursive rule (see Check)
+// that is not on the path of one already warned about.
+func (g *Grammar) leftWarnings() []string {
+	var list []string
+	warned := map[string]bool{}
+	for _, r := range g.Rules {
+		if warned[r.Name] {
+			continue
+		}
+		path := g.leftPath(r, r.Name, nil, map[string]bool{})
+		if path == nil {
+			continue
+		}
+		for _, n := range path {
+			warned[n] = true
+		}
+		list = append(list, g.ruleErr(r, `left recursive: %v`,
+			strings.Join(append([]string{r.Name}, path...), ` -> `)).Error())
+	}
+	return list
+}
+
+func (g *Grammar) ruleErr(r *Rule, format string, args ...any) error {
+	msg := fmt.Sprintf(format, args...)
+	if r.Line > 0 {
+		return fmt.Errorf(`line %v: %v`, r.Line, msg)
+	}
+	return fmt.Errorf(`%v: %v`, r.Name, msg)
+}
+
+// leftPath returns the path of rules called from the rule (without
+// consuming anything) that leads back to the target rule (or nil if
+// none does).
+func (g *Grammar) leftPath(r *Rule, target string, path []string, seen map[string]bool) []string {
+	for _, n := range g.leftRefs(r.Expr, nil) {
+		c := g.Lookup(n)
+		if c == nil {
+			continue
+		}
+		if c.Name == target {
+			return append(path, c.Name)
+		}
+		if seen[c.Name] {
+			continue
+		}
+		seen[c.Name] = true
+		if p := g.leftPath(c, target, append(path, c.Name), seen); p != nil {
+			return p
+		}
+	}
+	return nil
+}
+
+// leftRefs appends the names of rules that can be called by the
+// expression before anything is consumed.
+func (g *Grammar) leftRefs(e Expr, list []string) []string {
+	switch v := e.(type) {
+	case Seq:
+		for _, x := range v {
+			list = g.leftRefs(x, list)
+			if !g.nullable(x, map[string]bool{}) {
+				break
+			}
+		}
+	case Choice:
+		for _, x := range v {
+			list = g.leftRefs(x, list)
+		}
+	case Quant:
+		list = g.leftRefs(v.E, list)
+	case Look:
+		list = g.leftRefs(v.E, list)
+	case Capture:
+		list = g.leftRefs(v.E, list)
+	case Replace:
+		list = g.leftRefs(v.E, list)
+	case Ref:
+		list = append(list, string(v))
+	}
+	return list
+}
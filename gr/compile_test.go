@@ -0,0 +1,35 @@
+package gr_test
+
+import (
+	"fmt"
+
+	"github.com/rwxrob/pegn/gr"
+	"github.com/rwxrob/pegn/scanner"
+)
+
+func ExampleCompile() {
+
+	g, err := gr.Compile(`
+Sum <-- Num (('+' / '-') Num)*
+Num <-- digit+`)
+	fmt.Println(err)
+	fmt.Println(g.Parse(scanner.New(`1+22-3`)))
+
+	_, err = gr.Compile(`
+Sum <-- Num ('+' Num)*
+Num <-- digits`)
+	fmt.Println(err)
+
+	g, err = gr.Compile(`
+Expr <-- Sum / Num
+Sum  <-- Term? '+' Num
+Term <- SP* Expr
+Num  <-- digit+`)
+	fmt.Println(err, g.Warnings())
+
+	// Output:
+	// <nil>
+	// {"T":1,"N":[{"T":2,"V":"1"},{"T":2,"V":"22"},{"T":2,"V":"3"}]}
+	// line 3: undefined: digits
+	// <nil> [line 2: left recursive: Expr -> Sum -> Term -> Expr]
+}
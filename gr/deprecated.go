@@ -0,0 +1,73 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+package gr
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DeprecatedPrefix begins the Doc comment line marking the rule after
+// it as deprecated with the rest of the line (ex: what to use instead)
+// as its Deprecated reason (the same convention as Go).
+//
+//	# Deprecated: use Greeting instead.
+//	Greet <- Greeting
+const DeprecatedPrefix = `# Deprecated:`
+
+// readDeprecation sets the Deprecated and AliasOf of the rule from its
+// Doc and definition. Any simple definition (<-) of nothing but
+// another rule is an alias of that rule.
+func readDeprecation(r *Rule) {
+	for _, d := range r.Doc {
+		if strings.HasPrefix(d, DeprecatedPrefix) {
+			r.Deprecated = strings.TrimSpace(strings.TrimPrefix(d, DeprecatedPrefix))
+			if r.Deprecated == "" {
+				r.Deprecated = `deprecated`
+			}
+		}
+	}
+	if ref, is := r.Expr.(Ref); is && !r.Node {
+		r.AliasOf = string(ref)
+	}
+}
+
+// Resolve returns the rule with the given name (see Lookup) following
+// AliasOf to the rule finally referred to. Returns nil if not found or
+// if the aliases never end.
+func (g *Grammar) Resolve(name string) *Rule {
+	r := g.Lookup(name)
+	for i := 0; r != nil && r.AliasOf != ""; i++ {
+		if i > len(g.Rules) {
+			return nil
+		}
+		r = g.Lookup(r.AliasOf)
+	}
+	return r
+}
+
+// Warnings returns a line for every reference to a deprecated rule
+// (with the line of the rule referring to it) in order of definition.
+// References from rules that are themselves deprecated are ignored.
+// A line for every left recursive rule (see Check) follows.
+func (g *Grammar) Warnings() []string {
+	var list []string
+	for _, r := range g.Rules {
+		if r.Deprecated != "" {
+			continue
+		}
+		for _, n := range Refs(r.Expr) {
+			d := g.Lookup(n)
+			if d == nil || d.Deprecated == "" {
+				continue
+			}
+			w := fmt.Sprintf(`%v refers to deprecated %v: %v`, r.Name, d.Name, d.Deprecated)
+			if r.Line > 0 {
+				w = fmt.Sprintf(`line %v: %v`, r.Line, w)
+			}
+			list = append(list, w)
+		}
+	}
+	return append(list, g.leftWarnings()...)
+}
@@ -0,0 +1,35 @@
+package gr_test
+
+import (
+	"fmt"
+
+	"github.com/rwxrob/pegn/gr"
+)
+
+func ExampleGrammar_Warnings() {
+
+	g := gr.MustRead(`
+Greeting <-- Hello SP Name
+Hello    <-- 'hello' / 'hi'
+Name     <-- Word
+
+# Deprecated: use Hello instead.
+Greet <- Hello
+
+Wave <-- Greet SP Name
+
+# Deprecated: use Name instead.
+Word <- upper lower+`)
+
+	for _, w := range g.Warnings() {
+		fmt.Println(w)
+	}
+	fmt.Println(g.Rule(`Greet`).AliasOf, g.Resolve(`Greet`).Name)
+	fmt.Println(g.Resolve(`Greeting`).Name, g.Resolve(`Nope`))
+
+	// Output:
+	// line 4: Name refers to deprecated Word: use Name instead.
+	// line 9: Wave refers to deprecated Greet: use Hello instead.
+	// Hello Hello
+	// Greeting <nil>
+}
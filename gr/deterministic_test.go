@@ -0,0 +1,62 @@
+package gr_test
+
+import (
+	"fmt"
+	"math/rand"
+
+	"github.com/rwxrob/pegn/gr"
+)
+
+// Everything written from a grammar must be byte-for-byte identical
+// every time so that generated files are stable under go generate.
+func Example_deterministic() {
+
+	const src = `# Calc example.com/calc
+
+# whole expression
+Expr   <-- Term (ws* ('+' / '-') ws* Term)*
+Term   <-- Factor (ws* ('*' / '/') ws* Factor)*
+Factor <-- Number / '(' ws* Expr ws* ')' / Ident
+Number <-- sign? digit+ ('.' digit+)?
+Ident  <-- (alpha / UNDER) (alphanum / UNDER)*  # names
+Quoted <-  DQ (!DQ quotable / [x20-x21])* DQ
+`
+	outputs := map[string]func() string{
+		`PEGN`:      func() string { return gr.MustRead(src).String() },
+		`ANTLR`:     func() string { return gr.MustRead(src).ANTLR(``) },
+		`BuildInfo`: func() string { return gr.MustRead(src).BuildInfo().String() },
+		`Generate`: func() string {
+			s, _ := gr.MustRead(src).Generate(``, rand.New(rand.NewSource(1)))
+			return s
+		},
+		`Profile`: func() string {
+			p, _ := gr.MustRead(src).Profile(``, `1+2*(x-3.5)`, `a/b`)
+			return p.String()
+		},
+		`Reorder`: func() string {
+			p, _ := gr.MustRead(src).Profile(``, `1+2*(x-3.5)`, `a/b`)
+			g, notes := p.Reorder()
+			return fmt.Sprint(g, notes)
+		},
+	}
+
+	for _, name := range []string{`PEGN`, `ANTLR`, `BuildInfo`, `Generate`, `Profile`, `Reorder`} {
+		fn := outputs[name]
+		first := fn()
+		same := true
+		for i := 0; i < 50; i++ {
+			if fn() != first {
+				same = false
+			}
+		}
+		fmt.Println(name, same)
+	}
+
+	// Output:
+	// PEGN true
+	// ANTLR true
+	// BuildInfo true
+	// Generate true
+	// Profile true
+	// Reorder true
+}
@@ -0,0 +1,91 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+package gr
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rwxrob/pegn"
+	"github.com/rwxrob/pegn/curs"
+)
+
+// DiagPrefix begins the Doc comment line making the rule after it an
+// error production: a rule matching a common mistake with the rest of
+// the line as the message (Diag) to report when it does. Error
+// productions never match. Instead, when one would have matched (and
+// the rule it was tried for fails as a whole) its message is reported
+// as a Diagnostic rather than what was expected at the farthest
+// failure. Error productions are usually the last alternative of
+// a choice so that they are tried only when everything else has
+// failed.
+//
+//	Program <-- Stmt (LF Stmt)* (!. / Semi)
+//
+//	# Error: semicolons are not allowed here
+//	Semi <- SP* ';'
+const DiagPrefix = `# Error:`
+
+// WarningPrefix and InfoPrefix begin the Doc comment line of a rule
+// reported (with the rest of the line as its Diag) whenever it matches
+// as part of a scan that succeeds. Unlike error productions (see
+// DiagPrefix) these rules match as usual so that lint-style findings
+// (or what a lenient rule let through) are reported without failing.
+// They are pushed (in order of position) onto the error stack of the
+// scanner as a Diagnostic of severity pegn.SevWarning or pegn.SevInfo
+// after the scan.
+//
+//	Line <-- Text Trailing? LF
+//
+//	# Warning: trailing blanks
+//	Trailing <- blank+
+const (
+	WarningPrefix = `# Warning:`
+	InfoPrefix    = `# Info:`
+)
+
+// readDiag sets the Diag and Sev of the rule from its Doc.
+func readDiag(r *Rule) {
+	prefixes := []struct {
+		prefix string
+		sev    pegn.Severity
+	}{
+		{DiagPrefix, pegn.SevError},
+		{WarningPrefix, pegn.SevWarning},
+		{InfoPrefix, pegn.SevInfo},
+	}
+	for _, d := range r.Doc {
+		for _, p := range prefixes {
+			if !strings.HasPrefix(d, p.prefix) {
+				continue
+			}
+			r.Diag = strings.TrimSpace(strings.TrimPrefix(d, p.prefix))
+			r.Sev = p.sev
+			if r.Diag == "" {
+				r.Diag = `unexpected ` + r.Name
+			}
+		}
+	}
+}
+
+// Diagnostic is pushed (instead of a pegn.Error) by ScanRule and
+// ParseRule when an error production (see DiagPrefix) matched and, for
+// scans that succeed, for every warning or informational rule matched
+// (see WarningPrefix). It unwraps to the pegn.Error of the rule so that
+// it can be located the same way.
+type Diagnostic struct {
+	T   int           // ID of the rule
+	C   curs.R        // where the rule matched
+	Msg string        // Diag of the rule
+	Sev pegn.Severity // Sev of the rule
+}
+
+// Severity returns Sev (see pegn.SeverityOf).
+func (d Diagnostic) Severity() pegn.Severity { return d.Sev }
+
+// Error fulfills the error interface in the same form as pegn.Error.
+func (d Diagnostic) Error() string { return fmt.Sprintf(`%v at %v`, d.Msg, d.C) }
+
+// Unwrap returns the pegn.Error of the rule.
+func (d Diagnostic) Unwrap() error { return pegn.Error{T: d.T, C: d.C} }
@@ -0,0 +1,81 @@
+package gr_test
+
+import (
+	"fmt"
+
+	"github.com/rwxrob/pegn/gr"
+	"github.com/rwxrob/pegn/scanner"
+)
+
+func ExampleDiagPrefix() {
+
+	g := gr.MustRead(`
+Program <-- Stmt (EndLine Stmt)* (!. / Semi)
+Stmt    <-- Let / Print
+Let     <-- 'let ' Name ' = ' Num
+Print   <-- 'print ' Name
+Name    <-- lower+
+Num     <-- digit+
+EndLine <- LF
+
+# Error: semicolons are not allowed here
+Semi <- SP* ';'`)
+
+	fmt.Printf("%q\n", g.Rule(`Semi`).Diag)
+
+	s := scanner.New("let x = 1\nprint x;")
+	fmt.Println(g.ParseRule(`Program`, s))
+	s.Print()
+	fmt.Print(s.Error())
+
+	x, _ := g.Explain(``, "let x = 1;\nprint x")
+	fmt.Println(x)
+
+	s = scanner.New("let x = 1\nprint x")
+	fmt.Println(g.ParseRule(`Program`, s))
+
+	// Output:
+	// "semicolons are not allowed here"
+	// <nil>
+	// '\x00' 0-0 "let x = 1\n"
+	// 2:8: semicolons are not allowed here at 'x' 16-17
+	// line 1, column 10: semicolons are not allowed here
+	// {"T":1,"N":[{"T":2,"N":[{"T":3,"N":[{"T":5,"V":"x"},{"T":6,"V":"1"}]}]},{"T":2,"N":[{"T":4,"N":[{"T":5,"V":"x"}]}]}]}
+}
+
+func ExampleWarningPrefix() {
+
+	g := gr.MustRead(`
+Lines <-- Line+
+Line  <-- Word (SP Word)* Trailing? LF
+Word  <-- (Tabs / lower)+
+
+# Warning: trailing blanks
+Trailing <- blank+
+
+# Info: tabs within words are deprecated
+Tabs <- TAB`)
+
+	fmt.Println(g.Rule(`Tabs`).Sev, g.Rule(`Trailing`).Sev)
+
+	s := scanner.New("some line  \nwith\ttab\n")
+	n := g.ParseRule(`Lines`, s)
+	fmt.Println(n != nil)
+	for _, e := range s.ErrReport() {
+		fmt.Println(e.Sev, e)
+	}
+	fmt.Print(s.Error())
+
+	// a failed alternative never reports (Trailing before LF fails)
+	s = scanner.New("some \t")
+	fmt.Println(g.ParseRule(`Lines`, s), len(*s.Errors()))
+
+	// Output:
+	// info warning
+	// true
+	// warning 1:9: trailing blanks at 'e' 8-9
+	// info 2:4: tabs within words are deprecated at 'h' 15-16
+	// 1:10: warning: trailing blanks at 'e' 8-9
+	// 2:5: info: tabs within words are deprecated at 'h' 15-16
+	// <nil> 1
+}
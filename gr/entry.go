@@ -0,0 +1,100 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+package gr
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rwxrob/pegn/ast"
+	"github.com/rwxrob/pegn/scanner"
+)
+
+// EntryPrefix begins the Doc comment line marking the rule after it as
+// an entry of the grammar, a rule meant to be parsed on its own (see
+// ParseEntry). This allows one grammar to parse whole documents and
+// fragments of them (a single expression, a header) without
+// duplicating any rules. When no rule is marked the first rule is the
+// only entry.
+//
+//	# Entry
+//	Document <-- Header Body
+//
+//	# Entry
+//	Header   <-- Field+
+const EntryPrefix = `# Entry`
+
+// readEntry sets the Entry of the rule from its Doc.
+func readEntry(r *Rule) {
+	for _, d := range r.Doc {
+		if d == EntryPrefix || strings.HasPrefix(d, EntryPrefix+` `) ||
+			strings.HasPrefix(d, EntryPrefix+`:`) {
+			r.Entry = true
+		}
+	}
+}
+
+// Entries returns every entry rule of the grammar (see EntryPrefix) in
+// the order defined or just the first rule if none are marked. Parse
+// and Scan always use the first of them.
+func (g *Grammar) Entries() []*Rule {
+	var list []*Rule
+	for _, r := range g.Rules {
+		if r.Entry {
+			list = append(list, r)
+		}
+	}
+	if list == nil && len(g.Rules) > 0 {
+		list = g.Rules[:1]
+	}
+	return list
+}
+
+// entry returns the first entry rule (see Entries) or nil if there are
+// no rules at all.
+func (g *Grammar) entry() *Rule {
+	if list := g.Entries(); len(list) > 0 {
+		return list[0]
+	}
+	return nil
+}
+
+// ParseEntry parses the entire input (anything accepted by
+// scanner.S.Buffer) with the named entry rule (see Entries) or the
+// first if empty and returns the node tree (see ParseRule). The input
+// must match completely. If it does not, the *Explanation of why (see
+// Explain) is returned as the error. Naming a rule that is not an entry
+// is always an error so that only the rules meant to be parsed on
+// their own ever are.
+func (g *Grammar) ParseEntry(name string, input any) (*ast.Node, error) {
+	r := g.entry()
+	if name != "" {
+		r = nil
+		for _, e := range g.Entries() {
+			if e.Name == name {
+				r = e
+				break
+			}
+		}
+	}
+	if r == nil {
+		return nil, fmt.Errorf(`not an entry rule: %q`, name)
+	}
+	s := scanner.New()
+	if err := s.Buffer(input); err != nil {
+		return nil, err
+	}
+	n := g.ParseRule(r.Name, s)
+	if n != nil && s.Finished() {
+		return n, nil
+	}
+	x, err := g.Explain(r.Name, s.Buf)
+	if err != nil {
+		return nil, err
+	}
+	if x == nil {
+		return nil, fmt.Errorf(`%v: failed to parse`, r.Name)
+	}
+	return nil, x
+}
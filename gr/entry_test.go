@@ -0,0 +1,39 @@
+package gr_test
+
+import (
+	"fmt"
+
+	"github.com/rwxrob/pegn/gr"
+)
+
+func ExampleGrammar_ParseEntry() {
+
+	g := gr.MustRead(`
+# Entry
+Sum    <-- Number (Plus Number)*
+
+# Entry
+Number <-- digit+
+Plus   <-- '+'`)
+
+	for _, r := range g.Entries() {
+		fmt.Println(r.Name)
+	}
+
+	fmt.Println(g.ParseEntry(``, `1+22`))
+	fmt.Println(g.ParseEntry(`Number`, `42`))
+
+	_, err := g.ParseEntry(`Number`, `4x`)
+	fmt.Println(err)
+
+	_, err = g.ParseEntry(`Plus`, `+`)
+	fmt.Println(err)
+
+	// Output:
+	// Sum
+	// Number
+	// {"T":1,"N":[{"T":2,"V":"1"},{"T":3,"V":"+"},{"T":2,"V":"22"}]} <nil>
+	// {"T":2,"V":"42"} <nil>
+	// line 1, column 2: expected digit but found 'x'
+	// not an entry rule: "Plus"
+}
@@ -0,0 +1,57 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+package gr
+
+import "github.com/rwxrob/pegn"
+
+// Event is a single node rule (<--) matched (see Events) with JSON
+// field names suited to processing with tools like jq.
+type Event struct {
+	Rule  string `json:"rule"`            // name of rule matched
+	T     int    `json:"type"`            // rule ID (node type)
+	Depth int    `json:"depth"`           // number of node rules within
+	B     int    `json:"b"`               // byte offset of beginning
+	E     int    `json:"e"`               // byte offset of end
+	V     string `json:"value,omitempty"` // text matched (only if leaf)
+}
+
+// Events interprets the named rule (see ParseRule) calling fn with an
+// Event for every node rule matched in order of beginning (depth
+// first) rather than creating a node tree. This is the SAX to the DOM
+// of ParseRule. The rule itself is always the first event (even if
+// not a node rule). Returns the error pushed to the scanner on failure
+// or the first error returned by fn.
+func (g *Grammar) Events(name string, s pegn.Scanner, fn func(e Event) error) error {
+	m := newMachine(g, s, g.output())
+	m.acts = true
+	r := m.lookup(name)
+	if r == nil {
+		s.Expected(0)
+		return lastErr(s)
+	}
+	m.record = true
+	b := s.RuneE()
+	defer keep(s, b)()
+	if !m.match(r) {
+		m.push(r)
+		return lastErr(s)
+	}
+	m.report()
+	spans := m.spans
+	if !r.Node {
+		spans = append([]span{{rule: r, b: b, e: s.RuneE(), oe: m.outlen(), depth: -1}}, spans...)
+	}
+	buf, off := *s.Bytes(), offset(s)
+	base := spans[0].depth
+	for i, sp := range spans {
+		e := Event{Rule: sp.rule.Name, T: sp.rule.ID, Depth: sp.depth - base, B: sp.b, E: sp.e}
+		if i+1 == len(spans) || spans[i+1].depth <= sp.depth {
+			e.V = sp.value(buf, off, out(m))
+		}
+		if err := fn(e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,29 @@
+package gr_test
+
+import (
+	"fmt"
+
+	"github.com/rwxrob/pegn/gr"
+	"github.com/rwxrob/pegn/scanner"
+)
+
+func ExampleGrammar_Events() {
+
+	g := gr.MustRead(`
+Greeting <-- Hello SP+ Name
+Hello    <-- 'hello' / 'hi'
+Name     <-- upper lower+`)
+
+	s := scanner.New(`hi  Rob!`)
+	err := g.Events(`Greeting`, s, func(e gr.Event) error {
+		fmt.Printf("%+v\n", e)
+		return nil
+	})
+	fmt.Println(err)
+
+	// Output:
+	// {Rule:Greeting T:1 Depth:0 B:0 E:7 V:}
+	// {Rule:Hello T:2 Depth:1 B:0 E:2 V:hi}
+	// {Rule:Name T:3 Depth:1 B:4 E:7 V:Rob}
+	// <nil>
+}
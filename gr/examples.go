@@ -0,0 +1,48 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+package gr
+
+import (
+	"strconv"
+	"strings"
+)
+
+// ValidPrefix and InvalidPrefix begin the Doc comment lines of examples
+// of what the rule after them must (Valid) and must not (Invalid) match
+// completely, one per line. Examples are taken as is (without leading
+// and trailing white space) unless double quoted in which case they
+// are unquoted like Go strings so that any rune can be included. See
+// grtest.Examples for checking them.
+//
+//	# Valid: v1.2.3
+//	# Valid: "1.2.3\n"
+//	# Invalid: v1.2
+//	Version <- 'v'? digit+ '.' digit+ '.' digit+ LF?
+const (
+	ValidPrefix   = `# Valid:`
+	InvalidPrefix = `# Invalid:`
+)
+
+// readExamples sets the Valid and Invalid examples of the rule from its
+// Doc.
+func readExamples(r *Rule) {
+	for _, d := range r.Doc {
+		switch {
+		case strings.HasPrefix(d, ValidPrefix):
+			r.Valid = append(r.Valid, example(strings.TrimPrefix(d, ValidPrefix)))
+		case strings.HasPrefix(d, InvalidPrefix):
+			r.Invalid = append(r.Invalid, example(strings.TrimPrefix(d, InvalidPrefix)))
+		}
+	}
+}
+
+func example(a string) string {
+	a = strings.TrimSpace(a)
+	if len(a) > 1 && a[0] == '"' {
+		if s, err := strconv.Unquote(a); err == nil {
+			return s
+		}
+	}
+	return a
+}
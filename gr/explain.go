@@ -0,0 +1,130 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+package gr
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/rwxrob/pegn/scanner"
+)
+
+// Explanation describes why input failed to match a rule of a grammar
+// by reporting on the farthest position any rule reached before
+// failing (which is almost always where the actual mistake is).
+type Explanation struct {
+	Rule     string   // name of rule matched against
+	Pos      int      // byte offset of farthest failure
+	Line     int      // line of Pos (beginning with 1)
+	Column   int      // rune column of Pos (beginning with 1)
+	Found    string   // quoted rune found at Pos (or "end of data")
+	Stack    []string // rules being matched at Pos (outermost first)
+	Expected []string // what was expected at Pos
+	RuledOut []string // rules tried at Pos that failed
+	Diag     string   // message of error production matched at Pos (if any)
+}
+
+// Explain matches the named rule (or the first entry if empty) against the
+// entire input (anything accepted by scanner.S.Buffer) and returns nil
+// if the input matches completely. Otherwise, an Explanation is
+// returned. An error is returned only if the rule is not found or the
+// input could not be buffered.
+func (g *Grammar) Explain(rule string, input any) (*Explanation, error) {
+	if r := g.entry(); rule == "" && r != nil {
+		rule = r.Name
+	}
+	r := g.Lookup(rule)
+	if r == nil {
+		return nil, fmt.Errorf(`rule not found: %q`, rule)
+	}
+	s := scanner.New()
+	if err := s.Buffer(input); err != nil {
+		return nil, err
+	}
+
+	m := newMachine(g, s, nil)
+	ok := m.rule(r)
+	if ok && s.Finished() {
+		return nil, nil
+	}
+
+	x := &Explanation{Rule: r.Name}
+	switch {
+	case m.diag != nil:
+		x.Pos = m.diagmark.E
+		x.Stack = []string{r.Name}
+		x.Diag = m.diag.Diag
+	case m.isle != nil && m.far <= m.isle.end && (!ok || s.RuneE() < m.isle.end):
+		x.Pos = m.isle.pos
+		x.Stack = []string{r.Name}
+		x.Diag = m.isle.err.Error()
+	case ok && s.RuneE() > m.far:
+		x.Pos = s.RuneE()
+		x.Stack = []string{r.Name}
+		x.Expected = []string{`end of data`}
+	default:
+		x.Pos = m.far
+		if x.Pos < 0 {
+			x.Pos = 0
+		}
+		for _, i := range m.fstack {
+			x.Stack = append(x.Stack, i.Name)
+		}
+		x.Expected = m.expect
+		x.RuledOut = m.ruled
+	}
+
+	x.Line, x.Column = 1, 1
+	for _, c := range string(s.Buf[:x.Pos]) {
+		x.Column++
+		if c == '\n' {
+			x.Line++
+			x.Column = 1
+		}
+	}
+	x.Found = `end of data`
+	if x.Pos < len(s.Buf) {
+		c, _ := utf8.DecodeRune(s.Buf[x.Pos:])
+		x.Found = fmt.Sprintf(`%q`, c)
+	}
+
+	return x, nil
+}
+
+// Error fulfills the error interface with a single line summary (with
+// the Diag instead of what was expected if there is one).
+func (x *Explanation) Error() string {
+	if x.Diag != "" {
+		return fmt.Sprintf(`line %v, column %v: %v`, x.Line, x.Column, x.Diag)
+	}
+	return fmt.Sprintf(`line %v, column %v: expected %v but found %v`,
+		x.Line, x.Column, orList(x.Expected), x.Found)
+}
+
+// String returns the summary from Error followed by indented lines with
+// the rule stack and any rules ruled out.
+func (x *Explanation) String() string {
+	s := x.Error()
+	if len(x.Stack) > 0 {
+		s += "\n  in " + strings.Join(x.Stack, ` > `)
+	}
+	if len(x.RuledOut) > 0 {
+		s += "\n  ruled out " + strings.Join(x.RuledOut, `, `)
+	}
+	return s
+}
+
+// orList joins the list as English (a, b, or c).
+func orList(list []string) string {
+	switch len(list) {
+	case 0:
+		return `nothing`
+	case 1:
+		return list[0]
+	case 2:
+		return list[0] + ` or ` + list[1]
+	}
+	return strings.Join(list[:len(list)-1], `, `) + `, or ` + list[len(list)-1]
+}
@@ -0,0 +1,44 @@
+package gr_test
+
+import (
+	"fmt"
+
+	"github.com/rwxrob/pegn/gr"
+)
+
+func ExampleGrammar_Explain() {
+
+	g := gr.MustRead(`
+Greeting <-- Hello SP+ (Name / Nick) EndLine?
+Hello    <-- 'hello' / 'hi'
+Name     <-- upper lower+
+Nick     <-- lower{2,}
+EndLine  <-- LF / CRLF`)
+
+	x, _ := g.Explain(``, "hello\n  Rob")
+	fmt.Println(x.String())
+
+	x, _ = g.Explain(``, "hello  9")
+	fmt.Println(x.String())
+
+	x, _ = g.Explain(`Greeting`, "hi Rob!")
+	fmt.Println(x.String())
+
+	x, _ = g.Explain(`Greeting`, "hi Rob\n")
+	fmt.Println(x == nil)
+
+	_, err := g.Explain(`Missing`, "hi Rob\n")
+	fmt.Println(err)
+
+	// Output:
+	// line 1, column 6: expected SP but found '\n'
+	//   in Greeting
+	// line 1, column 8: expected SP, upper, or lower but found '9'
+	//   in Greeting > Name
+	//   ruled out Name, Nick
+	// line 1, column 7: expected lower, LF, or CRLF but found '!'
+	//   in Greeting > Name
+	//   ruled out EndLine
+	// true
+	// rule not found: "Missing"
+}
@@ -0,0 +1,236 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+package gr
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Expr is any PEGN expression. String must always return the
+// expression in canonical PEGN notation so that any Expr can be
+// written back out exactly as it would be read.
+type Expr interface {
+	String() string
+	prec() int
+}
+
+// precedence of expressions (lowest to highest) used to decide when
+// parenthesis are needed when printing
+const (
+	precChoice = iota + 1
+	precSeq
+	precReplace
+	precPrefix
+	precPrimary
+)
+
+// Choice is an ordered choice between expressions (a / b).
+type Choice []Expr
+
+// Seq is a sequence of expressions (a b).
+type Seq []Expr
+
+// Look is a positive (&a) or negative (!a) lookahead that never
+// advances the scanner.
+type Look struct {
+	Not bool // true for negative (!) lookahead
+	E   Expr
+}
+
+// Quant is a quantified expression. A negative Max means unbounded.
+// Common quantities are printed with their short forms (?, *, +).
+type Quant struct {
+	E   Expr
+	Min int
+	Max int
+}
+
+// Ref is a reference to a rule, token, or class by its name.
+type Ref string
+
+// Lit is a literal string ('some').
+type Lit string
+
+// Point is a single unicode code point in one of the PEGN numeric
+// forms: 'u' unicode (u00AD), 'x' hexadecimal (x20), 'b' binary (b101),
+// or 'o' octal (o20).
+type Point struct {
+	R    rune
+	Form byte
+}
+
+// Range is an inclusive range of code points. Form is the same as for
+// Point with the addition of 'a' for alphanumeric ranges ([a-z], [0-9]).
+type Range struct {
+	Lo   rune
+	Hi   rune
+	Form byte
+}
+
+// Any matches any single rune (.).
+type Any struct{}
+
+// Capture marks the part of an expression to be captured as the value
+// of the node (< a >). Named captures (<:tag a >) also become nodes of
+// their own (see CaptureNode) with the Tag so that they can be
+// retrieved by name (see ast.Node.Capture).
+type Capture struct {
+	E   Expr
+	Tag string
+}
+
+// Replace matches the expression but captures the With text instead of
+// what was matched (a => 'b'). With cannot include single quotes.
+type Replace struct {
+	E    Expr
+	With string
+}
+
+// Inject captures the text without matching anything (^'a'). Text
+// cannot include single quotes.
+type Inject string
+
+func (e Choice) prec() int  { return precChoice }
+func (e Seq) prec() int     { return precSeq }
+func (e Look) prec() int    { return precPrefix }
+func (e Quant) prec() int   { return precPrefix }
+func (e Ref) prec() int     { return precPrimary }
+func (e Lit) prec() int     { return precPrimary }
+func (e Point) prec() int   { return precPrimary }
+func (e Range) prec() int   { return precPrimary }
+func (e Any) prec() int     { return precPrimary }
+func (e Capture) prec() int { return precPrimary }
+func (e Replace) prec() int { return precReplace }
+func (e Inject) prec() int  { return precPrimary }
+
+// wrap returns the string form of e in parenthesis if its precedence
+// is lower than min.
+func wrap(e Expr, min int) string {
+	if e.prec() < min {
+		return `(` + e.String() + `)`
+	}
+	return e.String()
+}
+
+func (e Choice) String() string {
+	list := make([]string, len(e))
+	for i, v := range e {
+		list[i] = wrap(v, precSeq)
+	}
+	return strings.Join(list, ` / `)
+}
+
+func (e Seq) String() string {
+	list := make([]string, len(e))
+	for i, v := range e {
+		list[i] = wrap(v, precReplace)
+	}
+	return strings.Join(list, ` `)
+}
+
+func (e Look) String() string {
+	if e.Not {
+		return `!` + wrap(e.E, precPrefix)
+	}
+	return `&` + wrap(e.E, precPrefix)
+}
+
+func (e Quant) String() string {
+	s := wrap(e.E, precPrimary)
+	switch {
+	case e.Min == 0 && e.Max == 1:
+		return s + `?`
+	case e.Min == 0 && e.Max < 0:
+		return s + `*`
+	case e.Min == 1 && e.Max < 0:
+		return s + `+`
+	case e.Min == e.Max:
+		return fmt.Sprintf(`%v{%v}`, s, e.Min)
+	case e.Max < 0:
+		return fmt.Sprintf(`%v{%v,}`, s, e.Min)
+	}
+	return fmt.Sprintf(`%v{%v,%v}`, s, e.Min, e.Max)
+}
+
+func (e Ref) String() string     { return string(e) }
+func (e Lit) String() string     { return `'` + string(e) + `'` }
+func (e Any) String() string     { return `.` }
+func (e Capture) String() string { return `<` + tag(e.Tag) + ` ` + e.E.String() + ` >` }
+func (e Inject) String() string  { return `^'` + string(e) + `'` }
+func (e Replace) String() string { return wrap(e.E, precPrefix) + ` => '` + e.With + `'` }
+func (e Point) String() string   { return point(e.R, e.Form) }
+
+func (e Range) String() string {
+	if e.Form == 'a' {
+		return `[` + string(e.Lo) + `-` + string(e.Hi) + `]`
+	}
+	return `[` + point(e.Lo, e.Form) + `-` + point(e.Hi, e.Form) + `]`
+}
+
+// tag returns the tag of a named capture as it is written (:tag) or
+// nothing if empty.
+func tag(a string) string {
+	if a == "" {
+		return ""
+	}
+	return `:` + a
+}
+
+// point returns the PEGN notation for a single code point in the
+// given form.
+func point(r rune, form byte) string {
+	switch form {
+	case 'x':
+		return `x` + strings.ToUpper(strconv.FormatInt(int64(r), 16))
+	case 'b':
+		return `b` + strconv.FormatInt(int64(r), 2)
+	case 'o':
+		return `o` + strconv.FormatInt(int64(r), 8)
+	}
+	return fmt.Sprintf(`u%04X`, r)
+}
+
+// Walk calls fn for e and every expression within it (depth-first,
+// preorder) until fn returns false for a given expression at which
+// point the expressions within it are skipped.
+func Walk(e Expr, fn func(e Expr) bool) {
+	if e == nil || !fn(e) {
+		return
+	}
+	switch v := e.(type) {
+	case Choice:
+		for _, i := range v {
+			Walk(i, fn)
+		}
+	case Seq:
+		for _, i := range v {
+			Walk(i, fn)
+		}
+	case Look:
+		Walk(v.E, fn)
+	case Quant:
+		Walk(v.E, fn)
+	case Capture:
+		Walk(v.E, fn)
+	case Replace:
+		Walk(v.E, fn)
+	}
+}
+
+// Refs returns the unique names referenced from within the expression
+// in the order in which they first appear.
+func Refs(e Expr) []string {
+	var list []string
+	seen := map[string]bool{}
+	Walk(e, func(e Expr) bool {
+		if r, is := e.(Ref); is && !seen[string(r)] {
+			seen[string(r)] = true
+			list = append(list, string(r))
+		}
+		return true
+	})
+	return list
+}
@@ -0,0 +1,27 @@
+package gr_test
+
+import (
+	"fmt"
+
+	"github.com/rwxrob/pegn/gr"
+)
+
+func ExampleExpr() {
+
+	e := gr.Seq{
+		gr.Quant{E: gr.Choice{gr.Lit(`a`), gr.Ref(`b`)}, Min: 1, Max: -1},
+		gr.Look{Not: true, E: gr.Range{Lo: 'a', Hi: 'z', Form: 'a'}},
+		gr.Capture{E: gr.Seq{gr.Point{R: 0x20, Form: 'x'}, gr.Any{}}},
+	}
+	fmt.Println(e)
+
+	// Output:
+	// ('a' / b)+ ![a-z] < x20 . >
+}
+
+func ExampleRefs() {
+	g := gr.MustRead(`Some <- Other (SP Other)* !LF`)
+	fmt.Println(gr.Refs(g.Rules[0].Expr))
+	// Output:
+	// [Other SP LF]
+}
@@ -0,0 +1,117 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+package gr
+
+import (
+	"fmt"
+
+	"github.com/rwxrob/pegn/model"
+)
+
+// Extract moves the expression (in PEGN notation) from the named rule
+// into a new simple rule (<-) with the given name defined just after
+// it and replaces every occurrence of the expression in every rule of
+// the grammar with a reference to the new rule returning the number of
+// occurrences replaced. This decomposes overgrown rules mechanically
+// without changing what anything matches. Occurrences are the same
+// expression (written the same way, see Expr.String) or, for
+// sequences, any run of the same expressions within a longer sequence.
+// The new rule has the next unused ID so that the types of existing
+// nodes do not change. Returns an error (changing nothing) if the rule
+// does not contain the expression or if the name is not a valid name
+// unused by any rule (or by Builtins).
+//
+//	n, err := g.Extract(`Pair`, `lower+ '='`, `Key`)
+func (g *Grammar) Extract(rule, expr, name string) (int, error) {
+	r := g.Rule(rule)
+	if r == nil {
+		return 0, fmt.Errorf(`extract: rule not defined: %q`, rule)
+	}
+	if !isName(name) {
+		return 0, fmt.Errorf(`extract: invalid name: %q`, name)
+	}
+	if err := g.conflict(name, nil); err != nil {
+		return 0, fmt.Errorf(`extract: %w`, err)
+	}
+	src, err := Read(name + ` <- ` + expr)
+	if err != nil {
+		return 0, fmt.Errorf(`extract: %w`, err)
+	}
+	x := src.Rules[0].Expr
+	if _, n := extracted(r.Expr, x, Ref(name)); n == 0 {
+		return 0, fmt.Errorf(`extract: %v not found in %v`, x, r.Name)
+	}
+
+	var count, id int
+	for _, u := range g.Rules {
+		if u.ID > id {
+			id = u.ID
+		}
+		e, n := extracted(u.Expr, x, Ref(name))
+		if n == 0 {
+			continue
+		}
+		u.Expr = e
+		u.PEGN = u.String()
+		count += n
+	}
+
+	nr := &Rule{Expr: x}
+	nr.ID, nr.Name, nr.Type = id+1, name, model.TypeOf(name)
+	nr.PEGN = nr.String()
+	i := g.index(r.Name) + 1
+	g.Rules = append(g.Rules[:i], append([]*Rule{nr}, g.Rules[i:]...)...)
+	return count, nil
+}
+
+// extracted returns the expression with every occurrence of x replaced
+// with the reference (see Extract) and the number replaced.
+func extracted(e, x Expr, ref Ref) (Expr, int) {
+	if e.String() == x.String() {
+		return ref, 1
+	}
+	var count int
+	sub := func(e Expr) Expr {
+		e, n := extracted(e, x, ref)
+		count += n
+		return e
+	}
+	switch v := e.(type) {
+	case Choice:
+		c := make(Choice, len(v))
+		for i, y := range v {
+			c[i] = sub(y)
+		}
+		return c, count
+	case Seq:
+		var s Seq
+		run, isrun := x.(Seq)
+		for i := 0; i < len(v); i++ {
+			if isrun && i+len(run) <= len(v) && Seq(v[i:i+len(run)]).String() == run.String() {
+				s = append(s, ref)
+				count++
+				i += len(run) - 1
+				continue
+			}
+			s = append(s, sub(v[i]))
+		}
+		if len(s) == 1 {
+			return s[0], count
+		}
+		return s, count
+	case Look:
+		v.E = sub(v.E)
+		return v, count
+	case Quant:
+		v.E = sub(v.E)
+		return v, count
+	case Capture:
+		v.E = sub(v.E)
+		return v, count
+	case Replace:
+		v.E = sub(v.E)
+		return v, count
+	}
+	return e, count
+}
@@ -0,0 +1,35 @@
+package gr_test
+
+import (
+	"fmt"
+
+	"github.com/rwxrob/pegn/gr"
+	"github.com/rwxrob/pegn/scanner"
+)
+
+func ExampleGrammar_Extract() {
+
+	g := gr.MustRead(`
+# settings
+Config <-- (lower+ '=' Value LF)*
+Value  <-- digit+ / lower+ '=' digit+`)
+
+	fmt.Println(g.Extract(`Config`, `lower+ '='`, `Key`))
+	fmt.Print(g)
+	fmt.Println(g.Parse(scanner.New("a=1\nb=c=2\n")))
+
+	fmt.Println(g.Extract(`Config`, `upper+`, `Caps`))
+	fmt.Println(g.Extract(`Config`, `upper+`, `Upper`))
+	fmt.Println(g.Extract(`Config`, `digit+`, `Value`))
+
+	// Output:
+	// 2 <nil>
+	// # settings
+	// Config <-- (Key Value LF)*
+	// Key    <- lower+ '='
+	// Value  <-- digit+ / Key digit+
+	// {"T":1,"N":[{"T":2,"V":"1"},{"T":2,"V":"c=2"}]}
+	// 0 extract: upper+ not found in Config
+	// 0 extract: Upper conflicts with builtin upper
+	// 0 extract: Value conflicts with Value (line 4)
+}
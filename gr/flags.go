@@ -0,0 +1,79 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+package gr
+
+import (
+	"fmt"
+	"strings"
+)
+
+// IfPrefix begins the Doc comment line making the rule after it
+// conditional on the named flags, all of which must be set (or not set
+// when prefixed with !) for the rule to be kept by Select. The same
+// rule may be defined more than once so long as every definition of it
+// is conditional. This allows one grammar source to be both strict (for
+// validation) and lenient (for ingesting messy documents).
+//
+//	# If: strict
+//	Sep <- ','
+//
+//	# If: !strict
+//	Sep <- SP* (',' / ';') SP*
+const IfPrefix = `# If:`
+
+// readIf sets the If flags of the rule from its Doc.
+func readIf(r *Rule) {
+	for _, d := range r.Doc {
+		if strings.HasPrefix(d, IfPrefix) {
+			r.If = append(r.If, strings.Fields(strings.TrimPrefix(d, IfPrefix))...)
+		}
+	}
+}
+
+// Selected returns true if the rule is unconditional or if every one of
+// its If flags holds for the given flags.
+func (r *Rule) Selected(flags ...string) bool {
+	set := map[string]bool{}
+	for _, f := range flags {
+		set[f] = true
+	}
+	for _, f := range r.If {
+		if strings.HasPrefix(f, `!`) {
+			if set[f[1:]] {
+				return false
+			}
+			continue
+		}
+		if !set[f] {
+			return false
+		}
+	}
+	return true
+}
+
+// Select returns a new grammar with only the rules selected by the
+// flags (see IfPrefix and Rule.Selected). Rules keep their IDs (which
+// are the same for every definition of a rule) so node types do not
+// depend on the flags. An error is returned if more than one definition
+// of the same rule is selected. Grammars with conditional rules should
+// always be selected before use since otherwise only the first
+// definition of each rule is ever used.
+func (g *Grammar) Select(flags ...string) (*Grammar, error) {
+	n := *g
+	n.Rules = make([]*Rule, 0, len(g.Rules))
+	seen := map[string]*Rule{}
+	for _, r := range g.Rules {
+		if !r.Selected(flags...) {
+			continue
+		}
+		key := strings.ToLower(r.Name)
+		if d, has := seen[key]; has {
+			return nil, fmt.Errorf(`line %v: %v also selected (see line %v)`,
+				r.Line, r.Name, d.Line)
+		}
+		seen[key] = r
+		n.Rules = append(n.Rules, r)
+	}
+	return &n, nil
+}
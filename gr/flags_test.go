@@ -0,0 +1,47 @@
+package gr_test
+
+import (
+	"fmt"
+
+	"github.com/rwxrob/pegn/gr"
+	"github.com/rwxrob/pegn/scanner"
+)
+
+func ExampleGrammar_Select() {
+
+	g := gr.MustRead(`
+List <-- Item (Sep Item)*
+
+# If: strict
+Sep <- ','
+
+# If: !strict
+Sep <- SP* (',' / ';') SP*
+
+Item <-- lower+`)
+
+	strict, _ := g.Select(`strict`)
+	lenient, _ := g.Select()
+
+	for _, in := range []string{`a,b,c`, `a, b ;c`} {
+		s := scanner.New(in)
+		strict.Scan(s, nil)
+		fmt.Print(s.Finished(), ` `)
+		s = scanner.New(in)
+		lenient.Scan(s, nil)
+		fmt.Println(s.Finished())
+	}
+
+	fmt.Println(strict.Rule(`Sep`), strict.Rule(`Item`).ID)
+	fmt.Println(lenient.Rule(`Sep`), lenient.Rule(`Item`).ID)
+
+	_, err := gr.Read("Sep <- ','\n\n# If: strict\nSep <- ';'")
+	fmt.Println(err)
+
+	// Output:
+	// true true
+	// false true
+	// Sep <- ',' 3
+	// Sep <- SP* (',' / ';') SP* 3
+	// line 4: duplicate rule "Sep" (see line 1)
+}
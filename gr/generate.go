@@ -0,0 +1,244 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+package gr
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/rwxrob/pegn/scanner"
+)
+
+// Generate returns a random sentence that should match the named rule
+// (or the first if empty) using rnd (or a new time-seeded source if
+// nil) to make every choice. Quantities are kept small and once
+// nesting gets deep the alternatives that finish the fastest are
+// chosen so that recursive rules always end. Alternatives that cannot
+// be generated at all (such as surrogate code points) are avoided.
+// Lookaheads within a sequence are honored by generating whatever
+// follows them again (a few times at most) until the lookahead is
+// satisfied. Since
+// lookahead and ordered choice are not fully accounted for, sentences
+// that do not match are possible, but these almost always point to
+// a genuine problem with the grammar (such as an alternative that can
+// never be reached because an earlier one always matches first).
+func (g *Grammar) Generate(rule string, rnd *rand.Rand) (string, error) {
+	if rule == "" && len(g.Rules) > 0 {
+		rule = g.Rules[0].Name
+	}
+	r := g.Lookup(rule)
+	if r == nil {
+		return "", fmt.Errorf(`rule not found: %q`, rule)
+	}
+	if rnd == nil {
+		rnd = rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+	gen := &generator{g: g, rnd: rnd, costs: map[string]int{}}
+	var out strings.Builder
+	gen.rule(&out, r)
+	return out.String(), gen.err
+}
+
+// maximum nesting of rules before the cheapest alternatives are taken
+const genDepth = 24
+
+// maximum number of times to retry after an unsatisfied lookahead
+const genRetries = 16
+
+// anything that cannot finish (left recursion) costs this much
+const genInfinite = 1 << 20
+
+type generator struct {
+	g     *Grammar
+	rnd   *rand.Rand
+	depth int
+	costs map[string]int
+	err   error
+}
+
+func (gen *generator) rule(out *strings.Builder, r *Rule) {
+	gen.depth++
+	gen.expr(out, r.Expr)
+	gen.depth--
+}
+
+func (gen *generator) expr(out *strings.Builder, e Expr) {
+	if gen.err != nil {
+		return
+	}
+	deep := gen.depth > genDepth
+
+	switch v := e.(type) {
+
+	case Seq:
+		for i, x := range v {
+			l, is := x.(Look)
+			if !is {
+				gen.expr(out, x)
+				continue
+			}
+			var rest strings.Builder
+			for n := 0; n < genRetries; n++ {
+				rest.Reset()
+				gen.expr(&rest, v[i+1:])
+				if gen.satisfies(l, rest.String()) {
+					break
+				}
+			}
+			out.WriteString(rest.String())
+			return
+		}
+
+	case Choice:
+		var alts []Expr
+		min := genInfinite
+		for _, x := range v {
+			c := gen.cost(x, map[string]bool{})
+			switch {
+			case c >= genInfinite:
+				continue
+			case deep && c < min:
+				alts, min = []Expr{x}, c
+			case !deep:
+				alts = append(alts, x)
+			}
+		}
+		if len(alts) == 0 {
+			alts = v
+		}
+		gen.expr(out, alts[gen.rnd.Intn(len(alts))])
+
+	case Quant:
+		max := v.Max
+		if max < 0 || max > v.Min+3 {
+			max = v.Min + 3
+		}
+		count := v.Min
+		if !deep {
+			count += gen.rnd.Intn(max - v.Min + 1)
+		}
+		for n := 0; n < count; n++ {
+			gen.expr(out, v.E)
+		}
+
+	case Capture:
+		gen.expr(out, v.E)
+
+	case Replace:
+		gen.expr(out, v.E)
+
+	case Ref:
+		r := gen.g.Lookup(string(v))
+		if r == nil {
+			gen.err = fmt.Errorf(`undefined: %v`, v)
+			return
+		}
+		gen.rule(out, r)
+
+	case Lit:
+		out.WriteString(string(v))
+
+	case Point:
+		out.WriteRune(v.R)
+
+	case Range:
+		hi := v.Hi
+		if hi-v.Lo > 0xFF {
+			hi = v.Lo + 0xFF
+		}
+		for n := 0; n < genRetries; n++ {
+			r := v.Lo + rune(gen.rnd.Intn(int(hi-v.Lo)+1))
+			if utf8.ValidRune(r) {
+				out.WriteRune(r)
+				return
+			}
+		}
+		if !utf8.ValidRune(v.Lo) {
+			out.WriteRune(v.Hi)
+			return
+		}
+		out.WriteRune(v.Lo)
+
+	case Any:
+		out.WriteRune(rune(0x21 + gen.rnd.Intn(0x5E)))
+	}
+}
+
+// satisfies returns true if the lookahead is satisfied by the text that
+// would follow it.
+func (gen *generator) satisfies(l Look, text string) bool {
+	m := newMachine(gen.g, scanner.New(text), nil)
+	m.quiet++
+	return m.expr(l.E) != l.Not
+}
+
+// cost returns the least number of terminals that must be generated for
+// the expression. Recursive references and code points that cannot be
+// encoded as UTF-8 (surrogates) are considered infinite.
+func (gen *generator) cost(e Expr, seen map[string]bool) int {
+	switch v := e.(type) {
+	case Seq:
+		var sum int
+		for _, x := range v {
+			sum += gen.cost(x, seen)
+			if sum > genInfinite {
+				return genInfinite
+			}
+		}
+		return sum
+	case Choice:
+		min := genInfinite
+		for _, x := range v {
+			if c := gen.cost(x, seen); c < min {
+				min = c
+			}
+		}
+		return min
+	case Quant:
+		if v.Min == 0 {
+			return 0
+		}
+		c := gen.cost(v.E, seen) * v.Min
+		if c > genInfinite {
+			return genInfinite
+		}
+		return c
+	case Look:
+		return 0
+	case Capture:
+		return gen.cost(v.E, seen)
+	case Replace:
+		return gen.cost(v.E, seen)
+	case Inject:
+		return 0
+	case Ref:
+		if c, has := gen.costs[string(v)]; has {
+			return c
+		}
+		r := gen.g.Lookup(string(v))
+		if r == nil || seen[r.Name] {
+			return genInfinite
+		}
+		seen[r.Name] = true
+		c := gen.cost(r.Expr, seen)
+		delete(seen, r.Name)
+		if c < genInfinite {
+			c++
+			gen.costs[string(v)] = c
+		}
+		return c
+	case Point:
+		if !utf8.ValidRune(v.R) {
+			return genInfinite
+		}
+	case Range:
+		if !utf8.ValidRune(v.Lo) && !utf8.ValidRune(v.Hi) {
+			return genInfinite
+		}
+	}
+	return 1
+}
@@ -0,0 +1,36 @@
+package gr_test
+
+import (
+	"fmt"
+	"math/rand"
+
+	"github.com/rwxrob/pegn/gr"
+)
+
+func ExampleGrammar_Generate() {
+
+	g := gr.MustRead(`
+List  <-- '(' Item (SP Item)* ')'
+Item  <-  Word / List
+Word  <-- !'nil' lower{1,3}`)
+
+	rnd := rand.New(rand.NewSource(3))
+	for i := 0; i < 3; i++ {
+		s, err := g.Generate(``, rnd)
+		if err != nil {
+			fmt.Println(err)
+			continue
+		}
+		x, _ := g.Explain(``, s)
+		fmt.Println(x == nil)
+	}
+
+	_, err := g.Generate(`Nope`, rnd)
+	fmt.Println(err)
+
+	// Output:
+	// true
+	// true
+	// true
+	// rule not found: "Nope"
+}
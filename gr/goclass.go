@@ -0,0 +1,93 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+package gr
+
+import (
+	"fmt"
+	"go/format"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/rwxrob/pegn/model"
+)
+
+// goClassSwitch is the most ranges of a class written as a switch by
+// GoClass. Classes with more are written as a table instead.
+const goClassSwitch = 8
+
+// GoClass returns Go source code for a function (ex: isAlpha) reporting
+// whether a rune is in the named class (defined by the grammar or in
+// Builtins) for generated parsers to call directly rather than through
+// a pegn.ClassFunc. The class must be statically known: nothing but
+// a choice of points, ranges, single rune literals, and other such
+// classes and tokens. Every rune matched is first gathered into sorted
+// ranges (joining those that overlap or touch) which are then written
+// as a switch with each case in order (so that most runes are decided
+// after a comparison or two) or, for classes with many ranges, as
+// a table searched with a binary search.
+func (g *Grammar) GoClass(name string) (string, error) {
+	r := g.Lookup(name)
+	if r == nil {
+		return "", fmt.Errorf(`class not found: %q`, name)
+	}
+	if r.Type != model.ClassType {
+		return "", fmt.Errorf(`not a class: %q`, name)
+	}
+	var t classTable
+	if !collect(g.Lookup, r.Expr, &t, map[string]bool{}) {
+		return "", fmt.Errorf(`class %q is not a static set of runes`, name)
+	}
+	t = t.merged()
+
+	fn := `is` + goExported(name)
+	var out strings.Builder
+	fmt.Fprintf(&out, "func %v(r rune) bool {\n", fn)
+
+	if len(t) <= goClassSwitch {
+		out.WriteString("switch {\n")
+		for _, rng := range t {
+			fmt.Fprintf(&out, "case r < %v:\nreturn false\n", goRune(rng.Lo))
+			fmt.Fprintf(&out, "case r <= %v:\nreturn true\n", goRune(rng.Hi))
+		}
+		out.WriteString("}\nreturn false\n}\n")
+	} else {
+		tbl := fn + `Table`
+		fmt.Fprintf(&out, "i, j := 0, len(%v)\n", tbl)
+		out.WriteString("for i < j {\nh := int(uint(i+j) >> 1)\n")
+		fmt.Fprintf(&out, "if %v[h][1] < r {\ni = h + 1\n} else {\nj = h\n}\n}\n", tbl)
+		fmt.Fprintf(&out, "return i < len(%v) && %v[i][0] <= r\n}\n\n", tbl, tbl)
+		fmt.Fprintf(&out, "var %v = [...][2]rune{\n", tbl)
+		for _, rng := range t {
+			fmt.Fprintf(&out, "{%v, %v},\n", goRune(rng.Lo), goRune(rng.Hi))
+		}
+		out.WriteString("}\n")
+	}
+
+	src, err := format.Source([]byte(out.String()))
+	if err != nil {
+		return "", err
+	}
+	// added after formatting which drops it from fragments
+	doc := fmt.Sprintf("// %v reports whether r is in the %v class:\n//\n//\t%v\n", fn, r.Name, r)
+	return doc + string(src), nil
+}
+
+// goExported returns the name with the first letter upper case.
+func goExported(name string) string {
+	if name == "" {
+		return name
+	}
+	return strings.ToUpper(name[:1]) + name[1:]
+}
+
+// goRune returns a Go rune literal for printable ASCII and a hex
+// integer for everything else (including surrogates that cannot be
+// written as literals at all).
+func goRune(r rune) string {
+	if r < unicode.MaxASCII && unicode.IsPrint(r) {
+		return strconv.QuoteRune(r)
+	}
+	return fmt.Sprintf(`0x%X`, r)
+}
@@ -0,0 +1,81 @@
+package gr_test
+
+import (
+	"fmt"
+
+	"github.com/rwxrob/pegn/gr"
+)
+
+func ExampleGrammar_GoClass() {
+
+	g := gr.MustRead(`
+odd  <- '1' / '3' / '5' / '7' / '9' / 'b' / 'd' / 'f' / 'h'
+pair <- 'ab'`)
+
+	src, err := g.GoClass(`word`)
+	fmt.Println(err)
+	fmt.Print(src)
+
+	src, err = g.GoClass(`odd`)
+	fmt.Println(err)
+	fmt.Print(src)
+
+	_, err = g.GoClass(`pair`)
+	fmt.Println(err)
+
+	// Output:
+	// <nil>
+	// // isWord reports whether r is in the word class:
+	// //
+	// //	word <- upper / lower / digit / UNDER
+	// func isWord(r rune) bool {
+	// 	switch {
+	// 	case r < '0':
+	// 		return false
+	// 	case r <= '9':
+	// 		return true
+	// 	case r < 'A':
+	// 		return false
+	// 	case r <= 'Z':
+	// 		return true
+	// 	case r < '_':
+	// 		return false
+	// 	case r <= '_':
+	// 		return true
+	// 	case r < 'a':
+	// 		return false
+	// 	case r <= 'z':
+	// 		return true
+	// 	}
+	// 	return false
+	// }
+	// <nil>
+	// // isOdd reports whether r is in the odd class:
+	// //
+	// //	odd <- '1' / '3' / '5' / '7' / '9' / 'b' / 'd' / 'f' / 'h'
+	// func isOdd(r rune) bool {
+	// 	i, j := 0, len(isOddTable)
+	// 	for i < j {
+	// 		h := int(uint(i+j) >> 1)
+	// 		if isOddTable[h][1] < r {
+	// 			i = h + 1
+	// 		} else {
+	// 			j = h
+	// 		}
+	// 	}
+	// 	return i < len(isOddTable) && isOddTable[i][0] <= r
+	// }
+	//
+	// var isOddTable = [...][2]rune{
+	// 	{'1', '1'},
+	// 	{'3', '3'},
+	// 	{'5', '5'},
+	// 	{'7', '7'},
+	// 	{'9', '9'},
+	// 	{'b', 'b'},
+	// 	{'d', 'd'},
+	// 	{'f', 'f'},
+	// 	{'h', 'h'},
+	// }
+	// class "pair" is not a static set of runes
+}
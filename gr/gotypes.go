@@ -0,0 +1,193 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+package gr
+
+import (
+	"fmt"
+	"go/format"
+	"strings"
+)
+
+// GoTypes returns Go source code for the package (pkg) with typed
+// wrappers over ast.Node for every node rule (<--) of the grammar so
+// that code working with parse output gets compile-time safety rather
+// than checking node types by hand. For each node rule (ex: Let) the
+// following are written:
+//
+//   - a constant of the rule name with the rule ID as its node type
+//   - a Node wrapper type embedding *ast.Node (ex: LetNode)
+//   - a constructor checking the node type (ex: AsLet)
+//   - an accessor for each node rule that can appear directly under it
+//
+// Accessors of node rules appearing at most once return the wrapper of
+// the first one found (with a nil Node if none) and are named after the
+// rule (ex: Name). Those that can appear more than once return a slice
+// of wrappers and end with List (ex: NameList). Nodes under a node rule
+// are those of node rules reached through any number of non-node (<-)
+// rules.
+func (g *Grammar) GoTypes(pkg string) string {
+	var out strings.Builder
+	out.WriteString("// Code generated from PEGN by gr.Grammar.GoTypes. DO NOT EDIT.\n")
+	out.WriteString(`// ` + g.BuildInfo().String() + "\n\n")
+	fmt.Fprintf(&out, "package %v\n\n", pkg)
+	out.WriteString("import \"github.com/rwxrob/pegn/ast\"\n\n")
+
+	var nodes []*Rule
+	for _, r := range g.Rules {
+		if r.Node && g.Rule(r.Name) == r {
+			nodes = append(nodes, r)
+		}
+	}
+
+	out.WriteString("// Node types (rule IDs).\nconst (\n")
+	for _, r := range nodes {
+		fmt.Fprintf(&out, "%v = %v\n", r.Name, r.ID)
+	}
+	out.WriteString(")\n")
+
+	for _, r := range nodes {
+		t := r.Name + `Node`
+		fmt.Fprintf(&out, "\n// %v is a node of type %v:\n//\n//\t%v\n", t, r.Name, r)
+		fmt.Fprintf(&out, "type %v struct{ *ast.Node }\n", t)
+		fmt.Fprintf(&out, "\n// As%v returns the node wrapped in %v and true if it is of type %v.\n",
+			r.Name, t, r.Name)
+		fmt.Fprintf(&out, "func As%v(n *ast.Node) (%v, bool) {\n", r.Name, t)
+		fmt.Fprintf(&out, "if n == nil || n.T != %v {\nreturn %v{}, false\n}\n", r.Name, t)
+		fmt.Fprintf(&out, "return %v{n}, true\n}\n", t)
+
+		u := &under{g: g, count: map[string]int{}}
+		u.expr(r.Expr, 1, map[string]bool{})
+		for _, c := range u.order {
+			ct := c + `Node`
+			if u.count[c] < 2 && !u.recursive {
+				fmt.Fprintf(&out, "\n// %v returns the first %v node under this one (with a nil Node if none).\n", c, c)
+				fmt.Fprintf(&out, "func (n %v) %v() %v { return %v{first(n.Node, %v)} }\n",
+					t, c, ct, ct, c)
+				continue
+			}
+			fmt.Fprintf(&out, "\n// %vList returns every %v node under this one.\n", c, c)
+			fmt.Fprintf(&out, "func (n %v) %vList() []%v {\n", t, c, ct)
+			fmt.Fprintf(&out, "var list []%v\nfor _, u := range all(n.Node, %v) {\n", ct, c)
+			fmt.Fprintf(&out, "list = append(list, %v{u})\n}\nreturn list\n}\n", ct)
+		}
+	}
+
+	out.WriteString(goTypesHelpers)
+
+	src, err := format.Source([]byte(out.String()))
+	if err != nil {
+		return out.String()
+	}
+	return string(src)
+}
+
+const goTypesHelpers = `
+// first returns the first node of type t under n (or nil).
+func first(n *ast.Node, t int) *ast.Node {
+	if n == nil {
+		return nil
+	}
+	for _, u := range n.Nodes() {
+		if u.T == t {
+			return u
+		}
+	}
+	return nil
+}
+
+// all returns every node of type t under n.
+func all(n *ast.Node, t int) []*ast.Node {
+	if n == nil {
+		return nil
+	}
+	var list []*ast.Node
+	for _, u := range n.Nodes() {
+		if u.T == t {
+			list = append(list, u)
+		}
+	}
+	return list
+}
+`
+
+// under counts how many times each node rule can appear directly under
+// a node (2 meaning more than once) in order of first appearance.
+type under struct {
+	g         *Grammar
+	order     []string
+	count     map[string]int
+	recursive bool // a non-node rule refers to itself
+}
+
+func (u *under) add(name string, n int) {
+	if _, has := u.count[name]; !has {
+		u.order = append(u.order, name)
+	}
+	if u.count[name] += n; u.count[name] > 2 {
+		u.count[name] = 2
+	}
+}
+
+// expr adds the node rules of the expression n times (2 meaning many).
+func (u *under) expr(e Expr, n int, seen map[string]bool) {
+	switch v := e.(type) {
+
+	case Seq:
+		for _, x := range v {
+			u.expr(x, n, seen)
+		}
+
+	case Choice:
+		// each alternative on its own, keeping the most of any of them
+		before := u.count
+		most := map[string]int{}
+		for _, x := range v {
+			u.count = map[string]int{}
+			for k, c := range before {
+				u.count[k] = c
+			}
+			u.expr(x, n, seen)
+			for k, c := range u.count {
+				if c > most[k] {
+					most[k] = c
+				}
+			}
+		}
+		u.count = most
+		for k, c := range before {
+			if c > most[k] {
+				most[k] = c
+			}
+		}
+
+	case Quant:
+		switch {
+		case v.Max == 0:
+		case v.Max == 1:
+			u.expr(v.E, n, seen)
+		default:
+			u.expr(v.E, 2, seen)
+		}
+
+	case Capture:
+		u.expr(v.E, n, seen)
+
+	case Replace:
+		u.expr(v.E, n, seen)
+
+	case Ref:
+		r := u.g.Lookup(string(v))
+		switch {
+		case r == nil:
+		case r.Node:
+			u.add(r.Name, n)
+		case seen[r.Name]:
+			u.recursive = true
+		default:
+			seen[r.Name] = true
+			u.expr(r.Expr, n, seen)
+			delete(seen, r.Name)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package gr_test
+
+import (
+	"fmt"
+
+	"github.com/rwxrob/pegn/gr"
+)
+
+func ExampleGrammar_GoTypes() {
+
+	g := gr.MustRead(`
+Pair <-- Key '=' Vals
+Key  <-- lower+
+Vals <- Val (',' Val)*
+Val  <-- digit+`)
+
+	fmt.Print(g.GoTypes(`calc`))
+
+	// Output:
+	// // Code generated from PEGN by gr.Grammar.GoTypes. DO NOT EDIT.
+	// // pegn:build version=(devel) spec=2023-01 grammar=sha256:07a144b9a0847db0766e4ae00cf365c0239ec12e9dff3f777b1fde2687624f0f
+	//
+	// package calc
+	//
+	// import "github.com/rwxrob/pegn/ast"
+	//
+	// // Node types (rule IDs).
+	// const (
+	// 	Pair = 1
+	// 	Key  = 2
+	// 	Val  = 4
+	// )
+	//
+	// // PairNode is a node of type Pair:
+	// //
+	// //	Pair <-- Key '=' Vals
+	// type PairNode struct{ *ast.Node }
+	//
+	// // AsPair returns the node wrapped in PairNode and true if it is of type Pair.
+	// func AsPair(n *ast.Node) (PairNode, bool) {
+	// 	if n == nil || n.T != Pair {
+	// 		return PairNode{}, false
+	// 	}
+	// 	return PairNode{n}, true
+	// }
+	//
+	// // Key returns the first Key node under this one (with a nil Node if none).
+	// func (n PairNode) Key() KeyNode { return KeyNode{first(n.Node, Key)} }
+	//
+	// // ValList returns every Val node under this one.
+	// func (n PairNode) ValList() []ValNode {
+	// 	var list []ValNode
+	// 	for _, u := range all(n.Node, Val) {
+	// 		list = append(list, ValNode{u})
+	// 	}
+	// 	return list
+	// }
+	//
+	// // KeyNode is a node of type Key:
+	// //
+	// //	Key <-- lower+
+	// type KeyNode struct{ *ast.Node }
+	//
+	// // AsKey returns the node wrapped in KeyNode and true if it is of type Key.
+	// func AsKey(n *ast.Node) (KeyNode, bool) {
+	// 	if n == nil || n.T != Key {
+	// 		return KeyNode{}, false
+	// 	}
+	// 	return KeyNode{n}, true
+	// }
+	//
+	// // ValNode is a node of type Val:
+	// //
+	// //	Val <-- digit+
+	// type ValNode struct{ *ast.Node }
+	//
+	// // AsVal returns the node wrapped in ValNode and true if it is of type Val.
+	// func AsVal(n *ast.Node) (ValNode, bool) {
+	// 	if n == nil || n.T != Val {
+	// 		return ValNode{}, false
+	// 	}
+	// 	return ValNode{n}, true
+	// }
+	//
+	// // first returns the first node of type t under n (or nil).
+	// func first(n *ast.Node, t int) *ast.Node {
+	// 	if n == nil {
+	// 		return nil
+	// 	}
+	// 	for _, u := range n.Nodes() {
+	// 		if u.T == t {
+	// 			return u
+	// 		}
+	// 	}
+	// 	return nil
+	// }
+	//
+	// // all returns every node of type t under n.
+	// func all(n *ast.Node, t int) []*ast.Node {
+	// 	if n == nil {
+	// 		return nil
+	// 	}
+	// 	var list []*ast.Node
+	// 	for _, u := range n.Nodes() {
+	// 		if u.T == t {
+	// 			list = append(list, u)
+	// 		}
+	// 	}
+	// 	return list
+	// }
+}
@@ -0,0 +1,201 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+/*
+Package gr (grammar) contains the in-memory model of a PEGN grammar
+(rules made of expressions) along with the means to read it from PEGN
+source and write it back out in PEGN and other grammar notations.
+
+Everything written from a grammar (PEGN, ANTLR, reports, build info)
+is deterministic: the same grammar always produces exactly the same
+bytes no matter how many times or on which system it is written. This
+makes it safe to commit generated output and to verify it with go
+generate in continuous integration.
+*/
+package gr
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rwxrob/pegn"
+	"github.com/rwxrob/pegn/model"
+)
+
+// Grammar is an ordered set of rules along with the optional meta
+// data from the PEGN header lines (see Meta in the PEGN spec).
+type Grammar struct {
+	Name      string   // Ident from meta header (ex: PEGN)
+	Home      string   // Home from meta header (ex: pegn.dev/spec)
+	Copyright string   // full copyright line (without leading '# ')
+	License   string   // SPDX license identifier
+	Includes  []string // paths from '# Include' lines
+	Rules     []*Rule  // in order of definition
+	Trailer   []string // comment lines following last rule
+
+	// Delegates are rules scanned by Go code (ex: pratt.Parser.Scan)
+	// instead of their expressions when interpreted (see ScanRule).
+	// Each rule must still be defined, usually with an equivalent (but
+	// slower) expression for everything else (PEGN, ANTLR, Generate).
+	// Delegates are never written or read as part of the grammar.
+	Delegates map[string]pegn.ScanFunc
+
+	// Islands are rules with everything they match parsed by another
+	// grammar (see Island). Like Delegates, they are never written or
+	// read as part of the grammar.
+	Islands map[string]Island
+
+	// Recovery skips input that fails to parse so that ParseEach can
+	// continue after it (ex: SkipLine). When nil, parsing stops at the
+	// first failure.
+	Recovery Recovery
+
+	// Actions are called whenever the rule of the same name matches
+	// (see OnMatch). Like Delegates, they are never written or read as
+	// part of the grammar.
+	Actions map[string]Action
+}
+
+// Rule is a single named definition within a Grammar. The model.Rule
+// is embedded so that rules can be used anywhere meta data is wanted.
+type Rule struct {
+	model.Rule
+	Node  bool          // defined with <-- (produces node)
+	Expr  Expr          // right side of the definition
+	Doc   []string      // comment and blank lines before definition
+	Note  string        // trailing comment within definition
+	Line  int           // line of definition in source (if read)
+	If    []string      // flags required to be selected (see Select)
+	Diag  string        // message reported when matched (see DiagPrefix)
+	Sev   pegn.Severity // of Diag (see WarningPrefix and InfoPrefix)
+	Entry bool          // may be parsed on its own (see EntryPrefix)
+}
+
+// String returns the rule definition in PEGN notation without
+// comments.
+func (r *Rule) String() string {
+	arrow := ` <- `
+	if r.Node {
+		arrow = ` <-- `
+	}
+	return r.Name + arrow + r.Expr.String()
+}
+
+// MarshalText fulfills encoding.TextMarshaler with the same PEGN
+// definition as String but with literals that cannot be quoted as they
+// are written as Grammar.PEGN does so that it can always be read back
+// again (see UnmarshalText).
+func (r *Rule) MarshalText() ([]byte, error) {
+	c := *r
+	c.Expr = quoted(r.Expr)
+	return []byte(c.String()), nil
+}
+
+// UnmarshalText fulfills encoding.TextUnmarshaler by reading a single
+// PEGN definition (see Read) into the rule replacing it completely.
+func (r *Rule) UnmarshalText(text []byte) error {
+	g, err := Read(string(text))
+	if err != nil {
+		return err
+	}
+	if len(g.Rules) != 1 {
+		return fmt.Errorf(`expected one definition but found %v`, len(g.Rules))
+	}
+	*r = *g.Rules[0]
+	return nil
+}
+
+// String returns the full grammar in canonical PEGN form. Meta data is
+// written first as header lines followed by every rule in order with
+// its Doc lines and Note (as a trailing comment). The arrows of rules
+// not separated by Doc lines are aligned. The result of any grammar
+// that was read (see Read) can always be passed to Read to get the same
+// Grammar back again. Use PEGN for grammars built or changed
+// programmatically.
+func (g *Grammar) String() string {
+	var out strings.Builder
+
+	if g.Name != "" {
+		out.WriteString(`# ` + g.Name + ` ` + g.Home + "\n")
+		if g.Copyright != "" {
+			out.WriteString(`# ` + g.Copyright + "\n")
+		}
+		if g.License != "" {
+			out.WriteString(`# SPDX-License-Identifier: ` + g.License + "\n")
+		}
+		for _, i := range g.Includes {
+			out.WriteString(`# Include ` + i + "\n")
+		}
+		if len(g.Rules) > 0 && (len(g.Rules[0].Doc) == 0 || g.Rules[0].Doc[0] != "") {
+			out.WriteString("\n")
+		}
+	}
+
+	var width int
+	for i, r := range g.Rules {
+		for _, d := range r.Doc {
+			out.WriteString(d + "\n")
+		}
+		if i == 0 || len(r.Doc) > 0 {
+			width = 0
+			for _, n := range g.Rules[i:] {
+				if n != r && len(n.Doc) > 0 {
+					break
+				}
+				if len(n.Name) > width {
+					width = len(n.Name)
+				}
+			}
+		}
+		def := r.String()
+		out.WriteString(r.Name + strings.Repeat(` `, width-len(r.Name)) + def[len(r.Name):])
+		if r.Note != "" {
+			out.WriteString(`  # ` + r.Note)
+		}
+		out.WriteString("\n")
+	}
+
+	for _, t := range g.Trailer {
+		out.WriteString(t + "\n")
+	}
+
+	return out.String()
+}
+
+// MarshalText fulfills encoding.TextMarshaler with the canonical PEGN
+// from PEGN (which can always be read back again, see UnmarshalText)
+// allowing grammars to be embedded naturally into YAML, TOML, and
+// command line flag values.
+func (g *Grammar) MarshalText() ([]byte, error) { return []byte(g.PEGN()), nil }
+
+// UnmarshalText fulfills encoding.TextUnmarshaler by reading the PEGN
+// text (see Read) and replacing the grammar completely.
+func (g *Grammar) UnmarshalText(text []byte) error {
+	n, err := Read(string(text))
+	if err != nil {
+		return err
+	}
+	*g = *n
+	return nil
+}
+
+// Rule returns the rule with the given name (case-insensitive) or nil
+// if not found.
+func (g *Grammar) Rule(name string) *Rule {
+	for _, r := range g.Rules {
+		if strings.EqualFold(r.Name, name) {
+			return r
+		}
+	}
+	return nil
+}
+
+// ID returns the ID of the rule with the given name (case-insensitive)
+// and false if not found. This is useful for resolving rule names into
+// node types (see ast.ReadArray).
+func (g *Grammar) ID(name string) (int, bool) {
+	if r := g.Rule(name); r != nil {
+		return r.ID, true
+	}
+	return 0, false
+}
@@ -0,0 +1,85 @@
+package gr_test
+
+import (
+	"fmt"
+
+	"github.com/rwxrob/pegn/gr"
+	"github.com/rwxrob/pegn/scanner"
+)
+
+func ExampleGrammar_String() {
+
+	g := gr.MustRead(`# SAMPLE example.com/sample
+# SPDX-License-Identifier: Apache-2.0
+Greeting <-- Hello   SP+ Name
+Hello    <-- 'hello'/'hi'   # just two
+
+# names are capitalized
+Name <-- upper
+    lower+
+Nick  <- lower+
+
+# trailing
+`)
+
+	fmt.Print(g)
+	fmt.Println(gr.MustRead(g.String()).String() == g.String())
+
+	// Output:
+	// # SAMPLE example.com/sample
+	// # SPDX-License-Identifier: Apache-2.0
+	//
+	// Greeting <-- Hello SP+ Name
+	// Hello    <-- 'hello' / 'hi'  # just two
+	//
+	// # names are capitalized
+	// Name <-- upper lower+
+	// Nick <- lower+
+	//
+	// # trailing
+	// true
+}
+
+func ExampleGrammar_MarshalText() {
+
+	g := new(gr.Grammar)
+	err := g.UnmarshalText([]byte(`Some <-- 'thing'`))
+	fmt.Println(err)
+
+	text, _ := g.MarshalText()
+	fmt.Print(string(text))
+
+	r := g.Rules[0]
+	fmt.Println(r.UnmarshalText([]byte(`Other <- 'one' / 'two'`)))
+	text, _ = r.MarshalText()
+	fmt.Println(string(text), r.Type)
+
+	fmt.Println(r.UnmarshalText([]byte("A <- 'a'\nB <- 'b'")))
+
+	// built programmatically with quotes and non-printables
+	q := &gr.Rule{Node: true, Expr: gr.Seq{gr.Lit("it's"), gr.Lit("\x00\ttab"), gr.Ref(`SP`)}}
+	q.Name = `Q`
+	g = &gr.Grammar{Rules: []*gr.Rule{q}}
+	text, _ = g.MarshalText()
+	fmt.Print(string(text))
+	back := new(gr.Grammar)
+	fmt.Println(back.UnmarshalText(text))
+	in := "it's\x00\ttab "
+	fmt.Println(g.ScanRule(`Q`, scanner.New(in), nil), back.ScanRule(`Q`, scanner.New(in), nil))
+	again, _ := back.MarshalText()
+	fmt.Println(string(again) == string(text))
+	text, _ = q.MarshalText()
+	fmt.Println(r.UnmarshalText(text))
+
+	// Output:
+	// <nil>
+	// Some <-- 'thing'
+	// <nil>
+	// Other <- 'one' / 'two' 0
+	// expected one definition but found 2
+	// Q <-- 'it' x27 's' x0 x9 'tab' SP
+	// <nil>
+	// true true
+	// true
+	// <nil>
+}
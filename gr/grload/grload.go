@@ -0,0 +1,156 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+/*
+Package grload loads grammars at runtime from a directory so that
+a deployed application can gain new document formats without being
+rebuilt. Two kinds of files are loaded:
+
+	*.pegn  PEGN source compiled with gr.Compile and interpreted
+	*.so    Go plugins (go build -buildmode=plugin) exporting Grammar
+
+PEGN files work everywhere and are the portable choice. Go plugins
+must be built with exactly the same Go version and dependencies as the
+application and only work where the standard plugin package does
+(Linux, FreeBSD, and macOS with cgo). A plugin must export a Grammar
+variable (*gr.Grammar) or function (func() *gr.Grammar) and may also
+export a BuildInfo string variable with the stamp line of the grammar
+(see gr.BuildInfo) which is checked when loaded. Every grammar loaded
+is checked (see gr.Grammar.Check) so that one that could never be
+executed (undefined rules and such) fails to load rather than failing
+later as if its input did not match.
+*/
+package grload
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"plugin"
+	"sort"
+	"strings"
+
+	"github.com/rwxrob/pegn/gr"
+)
+
+// Dir loads every grammar file (see File) directly within dir and
+// returns them keyed by name: the Name from the meta header of the
+// grammar if it has one or the base name of the file (without
+// extension) if not. Other files and directories are ignored. Files
+// are loaded in lexical order and the first error stops the loading.
+func Dir(dir string) (map[string]*gr.Grammar, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Name() < entries[j].Name()
+	})
+	loaded := map[string]*gr.Grammar{}
+	from := map[string]string{}
+	for _, e := range entries {
+		ext := filepath.Ext(e.Name())
+		if e.IsDir() || (ext != `.pegn` && ext != `.so`) {
+			continue
+		}
+		path := filepath.Join(dir, e.Name())
+		g, err := File(path)
+		if err != nil {
+			return nil, err
+		}
+		name := g.Name
+		if name == "" {
+			name = strings.TrimSuffix(e.Name(), ext)
+		}
+		if prev, has := from[name]; has {
+			return nil, fmt.Errorf(`%v: grammar %q already loaded from %v`,
+				path, name, prev)
+		}
+		loaded[name] = g
+		from[name] = path
+	}
+	return loaded, nil
+}
+
+// Names returns the names of the grammars (from Dir) in sorted order.
+// Always use it (rather than ranging over the map) when producing
+// output so that the output is the same every time.
+func Names(grammars map[string]*gr.Grammar) []string {
+	names := make([]string, 0, len(grammars))
+	for n := range grammars {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// File loads a single grammar from PEGN source (*.pegn, see
+// gr.Compile) or a Go plugin (*.so) depending on the extension of the
+// path.
+func File(path string) (*gr.Grammar, error) {
+	switch filepath.Ext(path) {
+	case `.pegn`:
+		byt, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		g, err := gr.Compile(string(byt))
+		if err != nil {
+			return nil, fmt.Errorf(`%v: %w`, path, err)
+		}
+		return g, nil
+	case `.so`:
+		return Plugin(path)
+	}
+	return nil, fmt.Errorf(`%v: unsupported grammar file type`, path)
+}
+
+// Plugin opens the Go plugin at path and returns its exported Grammar
+// once checked (see gr.Grammar.Check). If the plugin also exports
+// BuildInfo it must match the grammar and
+// the pegn version of the running application (see gr.BuildInfo.Check).
+func Plugin(path string) (*gr.Grammar, error) {
+	p, err := plugin.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	sym, err := p.Lookup(`Grammar`)
+	if err != nil {
+		return nil, fmt.Errorf(`%v: %w`, path, err)
+	}
+	var g *gr.Grammar
+	switch v := sym.(type) {
+	case **gr.Grammar:
+		g = *v
+	case *gr.Grammar:
+		g = v
+	case func() *gr.Grammar:
+		g = v()
+	default:
+		return nil, fmt.Errorf(`%v: Grammar must be *gr.Grammar or func() *gr.Grammar, not %T`, path, sym)
+	}
+	if g == nil {
+		return nil, fmt.Errorf(`%v: Grammar is nil`, path)
+	}
+	if err := g.Check(); err != nil {
+		return nil, fmt.Errorf(`%v: %w`, path, err)
+	}
+
+	sym, err = p.Lookup(`BuildInfo`)
+	if err != nil {
+		return g, nil
+	}
+	stamp, is := sym.(*string)
+	if !is {
+		return nil, fmt.Errorf(`%v: BuildInfo must be string, not %T`, path, sym)
+	}
+	info, err := gr.ParseBuildInfo(*stamp)
+	if err != nil {
+		return nil, fmt.Errorf(`%v: %w`, path, err)
+	}
+	if err := info.Check(g); err != nil {
+		return nil, fmt.Errorf(`%v: %w`, path, err)
+	}
+	return g, nil
+}
@@ -0,0 +1,56 @@
+package grload_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/rwxrob/pegn/gr/grload"
+)
+
+func ExampleDir() {
+
+	dir, _ := os.MkdirTemp("", `grload`)
+	defer os.RemoveAll(dir)
+
+	os.WriteFile(filepath.Join(dir, `greet.pegn`),
+		[]byte("Greeting <-- 'hello' / 'hi'\n"), 0600)
+	os.WriteFile(filepath.Join(dir, `csv.pegn`),
+		[]byte("# CSV example.com/csv\n\nFile <-- Row+\nRow <-- (!LF .)* LF\n"), 0600)
+	os.WriteFile(filepath.Join(dir, `README.md`), []byte(`ignored`), 0600)
+
+	grammars, err := grload.Dir(dir)
+	if err != nil {
+		fmt.Println(err)
+	}
+	fmt.Println(grload.Names(grammars))
+	fmt.Print(grammars[`greet`])
+	fmt.Println(grammars[`CSV`].Rules[0])
+
+	_, err = grload.File(filepath.Join(dir, `README.md`))
+	fmt.Println(err != nil)
+
+	// Output:
+	// [CSV greet]
+	// Greeting <-- 'hello' / 'hi'
+	// File <-- Row+
+	// true
+}
+
+func ExampleFile() {
+
+	dir, _ := os.MkdirTemp("", `grload`)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, `list.pegn`)
+	os.WriteFile(path, []byte("List <-- Item (',' Item)*\n"), 0600)
+
+	g, err := grload.File(path)
+	fmt.Println(g == nil, err != nil)
+	_, err = grload.Dir(dir)
+	fmt.Println(filepath.Base(err.Error()))
+
+	// Output:
+	// true true
+	// list.pegn: line 1: undefined: Item
+}
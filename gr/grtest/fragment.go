@@ -0,0 +1,67 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+package grtest
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/rwxrob/pegn/ast"
+	"github.com/rwxrob/pegn/gr"
+)
+
+// Fragment parses the input completely with the named entry rule (see
+// gr.Grammar.ParseEntry) and returns an error unless the tree is the
+// same as want (in the array form read by ast.ReadArray with rule names
+// for types) once every node of the trivia rules (white space,
+// comments) has been removed from both. This keeps tests of fragments
+// from breaking every time what is skipped between tokens changes. The
+// error lists only the edits from want to what was parsed (see
+// ast.Diff) rather than both trees.
+// Only node rules (<--) produce nodes so trivia must be node rules to be
+// ignored.
+//
+//	err := grtest.Fragment(g, `Sum`, `1 + 2`,
+//		`["Sum",[["Number","1"],["Plus","+"],["Number","2"]]]`, `WS`)
+func Fragment(g *gr.Grammar, rule, input, want string, trivia ...string) error {
+	skip := map[int]bool{}
+	for _, name := range trivia {
+		id, has := g.ID(name)
+		if !has {
+			return fmt.Errorf(`fragment: unknown trivia rule: %q`, name)
+		}
+		skip[id] = true
+	}
+	w, err := ast.ReadArray([]byte(want), g.ID)
+	if err != nil {
+		return fmt.Errorf(`fragment: want: %w`, err)
+	}
+	n, err := g.ParseEntry(rule, input)
+	if err != nil {
+		return fmt.Errorf(`fragment: %q: %w`, input, err)
+	}
+	edits := ast.Diff(strip(w, skip), strip(n, skip))
+	if len(edits) == 0 {
+		return nil
+	}
+	msg := fmt.Sprintf(`fragment: %q: parsed tree differs from want:`, input)
+	for _, e := range edits {
+		msg += "\n" + e.String()
+	}
+	return errors.New(msg)
+}
+
+// strip cuts every node (but the root) of the types from the tree.
+func strip(n *ast.Node, types map[int]bool) *ast.Node {
+	var cut []*ast.Node
+	n.WalkDeepPre(func(u *ast.Node) {
+		if u != n && types[u.T] {
+			cut = append(cut, u)
+		}
+	})
+	for _, u := range cut {
+		u.Cut()
+	}
+	return n
+}
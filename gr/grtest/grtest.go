@@ -0,0 +1,127 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+/*
+Package grtest provides property-based checks of grammars meant to be
+called from the tests of any package that ships a grammar (much like
+net/http/httptest). Each check returns an error so that it can be used
+from examples as well as from Test functions (see Run).
+*/
+package grtest
+
+import (
+	"fmt"
+	"math/rand"
+
+	"github.com/rwxrob/pegn/gr"
+)
+
+// RoundTrip returns an error unless writing the grammar out as PEGN and
+// reading it back in produces exactly the same PEGN again (format
+// following parse is the identity). The same is true of the PEGN
+// reconstructed from the model (see gr.Grammar.PEGN). Every rule is also
+// checked on its own.
+func RoundTrip(g *gr.Grammar) error {
+	for _, write := range []func(g *gr.Grammar) string{
+		(*gr.Grammar).String,
+		(*gr.Grammar).PEGN,
+	} {
+		want := write(g)
+		n, err := gr.Read(want)
+		if err != nil {
+			return fmt.Errorf(`round trip: %w`, err)
+		}
+		if got := write(n); got != want {
+			return fmt.Errorf("round trip: changed\n%v\nto\n%v", want, got)
+		}
+	}
+	for _, r := range g.Rules {
+		var n gr.Rule
+		if err := n.UnmarshalText([]byte(r.String())); err != nil {
+			return fmt.Errorf(`round trip: %v: %w`, r.Name, err)
+		}
+		if n.String() != r.String() {
+			return fmt.Errorf(`round trip: %v: changed to %v`, r, &n)
+		}
+	}
+	return nil
+}
+
+// Sentences generates n random sentences from the named rule (or the
+// first if empty) using the given seed (see gr.Grammar.Generate) and
+// returns an error for the first that the grammar fails to match
+// completely (parse following generate succeeds).
+func Sentences(g *gr.Grammar, rule string, n int, seed int64) error {
+	rnd := rand.New(rand.NewSource(seed))
+	for i := 0; i < n; i++ {
+		s, err := g.Generate(rule, rnd)
+		if err != nil {
+			return err
+		}
+		x, err := g.Explain(rule, s)
+		if err != nil {
+			return err
+		}
+		if x != nil {
+			return fmt.Errorf(`sentence %v (%q): %v`, i+1, s, x)
+		}
+	}
+	return nil
+}
+
+// Examples returns an error for the first Valid example of any rule
+// of the grammar that the rule fails to match completely or Invalid
+// example that it does (see gr.ValidPrefix).
+func Examples(g *gr.Grammar) error {
+	for _, r := range g.Rules {
+		for _, v := range r.Valid {
+			x, err := g.Explain(r.Name, v)
+			if err != nil {
+				return err
+			}
+			if x != nil {
+				return fmt.Errorf(`%v: valid example %q: %v`, r.Name, v, x)
+			}
+		}
+		for _, v := range r.Invalid {
+			x, err := g.Explain(r.Name, v)
+			if err != nil {
+				return err
+			}
+			if x == nil {
+				return fmt.Errorf(`%v: invalid example %q matched`, r.Name, v)
+			}
+		}
+	}
+	return nil
+}
+
+// TB is the subset of testing.TB used by Run so that this package need
+// not import testing into non-test builds.
+type TB interface {
+	Helper()
+	Errorf(format string, args ...any)
+}
+
+// DefaultSentences is the number of sentences generated by Run for
+// every rule.
+var DefaultSentences = 100
+
+// Run reports an error through t for every rule of the grammar that
+// fails RoundTrip, Examples, or Sentences (with DefaultSentences and
+// a seed of 1 so that failures can always be reproduced). Only the rules of the
+// grammar itself are checked, not the builtins.
+func Run(t TB, g *gr.Grammar) {
+	t.Helper()
+	if err := RoundTrip(g); err != nil {
+		t.Errorf(`%v`, err)
+	}
+	if err := Examples(g); err != nil {
+		t.Errorf(`%v`, err)
+	}
+	for _, r := range g.Rules {
+		if err := Sentences(g, r.Name, DefaultSentences, 1); err != nil {
+			t.Errorf(`%v: %v`, r.Name, err)
+		}
+	}
+}
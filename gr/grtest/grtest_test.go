@@ -0,0 +1,93 @@
+package grtest_test
+
+import (
+	"fmt"
+
+	"github.com/rwxrob/pegn/gr"
+	"github.com/rwxrob/pegn/gr/grtest"
+)
+
+func ExampleRoundTrip() {
+	fmt.Println(grtest.RoundTrip(gr.Builtins))
+	// Output:
+	// <nil>
+}
+
+func ExampleSentences() {
+
+	g := gr.MustRead(`
+Greeting <-- Hello SP+ Name
+Hello    <-- 'hello' / 'hi'
+Name     <-- upper lower+`)
+
+	fmt.Println(grtest.Sentences(g, ``, 100, 1))
+
+	// an alternative shadowed by an earlier one is never reached
+	g = gr.MustRead(`Keyword <-- 'in' / 'int'`)
+	fmt.Println(grtest.Sentences(g, ``, 100, 1))
+
+	// Output:
+	// <nil>
+	// sentence 1 ("int"): line 1, column 3: expected end of data but found 't'
+
+}
+
+func ExampleExamples() {
+
+	g := gr.MustRead(`
+# Valid: v1.2.3
+# Valid: "1.2.3"
+# Invalid: v1.2
+Version <-- 'v'? digit+ '.' digit+ '.' digit+`)
+
+	fmt.Println(grtest.Examples(g))
+
+	g = gr.MustRead(`
+# Valid: 1.2.3-rc1
+Version <-- digit+ '.' digit+ '.' digit+`)
+
+	fmt.Println(grtest.Examples(g))
+
+	// Output:
+	// <nil>
+	// Version: valid example "1.2.3-rc1": line 1, column 6: expected digit but found '-'
+}
+
+type reporter struct{}
+
+func (reporter) Helper() {}
+
+func (reporter) Errorf(format string, args ...any) {
+	fmt.Printf(format+"\n", args...)
+}
+
+func ExampleRun() {
+	grtest.Run(reporter{}, gr.Builtins)
+	// Output:
+}
+
+func ExampleFragment() {
+
+	g := gr.MustRead(`
+# Entry
+Sum     <-- Number (WS? Plus WS? Number)*
+
+# Entry
+Number  <-- digit+
+Plus    <-- '+'
+WS      <-- (SP / Comment)+
+Comment <-- '/*' (!'*/' unipoint)* '*/'`)
+
+	want := `["Sum",[["Number","1"],["Plus","+"],["Number","2"]]]`
+	fmt.Println(grtest.Fragment(g, `Sum`, `1+2`, want, `WS`))
+	fmt.Println(grtest.Fragment(g, `Sum`, `1 /* one */ + 2`, want, `WS`))
+	fmt.Println(grtest.Fragment(g, `Sum`, `1 + 3`, want, `WS`))
+	fmt.Println(grtest.Fragment(g, `Plus`, `+`, `["Plus","+"]`))
+
+	// Output:
+	// <nil>
+	// <nil>
+	// fragment: "1 + 3": parsed tree differs from want:
+	// change /2: {"T":2,"V":"2"} => {"T":2,"V":"3"}
+	// fragment: "+": not an entry rule: "Plus"
+}
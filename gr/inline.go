@@ -0,0 +1,126 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+package gr
+
+import "github.com/rwxrob/pegn/model"
+
+// Inlining decides which rules Inline replaces with their expressions
+// wherever they are referenced. Inlining trades the overhead of calling
+// a rule for larger rules: more work for code generators and the Go
+// compiler and (for generated parsers) bigger binaries. Zero for either
+// disables inlining entirely. See the BenchmarkGrammar_Inline_*
+// benchmarks for the effect on interpreted grammars (about ten percent
+// faster for rules with small separators).
+type Inlining struct {
+	Size  int // most expressions (see Size) in a rule to be inlined
+	Depth int // most rules inlined within one another (1 for direct only)
+}
+
+// DefaultInlining inlines only the smallest rules (such as a keyword
+// followed by spacing) and those directly within them.
+var DefaultInlining = Inlining{Size: 8, Depth: 2}
+
+// Size returns the number of expressions within (and including) the
+// expression (see Walk).
+func Size(e Expr) int {
+	var n int
+	Walk(e, func(Expr) bool { n++; return true })
+	return n
+}
+
+// Inline returns a new grammar with references to small rules replaced
+// by the expressions of those rules, leaving the original unchanged.
+// Only rules (not tokens or classes, which are already reported and
+// generated as terminals) that produce no nodes (<-) are inlined, and
+// never those that do something when matched as a rule: Delegates,
+// Islands, rules with Actions (see OnMatch), error productions and
+// other diagnostic rules (see DiagPrefix and WarningPrefix), rules
+// with flags (see If), and rules that are left recursive (directly or
+// through others, see ScanRule) since those are grown as rules rather
+// than matched as expressions. Inlining never changes what a grammar accepts,
+// reports, or calls. Inlined rules are still defined since they
+// may be referenced from rules that are not. Since an inlined rule is
+// no longer matched as a rule it is never reported by name when its
+// expression fails. Rules keep their IDs.
+func (g *Grammar) Inline(in Inlining) *Grammar {
+	n := *g
+	n.Rules = make([]*Rule, len(g.Rules))
+	for i, r := range g.Rules {
+		c := *r
+		c.Expr = g.inline(r.Expr, in, in.Depth)
+		c.PEGN = c.String()
+		n.Rules[i] = &c
+	}
+	return &n
+}
+
+// inlinable returns the rule referenced if it may be inlined.
+func (g *Grammar) inlinable(name string, in Inlining) *Rule {
+	r := g.Rule(name)
+	if r == nil || r.Node || r.Type != model.RuleType {
+		return nil
+	}
+	if r.Diag != "" || len(r.If) > 0 {
+		return nil
+	}
+	if _, has := g.Delegates[r.Name]; has {
+		return nil
+	}
+	if _, has := g.Islands[r.Name]; has {
+		return nil
+	}
+	if _, has := g.Actions[r.Name]; has {
+		return nil
+	}
+	if g.leftPath(r, r.Name, nil, map[string]bool{}) != nil {
+		return nil
+	}
+	if Size(r.Expr) > in.Size {
+		return nil
+	}
+	return r
+}
+
+func (g *Grammar) inline(e Expr, in Inlining, depth int) Expr {
+	if depth <= 0 || in.Size <= 0 {
+		return e
+	}
+	switch v := e.(type) {
+	case Ref:
+		if r := g.inlinable(string(v), in); r != nil {
+			return g.inline(r.Expr, in, depth-1)
+		}
+	case Choice:
+		var c Choice
+		for _, x := range v {
+			x = g.inline(x, in, depth)
+			if inner, is := x.(Choice); is {
+				c = append(c, inner...)
+				continue
+			}
+			c = append(c, x)
+		}
+		return c
+	case Seq:
+		var s Seq
+		for _, x := range v {
+			x = g.inline(x, in, depth)
+			if inner, is := x.(Seq); is {
+				s = append(s, inner...)
+				continue
+			}
+			s = append(s, x)
+		}
+		return s
+	case Look:
+		return Look{Not: v.Not, E: g.inline(v.E, in, depth)}
+	case Quant:
+		return Quant{E: g.inline(v.E, in, depth), Min: v.Min, Max: v.Max}
+	case Capture:
+		return Capture{E: g.inline(v.E, in, depth), Tag: v.Tag}
+	case Replace:
+		return Replace{E: g.inline(v.E, in, depth), With: v.With}
+	}
+	return e
+}
@@ -0,0 +1,116 @@
+package gr_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/rwxrob/pegn"
+	"github.com/rwxrob/pegn/curs"
+	"github.com/rwxrob/pegn/gr"
+	"github.com/rwxrob/pegn/scanner"
+)
+
+const inlineGrammar = `
+List  <-- Item (Comma Item)*
+Comma <- SP* ',' SP*
+Item  <-- Key Eq Val
+Eq    <- SP* '=' SP*
+Key   <-- lower+
+Val   <-- digit+`
+
+func ExampleGrammar_Inline() {
+
+	g := gr.MustRead(inlineGrammar)
+
+	for _, r := range g.Inline(gr.Inlining{Size: 6, Depth: 1}).Rules {
+		fmt.Println(r)
+	}
+	fmt.Println(gr.Size(g.Rule(`Comma`).Expr))
+
+	n := g.Inline(gr.DefaultInlining).ParseRule(`List`, scanner.New(`a = 1, b=2`))
+	n.Println()
+
+	// Output:
+	// List <-- Item (SP* ',' SP* Item)*
+	// Comma <- SP* ',' SP*
+	// Item <-- Key SP* '=' SP* Val
+	// Eq <- SP* '=' SP*
+	// Key <-- lower+
+	// Val <-- digit+
+	// 6
+	// {"T":1,"N":[{"T":3,"N":[{"T":5,"V":"a"},{"T":6,"V":"1"}]},{"T":3,"N":[{"T":5,"V":"b"},{"T":6,"V":"2"}]}]}
+}
+
+var inlineInput = strings.Repeat(`some = 1, other=22 , `, 10000) + `last=3`
+
+func benchmarkInline(b *testing.B, in gr.Inlining) {
+	g := gr.MustRead(inlineGrammar).Inline(in)
+	b.SetBytes(int64(len(inlineInput)))
+	for i := 0; i < b.N; i++ {
+		if !g.ScanRule(`List`, scanner.New(inlineInput), nil) {
+			b.Fatal(`failed to scan`)
+		}
+	}
+}
+
+func BenchmarkGrammar_Inline_none(b *testing.B) { benchmarkInline(b, gr.Inlining{}) }
+
+func BenchmarkGrammar_Inline_default(b *testing.B) {
+	benchmarkInline(b, gr.DefaultInlining)
+}
+
+func ExampleGrammar_Inline_unchanged() {
+
+	g := gr.MustRead(`
+Program <-- Stmt (LF Stmt)* (!. / Semi)
+Stmt    <-- Word (Comma Word)* Trailing?
+Word    <-- lower+
+Comma   <- ','
+
+# Warning: trailing blanks
+Trailing <- SP+
+
+# Error: semicolons are not allowed here
+Semi <- SP* ';'`)
+
+	var commas int
+	g.OnMatch(`Comma`, func(pegn.Scanner, curs.R) error { commas++; return nil })
+
+	for _, g := range []*gr.Grammar{g, g.Inline(gr.DefaultInlining)} {
+		commas = 0
+		for _, in := range []string{"ab;", "a,b,c  "} {
+			s := scanner.New(in)
+			fmt.Println(g.ScanRule(`Program`, s, nil), commas)
+			for _, err := range *s.Errors() {
+				fmt.Println(err)
+			}
+		}
+	}
+
+	// Output:
+	// false 0
+	// semicolons are not allowed here at 'b' 1-2
+	// true 2
+	// trailing blanks at 'c' 4-5
+	// false 0
+	// semicolons are not allowed here at 'b' 1-2
+	// true 2
+	// trailing blanks at 'c' 4-5
+}
+
+func ExampleGrammar_Inline_leftRecursive() {
+
+	g := gr.MustRead(`
+Bx <- Ax
+Ax <- Ax 'x' / 'y'`)
+
+	for _, g := range []*gr.Grammar{g, g.Inline(gr.DefaultInlining)} {
+		s := scanner.New("yxx")
+		fmt.Println(g.ScanRule(`Bx`, s, nil), s.RuneE(), g.Rule(`Bx`))
+	}
+
+	// Output:
+	// true 3 Bx <- Ax
+	// true 3 Bx <- Ax
+}
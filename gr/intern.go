@@ -0,0 +1,108 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+package gr
+
+import (
+	"sort"
+
+	"github.com/rwxrob/pegn/model"
+)
+
+// interner keeps a single copy of every string read (names and
+// literals) so that grammars repeating the same keywords in hundreds of
+// rules hold each only once.
+type interner map[string]string
+
+func (in interner) intern(a string) string {
+	if s, has := in[a]; has {
+		return s
+	}
+	in[a] = a
+	return a
+}
+
+// classTable is a sorted list of non-overlapping inclusive ranges of
+// every rune matched by a class.
+type classTable []Range
+
+// has returns true if the rune is within any of the ranges.
+func (t classTable) has(r rune) bool {
+	i := sort.Search(len(t), func(i int) bool { return t[i].Hi >= r })
+	return i < len(t) && t[i].Lo <= r
+}
+
+// classTables are the tables of every Builtins class that is nothing
+// but a choice of points, ranges, and other such classes or tokens. They
+// are shared by every grammar so that matching a builtin class is
+// a single lookup rather than a walk through every alternative.
+var classTables = tablesOf(Builtins)
+
+func tablesOf(g *Grammar) map[*Rule]classTable {
+	tables := map[*Rule]classTable{}
+	for _, r := range g.Rules {
+		if r.Type != model.ClassType || g.Rule(r.Name) != r {
+			continue
+		}
+		var t classTable
+		if collect(g.Rule, r.Expr, &t, map[string]bool{}) {
+			tables[r] = t.merged()
+		}
+	}
+	return tables
+}
+
+// collect adds every range matched by the expression to the table
+// returning false if it matches anything other than single runes.
+// References are resolved with lookup.
+func collect(lookup func(name string) *Rule, e Expr, t *classTable, seen map[string]bool) bool {
+	switch v := e.(type) {
+	case Choice:
+		for _, x := range v {
+			if !collect(lookup, x, t, seen) {
+				return false
+			}
+		}
+		return true
+	case Point:
+		*t = append(*t, Range{Lo: v.R, Hi: v.R})
+		return true
+	case Range:
+		*t = append(*t, Range{Lo: v.Lo, Hi: v.Hi})
+		return true
+	case Lit:
+		runes := []rune(string(v))
+		if len(runes) != 1 {
+			return false
+		}
+		*t = append(*t, Range{Lo: runes[0], Hi: runes[0]})
+		return true
+	case Ref:
+		r := lookup(string(v))
+		if r == nil || r.Type == model.RuleType || seen[r.Name] {
+			return false
+		}
+		seen[r.Name] = true // within this reference only (never recursive)
+		ok := collect(lookup, r.Expr, t, seen)
+		delete(seen, r.Name)
+		return ok
+	}
+	return false
+}
+
+// merged returns the table sorted with overlapping and adjacent ranges
+// joined.
+func (t classTable) merged() classTable {
+	sort.Slice(t, func(i, j int) bool { return t[i].Lo < t[j].Lo })
+	var out classTable
+	for _, r := range t {
+		if n := len(out); n > 0 && r.Lo <= out[n-1].Hi+1 {
+			if r.Hi > out[n-1].Hi {
+				out[n-1].Hi = r.Hi
+			}
+			continue
+		}
+		out = append(out, r)
+	}
+	return out
+}
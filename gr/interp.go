@@ -0,0 +1,632 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+package gr
+
+import (
+	"sort"
+
+	"github.com/rwxrob/pegn"
+	"github.com/rwxrob/pegn/ast"
+	"github.com/rwxrob/pegn/curs"
+	"github.com/rwxrob/pegn/model"
+)
+
+// Scan fulfills pegn.ScanFunc by interpreting the first entry rule of
+// the grammar (see Entries and ScanRule).
+func (g *Grammar) Scan(s pegn.Scanner, buf *[]rune) bool {
+	r := g.entry()
+	if r == nil {
+		return s.Expected(0)
+	}
+	return g.ScanRule(r.Name, s, buf)
+}
+
+// ScanRule interprets the named rule directly from the grammar
+// following the pegn.ScanFunc contract. Runes consumed are appended to
+// buf (if not nil) and removed again when backtracking. On failure
+// a single pegn.Error is pushed with the ID of the innermost rule
+// being matched at the farthest position reached and a cursor
+// pointing to that position. Unknown rule names always fail. Rules
+// with Delegates are scanned by them instead. Left recursive rules
+// (see Check) match as much as they can rather than never ending so
+// that grammars ported from tools that allow left recursion (Expr <--
+// Expr '+' Term / Term) can be read (see Read) and used as is.
+func (g *Grammar) ScanRule(name string, s pegn.Scanner, buf *[]rune) bool {
+	m := newMachine(g, s, buf)
+	m.acts = true
+	r := m.lookup(name)
+	if r == nil {
+		return s.Expected(0)
+	}
+	if len(g.Actions) > 0 {
+		defer keep(s, s.RuneE())()
+	}
+	if m.match(r) {
+		m.report()
+		return true
+	}
+	m.push(r)
+	return false
+}
+
+// machine holds the state of a single interpretation of a grammar
+// against a scanner including what is needed to report the farthest
+// failure.
+type machine struct {
+	g     *Grammar
+	s     pegn.Scanner
+	buf   *[]rune
+	rules map[string]*Rule
+	stack []*Rule
+	quiet int // failures are not recorded when > 0
+
+	record bool   // record spans of successful node rules
+	spans  []span // in preorder, dropped again when backtracking
+	depth  int    // current depth of node rules being recorded
+
+	tried func(c Choice, i int, ok bool) // called after every alternative
+
+	far     int      // farthest byte offset of any failure
+	farmark curs.R   // cursor at far
+	fstack  []*Rule  // deepest rules being matched at far
+	expect  []string // terminals expected at far
+	ruled   []string // rules tried at far that failed
+
+	diag     *Rule  // error production matched farthest (see DiagPrefix)
+	diagmark curs.R // cursor where diag matched
+
+	notes []Diagnostic // warnings and info (see WarningPrefix)
+
+	isle *islandErr // farthest island that failed (see Island)
+
+	acts    bool      // call Actions (see OnMatch)
+	pending []pending // actions to call once matched, dropped when backtracking
+	acterr  error     // returned by an action or limiter (stops everything)
+
+	last  interface{ SetLastMatch(b, e int) } // nil unless scanner records
+	trace tracer                              // nil unless scanner traces
+	limit limiter                             // nil unless scanner limits
+
+	tags map[string]*Rule // stand-in rules of named captures (see tagged)
+
+	leftrec map[*Rule]bool    // rules known to be left recursive (or not)
+	seeds   map[seedkey]*seed // growing left recursive rules
+}
+
+// tracer is implemented by scanners that trace (or profile) every rule
+// entered and exited (see scanner.S.TraceEnter). Only the rules of the
+// grammar itself are traced since the IDs of Builtins overlap them.
+type tracer interface {
+	TraceEnter(t int)
+	TraceExit(t, b int, ok bool)
+}
+
+// limiter is implemented by scanners that limit how deeply rules may
+// be nested within one another (ex: sandbox) since deeply nested input
+// would otherwise exhaust the Go stack, which cannot be recovered
+// from. CheckDepth is called before every rule is entered with the
+// number of rules that would then be nested. An error stops the scan
+// just as an error from an action does (see OnMatch).
+type limiter interface {
+	CheckDepth(depth int) error
+}
+
+// keeper is implemented by scanners that drop what has already been
+// scanned (see scanner.S.Keep) so that whatever node values and actions
+// need is kept until they are done.
+type keeper interface {
+	Keep(off int) int
+}
+
+// keep keeps everything from the offset on (see keeper) until the
+// function returned is called.
+func keep(s pegn.Scanner, off int) func() {
+	k, is := s.(keeper)
+	if !is {
+		return func() {}
+	}
+	prev := k.Keep(off)
+	return func() { k.Keep(prev) }
+}
+
+// span is the range of bytes matched by a node rule (<--) at a given
+// depth of nested node rules. Taken in order, spans contain everything
+// needed to create a node tree.
+type span struct {
+	rule   *Rule
+	b, e   int
+	ob, oe int // runes of output buffer (if rewriting, see Replace)
+	depth  int
+	sub    *ast.Node // parsed by island (see Island)
+}
+
+// value returns the text of the span from the output buffer (if
+// rewriting) or from the buffer of the scanner beginning at offset off.
+func (sp span) value(buf []byte, off int, out []rune) string {
+	if out != nil {
+		return string(out[sp.ob:sp.oe])
+	}
+	return string(buf[sp.b-off : sp.e-off])
+}
+
+// state is everything that must be restored when backtracking.
+type state struct {
+	c       curs.R
+	n       int // length of buf
+	spans   int // length of spans
+	notes   int // length of notes
+	pending int // length of pending
+}
+
+func newMachine(g *Grammar, s pegn.Scanner, buf *[]rune) *machine {
+	m := &machine{g: g, s: s, buf: buf, rules: map[string]*Rule{}}
+	m.leftrec = map[*Rule]bool{}
+	m.seeds = map[seedkey]*seed{}
+	m.far = -1
+	m.last, _ = s.(interface{ SetLastMatch(b, e int) })
+	m.trace, _ = s.(tracer)
+	m.limit, _ = s.(limiter)
+	return m
+}
+
+func (m *machine) lookup(name string) *Rule {
+	if r, has := m.rules[name]; has {
+		return r
+	}
+	r := m.g.Lookup(name)
+	m.rules[name] = r
+	return r
+}
+
+// push pushes the farthest failure (or the Diagnostic of any error
+// production matched) onto the scanner error stack.
+func (m *machine) push(top *Rule) {
+	if m.acterr != nil {
+		m.s.ErrPush(m.acterr)
+		return
+	}
+	if m.diag != nil {
+		m.s.ErrPush(Diagnostic{T: m.diag.ID, C: m.diagmark, Msg: m.diag.Diag})
+		return
+	}
+	if m.isle != nil && m.far <= m.isle.end {
+		m.s.ErrPush(m.isle.err)
+		return
+	}
+	id := top.ID
+	if n := len(m.fstack); n > 0 {
+		id = m.fstack[n-1].ID
+	}
+	if m.far < 0 {
+		m.s.Expected(id)
+		return
+	}
+	m.s.ErrPush(pegn.Error{T: id, C: m.farmark})
+}
+
+// expected records that the expression was expected at the cursor
+// (unless quiet). The description (see Expr.String) is only created
+// when recorded since most failures are not the farthest.
+func (m *machine) expected(c curs.R, e Expr) {
+	if m.quiet > 0 || c.E < m.far {
+		return
+	}
+	desc := e.String()
+	switch {
+	case c.E > m.far:
+		m.far = c.E
+		m.farmark = c
+		m.fstack = append([]*Rule{}, m.stack...)
+		m.expect = []string{desc}
+		m.ruled = nil
+	case c.E == m.far:
+		m.expect = appendUniq(m.expect, desc)
+		if len(m.stack) > len(m.fstack) {
+			m.fstack = append(m.fstack[:0], m.stack...)
+		}
+	}
+}
+
+func appendUniq(list []string, a string) []string {
+	for _, i := range list {
+		if i == a {
+			return list
+		}
+	}
+	return append(list, a)
+}
+
+// outlen returns the number of runes in the output buffer (if any).
+func (m *machine) outlen() int {
+	if m.buf == nil {
+		return 0
+	}
+	return len(*m.buf)
+}
+
+func (m *machine) add(r rune) {
+	if m.buf != nil {
+		*m.buf = append(*m.buf, r)
+	}
+}
+
+func (m *machine) save() state {
+	st := state{c: m.s.Mark(), spans: len(m.spans), notes: len(m.notes), pending: len(m.pending)}
+	if m.buf != nil {
+		st.n = len(*m.buf)
+	}
+	return st
+}
+
+// restore moves the scanner back and drops anything buffered or
+// recorded since the state was saved.
+func (m *machine) restore(st state) {
+	m.s.Goto(st.c)
+	if m.buf != nil {
+		*m.buf = (*m.buf)[:st.n]
+	}
+	m.spans = m.spans[:st.spans]
+	m.notes = m.notes[:st.notes]
+	m.pending = m.pending[:st.pending]
+}
+
+// rule matches a rule treating tokens and classes as terminals so that
+// they are reported by name rather than by what they contain.
+func (m *machine) rule(r *Rule) bool {
+	if m.acterr != nil {
+		return false
+	}
+	if m.limit != nil {
+		if err := m.limit.CheckDepth(len(m.stack) + 1); err != nil {
+			m.acterr = err
+			return false
+		}
+	}
+	start := m.s.Mark()
+	var st state
+	prod := r.Diag != "" && r.Sev == pegn.SevError // error production
+	act := m.action(r)
+	if prod {
+		st = m.save()
+	}
+	terminal := r.Type != model.RuleType
+	if terminal {
+		m.quiet++
+	}
+	i := -1
+	if m.record && r.Node {
+		i = len(m.spans)
+		m.spans = append(m.spans, span{rule: r, b: start.E, ob: m.outlen(), depth: m.depth})
+		m.depth++
+	}
+	m.stack = append(m.stack, r)
+	traced := m.trace != nil && !builtin[r]
+	if traced {
+		m.trace.TraceEnter(r.ID)
+	}
+	is, isle := m.g.Islands[r.Name]
+	var ist state
+	if isle {
+		ist = m.save()
+	}
+	var ok bool
+	if fn, has := m.g.Delegates[r.Name]; has {
+		ok = m.delegate(r, fn)
+	} else if t, has := classTables[r]; has {
+		ok = m.class(t)
+	} else if m.recursive(r) {
+		ok = m.grow(r)
+	} else {
+		ok = m.expr(r.Expr)
+	}
+	if ok && isle {
+		ok = m.island(r, is, ist)
+	}
+	m.stack = m.stack[:len(m.stack)-1]
+	if terminal {
+		m.quiet--
+	}
+	switch {
+	case ok && prod:
+		m.restore(st)
+		m.diagnose(r, start)
+		ok = false
+	case ok && r.Diag != "" && m.quiet == 0:
+		m.notes = append(m.notes, Diagnostic{T: r.ID, C: start, Msg: r.Diag, Sev: r.Sev})
+	}
+	if ok && act != nil {
+		c := curs.R{Buf: m.s.Bytes(), R: m.s.Rune(), B: start.E, E: m.s.RuneE()}
+		m.pending = append(m.pending, pending{act, c})
+	}
+	if ok && m.last != nil {
+		m.last.SetLastMatch(start.E, m.s.RuneE())
+	}
+	if traced {
+		m.trace.TraceExit(r.ID, start.E, ok)
+	}
+	if i >= 0 {
+		m.depth--
+		if ok {
+			m.spans[i].e, m.spans[i].oe = m.s.RuneE(), m.outlen()
+		} else {
+			m.spans = m.spans[:i]
+		}
+	}
+	if !ok && !prod {
+		if terminal {
+			m.expected(start, Ref(r.Name))
+		} else if m.quiet == 0 && start.E == m.far {
+			m.ruled = appendUniq(m.ruled, r.Name)
+		}
+	}
+	return ok
+}
+
+// report pushes the warnings and info of a successful scan in order
+// of position.
+func (m *machine) report() {
+	sort.SliceStable(m.notes, func(i, j int) bool { return m.notes[i].C.E < m.notes[j].C.E })
+	for _, d := range m.notes {
+		m.s.ErrPush(d)
+	}
+}
+
+// diagnose records that the error production matched at the cursor
+// (unless quiet) keeping only the farthest (or first of those as far).
+func (m *machine) diagnose(r *Rule, c curs.R) {
+	if m.quiet > 0 {
+		return
+	}
+	if m.diag == nil || c.E > m.diagmark.E {
+		m.diag, m.diagmark = r, c
+	}
+}
+
+// delegate scans the rule with fn treating it as a terminal. Errors
+// pushed by fn are dropped so that the farthest failure is reported the
+// same as for any other rule.
+func (m *machine) delegate(r *Rule, fn pegn.ScanFunc) bool {
+	st := m.save()
+	errs := m.s.Errors()
+	n := len(*errs)
+	ok := fn(m.s, m.buf)
+	*errs = (*errs)[:n]
+	if !ok {
+		m.restore(st)
+		m.expected(st.c, Ref(r.Name))
+	}
+	return ok
+}
+
+// recursive returns true if the rule is left recursive (see Check).
+func (m *machine) recursive(r *Rule) bool {
+	is, has := m.leftrec[r]
+	if !has {
+		is = m.g.leftPath(r, r.Name, nil, map[string]bool{}) != nil
+		m.leftrec[r] = is
+	}
+	return is
+}
+
+// seedkey is a left recursive rule at a given position.
+type seedkey struct {
+	rule *Rule
+	at   int
+}
+
+// seed is the longest match so far of a left recursive rule at
+// a position with everything needed to replay it.
+type seed struct {
+	ok      bool
+	end     curs.R
+	buf     []rune
+	spans   []span // with depth relative to that of the rule
+	pending []pending
+}
+
+// grow matches a left recursive rule by growing a seed (Warth et al.):
+// the rule first fails wherever it calls itself at the same position,
+// then the expression is tried again and again with each call to
+// itself matching what the last try matched until a try matches no
+// more than the last. Rules that are left recursive only through other
+// left recursive rules (each growing its own seed) are supported so
+// long as every rule of the cycle is reached from the first.
+func (m *machine) grow(r *Rule) bool {
+	key := seedkey{r, m.s.RuneE()}
+	if sd, has := m.seeds[key]; has {
+		return m.replay(sd)
+	}
+	st := m.save()
+	sd := new(seed)
+	m.seeds[key] = sd
+	for {
+		m.restore(st)
+		if !m.expr(r.Expr) || sd.ok && m.s.RuneE() <= sd.end.E {
+			break
+		}
+		sd.ok, sd.end = true, m.s.Mark()
+		if m.buf != nil {
+			sd.buf = append(sd.buf[:0], (*m.buf)[st.n:]...)
+		}
+		sd.spans = append(sd.spans[:0], m.spans[st.spans:]...)
+		for i := range sd.spans {
+			sd.spans[i].depth -= m.depth
+		}
+		sd.pending = append(sd.pending[:0], m.pending[st.pending:]...)
+	}
+	delete(m.seeds, key)
+	m.restore(st)
+	return m.replay(sd)
+}
+
+// replay moves to the end of the seed and adds what it matched
+// (including the actions pending).
+func (m *machine) replay(sd *seed) bool {
+	if !sd.ok {
+		return false
+	}
+	m.s.Goto(sd.end)
+	if m.buf != nil {
+		*m.buf = append(*m.buf, sd.buf...)
+	}
+	for _, sp := range sd.spans {
+		sp.depth += m.depth
+		m.spans = append(m.spans, sp)
+	}
+	m.pending = append(m.pending, sd.pending...)
+	return true
+}
+
+// class matches a single rune from the table of a builtin class.
+func (m *machine) class(t classTable) bool {
+	c := m.s.Mark()
+	if !m.s.Scan() || !t.has(m.s.Rune()) {
+		m.s.Goto(c)
+		return false
+	}
+	m.add(m.s.Rune())
+	return true
+}
+
+func (m *machine) expr(e Expr) bool {
+	if m.acterr != nil {
+		return false
+	}
+	st := m.save()
+	c := st.c
+
+	switch v := e.(type) {
+
+	case Seq:
+		for _, x := range v {
+			if !m.expr(x) {
+				m.restore(st)
+				return false
+			}
+		}
+		return true
+
+	case Choice:
+		for i, x := range v {
+			ok := m.expr(x)
+			if m.tried != nil {
+				m.tried(v, i, ok)
+			}
+			if ok {
+				return true
+			}
+		}
+		return false
+
+	case Quant:
+		var count int
+		for v.Max < 0 || count < v.Max {
+			before := m.s.RuneE()
+			if !m.expr(v.E) {
+				break
+			}
+			count++
+			if m.s.RuneE() == before {
+				if count < v.Min {
+					count = v.Min
+				}
+				break
+			}
+		}
+		if count < v.Min {
+			m.restore(st)
+			return false
+		}
+		return true
+
+	case Look:
+		m.quiet++
+		ok := m.expr(v.E)
+		m.quiet--
+		m.restore(st)
+		if ok == v.Not {
+			m.expected(c, v)
+			return false
+		}
+		return true
+
+	case Capture:
+		if v.Tag == "" || !m.record {
+			return m.expr(v.E)
+		}
+		i := len(m.spans)
+		m.spans = append(m.spans, span{rule: m.tagged(v.Tag), b: c.E, ob: m.outlen(), depth: m.depth})
+		m.depth++
+		ok := m.expr(v.E)
+		m.depth--
+		if !ok {
+			m.spans = m.spans[:i]
+			return false
+		}
+		m.spans[i].e, m.spans[i].oe = m.s.RuneE(), m.outlen()
+		return true
+
+	case Replace:
+		if !m.expr(v.E) {
+			return false
+		}
+		if m.buf != nil {
+			*m.buf = append((*m.buf)[:st.n], []rune(v.With)...)
+		}
+		return true
+
+	case Inject:
+		if m.buf != nil {
+			*m.buf = append(*m.buf, []rune(string(v))...)
+		}
+		return true
+
+	case Ref:
+		r := m.lookup(string(v))
+		if r == nil {
+			m.expected(c, v)
+			return false
+		}
+		return m.rule(r)
+
+	case Lit:
+		if !m.s.Peek(string(v)) {
+			m.expected(c, v)
+			return false
+		}
+		for range string(v) {
+			m.s.Scan()
+			m.add(m.s.Rune())
+		}
+		return true
+
+	case Point:
+		if !m.s.Scan() || m.s.Rune() != v.R {
+			m.restore(st)
+			m.expected(c, v)
+			return false
+		}
+		m.add(v.R)
+		return true
+
+	case Range:
+		if !m.s.Scan() || m.s.Rune() < v.Lo || m.s.Rune() > v.Hi {
+			m.restore(st)
+			m.expected(c, v)
+			return false
+		}
+		m.add(m.s.Rune())
+		return true
+
+	case Any:
+		if !m.s.Scan() {
+			m.expected(c, v)
+			return false
+		}
+		m.add(m.s.Rune())
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,136 @@
+package gr_test
+
+import (
+	"fmt"
+
+	"github.com/rwxrob/pegn"
+	"github.com/rwxrob/pegn/ast"
+	"github.com/rwxrob/pegn/gr"
+	"github.com/rwxrob/pegn/pratt"
+	"github.com/rwxrob/pegn/scanner"
+)
+
+func ExampleGrammar_ScanRule() {
+
+	g := gr.MustRead(`
+Greeting <-- Hello SP+ Name
+Hello    <-- 'hello' / 'hi'
+Name     <-- upper lower+`)
+
+	s := scanner.New(`hi  Rob!`)
+	buf := []rune{}
+	fmt.Println(g.ScanRule(`Greeting`, s, &buf))
+	fmt.Printf("%q\n", string(buf))
+	s.Print()
+
+	s = scanner.New(`hello rob`)
+	fmt.Println(g.Scan(s, nil))
+	s.Print()
+	fmt.Println(s.Errors())
+
+	// Output:
+	// true
+	// "hi  Rob"
+	// 'b' 6-7 "!"
+	// false
+	// '\x00' 0-0 "hello rob"
+	// &[expecting type 3 at ' ' 5-6]
+}
+
+func ExampleGrammar_Delegates() {
+
+	g := gr.MustRead(`
+Let  <-- Name SP* '=' SP* Expr
+Expr <-- Num (SP* ('+' / '*') SP* Num)*
+Name <-- lower+
+Num  <-- digit+`)
+
+	expr := pratt.New(2, pratt.Table{
+		Infix: []pratt.Op{
+			{T: 10, Op: `+`, Prec: 1},
+			{T: 11, Op: `*`, Prec: 2},
+		},
+	}, func(s pegn.Scanner) *ast.Node {
+		buf := make([]rune, 0, 8)
+		if !g.ScanRule(`Num`, s, &buf) {
+			return nil
+		}
+		return &ast.Node{T: 4, V: string(buf)}
+	})
+	expr.Skip = func(s pegn.Scanner, buf *[]rune) bool {
+		for s.Peek(` `) {
+			s.Scan()
+		}
+		return true
+	}
+	g.Delegates = map[string]pegn.ScanFunc{`Expr`: expr.Scan}
+
+	s := scanner.New(`x = 1 + 2*3;`)
+	buf := []rune{}
+	fmt.Println(g.ScanRule(`Let`, s, &buf))
+	fmt.Printf("%q\n", string(buf))
+	s.Print()
+
+	s = scanner.New(`x = y`)
+	fmt.Println(g.ScanRule(`Let`, s, nil))
+	fmt.Println(s.Errors())
+
+	// Output:
+	// true
+	// "x = 1 + 2*3"
+	// '3' 10-11 ";"
+	// false
+	// &[expecting type 2 at ' ' 3-4]
+}
+
+func ExampleGrammar_ScanRule_classes() {
+
+	g := gr.MustRead(`
+Words <-- word+ (ws+ word+)* punct?
+`)
+
+	for _, in := range []string{"some_thing  else\t2!", "some ~"} {
+		s := scanner.New(in)
+		buf := []rune{}
+		fmt.Println(g.ScanRule(`Words`, s, &buf), s.Finished())
+		fmt.Printf("%q\n", string(buf))
+	}
+
+	// Output:
+	// true true
+	// "some_thing  else\t2!"
+	// true false
+	// "some"
+}
+
+func ExampleGrammar_ScanRule_leftRecursive() {
+
+	g := gr.MustRead(`
+Expr <-- Expr '-' Term / Term
+Term <-- Term '*' Num / Num
+Num  <-- digit+`)
+
+	s := scanner.New(`10-2*3-4`)
+	buf := []rune{}
+	fmt.Println(g.ScanRule(`Expr`, s, &buf), string(buf))
+
+	s = scanner.New(`10-2*3-4`)
+	g.ParseRule(`Expr`, s).Println()
+
+	// indirect
+	g = gr.MustRead(`
+List <-- Item / Num
+Item <- List ',' Num
+Num  <-- digit+`)
+	g.ParseRule(`List`, scanner.New(`1,2,3`)).Println()
+
+	g, err := gr.Compile(`Expr <-- Expr '-' Num / Num
+Num <-- digit+`)
+	fmt.Println(err, g.Warnings())
+
+	// Output:
+	// true 10-2*3-4
+	// {"T":1,"N":[{"T":1,"N":[{"T":1,"N":[{"T":2,"N":[{"T":3,"V":"10"}]}]},{"T":2,"N":[{"T":2,"N":[{"T":3,"V":"2"}]},{"T":3,"V":"3"}]}]},{"T":2,"N":[{"T":3,"V":"4"}]}]}
+	// {"T":1,"N":[{"T":1,"N":[{"T":1,"N":[{"T":3,"V":"1"}]},{"T":3,"V":"2"}]},{"T":3,"V":"3"}]}
+	// <nil> [line 1: left recursive: Expr -> Expr]
+}
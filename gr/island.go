@@ -0,0 +1,86 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+package gr
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/rwxrob/pegn"
+	"github.com/rwxrob/pegn/ast"
+	"github.com/rwxrob/pegn/scanner"
+)
+
+// Island is another grammar (an embedded language) that parses
+// everything matched by a rule of this one (see Grammar.Islands) such
+// as the JSON within a fenced block of Markdown. The rule matches as
+// usual (finding where the island ends) and then the island must
+// parse exactly what it matched with its entry rule (see Entries) or
+// the rule fails. The tree parsed by the island replaces any nodes the
+// rule itself produced with every node of it having the Origin so
+// that the node types of the island (which overlap those of this
+// grammar) can be told apart. Events reports the island as a single
+// event of the rule.
+type Island struct {
+	Grammar *Grammar
+	Rule    string      // entry rule of Grammar (first entry if empty)
+	Origin  *ast.Origin // of island nodes (Grammar.Name if nil)
+}
+
+// islandErr is the failure of an island parse (the farthest kept). It
+// is reported rather than the farthest failure unless that is beyond
+// the end of what the island was given.
+type islandErr struct {
+	err error
+	pos int // of failure within island
+	end int // of everything island was given
+}
+
+// island parses everything matched by the rule since the state was
+// saved with the island. On success the spans recorded since are
+// replaced with one of the tree parsed. Otherwise, everything is
+// restored and the error of the island kept to be reported (see push).
+func (m *machine) island(r *Rule, is Island, st state) bool {
+	b, e := st.c.E, m.s.RuneE()
+	var sub *scanner.S
+	if ss, can := m.s.(interface{ SubScanner(b, e int) *scanner.S }); can {
+		sub = ss.SubScanner(b, e)
+	} else {
+		off := offset(m.s)
+		sub = scanner.New((*m.s.Bytes())[b-off : e-off])
+	}
+	o := is.Origin
+	if o == nil {
+		o = ast.Intern(is.Grammar.Name, ``)
+	}
+	name := is.Rule
+	if name == `` {
+		if entry := is.Grammar.entry(); entry != nil {
+			name = entry.Name
+		}
+	}
+	n := is.Grammar.ParseRule(name, sub)
+	if n == nil || !sub.Finished() {
+		var err error = pegn.Error{T: r.ID, C: sub.Mark()}
+		if errs := *sub.Errors(); n == nil && len(errs) > 0 {
+			err = errs[len(errs)-1]
+		}
+		err = fmt.Errorf(`%v: %w`, o, err)
+		var pe pegn.Error
+		pos := e
+		if errors.As(err, &pe) {
+			pos = pe.C.E
+		}
+		if m.isle == nil || pos > m.isle.pos {
+			m.isle = &islandErr{err, pos, e}
+		}
+		m.restore(st)
+		return false
+	}
+	n.SetOrigin(o)
+	if m.record {
+		m.spans = append(m.spans[:st.spans], span{rule: r, b: b, e: e, depth: m.depth, sub: n})
+	}
+	return true
+}
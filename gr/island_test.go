@@ -0,0 +1,46 @@
+package gr_test
+
+import (
+	"fmt"
+
+	"github.com/rwxrob/pegn/ast"
+	"github.com/rwxrob/pegn/gr"
+	"github.com/rwxrob/pegn/scanner"
+)
+
+func ExampleIsland() {
+
+	doc := gr.MustCompile(`
+Doc   <-- (Fence / Line)*
+Fence <-- '~~~json' LF Body '~~~' LF
+Body  <-  (!'~~~' unipoint)*
+Line  <-- !'~~~' (!LF unipoint)* LF`)
+
+	json := gr.MustCompile(`
+# Ident: json
+List  <-- '[' WS? (Num (WS? ',' WS? Num)*)? WS? ']' WS?
+Num   <-- digit+
+WS    <-  (SP / LF)+`)
+
+	doc.Islands = map[string]gr.Island{
+		`Body`: {Grammar: json, Origin: ast.Intern(`json`, `v1`)},
+	}
+
+	s := scanner.New("intro\n~~~json\n[1, 22]\n~~~\n")
+	n := doc.Parse(s)
+	n.WalkDeepPre(func(n *ast.Node) {
+		fmt.Printf("%v %v %q\n", n.O, n.T, n.V)
+	})
+
+	x, _ := doc.Explain(``, "intro\n~~~json\n[1, x]\n~~~\n")
+	fmt.Println(x)
+
+	// Output:
+	// <nil> 1 ""
+	// <nil> 4 "intro\n"
+	// <nil> 2 ""
+	// json@v1 1 ""
+	// json@v1 2 "1"
+	// json@v1 2 "22"
+	// line 3, column 5: json@v1: expecting type 2 at ' ' 17-18
+}
@@ -0,0 +1,138 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+package gr
+
+import (
+	"fmt"
+	"unicode"
+)
+
+// Lenience names the rules of a grammar to relax (see Lenient) when
+// ingesting messy real-world documents against a strict grammar.
+type Lenience struct {
+	Space    []string // rules that may be followed by any blanks (blank*)
+	Optional []string // rules that may be missing entirely (ex: terminators)
+	Fold     []string // rules whose literals and points match in any case
+}
+
+// Lenient returns a new grammar with the rules named by the Lenience
+// relaxed, leaving the original unchanged. Each relaxation rewrites
+// only the definition of the rule itself (not the rules it refers to)
+// so list every rule to be relaxed. Relaxed rules keep their IDs so
+// parse output has the same node types either way. An error is
+// returned if any rule named is not defined by the grammar.
+func (g *Grammar) Lenient(l Lenience) (*Grammar, error) {
+	n := *g
+	n.Rules = append([]*Rule{}, g.Rules...)
+
+	relax := func(names []string, fn func(e Expr) Expr) error {
+		for _, name := range names {
+			i := n.index(name)
+			if i < 0 {
+				return fmt.Errorf(`rule not found: %q`, name)
+			}
+			r := *n.Rules[i]
+			r.Expr = fn(r.Expr)
+			r.PEGN = r.String()
+			n.Rules[i] = &r
+		}
+		return nil
+	}
+
+	if err := relax(l.Fold, fold); err != nil {
+		return nil, err
+	}
+	err := relax(l.Space, func(e Expr) Expr {
+		return Seq{e, Quant{E: Ref(`blank`), Min: 0, Max: -1}}
+	})
+	if err != nil {
+		return nil, err
+	}
+	err = relax(l.Optional, func(e Expr) Expr {
+		return Quant{E: e, Min: 0, Max: 1}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &n, nil
+}
+
+// index returns the index of the first definition of the named rule
+// (case-insensitive) or -1.
+func (g *Grammar) index(name string) int {
+	r := g.Rule(name)
+	for i, d := range g.Rules {
+		if d == r {
+			return i
+		}
+	}
+	return -1
+}
+
+// fold returns the expression with every literal and point matching in
+// any case.
+func fold(e Expr) Expr {
+	switch v := e.(type) {
+	case Choice:
+		c := make(Choice, len(v))
+		for i, x := range v {
+			c[i] = fold(x)
+		}
+		return c
+	case Seq:
+		s := make(Seq, len(v))
+		for i, x := range v {
+			s[i] = fold(x)
+		}
+		return s
+	case Quant:
+		v.E = fold(v.E)
+		return v
+	case Look:
+		v.E = fold(v.E)
+		return v
+	case Capture:
+		v.E = fold(v.E)
+		return v
+	case Replace:
+		v.E = fold(v.E)
+		return v
+	case Point:
+		lo, up := unicode.ToLower(v.R), unicode.ToUpper(v.R)
+		if lo == up {
+			return v
+		}
+		return Choice{Point{R: lo, Form: v.Form}, Point{R: up, Form: v.Form}}
+	case Lit:
+		return foldLit(string(v))
+	}
+	return e
+}
+
+// foldLit returns a sequence of the literal with every cased rune
+// replaced by a choice of lower or upper case (keeping runs of uncased
+// runes together as literals).
+func foldLit(a string) Expr {
+	var s Seq
+	var run []rune
+	for _, r := range a {
+		lo, up := unicode.ToLower(r), unicode.ToUpper(r)
+		if lo == up {
+			run = append(run, r)
+			continue
+		}
+		if len(run) > 0 {
+			s = append(s, Lit(run))
+			run = nil
+		}
+		s = append(s, Choice{Lit(string(lo)), Lit(string(up))})
+	}
+	if len(run) > 0 {
+		s = append(s, Lit(run))
+	}
+	if len(s) == 1 {
+		return s[0]
+	}
+	return s
+}
@@ -0,0 +1,46 @@
+package gr_test
+
+import (
+	"fmt"
+
+	"github.com/rwxrob/pegn/gr"
+	"github.com/rwxrob/pegn/scanner"
+)
+
+func ExampleGrammar_Lenient() {
+
+	g := gr.MustRead(`
+Doc   <-- (Stmt EOL)+ !.
+Stmt  <-- Key '=' Value Semi
+Key   <-- 'name' / 'id'
+Value <-- alnum+
+Semi  <-  ';'
+EOL   <-  LF`)
+
+	l, err := g.Lenient(gr.Lenience{
+		Space:    []string{`Value`, `Semi`},
+		Optional: []string{`Semi`},
+		Fold:     []string{`Key`},
+	})
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	for _, r := range l.Rules[1:5] {
+		fmt.Println(r)
+	}
+
+	in := "NAME=pegn  \nid=42 ;  \n"
+	fmt.Println(g.Scan(scanner.New(in), nil), l.Scan(scanner.New(in), nil))
+
+	_, err = g.Lenient(gr.Lenience{Fold: []string{`Nope`}})
+	fmt.Println(err)
+
+	// Output:
+	// Stmt <-- Key '=' Value Semi
+	// Key <-- ('n' / 'N') ('a' / 'A') ('m' / 'M') ('e' / 'E') / ('i' / 'I') ('d' / 'D')
+	// Value <-- alnum+ blank*
+	// Semi <- (';' blank*)?
+	// false true
+	// rule not found: "Nope"
+}
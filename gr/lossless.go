@@ -0,0 +1,62 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+package gr
+
+import (
+	"github.com/rwxrob/pegn"
+	"github.com/rwxrob/pegn/ast"
+)
+
+// TriviaNode is the type of the nodes of text matched outside of any
+// node by ParseLossless (see ast.TriviaNode).
+const TriviaNode = ast.TriviaNode
+
+// ParseLossless parses the named rule as ParseRule does but returns
+// a concrete syntax tree instead: everything matched that did not
+// become a node of its own (whitespace, comments, delimiters) is kept
+// as a TriviaNode before, between, or after the nodes of the node it
+// was matched within, and every value is the source text itself (even
+// for rules that change it, see Replace) so that the Source of the tree
+// is exactly the input matched, byte for byte.
+func (g *Grammar) ParseLossless(name string, s pegn.Scanner) *ast.Node {
+	n := g.ParseRule(name, s)
+	if n == nil {
+		return nil
+	}
+	trivia(n, *s.Bytes(), offset(s))
+	return n
+}
+
+// trivia adds a TriviaNode for every gap between the nodes under n (and
+// all of theirs) and sets the value of every node without any to its
+// source. The buffer begins at offset off. Nodes not within the bytes
+// of n (not parsed from the same source) are left as they are.
+func trivia(n *ast.Node, buf []byte, off int) {
+	src := func(b, e int) *ast.Node {
+		return &ast.Node{T: TriviaNode, V: string(buf[b-off : e-off]), B: b, E: e}
+	}
+	nodes := n.Nodes()
+	if len(nodes) == 0 {
+		n.V = src(n.B, n.E).V
+		return
+	}
+	at := n.B
+	for _, u := range nodes {
+		if u.B < at || u.E < u.B || u.E > n.E {
+			return
+		}
+		at = u.E
+	}
+	at = n.B
+	for _, u := range nodes {
+		if u.B > at {
+			u.InsertBefore(src(at, u.B))
+		}
+		trivia(u, buf, off)
+		at = u.E
+	}
+	if at < n.E {
+		n.Append(src(at, n.E))
+	}
+}
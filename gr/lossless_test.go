@@ -0,0 +1,29 @@
+package gr_test
+
+import (
+	"fmt"
+
+	"github.com/rwxrob/pegn/gr"
+	"github.com/rwxrob/pegn/scanner"
+)
+
+func ExampleGrammar_ParseLossless() {
+
+	g := gr.MustCompile(`
+List  <-- '(' SP* Item (SP* ',' SP* Item)* SP* ')'
+Item  <-- lower+
+`)
+	in := `( ab ,cd,  ef )`
+
+	n := g.ParseRule(`List`, scanner.New(in))
+	fmt.Println(n)
+
+	n = g.ParseLossless(`List`, scanner.New(in))
+	fmt.Println(n)
+	fmt.Println(n.Source() == in)
+
+	// Output:
+	// {"T":1,"N":[{"T":2,"V":"ab"},{"T":2,"V":"cd"},{"T":2,"V":"ef"}]}
+	// {"T":1,"N":[{"T":-2147483646,"V":"( "},{"T":2,"V":"ab"},{"T":-2147483646,"V":" ,"},{"T":2,"V":"cd"},{"T":-2147483646,"V":",  "},{"T":2,"V":"ef"},{"T":-2147483646,"V":" )"}]}
+	// true
+}
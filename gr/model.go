@@ -0,0 +1,131 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+package gr
+
+import "github.com/rwxrob/pegn/model"
+
+// Model is the full structure of a grammar (see Grammar.Model) as
+// plain data with JSON field names so that external tools
+// (visualizers, converters, analyzers, in any language) can work with
+// grammars without reading PEGN themselves. Unlike Grammar (which
+// marshals rules as PEGN text) every expression is a ModelExpr of
+// a given Kind. Fields are only ever added to the model, never changed
+// or removed.
+type Model struct {
+	Name      string       `json:"name,omitempty"`
+	Home      string       `json:"home,omitempty"`
+	Copyright string       `json:"copyright,omitempty"`
+	License   string       `json:"license,omitempty"`
+	Rules     []*ModelRule `json:"rules"`
+}
+
+// ModelRule is a single rule of a Model.
+type ModelRule struct {
+	ID    int        `json:"id"`
+	Name  string     `json:"name"`
+	Type  string     `json:"type"`            // rule, token, or class
+	Node  bool       `json:"node,omitempty"`  // defined with <--
+	Entry bool       `json:"entry,omitempty"` // see EntryPrefix
+	Doc   []string   `json:"doc,omitempty"`
+	Diag  string     `json:"diag,omitempty"` // see DiagPrefix
+	Sev   string     `json:"sev,omitempty"`  // of Diag (error, warning, info)
+	Expr  *ModelExpr `json:"expr"`
+}
+
+// Kinds of ModelExpr, one for every type of Expr (see Look for the
+// difference between and and not).
+const (
+	KindChoice  = `choice`  // Exprs (Choice)
+	KindSeq     = `seq`     // Exprs (Seq)
+	KindAnd     = `and`     // Exprs[0] (Look)
+	KindNot     = `not`     // Exprs[0] (Look)
+	KindQuant   = `quant`   // Exprs[0], Min, Max (Quant)
+	KindRef     = `ref`     // Text is name (Ref)
+	KindLit     = `lit`     // Text (Lit)
+	KindPoint   = `point`   // Lo (Point)
+	KindRange   = `range`   // Lo, Hi (Range)
+	KindAny     = `any`     // (Any)
+	KindCapture = `capture` // Exprs[0], Text is tag (Capture)
+	KindReplace = `replace` // Exprs[0], Text is replacement (Replace)
+	KindInject  = `inject`  // Text (Inject)
+)
+
+// ModelExpr is a single expression of a ModelRule. Which fields are set
+// depends on the Kind. Code points (Lo, Hi) are integers.
+type ModelExpr struct {
+	Kind  string       `json:"kind"`
+	Exprs []*ModelExpr `json:"exprs,omitempty"`
+	Text  string       `json:"text,omitempty"`
+	Min   int          `json:"min,omitempty"`
+	Max   int          `json:"max,omitempty"` // -1 for unbounded
+	Lo    rune         `json:"lo,omitempty"`
+	Hi    rune         `json:"hi,omitempty"`
+}
+
+// Model returns the Model of the grammar. Changes to it do not change
+// the grammar.
+func (g *Grammar) Model() *Model {
+	m := &Model{Name: g.Name, Home: g.Home, Copyright: g.Copyright, License: g.License}
+	m.Rules = make([]*ModelRule, 0, len(g.Rules))
+	for _, r := range g.Rules {
+		mr := &ModelRule{
+			ID: r.ID, Name: r.Name, Type: typeNames[r.Type], Node: r.Node,
+			Entry: r.Entry, Doc: append([]string(nil), r.Doc...), Diag: r.Diag,
+			Expr: modelExpr(r.Expr),
+		}
+		if r.Diag != "" {
+			mr.Sev = r.Sev.String()
+		}
+		m.Rules = append(m.Rules, mr)
+	}
+	return m
+}
+
+var typeNames = map[int]string{
+	model.RuleType:  `rule`,
+	model.TokenType: `token`,
+	model.ClassType: `class`,
+}
+
+func modelExpr(e Expr) *ModelExpr {
+	switch v := e.(type) {
+	case Choice:
+		return &ModelExpr{Kind: KindChoice, Exprs: modelExprs(v)}
+	case Seq:
+		return &ModelExpr{Kind: KindSeq, Exprs: modelExprs(v)}
+	case Look:
+		x := &ModelExpr{Kind: KindAnd, Exprs: modelExprs([]Expr{v.E})}
+		if v.Not {
+			x.Kind = KindNot
+		}
+		return x
+	case Quant:
+		return &ModelExpr{Kind: KindQuant, Exprs: modelExprs([]Expr{v.E}), Min: v.Min, Max: v.Max}
+	case Ref:
+		return &ModelExpr{Kind: KindRef, Text: string(v)}
+	case Lit:
+		return &ModelExpr{Kind: KindLit, Text: string(v)}
+	case Point:
+		return &ModelExpr{Kind: KindPoint, Lo: v.R}
+	case Range:
+		return &ModelExpr{Kind: KindRange, Lo: v.Lo, Hi: v.Hi}
+	case Any:
+		return &ModelExpr{Kind: KindAny}
+	case Capture:
+		return &ModelExpr{Kind: KindCapture, Exprs: modelExprs([]Expr{v.E}), Text: v.Tag}
+	case Replace:
+		return &ModelExpr{Kind: KindReplace, Exprs: modelExprs([]Expr{v.E}), Text: v.With}
+	case Inject:
+		return &ModelExpr{Kind: KindInject, Text: string(v)}
+	}
+	return nil
+}
+
+func modelExprs(list []Expr) []*ModelExpr {
+	x := make([]*ModelExpr, len(list))
+	for i, e := range list {
+		x[i] = modelExpr(e)
+	}
+	return x
+}
@@ -0,0 +1,28 @@
+package gr_test
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/rwxrob/pegn/gr"
+)
+
+func ExampleGrammar_Model() {
+
+	g := gr.MustRead(`
+# Entry
+Pair  <-- Key '=' !SP Value?
+Key   <- [a-z]+
+Value <- <:v (. / x20){1,8}>`)
+
+	m := g.Model()
+	for _, r := range m.Rules {
+		byt, _ := json.Marshal(r)
+		fmt.Println(string(byt))
+	}
+
+	// Output:
+	// {"id":1,"name":"Pair","type":"rule","node":true,"entry":true,"doc":["# Entry"],"expr":{"kind":"seq","exprs":[{"kind":"ref","text":"Key"},{"kind":"lit","text":"="},{"kind":"not","exprs":[{"kind":"ref","text":"SP"}]},{"kind":"quant","exprs":[{"kind":"ref","text":"Value"}],"max":1}]}}
+	// {"id":2,"name":"Key","type":"rule","expr":{"kind":"quant","exprs":[{"kind":"range","lo":97,"hi":122}],"min":1,"max":-1}}
+	// {"id":3,"name":"Value","type":"rule","expr":{"kind":"capture","exprs":[{"kind":"quant","exprs":[{"kind":"choice","exprs":[{"kind":"any"},{"kind":"point","lo":32}]}],"min":1,"max":8}],"text":"v"}}
+}
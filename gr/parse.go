@@ -0,0 +1,149 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+package gr
+
+import (
+	"github.com/rwxrob/pegn"
+	"github.com/rwxrob/pegn/ast"
+)
+
+// Parse interprets the first entry rule of the grammar (see Entries
+// and ParseRule).
+func (g *Grammar) Parse(s pegn.Scanner) *ast.Node {
+	r := g.entry()
+	if r == nil {
+		s.Expected(0)
+		return nil
+	}
+	return g.ParseRule(r.Name, s)
+}
+
+// ParseRule interprets the named rule (see ScanRule) and returns the
+// node tree of everything it matched. Every node rule (<--) matched
+// becomes a node of the type of its rule ID under the node of the node
+// rule it was matched within. Nodes without any nodes under them have
+// the text matched as their value. The root node is always of the named
+// rule (even if not a node rule itself). Returns nil on failure with
+// the error pushed as for ScanRule.
+func (g *Grammar) ParseRule(name string, s pegn.Scanner) *ast.Node {
+	m := newMachine(g, s, g.output())
+	m.acts = true
+	r := m.lookup(name)
+	if r == nil {
+		s.Expected(0)
+		return nil
+	}
+	m.record = true
+	b := s.RuneE()
+	defer keep(s, b)()
+	if !m.match(r) {
+		m.push(r)
+		return nil
+	}
+	m.report()
+	spans := m.spans
+	if !r.Node {
+		spans = append([]span{{rule: r, b: b, e: s.RuneE(), oe: m.outlen(), depth: -1}}, spans...)
+	}
+	n, _ := tree(*s.Bytes(), offset(s), out(m), spans)
+	return n
+}
+
+// ParseEach parses the named rule again and again (see ParseRule) until
+// the scanner is finished calling fn with each node tree as soon as it
+// is parsed so that only one is ever held in memory at a time. This is
+// how huge documents of many records (logs, data dumps) are parsed with
+// a rule for a single record. Subtrees can be streamed to disk as they
+// are parsed by passing the Write method of an ast.JSONLWriter as fn.
+// Stops at the first failure returning the error pushed to the scanner
+// or the first error returned by fn. With a Recovery, input that fails
+// to parse is skipped instead and passed to fn as an ErrorNode (leaving
+// the error on the scanner error stack) so that parsing continues with
+// the rest.
+func (g *Grammar) ParseEach(name string, s pegn.Scanner, fn func(n *ast.Node) error) error {
+	for !s.Finished() {
+		b, c := s.RuneE(), s.Mark()
+		n := g.ParseRule(name, s)
+		if n == nil {
+			s.Goto(c)
+			if n = g.recover(s, b); n == nil {
+				return lastErr(s)
+			}
+		}
+		if err := fn(n); err != nil {
+			return err
+		}
+		if s.RuneE() == b {
+			break
+		}
+	}
+	return nil
+}
+
+// lastErr returns the last error pushed to the scanner.
+func lastErr(s pegn.Scanner) error {
+	errs := *s.Errors()
+	return errs[len(errs)-1]
+}
+
+// offset returns the offset of the first byte of the scanner buffer
+// (see scanner.NewStreaming).
+func offset(s pegn.Scanner) int {
+	if o, is := s.(interface{ Offset() int }); is {
+		return o.Offset()
+	}
+	return 0
+}
+
+// output returns a new output buffer if any rule of the grammar changes
+// what is captured (see Replace and Inject) so that node values are
+// what was captured rather than what was matched. Otherwise, nil.
+func (g *Grammar) output() *[]rune {
+	for _, r := range g.Rules {
+		var rewrites bool
+		Walk(r.Expr, func(e Expr) bool {
+			switch e.(type) {
+			case Replace, Inject:
+				rewrites = true
+			}
+			return !rewrites
+		})
+		if rewrites {
+			buf := []rune{}
+			return &buf
+		}
+	}
+	return nil
+}
+
+// out returns the output buffer of the machine (or nil if none).
+func out(m *machine) []rune {
+	if m.buf == nil {
+		return nil
+	}
+	return *m.buf
+}
+
+// tree returns the node of the first span with every span following
+// it that is deeper under it and the spans remaining after them. The
+// buffer begins at offset off. Values are taken from out instead when
+// not nil.
+func tree(buf []byte, off int, out []rune, spans []span) (*ast.Node, []span) {
+	top := spans[0]
+	if top.sub != nil {
+		return top.sub, spans[1:]
+	}
+	n := &ast.Node{T: top.rule.ID, Tag: tagOf(top.rule), B: top.b, E: top.e}
+	spans = spans[1:]
+	for len(spans) > 0 && spans[0].depth > top.depth {
+		var u *ast.Node
+		u, spans = tree(buf, off, out, spans)
+		u.P = n
+		n.Append(u)
+	}
+	if n.Count == 0 {
+		n.V = top.value(buf, off, out)
+	}
+	return n, spans
+}
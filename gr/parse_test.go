@@ -0,0 +1,89 @@
+package gr_test
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/rwxrob/pegn/ast"
+	"github.com/rwxrob/pegn/gr"
+	"github.com/rwxrob/pegn/scanner"
+)
+
+func ExampleGrammar_ParseRule() {
+
+	g := gr.MustRead(`
+Greeting <-- Hello SP+ Name
+Hello    <-- 'hello' / 'hi'
+Name     <-- upper lower+
+Names    <-  Name (SP Name)*`)
+
+	fmt.Println(g.Parse(scanner.New(`hi  Rob!`)))
+	n := g.ParseRule(`Names`, scanner.New(`Rob Doris`))
+	fmt.Println(n)
+	for _, u := range n.Nodes() {
+		fmt.Println(u.V, u.B, u.E)
+	}
+
+	s := scanner.New(`hello rob`)
+	fmt.Println(g.Parse(s), s.Errors())
+
+	// Output:
+	// {"T":1,"N":[{"T":2,"V":"hi"},{"T":3,"V":"Rob"}]}
+	// {"T":4,"N":[{"T":3,"V":"Rob"},{"T":3,"V":"Doris"}]}
+	// Rob 0 3
+	// Doris 4 9
+	// <nil> &[expecting type 3 at ' ' 5-6]
+}
+
+func ExampleGrammar_ParseEach() {
+
+	g := gr.MustRead(`
+Record <-- Key '=' Value LF
+Key    <-- lower+
+Value  <-- digit+`)
+
+	var disk bytes.Buffer
+	out := ast.NewJSONLWriter(&disk)
+	s := scanner.New("a=1\nb=22\nc=333\n")
+	fmt.Println(g.ParseEach(`Record`, s, out.Write), out.Len)
+	out.Flush()
+	fmt.Print(disk.String())
+
+	s = scanner.New("a=1\nb=x\n")
+	fmt.Println(g.ParseEach(`Record`, s, func(n *ast.Node) error { return nil }))
+
+	// Output:
+	// <nil> 3
+	// {"T":1,"N":[{"T":2,"V":"a"},{"T":3,"V":"1"}]}
+	// {"T":1,"N":[{"T":2,"V":"b"},{"T":3,"V":"22"}]}
+	// {"T":1,"N":[{"T":2,"V":"c"},{"T":3,"V":"333"}]}
+	// expecting type 3 at '=' 5-6
+}
+
+func ExampleGrammar_ParseEach_streaming() {
+
+	g := gr.MustRead(`
+Line <-- Word LF
+Word <-- lower+`)
+
+	// every word is far longer than the window
+	defer func(n int) { scanner.StreamChunk = n }(scanner.StreamChunk)
+	scanner.StreamChunk = 16
+	in := strings.Repeat(strings.Repeat(`a`, 200)+"\n", 3)
+	s := scanner.NewStreaming(strings.NewReader(in), 8)
+
+	fmt.Println(g.ParseEach(`Line`, s, func(n *ast.Node) error {
+		w := n.Nodes()[0]
+		fmt.Println(w.B, w.E, len(w.V), w.V == strings.Repeat(`a`, 200))
+		return nil
+	}))
+	fmt.Println(s.ReadErr, s.Offset() > 0)
+
+	// Output:
+	// 0 200 200 true
+	// 201 401 200 true
+	// 402 602 200 true
+	// <nil>
+	// <nil> true
+}
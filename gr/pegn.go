@@ -0,0 +1,202 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+package gr
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/rwxrob/pegn"
+)
+
+// PEGN returns the canonical PEGN source (see String) of the grammar
+// even if it was built or changed programmatically (imported from
+// another notation, transformed, or compiled) rather than read. Every
+// rule with meta data not yet in its Doc (Entry, Deprecated, If, Diag,
+// Valid, and Invalid) has the Doc comment lines for it added and
+// everything in the model that has no notation of its own is written
+// with one that does:
+//
+//   - literals with runes that cannot be quoted (single quotes and
+//     control characters) as sequences of literals and code points
+//   - empty literals as nothing repeated no times (.{0})
+//   - alphanumeric ranges of runes that cannot be written as is (white
+//     space, control characters, ]) as hexadecimal ranges
+//   - Doc and Trailer lines that are not comments as comments
+//   - Notes over several lines as one
+//
+// Reading the result (see Read) always produces an equivalent grammar.
+func (g *Grammar) PEGN() string {
+	n := *g
+	n.Rules = make([]*Rule, len(g.Rules))
+	for i, r := range g.Rules {
+		c := *r
+		c.Expr = quoted(r.Expr)
+		c.Doc = comments(docOf(r, i > 0))
+		c.Note = strings.Join(strings.Fields(r.Note), ` `)
+		n.Rules[i] = &c
+	}
+	n.Trailer = comments(g.Trailer)
+	return n.String()
+}
+
+// comments returns the lines with every one that is neither blank nor
+// a comment made into one.
+func comments(lines []string) []string {
+	var out []string
+	for i, l := range lines {
+		if strings.TrimSpace(l) == "" || strings.HasPrefix(l, `#`) {
+			continue
+		}
+		if out == nil {
+			out = append([]string{}, lines...)
+		}
+		out[i] = `# ` + l
+	}
+	if out == nil {
+		return lines
+	}
+	return out
+}
+
+// docOf returns the Doc of the rule with lines added for any meta data
+// it does not already have (separated from the rule before it if
+// spaced).
+func docOf(r *Rule, spaced bool) []string {
+	has := func(prefix string) bool {
+		for _, d := range r.Doc {
+			if strings.HasPrefix(d, prefix) {
+				return true
+			}
+		}
+		return false
+	}
+	var add []string
+	if r.Entry && !has(EntryPrefix) {
+		add = append(add, EntryPrefix)
+	}
+	if r.Deprecated != "" && !has(DeprecatedPrefix) {
+		add = append(add, DeprecatedPrefix+` `+r.Deprecated)
+	}
+	if len(r.If) > 0 && !has(IfPrefix) {
+		add = append(add, IfPrefix+` `+strings.Join(r.If, ` `))
+	}
+	if r.Diag != "" {
+		prefix := DiagPrefix
+		switch r.Sev {
+		case pegn.SevWarning:
+			prefix = WarningPrefix
+		case pegn.SevInfo:
+			prefix = InfoPrefix
+		}
+		if !has(prefix) {
+			add = append(add, prefix+` `+r.Diag)
+		}
+	}
+	if len(r.Valid) > 0 && !has(ValidPrefix) {
+		for _, v := range r.Valid {
+			add = append(add, ValidPrefix+` `+exampleText(v))
+		}
+	}
+	if len(r.Invalid) > 0 && !has(InvalidPrefix) {
+		for _, v := range r.Invalid {
+			add = append(add, InvalidPrefix+` `+exampleText(v))
+		}
+	}
+	if len(add) == 0 {
+		return r.Doc
+	}
+	doc := append([]string{}, r.Doc...)
+	if len(doc) == 0 && spaced {
+		doc = append(doc, "")
+	}
+	return append(doc, add...)
+}
+
+// exampleText returns the example as it must be written after
+// ValidPrefix or InvalidPrefix to be read back the same (see example).
+func exampleText(a string) string {
+	if a == strings.TrimSpace(a) && !strings.HasPrefix(a, `"`) &&
+		strings.IndexFunc(a, unicode.IsControl) < 0 {
+		return a
+	}
+	return strconv.Quote(a)
+}
+
+// quoted returns the expression with every literal that cannot be
+// written between single quotes split into a sequence of literals and
+// code points.
+func quoted(e Expr) Expr {
+	switch v := e.(type) {
+	case Choice:
+		c := make(Choice, len(v))
+		for i, x := range v {
+			c[i] = quoted(x)
+		}
+		return c
+	case Seq:
+		var s Seq
+		for _, x := range v {
+			x = quoted(x)
+			if inner, is := x.(Seq); is {
+				s = append(s, inner...)
+				continue
+			}
+			s = append(s, x)
+		}
+		return s
+	case Quant:
+		v.E = quoted(v.E)
+		return v
+	case Look:
+		v.E = quoted(v.E)
+		return v
+	case Capture:
+		v.E = quoted(v.E)
+		return v
+	case Replace:
+		v.E = quoted(v.E)
+		return v
+	case Lit:
+		if v == "" {
+			return Quant{E: Any{}, Min: 0, Max: 0}
+		}
+		return quotedLit(string(v))
+	case Range:
+		if v.Form == 'a' && (!plain(v.Lo) || !plain(v.Hi)) {
+			v.Form = 'x'
+		}
+		return v
+	}
+	return e
+}
+
+// plain returns true if the rune can be written as is within a range.
+func plain(r rune) bool {
+	return r != ']' && !unicode.IsSpace(r) && !unicode.IsControl(r)
+}
+
+func quotedLit(a string) Expr {
+	var s Seq
+	var run []rune
+	for _, r := range a {
+		if r != '\'' && !unicode.IsControl(r) {
+			run = append(run, r)
+			continue
+		}
+		if len(run) > 0 {
+			s = append(s, Lit(run))
+			run = nil
+		}
+		s = append(s, Point{R: r, Form: 'x'})
+	}
+	if len(run) > 0 {
+		s = append(s, Lit(run))
+	}
+	if len(s) == 1 {
+		return s[0]
+	}
+	return s
+}
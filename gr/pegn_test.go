@@ -0,0 +1,61 @@
+package gr_test
+
+import (
+	"fmt"
+
+	"github.com/rwxrob/pegn"
+	"github.com/rwxrob/pegn/gr"
+	"github.com/rwxrob/pegn/model"
+)
+
+func ExampleGrammar_PEGN() {
+
+	g := &gr.Grammar{Rules: []*gr.Rule{
+		{Rule: model.Rule{Name: `Quote`, Valid: []string{`'hi'`, " hi\n"}},
+			Node: true, Expr: gr.Seq{gr.Lit(`'`), gr.Ref(`Text`), gr.Lit("'\n")}},
+		{Rule: model.Rule{Name: `Text`}, Expr: gr.Quant{E: gr.Ref(`alpha`), Min: 1, Max: -1}},
+		{Rule: model.Rule{Name: `Old`, Deprecated: `use Quote instead`},
+			Expr: gr.Ref(`Quote`), Diag: `old quotes`, Sev: pegn.SevWarning},
+	}}
+	src := g.PEGN()
+	fmt.Print(src)
+
+	n, err := gr.Read(src)
+	fmt.Println(err, n.PEGN() == src)
+	fmt.Printf("%q %v\n", n.Rules[0].Valid, n.Rules[2].Deprecated)
+
+	// Output:
+	// # Valid: 'hi'
+	// # Valid: " hi\n"
+	// Quote <-- x27 Text x27 xA
+	// Text  <- alpha+
+	//
+	// # Deprecated: use Quote instead
+	// # Warning: old quotes
+	// Old <- Quote
+	// <nil> true
+	// ["'hi'" " hi\n"] use Quote instead
+}
+
+func ExampleGrammar_PEGN_notation() {
+
+	g := &gr.Grammar{
+		Rules: []*gr.Rule{
+			{Rule: model.Rule{Name: `Empty`}, Doc: []string{`made by hand`},
+				Expr: gr.Seq{gr.Lit(``), gr.Range{Lo: ' ', Hi: ']', Form: 'a'}},
+				Note: "first\nsecond"},
+		},
+		Trailer: []string{`the end`},
+	}
+	src := g.PEGN()
+	fmt.Print(src)
+
+	n, err := gr.Read(src)
+	fmt.Println(err, n.PEGN() == src)
+
+	// Output:
+	// # made by hand
+	// Empty <- .{0} [x20-x5D]  # first second
+	// # the end
+	// <nil> true
+}
@@ -0,0 +1,200 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+package gr
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/rwxrob/pegn/scanner"
+)
+
+// Profile records how often every alternative of every choice within
+// the rules of a grammar was tried and how often it matched over
+// a corpus of inputs (see Grammar.Profile). Since PEG tries the
+// alternatives of a choice in order, putting those that match most
+// often first means fewer alternatives are tried on average (see
+// Reorder).
+type Profile struct {
+	Grammar *Grammar
+	Rule    string           // rule matched against each input
+	Corpus  []string         // inputs matched
+	Choices []*ChoiceProfile // in rule order then Walk order
+}
+
+// ChoiceProfile counts the tries and matches of the alternatives of
+// a single choice.
+type ChoiceProfile struct {
+	Rule   string // rule containing the choice
+	Index  int    // of choice within the rule (in Walk order)
+	Choice Choice
+	Tries  []int // by alternative
+	Hits   []int // by alternative
+}
+
+// Profile matches the named rule (or the first if empty) against every
+// input of the corpus counting the tries and matches of every
+// alternative of every choice in the rules of the grammar (not the
+// builtins). Whether an input matches or not does not matter.
+func (g *Grammar) Profile(rule string, corpus ...string) (*Profile, error) {
+	if rule == "" && len(g.Rules) > 0 {
+		rule = g.Rules[0].Name
+	}
+	r := g.Lookup(rule)
+	if r == nil {
+		return nil, fmt.Errorf(`rule not found: %q`, rule)
+	}
+
+	p := &Profile{Grammar: g, Rule: r.Name, Corpus: corpus}
+	byexpr := map[*Expr]*ChoiceProfile{}
+	for _, r := range g.Rules {
+		var n int
+		Walk(r.Expr, func(e Expr) bool {
+			if c, is := e.(Choice); is {
+				cp := &ChoiceProfile{
+					Rule:   r.Name,
+					Index:  n,
+					Choice: c,
+					Tries:  make([]int, len(c)),
+					Hits:   make([]int, len(c)),
+				}
+				p.Choices = append(p.Choices, cp)
+				byexpr[&c[0]] = cp
+				n++
+			}
+			return true
+		})
+	}
+
+	for _, in := range corpus {
+		m := newMachine(g, scanner.New(in), nil)
+		m.tried = func(c Choice, i int, ok bool) {
+			cp, has := byexpr[&c[0]]
+			if !has {
+				return
+			}
+			cp.Tries[i]++
+			if ok {
+				cp.Hits[i]++
+			}
+		}
+		m.rule(r)
+	}
+
+	return p, nil
+}
+
+// String returns every choice that was tried with the matches and tries
+// of each alternative one per line.
+//
+//	Value: Float (1/3) / Int (2/2) / Word (0/0)
+func (p *Profile) String() string {
+	var out strings.Builder
+	for _, cp := range p.Choices {
+		if cp.Tries[0] == 0 {
+			continue
+		}
+		alts := make([]string, len(cp.Choice))
+		for i, e := range cp.Choice {
+			alts[i] = fmt.Sprintf(`%v (%v/%v)`, wrap(e, precSeq), cp.Hits[i], cp.Tries[i])
+		}
+		out.WriteString(cp.Rule + `: ` + strings.Join(alts, ` / `) + "\n")
+	}
+	return out.String()
+}
+
+// order returns the indexes of the alternatives with the most matched
+// first (keeping the original order for those matched equally).
+func (cp *ChoiceProfile) order() []int {
+	idx := make([]int, len(cp.Choice))
+	for i := range idx {
+		idx[i] = i
+	}
+	sort.SliceStable(idx, func(a, b int) bool {
+		return cp.Hits[idx[a]] > cp.Hits[idx[b]]
+	})
+	return idx
+}
+
+// Reorder returns a copy of the grammar with the alternatives of every
+// choice ordered by how often they matched (most first) along with
+// a note for every choice that was (or could not be) reordered. Since
+// the order of alternatives matters in PEG a choice is only reordered
+// if none of its alternatives can match nothing and matching every
+// input of the corpus still produces exactly the same result afterward
+// (see Ambiguities). Rules that were not reordered are shared with the
+// original grammar.
+func (p *Profile) Reorder() (*Grammar, []string) {
+	g := *p.Grammar
+	g.Rules = append([]*Rule{}, p.Grammar.Rules...)
+	notes := make([]string, len(p.Choices))
+
+	want := make([]string, len(p.Corpus))
+	for i, in := range p.Corpus {
+		want[i] = p.Grammar.result(p.Rule, in)
+	}
+
+	// last first since reordering a choice changes the Walk order of
+	// those within it (but never of those before it)
+	for ci := len(p.Choices) - 1; ci >= 0; ci-- {
+		cp := p.Choices[ci]
+		order := cp.order()
+		changed := false
+		for i, n := range order {
+			if i != n {
+				changed = true
+			}
+		}
+		if !changed {
+			continue
+		}
+
+		alts := make([]string, len(order))
+		for i, n := range order {
+			alts[i] = wrap(cp.Choice[n], precSeq)
+		}
+		desc := cp.Rule + `: ` + cp.Choice.String() + ` -> ` + strings.Join(alts, ` / `)
+
+		if !g.safeChoice(cp.Choice) {
+			notes[ci] = desc + ` (skipped, an alternative can match nothing)`
+			continue
+		}
+
+		var ri int
+		for i, r := range g.Rules {
+			if r.Name == cp.Rule {
+				ri = i
+			}
+		}
+		prev := g.Rules[ri]
+		r := *prev
+		r.Expr = withChoice(r.Expr, cp.Index, func(c Choice) Choice {
+			n := make(Choice, len(c))
+			for i, o := range order {
+				n[i] = c[o]
+			}
+			return n
+		})
+		r.PEGN = r.String()
+		g.Rules[ri] = &r
+
+		for i, in := range p.Corpus {
+			if g.result(p.Rule, in) != want[i] {
+				g.Rules[ri] = prev
+				desc += fmt.Sprintf(` (skipped, changes result for %q)`, in)
+				break
+			}
+		}
+		notes[ci] = desc
+	}
+
+	var list []string
+	for _, n := range notes {
+		if n != "" {
+			list = append(list, n)
+		}
+	}
+	return &g, list
+}
@@ -0,0 +1,43 @@
+package gr_test
+
+import (
+	"fmt"
+
+	"github.com/rwxrob/pegn/gr"
+)
+
+func ExampleProfile_Reorder() {
+
+	g := gr.MustRead(`
+Values  <-- Value (Sep Value)*
+Sep     <-  ',' / SP*
+Value   <-- Word / Number
+Number  <-- Float / Int
+Float   <-- digit+ '.' digit+
+Int     <-- digit+
+Word    <-- alpha+`)
+
+	p, _ := g.Profile(``, `1 2 3,x`, `5 6.5`, `7 8 9`)
+	fmt.Print(p)
+
+	n, notes := p.Reorder()
+	for _, i := range notes {
+		fmt.Println(i)
+	}
+	fmt.Print(n)
+
+	// Output:
+	// Sep: ',' (1/9) / SP* (8/8)
+	// Value: Word (1/12) / Number (8/11)
+	// Number: Float (1/11) / Int (7/10)
+	// Sep: ',' / SP* -> SP* / ',' (skipped, an alternative can match nothing)
+	// Value: Word / Number -> Number / Word
+	// Number: Float / Int -> Int / Float (skipped, changes result for "5 6.5")
+	// Values <-- Value (Sep Value)*
+	// Sep    <- ',' / SP*
+	// Value  <-- Number / Word
+	// Number <-- Float / Int
+	// Float  <-- digit+ '.' digit+
+	// Int    <-- digit+
+	// Word   <-- alpha+
+}
@@ -0,0 +1,19 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+package gr
+
+import "github.com/rwxrob/pegn/ast"
+
+// Query returns the nodes selected from the tree (parsed with the
+// grammar) by the path expression with the rule names of the grammar
+// resolved into types (see ast.ParseSelector).
+//
+//	names, err := g.Query(tree, `//Rule/Name`)
+func (g *Grammar) Query(n *ast.Node, expr string) ([]*ast.Node, error) {
+	sel, err := ast.ParseSelector(expr, g.ID)
+	if err != nil {
+		return nil, err
+	}
+	return sel.Select(n), nil
+}
@@ -0,0 +1,36 @@
+package gr_test
+
+import (
+	"fmt"
+
+	"github.com/rwxrob/pegn/gr"
+	"github.com/rwxrob/pegn/scanner"
+)
+
+func ExampleGrammar_Query() {
+
+	g := gr.MustCompile(`
+Config  <-- (Comment / Setting)*
+Comment <-- '#' (!LF .)* LF
+Setting <-- Key '=' Value LF
+Key     <-- lower+
+Value   <-- <:num digit+> / lower+`)
+
+	n := g.Parse(scanner.New("# ports\nweb=80\n# db\ndb=5432\nmode=dev\n"))
+
+	for _, q := range []string{`Setting/Key`, `//Comment`, `//:num`, `/Config/*/Value`, `Nope`} {
+		list, err := g.Query(n, q)
+		fmt.Print(q, ` `, err)
+		for _, u := range list {
+			fmt.Printf(" %q", u.V)
+		}
+		fmt.Println()
+	}
+
+	// Output:
+	// Setting/Key <nil> "web" "db" "mode"
+	// //Comment <nil> "# ports\n" "# db\n"
+	// //:num <nil> "80" "5432"
+	// /Config/*/Value <nil> "" "" "dev"
+	// Nope unknown type in selector: "Nope"
+}
@@ -0,0 +1,637 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+package gr
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/rwxrob/pegn/model"
+)
+
+// Read reads PEGN source into a new Grammar. Every definition must
+// begin at the start of a line and may continue on any number of
+// following indented lines. Comment and blank lines before
+// a definition are kept as its Doc and comments within it as its Note.
+// Meta header lines (see Meta in the PEGN spec) are only recognized at
+// the very beginning of the source. Errors include the line number.
+func Read(src string) (*Grammar, error) {
+	g := new(Grammar)
+	lines := strings.Split(strings.ReplaceAll(src, "\r\n", "\n"), "\n")
+	header := true
+
+	var doc []string
+	var cur *def
+	var ids int
+	strs := interner{}
+
+	finish := func() error {
+		if cur == nil {
+			return nil
+		}
+		r, err := readRule(cur, strs)
+		cur = nil
+		if err != nil {
+			return err
+		}
+		if d := g.Rule(r.Name); d != nil {
+			if len(d.If) == 0 || len(r.If) == 0 {
+				return fmt.Errorf(`line %v: duplicate rule %q (see line %v)`,
+					r.Line, r.Name, d.Line)
+			}
+			r.ID = d.ID
+		} else {
+			ids++
+			r.ID = ids
+		}
+		g.Rules = append(g.Rules, r)
+		return nil
+	}
+
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		switch {
+
+		case len(trimmed) == 0:
+			if err := finish(); err != nil {
+				return nil, err
+			}
+			header = false
+			if len(g.Rules) > 0 || len(doc) > 0 {
+				doc = append(doc, "")
+			}
+
+		case line[0] == '#':
+			if err := finish(); err != nil {
+				return nil, err
+			}
+			if header && readMeta(g, line) {
+				continue
+			}
+			header = false
+			doc = append(doc, line)
+
+		case line[0] == ' ' || line[0] == '\t':
+			if cur != nil {
+				cur.text += "\n" + line
+				continue
+			}
+			if trimmed[0] != '#' {
+				return nil, fmt.Errorf(`line %v: unexpected indentation`, i+1)
+			}
+			doc = append(doc, trimmed)
+
+		default:
+			if err := finish(); err != nil {
+				return nil, err
+			}
+			header = false
+			cur = &def{line: i + 1, text: line, doc: doc}
+			doc = nil
+		}
+	}
+
+	if err := finish(); err != nil {
+		return nil, err
+	}
+	for len(doc) > 0 && doc[len(doc)-1] == "" {
+		doc = doc[:len(doc)-1]
+	}
+	g.Trailer = doc
+
+	return g, nil
+}
+
+// ReadFile reads the file at path and passes it to Read.
+func ReadFile(path string) (*Grammar, error) {
+	byt, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Read(string(byt))
+}
+
+// MustRead calls Read and panics if there is an error. Use only for
+// grammars known to be valid (embedded, generated, and such).
+func MustRead(src string) *Grammar {
+	g, err := Read(src)
+	if err != nil {
+		panic(err)
+	}
+	return g
+}
+
+// readMeta sets the meta information from a single header line and
+// returns false if the line is not meta data.
+func readMeta(g *Grammar, line string) bool {
+	if !strings.HasPrefix(line, `# `) {
+		return false
+	}
+	text := line[2:]
+	switch {
+	case g.Name == "":
+		f := strings.Fields(text)
+		if len(f) != 2 || !isIdent(f[0]) {
+			return false
+		}
+		g.Name, g.Home = f[0], f[1]
+	case strings.HasPrefix(text, `Copyright `) && g.Copyright == "":
+		g.Copyright = text
+	case strings.HasPrefix(text, `SPDX-License-Identifier: `) && g.License == "":
+		g.License = strings.TrimPrefix(text, `SPDX-License-Identifier: `)
+	case strings.HasPrefix(text, `Include `):
+		g.Includes = append(g.Includes, strings.TrimPrefix(text, `Include `))
+	default:
+		return false
+	}
+	return true
+}
+
+// isIdent returns true for a valid PEGN meta Ident (upper{2,12}).
+func isIdent(a string) bool {
+	if len(a) < 2 || len(a) > 12 {
+		return false
+	}
+	for _, r := range a {
+		if r < 'A' || r > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+// def is the raw text of a single definition (which may span lines)
+// along with any comment lines that came before it.
+type def struct {
+	line int
+	text string
+	doc  []string
+}
+
+// ------------------------------- lexer ------------------------------
+
+const (
+	tokEOF = iota
+	tokName
+	tokArrow
+	tokLit
+	tokPoint
+	tokRange
+	tokCount
+	tokOp
+	tokTag // beginning of named capture (<:tag)
+)
+
+type token struct {
+	kind int
+	text string
+	line int
+}
+
+type lexer struct {
+	buf  []rune
+	pos  int
+	line int
+	note []string
+}
+
+func isWordRune(r rune) bool {
+	return r == '_' || (r >= 'a' && r <= 'z') ||
+		(r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9')
+}
+
+// skip moves past any whitespace and comments saving the comments.
+func (l *lexer) skip() {
+	for l.pos < len(l.buf) {
+		switch l.buf[l.pos] {
+		case '\n':
+			l.line++
+			l.pos++
+		case ' ', '\t', '\r':
+			l.pos++
+		case '#':
+			b := l.pos + 1
+			for l.pos < len(l.buf) && l.buf[l.pos] != '\n' {
+				l.pos++
+			}
+			l.note = append(l.note, strings.TrimSpace(string(l.buf[b:l.pos])))
+		default:
+			return
+		}
+	}
+}
+
+// until returns the text up to the closing rune and moves past it.
+func (l *lexer) until(close rune) (string, error) {
+	b := l.pos + 1
+	for l.pos++; l.pos < len(l.buf); l.pos++ {
+		switch l.buf[l.pos] {
+		case close:
+			l.pos++
+			return string(l.buf[b : l.pos-1]), nil
+		case '\n':
+			return "", fmt.Errorf(`line %v: missing closing %q`, l.line, close)
+		}
+	}
+	return "", fmt.Errorf(`line %v: missing closing %q`, l.line, close)
+}
+
+func (l *lexer) next() (token, error) {
+	l.skip()
+	t := token{line: l.line}
+	if l.pos >= len(l.buf) {
+		return t, nil
+	}
+	r := l.buf[l.pos]
+	var err error
+
+	switch {
+
+	case r == '\'':
+		t.kind = tokLit
+		t.text, err = l.until('\'')
+
+	case r == '[':
+		t.kind = tokRange
+		t.text, err = l.until(']')
+
+	case r == '{':
+		t.kind = tokCount
+		t.text, err = l.until('}')
+
+	case r == '<' && l.pos+1 < len(l.buf) && l.buf[l.pos+1] == '-':
+		t.kind = tokArrow
+		t.text = `<-`
+		l.pos += 2
+		if l.pos < len(l.buf) && l.buf[l.pos] == '-' {
+			t.text = `<--`
+			l.pos++
+		}
+
+	case r == '<' && l.pos+1 < len(l.buf) && l.buf[l.pos+1] == ':':
+		t.kind = tokTag
+		l.pos += 2
+		b := l.pos
+		for l.pos < len(l.buf) && isWordRune(l.buf[l.pos]) {
+			l.pos++
+		}
+		t.text = string(l.buf[b:l.pos])
+		if t.text == "" {
+			err = fmt.Errorf(`line %v: missing capture tag after '<:'`, l.line)
+		}
+
+	case isWordRune(r):
+		b := l.pos
+		for l.pos < len(l.buf) && isWordRune(l.buf[l.pos]) {
+			l.pos++
+		}
+		t.text = string(l.buf[b:l.pos])
+		t.kind = tokName
+		if _, _, is := readPoint(t.text); is {
+			t.kind = tokPoint
+		}
+
+	case r == '=' && l.pos+1 < len(l.buf) && l.buf[l.pos+1] == '>':
+		t.kind = tokOp
+		t.text = `=>`
+		l.pos += 2
+
+	case strings.ContainsRune(`/()!&?*+.<>^`, r):
+		t.kind = tokOp
+		t.text = string(r)
+		l.pos++
+
+	default:
+		err = fmt.Errorf(`line %v: unexpected %q`, l.line, r)
+	}
+
+	return t, err
+}
+
+// readPoint parses a single code point in any of the numeric forms
+// (u, x, b, o) returning false if not a valid point.
+func readPoint(a string) (rune, byte, bool) {
+	if len(a) < 2 {
+		return 0, 0, false
+	}
+	base := 16
+	switch a[0] {
+	case 'u', 'x':
+		for _, r := range a[1:] {
+			if !(r >= '0' && r <= '9') && !(r >= 'A' && r <= 'F') {
+				return 0, 0, false
+			}
+		}
+	case 'b':
+		base = 2
+	case 'o':
+		base = 8
+	default:
+		return 0, 0, false
+	}
+	n, err := strconv.ParseInt(a[1:], base, 32)
+	if err != nil || n > 0x10FFFF {
+		return 0, 0, false
+	}
+	return rune(n), a[0], true
+}
+
+// ------------------------------ parser ------------------------------
+
+type parser struct {
+	lex  *lexer
+	tok  token
+	strs interner // shared by every rule of the grammar
+}
+
+func (p *parser) advance() error {
+	t, err := p.lex.next()
+	p.tok = t
+	return err
+}
+
+func (p *parser) isOp(a string) bool {
+	return p.tok.kind == tokOp && p.tok.text == a
+}
+
+func (p *parser) errorf(format string, args ...any) error {
+	return fmt.Errorf(`line %v: `+format,
+		append([]any{p.tok.line}, args...)...)
+}
+
+func (p *parser) unexpected() error {
+	if p.tok.kind == tokEOF {
+		return p.errorf(`unexpected end of definition`)
+	}
+	return p.errorf(`unexpected %q`, p.tok.text)
+}
+
+func readRule(d *def, strs interner) (*Rule, error) {
+	p := &parser{lex: &lexer{buf: []rune(d.text), line: d.line}, strs: strs}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	if p.tok.kind != tokName {
+		return nil, p.errorf(`expected rule name`)
+	}
+
+	r := new(Rule)
+	r.Name = p.strs.intern(p.tok.text)
+	r.Type = model.TypeOf(r.Name)
+	r.Line = d.line
+	r.Doc = d.doc
+
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	if p.tok.kind != tokArrow {
+		return nil, p.errorf(`expected <- or <-- after %v`, r.Name)
+	}
+	r.Node = p.tok.text == `<--`
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+
+	e, err := p.expr()
+	if err != nil {
+		return nil, err
+	}
+	if p.tok.kind != tokEOF {
+		return nil, p.unexpected()
+	}
+
+	r.Expr = e
+	r.Note = strings.Join(p.lex.note, ` `)
+	r.PEGN = r.String()
+	readDeprecation(r)
+	readIf(r)
+	readDiag(r)
+	readExamples(r)
+	readEntry(r)
+	return r, nil
+}
+
+func (p *parser) expr() (Expr, error) {
+	var alts Choice
+	for {
+		e, err := p.seq()
+		if err != nil {
+			return nil, err
+		}
+		alts = append(alts, e)
+		if !p.isOp(`/`) {
+			break
+		}
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+	}
+	if len(alts) == 1 {
+		return alts[0], nil
+	}
+	return alts, nil
+}
+
+func (p *parser) seq() (Expr, error) {
+	var seq Seq
+	for p.tok.kind != tokEOF && !p.isOp(`/`) && !p.isOp(`)`) && !p.isOp(`>`) {
+		e, err := p.replaced()
+		if err != nil {
+			return nil, err
+		}
+		seq = append(seq, e)
+	}
+	switch len(seq) {
+	case 0:
+		return nil, p.unexpected()
+	case 1:
+		return seq[0], nil
+	}
+	return seq, nil
+}
+
+func (p *parser) replaced() (Expr, error) {
+	e, err := p.prefixed()
+	if err != nil || !p.isOp(`=>`) {
+		return e, err
+	}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	if p.tok.kind != tokLit {
+		return nil, p.errorf(`expected literal after '=>'`)
+	}
+	e = Replace{E: e, With: p.strs.intern(p.tok.text)}
+	return e, p.advance()
+}
+
+func (p *parser) prefixed() (Expr, error) {
+	if !p.isOp(`!`) && !p.isOp(`&`) {
+		return p.suffixed()
+	}
+	not := p.tok.text == `!`
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	e, err := p.suffixed()
+	if err != nil {
+		return nil, err
+	}
+	return Look{Not: not, E: e}, nil
+}
+
+func (p *parser) suffixed() (Expr, error) {
+	e, err := p.primary()
+	if err != nil {
+		return nil, err
+	}
+	q := Quant{E: e}
+	switch {
+	case p.isOp(`?`):
+		q.Min, q.Max = 0, 1
+	case p.isOp(`*`):
+		q.Min, q.Max = 0, -1
+	case p.isOp(`+`):
+		q.Min, q.Max = 1, -1
+	case p.tok.kind == tokCount:
+		if q.Min, q.Max, err = p.count(p.tok.text); err != nil {
+			return nil, err
+		}
+	default:
+		return e, nil
+	}
+	return q, p.advance()
+}
+
+// count parses the inside of {n}, {n,}, and {n,m}.
+func (p *parser) count(a string) (int, int, error) {
+	smin, smax, comma := strings.Cut(a, `,`)
+	min, err := strconv.Atoi(strings.TrimSpace(smin))
+	if err != nil || min < 0 {
+		return 0, 0, p.errorf(`invalid count {%v}`, a)
+	}
+	if !comma {
+		return min, min, nil
+	}
+	if len(strings.TrimSpace(smax)) == 0 {
+		return min, -1, nil
+	}
+	max, err := strconv.Atoi(strings.TrimSpace(smax))
+	if err != nil || max < min {
+		return 0, 0, p.errorf(`invalid count {%v}`, a)
+	}
+	return min, max, nil
+}
+
+func (p *parser) primary() (Expr, error) {
+	t := p.tok
+	var e Expr
+
+	switch t.kind {
+
+	case tokName:
+		e = Ref(p.strs.intern(t.text))
+
+	case tokLit:
+		if len(t.text) == 0 {
+			return nil, p.errorf(`empty literal`)
+		}
+		e = Lit(p.strs.intern(t.text))
+
+	case tokPoint:
+		r, form, _ := readPoint(t.text)
+		e = Point{R: r, Form: form}
+
+	case tokRange:
+		rng, err := p.rng(t.text)
+		if err != nil {
+			return nil, err
+		}
+		e = rng
+
+	case tokTag:
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		inner, err := p.expr()
+		if err != nil {
+			return nil, err
+		}
+		if !p.isOp(`>`) {
+			return nil, p.errorf(`expected '>'`)
+		}
+		e = Capture{E: inner, Tag: p.strs.intern(t.text)}
+
+	case tokOp:
+		switch t.text {
+		case `.`:
+			e = Any{}
+		case `^`:
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			if p.tok.kind != tokLit {
+				return nil, p.errorf(`expected literal after '^'`)
+			}
+			e = Inject(p.strs.intern(p.tok.text))
+		case `(`, `<`:
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			inner, err := p.expr()
+			if err != nil {
+				return nil, err
+			}
+			if t.text == `(` {
+				if !p.isOp(`)`) {
+					return nil, p.errorf(`expected ')'`)
+				}
+				e = inner
+			} else {
+				if !p.isOp(`>`) {
+					return nil, p.errorf(`expected '>'`)
+				}
+				e = Capture{E: inner}
+			}
+		}
+	}
+
+	if e == nil {
+		return nil, p.unexpected()
+	}
+	return e, p.advance()
+}
+
+// rng parses the inside of any of the range forms ([a-z], [x20-x2F]).
+func (p *parser) rng(a string) (Range, error) {
+	var rng Range
+	if len(a) == 0 {
+		return rng, p.errorf(`empty range`)
+	}
+	i := strings.Index(a[1:], `-`) + 1
+	if i <= 0 {
+		return rng, p.errorf(`invalid range [%v]`, a)
+	}
+	lo, hi := strings.TrimSpace(a[:i]), strings.TrimSpace(a[i+1:])
+
+	var lform, hform byte
+	if l := []rune(lo); len(l) == 1 {
+		rng.Lo, lform = l[0], 'a'
+	} else {
+		rng.Lo, lform, _ = readPoint(lo)
+	}
+	if h := []rune(hi); len(h) == 1 {
+		rng.Hi, hform = h[0], 'a'
+	} else {
+		rng.Hi, hform, _ = readPoint(hi)
+	}
+
+	if lform == 0 || lform != hform || rng.Lo > rng.Hi {
+		return rng, p.errorf(`invalid range [%v]`, a)
+	}
+	rng.Form = lform
+	return rng, nil
+}
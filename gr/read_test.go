@@ -0,0 +1,76 @@
+package gr_test
+
+import (
+	"fmt"
+
+	"github.com/rwxrob/pegn/gr"
+)
+
+func ExampleRead() {
+
+	g, err := gr.Read(`# SAMPLE example.com/sample
+# Copyright 2023 Some One
+# SPDX-License-Identifier: Apache-2.0
+
+# a greeting
+Greeting <-- Hello SP+ Name
+Hello    <-- 'hello' / 'hi'   # just two
+Name     <-- upper lower+
+             (DASH upper lower+)?
+`)
+
+	fmt.Println(err)
+	fmt.Println(g.Name, g.Home, g.License)
+	for _, r := range g.Rules {
+		fmt.Printf("%v %v %q %q\n", r.ID, r, r.Doc, r.Note)
+	}
+
+	// Output:
+	// <nil>
+	// SAMPLE example.com/sample Apache-2.0
+	// 1 Greeting <-- Hello SP+ Name ["# a greeting"] ""
+	// 2 Hello <-- 'hello' / 'hi' [] "just two"
+	// 3 Name <-- upper lower+ (DASH upper lower+)? [] ""
+}
+
+func ExampleRead_forms() {
+
+	g := gr.MustRead(`Forms <- [a-z] [x20-x2F] [u0000-u10FFFF] uFEFF b101 o17 . !ws &'x' x{2} x{2,} x{2,4}`)
+	fmt.Println(g.Rules[0])
+
+	// Output:
+	// Forms <- [a-z] [x20-x2F] [u0000-u10FFFF] uFEFF b101 o17 . !ws &'x' x{2} x{2,} x{2,4}
+
+}
+
+func ExampleRead_errors() {
+
+	_, err := gr.Read("Some <- 'thing'\nsome <- 'other'")
+	fmt.Println(err)
+
+	_, err = gr.Read("Some <- ('thing'")
+	fmt.Println(err)
+
+	_, err = gr.Read("Some <- [z-a]")
+	fmt.Println(err)
+
+	_, err = gr.Read("\n\nSome 'thing'")
+	fmt.Println(err)
+
+	// Output:
+	// line 2: duplicate rule "some" (see line 1)
+	// line 1: expected ')'
+	// line 1: invalid range [z-a]
+	// line 3: expected <- or <-- after Some
+}
+
+func ExampleGrammar_Lookup() {
+	g := gr.MustRead(`Greeting <-- 'hi' SP`)
+	fmt.Println(g.Lookup(`Greeting`))
+	fmt.Println(g.Lookup(`SP`))
+	fmt.Println(g.Lookup(`Missing`))
+	// Output:
+	// Greeting <-- 'hi' SP
+	// SP <- x20
+	// <nil>
+}
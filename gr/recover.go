@@ -0,0 +1,43 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+package gr
+
+import (
+	"github.com/rwxrob/pegn"
+	"github.com/rwxrob/pegn/ast"
+)
+
+// ErrorNode is the type of the node passed in place of input that
+// failed to parse and was skipped (see Recovery) with the text skipped
+// as its value and the error pushed to the scanner as its Err (see
+// ast.NewError).
+const ErrorNode = ast.ErrorNode
+
+// Recovery moves the scanner past input that failed to parse (from
+// where parsing began) so that parsing can continue after it (see
+// Grammar.Recovery). It must return false (without moving) if there is
+// nothing it can skip.
+type Recovery func(s pegn.Scanner) bool
+
+// SkipLine is the Recovery for line-oriented formats (logs, configs,
+// records of any kind one per line) skipping everything up to and
+// including the next line ending (or to the end of data) so that one bad
+// line never prevents reading the rest.
+func SkipLine(s pegn.Scanner) bool {
+	b := s.RuneE()
+	for s.Scan() && s.Rune() != '\n' {
+	}
+	return s.RuneE() > b
+}
+
+// recover skips the input that failed to parse from b with the
+// Recovery of the grammar returning the ErrorNode of what was skipped
+// (with the last error pushed) or nil if nothing could be.
+func (g *Grammar) recover(s pegn.Scanner, b int) *ast.Node {
+	if g.Recovery == nil || !g.Recovery(s) || s.RuneE() <= b {
+		return nil
+	}
+	off := offset(s)
+	return ast.NewError(string((*s.Bytes())[b-off:s.RuneE()-off]), lastErr(s))
+}
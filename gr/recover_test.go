@@ -0,0 +1,42 @@
+package gr_test
+
+import (
+	"fmt"
+
+	"github.com/rwxrob/pegn/ast"
+	"github.com/rwxrob/pegn/gr"
+	"github.com/rwxrob/pegn/scanner"
+)
+
+func ExampleSkipLine() {
+
+	g := gr.MustRead(`
+Record <-- Key '=' Value LF
+Key    <-- lower+
+Value  <-- digit+`)
+	g.Recovery = gr.SkipLine
+
+	s := scanner.New("a=1\nb=x\nc=3\nbad")
+	err := g.ParseEach(`Record`, s, func(n *ast.Node) error {
+		if n.T == gr.ErrorNode {
+			fmt.Printf("error: %q (%v)\n", n.V, n.Err)
+			return nil
+		}
+		buf, _ := n.MarshalShort()
+		fmt.Println(string(buf))
+		return nil
+	})
+	fmt.Println(err)
+	for _, e := range *s.Errors() {
+		fmt.Println(e)
+	}
+
+	// Output:
+	// [1,[[2,"a"],[3,"1"]]]
+	// error: "b=x\n" (expecting type 3 at '=' 5-6)
+	// [1,[[2,"c"],[3,"3"]]]
+	// error: "bad" (expecting type 2 at 'd' 14-15)
+	// <nil>
+	// expecting type 3 at '=' 5-6
+	// expecting type 2 at 'd' 14-15
+}
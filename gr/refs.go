@@ -0,0 +1,116 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+package gr
+
+import (
+	"fmt"
+	"strings"
+)
+
+// References returns the unique names referred to by the named rule
+// (see Refs) in the order they first appear or nil if the grammar does
+// not define the rule.
+func (g *Grammar) References(rule string) []string {
+	r := g.Rule(rule)
+	if r == nil {
+		return nil
+	}
+	return Refs(r.Expr)
+}
+
+// ReferencedBy returns the names of every rule of the grammar referring
+// to the named rule (case-insensitive, like Rule) in order of
+// definition. The rule need not be defined by the grammar so that uses
+// of Builtins (or of rules yet to be defined) can be found as well.
+// A rule renamed safely is one with every rule returned changed to
+// refer to the new name.
+func (g *Grammar) ReferencedBy(rule string) []string {
+	var list []string
+	for _, r := range g.Rules {
+		for _, n := range Refs(r.Expr) {
+			if strings.EqualFold(n, rule) {
+				list = append(list, r.Name)
+				break
+			}
+		}
+	}
+	return list
+}
+
+// Usage is where a single rule of a grammar is used (see WhereUsed).
+type Usage struct {
+	Rule      string   `json:"rule"`
+	Line      int      `json:"line,omitempty"`   // of definition (if read)
+	UsedBy    []string `json:"usedby,omitempty"` // see ReferencedBy
+	Entry     bool     `json:"entry,omitempty"`  // see Entries
+	Reachable bool     `json:"reachable"`        // from any entry
+}
+
+// Usages is the where-used report of a grammar (see WhereUsed).
+type Usages []Usage
+
+// WhereUsed returns the Usage of every rule of the grammar in order of
+// definition. Rules that are not Reachable from any entry (see
+// Entries) are never used when parsing and are safe to remove (along
+// with those only they refer to).
+func (g *Grammar) WhereUsed() Usages {
+	reach := map[*Rule]bool{}
+	var visit func(r *Rule)
+	visit = func(r *Rule) {
+		if reach[r] {
+			return
+		}
+		reach[r] = true
+		for _, n := range Refs(r.Expr) {
+			if d := g.Rule(n); d != nil {
+				visit(d)
+			}
+		}
+	}
+	entries := map[*Rule]bool{}
+	for _, r := range g.Entries() {
+		entries[r] = true
+		visit(r)
+	}
+	list := make(Usages, 0, len(g.Rules))
+	for _, r := range g.Rules {
+		list = append(list, Usage{
+			Rule: r.Name, Line: r.Line, UsedBy: g.ReferencedBy(r.Name),
+			Entry: entries[r], Reachable: reach[r],
+		})
+	}
+	return list
+}
+
+// Unreachable returns the names of the rules that are not Reachable.
+func (u Usages) Unreachable() []string {
+	var list []string
+	for _, i := range u {
+		if !i.Reachable {
+			list = append(list, i.Rule)
+		}
+	}
+	return list
+}
+
+// String returns the report as a line for every rule with the rules
+// using it (or entry, unused, or unreachable).
+func (u Usages) String() string {
+	var out strings.Builder
+	for _, i := range u {
+		used := strings.Join(i.UsedBy, ` `)
+		switch {
+		case i.Entry && used == "":
+			used = `(entry)`
+		case i.Entry:
+			used = `(entry) ` + used
+		case used == "":
+			used = `(unused)`
+		case !i.Reachable:
+			used += ` (unreachable)`
+		}
+		fmt.Fprintf(&out, "%-16v %v\n", i.Rule, used)
+	}
+	return out.String()
+}
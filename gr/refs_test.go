@@ -0,0 +1,40 @@
+package gr_test
+
+import (
+	"fmt"
+
+	"github.com/rwxrob/pegn/gr"
+)
+
+func ExampleGrammar_WhereUsed() {
+
+	g := gr.MustRead(`
+Pair  <-- Key '=' Value
+Key   <-- Word
+Value <-- Word / Num
+Word  <- lower+
+Num   <- digit+
+Old   <- Word SP Legacy
+Legacy <- upper+`)
+
+	fmt.Println(g.References(`Value`))
+	fmt.Println(g.ReferencedBy(`word`))
+	fmt.Println(g.ReferencedBy(`digit`))
+
+	u := g.WhereUsed()
+	fmt.Print(u)
+	fmt.Println(u.Unreachable())
+
+	// Output:
+	// [Word Num]
+	// [Key Value Old]
+	// [Num]
+	// Pair             (entry)
+	// Key              Pair
+	// Value            Pair
+	// Word             Key Value Old
+	// Num              Value
+	// Old              (unused)
+	// Legacy           Old (unreachable)
+	// [Old Legacy]
+}
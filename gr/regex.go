@@ -0,0 +1,326 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+package gr
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Regex returns a Go regexp pattern (anchored at both ends) matching
+// exactly the same input as the rule matches completely so that simple
+// validations (tokens, classes, identifiers) can use package regexp
+// rather than a scanner. Only the builtins may be referenced (see
+// Grammar.Regexes for rules referring to others). Since PEG choices and
+// repetition never backtrack but regular expressions do, an error is
+// returned unless backtracking could never change the result: the
+// alternatives of every choice must begin with different runes (or be
+// nothing but single runes) and nothing that follows repetition may
+// begin with a rune that could begin another repetition. Lookahead
+// is never regular (in Go) and is always an error.
+func (r *Rule) Regex() (string, error) {
+	return regex(r, Builtins.Lookup)
+}
+
+// Regexes returns the pattern (see Rule.Regex) of every rule of the
+// grammar that has one by name. References to other rules of the
+// grammar are resolved (and therefore must not be recursive).
+// Delegates are never included since their expressions are only
+// approximations.
+func (g *Grammar) Regexes() map[string]string {
+	list := map[string]string{}
+	for _, r := range g.Rules {
+		if _, has := g.Delegates[r.Name]; has {
+			continue
+		}
+		if p, err := regex(r, g.Lookup); err == nil {
+			list[r.Name] = p
+		}
+	}
+	return list
+}
+
+func regex(r *Rule, lookup func(name string) *Rule) (string, error) {
+	x := regexer{lookup: lookup, seen: map[string]bool{r.Name: true}}
+	var out strings.Builder
+	if err := x.write(&out, r.Expr, nil); err != nil {
+		return "", fmt.Errorf(`%v: not regular: %w`, r.Name, err)
+	}
+	return `^(?:` + out.String() + `)$`, nil
+}
+
+// regexer writes patterns resolving references with lookup. Rules
+// being written are seen so that recursion is caught.
+type regexer struct {
+	lookup func(name string) *Rule
+	seen   map[string]bool
+}
+
+// write writes the pattern of the expression that may be followed by
+// any of the runes of follow (nil for the end of the input).
+func (x *regexer) write(out *strings.Builder, e Expr, follow classTable) error {
+	var t classTable
+	if collect(x.lookup, e, &t, map[string]bool{}) {
+		out.WriteString(classPattern(t.merged()))
+		return nil
+	}
+	switch v := e.(type) {
+
+	case Lit:
+		out.WriteString(regexp.QuoteMeta(string(v)))
+		return nil
+
+	case Any:
+		out.WriteString(`(?s:.)`)
+		return nil
+
+	case Capture:
+		if v.Tag == "" {
+			return x.group(out, v.E, follow)
+		}
+		out.WriteString(`(?P<` + v.Tag + `>`)
+		if err := x.write(out, v.E, follow); err != nil {
+			return err
+		}
+		out.WriteString(`)`)
+		return nil
+
+	case Replace:
+		return x.group(out, v.E, follow)
+
+	case Inject:
+		return nil
+
+	case Ref:
+		r := x.lookup(string(v))
+		if r == nil {
+			return fmt.Errorf(`undefined %v`, v)
+		}
+		if x.seen[r.Name] {
+			return fmt.Errorf(`%v is recursive`, r.Name)
+		}
+		x.seen[r.Name] = true
+		defer delete(x.seen, r.Name)
+		return x.group(out, r.Expr, follow)
+
+	case Seq:
+		// the follow of each is what the rest may begin with
+		follows := make([]classTable, len(v))
+		f := follow
+		for i := len(v) - 1; i >= 0; i-- {
+			follows[i] = f
+			first, empty, err := x.first(v[i])
+			if err != nil {
+				return err
+			}
+			if empty {
+				f = append(first, f...).merged()
+			} else {
+				f = first
+			}
+		}
+		for i, e := range v {
+			write := x.write
+			if _, is := e.(Choice); is {
+				write = x.group
+			}
+			if err := write(out, e, follows[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case Choice:
+		var all classTable
+		for i, e := range v {
+			first, empty, err := x.first(e)
+			if err != nil {
+				return err
+			}
+			if empty {
+				return fmt.Errorf(`alternative %v may match nothing`, e)
+			}
+			if all.overlaps(first) {
+				return fmt.Errorf(`alternative %v begins like one before it`, e)
+			}
+			all = append(all, first...).merged()
+			if i > 0 {
+				out.WriteString(`|`)
+			}
+			if err := x.write(out, e, follow); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case Quant:
+		first, empty, err := x.first(v.E)
+		if err != nil {
+			return err
+		}
+		if empty {
+			return fmt.Errorf(`repetition of %v may match nothing`, v.E)
+		}
+		if v.Max != v.Min && first.overlaps(follow) {
+			return fmt.Errorf(`%v may begin what follows it`, v)
+		}
+		inner := append(first, follow...).merged()
+		if err := x.group(out, v.E, inner); err != nil {
+			return err
+		}
+		switch {
+		case v.Min == 0 && v.Max == 1:
+			out.WriteString(`?`)
+		case v.Min == 0 && v.Max < 0:
+			out.WriteString(`*`)
+		case v.Min == 1 && v.Max < 0:
+			out.WriteString(`+`)
+		case v.Min == v.Max:
+			fmt.Fprintf(out, `{%v}`, v.Min)
+		case v.Max < 0:
+			fmt.Fprintf(out, `{%v,}`, v.Min)
+		default:
+			fmt.Fprintf(out, `{%v,%v}`, v.Min, v.Max)
+		}
+		return nil
+
+	case Look:
+		return fmt.Errorf(`lookahead %v`, v)
+	}
+	return fmt.Errorf(`unsupported %v`, e)
+}
+
+// group writes the expression as a non-capturing group unless it is
+// a single rune, class, or reference (which are always grouped).
+func (x *regexer) group(out *strings.Builder, e Expr, follow classTable) error {
+	if _, is := e.(Ref); is {
+		return x.write(out, e, follow)
+	}
+	var t classTable
+	if collect(x.lookup, e, &t, map[string]bool{}) {
+		out.WriteString(classPattern(t.merged()))
+		return nil
+	}
+	out.WriteString(`(?:`)
+	if err := x.write(out, e, follow); err != nil {
+		return err
+	}
+	out.WriteString(`)`)
+	return nil
+}
+
+// first returns every rune the expression may begin with and whether
+// it may match nothing at all.
+func (x *regexer) first(e Expr) (classTable, bool, error) {
+	switch v := e.(type) {
+	case Lit:
+		r, n := utf8.DecodeRuneInString(string(v))
+		if n == 0 {
+			return nil, true, nil
+		}
+		return classTable{{Lo: r, Hi: r}}, false, nil
+	case Point:
+		return classTable{{Lo: v.R, Hi: v.R}}, false, nil
+	case Range:
+		return classTable{{Lo: v.Lo, Hi: v.Hi}}, false, nil
+	case Any:
+		return classTable{{Lo: 0, Hi: unicode.MaxRune}}, false, nil
+	case Capture:
+		return x.first(v.E)
+	case Replace:
+		return x.first(v.E)
+	case Inject:
+		return nil, true, nil
+	case Ref:
+		r := x.lookup(string(v))
+		if r == nil {
+			return nil, false, fmt.Errorf(`undefined %v`, v)
+		}
+		if x.seen[r.Name] {
+			return nil, false, fmt.Errorf(`%v is recursive`, r.Name)
+		}
+		x.seen[r.Name] = true
+		defer delete(x.seen, r.Name)
+		return x.first(r.Expr)
+	case Quant:
+		t, empty, err := x.first(v.E)
+		return t, empty || v.Min == 0, err
+	case Choice:
+		var all classTable
+		var empty bool
+		for _, e := range v {
+			t, none, err := x.first(e)
+			if err != nil {
+				return nil, false, err
+			}
+			all, empty = append(all, t...), empty || none
+		}
+		return all.merged(), empty, nil
+	case Seq:
+		var all classTable
+		for _, e := range v {
+			t, empty, err := x.first(e)
+			if err != nil {
+				return nil, false, err
+			}
+			all = append(all, t...)
+			if !empty {
+				return all.merged(), false, nil
+			}
+		}
+		return all.merged(), true, nil
+	case Look:
+		return nil, false, fmt.Errorf(`lookahead %v`, v)
+	}
+	return nil, false, fmt.Errorf(`unsupported %v`, e)
+}
+
+// overlaps returns true if any rune is in both (merged) tables.
+func (t classTable) overlaps(o classTable) bool {
+	for i, j := 0, 0; i < len(t) && j < len(o); {
+		switch {
+		case t[i].Hi < o[j].Lo:
+			i++
+		case o[j].Hi < t[i].Lo:
+			j++
+		default:
+			return true
+		}
+	}
+	return false
+}
+
+// classPattern returns the table as a regexp character class (or
+// a single escaped rune).
+func classPattern(t classTable) string {
+	if len(t) == 1 && t[0].Lo == t[0].Hi {
+		return regexp.QuoteMeta(string(t[0].Lo))
+	}
+	if len(t) == 1 && t[0].Lo == 0 && t[0].Hi == unicode.MaxRune {
+		return `(?s:.)`
+	}
+	var out strings.Builder
+	out.WriteString(`[`)
+	for _, r := range t {
+		out.WriteString(classRune(r.Lo))
+		if r.Hi != r.Lo {
+			if r.Hi > r.Lo+1 {
+				out.WriteString(`-`)
+			}
+			out.WriteString(classRune(r.Hi))
+		}
+	}
+	out.WriteString(`]`)
+	return out.String()
+}
+
+func classRune(r rune) string {
+	if ' ' < r && r < utf8.RuneSelf-1 && !strings.ContainsRune(`\]^-[`, r) {
+		return string(r)
+	}
+	return fmt.Sprintf(`\x{%X}`, r)
+}
@@ -0,0 +1,47 @@
+package gr_test
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+
+	"github.com/rwxrob/pegn/gr"
+)
+
+func ExampleGrammar_Regexes() {
+
+	g := gr.MustRead(`
+Version <-- 'v'? Num '.' Num '.' Num
+Num     <-  '0' / [1-9] digit*
+Ident   <-  (alpha / '_') (alnum / '_')*
+Keyword <-  'in' / 'int'
+Comment <-  '#' (!LF .)*
+Lazy    <-  digit* '0'`)
+
+	p := g.Regexes()
+	var names []string
+	for n := range p {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	for _, n := range names {
+		fmt.Println(n, p[n])
+	}
+
+	v := regexp.MustCompile(p[`Version`])
+	fmt.Println(v.MatchString(`v1.20.3`), v.MatchString(`1.02.3`))
+
+	for _, n := range []string{`Keyword`, `Comment`, `Lazy`} {
+		_, err := g.Rule(n).Regex()
+		fmt.Println(err)
+	}
+
+	// Output:
+	// Ident ^(?:[A-Z_a-z][0-9A-Z_a-z]*)$
+	// Num ^(?:0|[1-9][0-9]*)$
+	// Version ^(?:v?(?:0|[1-9][0-9]*)\.(?:0|[1-9][0-9]*)\.(?:0|[1-9][0-9]*))$
+	// true false
+	// Keyword: not regular: alternative 'int' begins like one before it
+	// Comment: not regular: lookahead !LF
+	// Lazy: not regular: digit* may begin what follows it
+}
@@ -0,0 +1,24 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+package gr
+
+import "github.com/rwxrob/pegn/rule"
+
+// Register adds every rule of the grammar to the rule registry (see
+// rule.Replace) so that errors of the grammar describe rules by name
+// (and description) rather than by type alone. Since the rules of
+// a grammar read while running have IDs that only mean anything to
+// that grammar, any rule registered before with the same ID or name
+// (such as from an earlier version of the grammar) is replaced.
+// Register is never called by Read or Compile since programs may load
+// many grammars at once. Call it for the one grammar (if any) that
+// errors are reported for.
+func (g *Grammar) Register() error {
+	for _, r := range g.Rules {
+		if err := rule.Replace(r.Rule); err != nil {
+			return err
+		}
+	}
+	return nil
+}
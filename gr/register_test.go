@@ -0,0 +1,39 @@
+package gr_test
+
+import (
+	"fmt"
+
+	"github.com/rwxrob/pegn/gr"
+	"github.com/rwxrob/pegn/rule"
+	"github.com/rwxrob/pegn/scanner"
+)
+
+func ExampleGrammar_Register() {
+
+	g := gr.MustRead(`
+Greeting <-- Hello SP+ Name
+Hello    <-- 'hello' / 'hi'
+Name     <-- upper lower+`)
+
+	s := scanner.New(`hello rob`)
+	fmt.Println(g.Scan(s, nil), s.Errors())
+
+	fmt.Println(g.Register())
+	s = scanner.New(`hello rob`)
+	fmt.Println(g.Scan(s, nil), s.Errors())
+
+	g = gr.MustRead(`Word <-- lower+`)
+	fmt.Println(g.Register())
+	s = scanner.New(`ROB`)
+	fmt.Println(g.Scan(s, nil), s.Errors())
+	_, has := rule.ByName(`Greeting`)
+	fmt.Println(has)
+
+	// Output:
+	// false &[expecting type 3 at ' ' 5-6]
+	// <nil>
+	// false &[expecting Name at line 1, column 7]
+	// <nil>
+	// false &[expecting Word at line 1, column 1]
+	// false
+}
@@ -0,0 +1,131 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+package gr
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rwxrob/pegn/model"
+)
+
+// Rename changes the name of the rule defined by the grammar from old
+// to name (both case-insensitive) along with every reference to it
+// (see ReferencedBy) and any Delegates, Islands, and Actions of it.
+// Everything else (IDs, Doc comments, Notes, order) is left alone so
+// that writing the grammar (see String) changes only the names.
+// Returns an error (changing nothing) if old is not defined, if name
+// is already used by another rule (or by Builtins, which it would
+// shadow), or if the case of name implies another type of rule (see
+// model.TypeOf) since that would change what the rule matches.
+func (g *Grammar) Rename(old, name string) error {
+	r := g.Rule(old)
+	if r == nil {
+		return fmt.Errorf(`rename: rule not defined: %q`, old)
+	}
+	if !isName(name) {
+		return fmt.Errorf(`rename: invalid name: %q`, name)
+	}
+	if err := g.conflict(name, r); err != nil {
+		return fmt.Errorf(`rename: %w`, err)
+	}
+	if t := model.TypeOf(name); t != r.Type {
+		return fmt.Errorf(`rename: %v is not a %v like %v`, name, typeNames[r.Type], r.Name)
+	}
+	from := r.Name
+	r.Name = name
+	for _, u := range g.Rules {
+		e := renamed(u.Expr, from, name)
+		if e.String() == u.Expr.String() {
+			continue
+		}
+		u.Expr = e
+		if strings.EqualFold(u.AliasOf, from) {
+			u.AliasOf = name
+		}
+		u.PEGN = u.String()
+	}
+	r.PEGN = r.String()
+	rekey(g.Delegates, from, name)
+	rekey(g.Islands, from, name)
+	rekey(g.Actions, from, name)
+	return nil
+}
+
+// conflict returns an error if the name (case-insensitive) is used by
+// any rule of the grammar other than r or by Builtins.
+func (g *Grammar) conflict(name string, r *Rule) error {
+	if d := g.Rule(name); d != nil && d != r {
+		if d.Line > 0 {
+			return fmt.Errorf(`%v conflicts with %v (line %v)`, name, d.Name, d.Line)
+		}
+		return fmt.Errorf(`%v conflicts with %v`, name, d.Name)
+	}
+	if d := Builtins.Rule(name); d != nil && g != Builtins {
+		return fmt.Errorf(`%v conflicts with builtin %v`, name, d.Name)
+	}
+	return nil
+}
+
+// isName returns true if the name is a valid rule name (and not read
+// as a code point instead, see Point).
+func isName(a string) bool {
+	if _, _, is := readPoint(a); is || a == "" {
+		return false
+	}
+	for _, r := range a {
+		if !isWordRune(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// renamed returns the expression with every reference to from
+// (case-insensitive) changed to name.
+func renamed(e Expr, from, name string) Expr {
+	switch v := e.(type) {
+	case Ref:
+		if strings.EqualFold(string(v), from) {
+			return Ref(name)
+		}
+	case Choice:
+		c := make(Choice, len(v))
+		for i, x := range v {
+			c[i] = renamed(x, from, name)
+		}
+		return c
+	case Seq:
+		s := make(Seq, len(v))
+		for i, x := range v {
+			s[i] = renamed(x, from, name)
+		}
+		return s
+	case Look:
+		v.E = renamed(v.E, from, name)
+		return v
+	case Quant:
+		v.E = renamed(v.E, from, name)
+		return v
+	case Capture:
+		v.E = renamed(v.E, from, name)
+		return v
+	case Replace:
+		v.E = renamed(v.E, from, name)
+		return v
+	}
+	return e
+}
+
+// rekey moves the value of the map from one key (case-insensitive) to
+// another.
+func rekey[T any](m map[string]T, from, name string) {
+	for k, v := range m {
+		if strings.EqualFold(k, from) {
+			delete(m, k)
+			m[name] = v
+			return
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package gr_test
+
+import (
+	"fmt"
+
+	"github.com/rwxrob/pegn/gr"
+)
+
+func ExampleGrammar_Rename() {
+
+	g := gr.MustRead(`
+# the whole thing
+Pair  <-- Key '=' Value # one per line
+Key   <-- lower+
+Value <-- Key / digit+`)
+
+	fmt.Println(g.Rename(`key`, `Name`))
+	fmt.Print(g)
+	fmt.Println(g.ReferencedBy(`Key`))
+
+	fmt.Println(g.Rename(`Name`, `value`))
+	fmt.Println(g.Rename(`Name`, `NAME`))
+	fmt.Println(g.Rename(`Name`, `Digit`))
+	fmt.Println(g.Rename(`Nope`, `Other`))
+
+	// Output:
+	// <nil>
+	// # the whole thing
+	// Pair  <-- Name '=' Value  # one per line
+	// Name  <-- lower+
+	// Value <-- Name / digit+
+	// []
+	// rename: value conflicts with Value (line 5)
+	// rename: NAME is not a rule like Name
+	// rename: Digit conflicts with builtin digit
+	// rename: rule not defined: "Nope"
+}
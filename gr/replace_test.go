@@ -0,0 +1,30 @@
+package gr_test
+
+import (
+	"fmt"
+
+	"github.com/rwxrob/pegn/gr"
+	"github.com/rwxrob/pegn/scanner"
+)
+
+func ExampleReplace() {
+
+	g := gr.MustCompile(`
+Line  <-- Word (Sep Word)* LF => ''
+Word  <-- ^'<' lower+ ^'>'
+Sep   <-  SP+ => ' ' / ',' => ''`)
+
+	fmt.Print(g)
+	fmt.Println(g.Parse(scanner.New("one   two,three\n")))
+
+	var buf []rune
+	g.Scan(scanner.New("a,b\n"), &buf)
+	fmt.Printf("%q\n", string(buf))
+
+	// Output:
+	// Line <-- Word (Sep Word)* LF => ''
+	// Word <-- ^'<' lower+ ^'>'
+	// Sep  <- SP+ => ' ' / ',' => ''
+	// {"T":1,"N":[{"T":2,"V":"<one>"},{"T":2,"V":"<two>"},{"T":2,"V":"<three>"}]}
+	// "<a><b>"
+}
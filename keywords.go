@@ -0,0 +1,81 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+package pegn
+
+import "unicode"
+
+// KeywordSet returns a ScanFunc matching the longest of the keywords
+// that is not immediately followed by a word rune (letter, digit, or
+// underscore) so that "int" matches in "int x" but nothing matches in
+// "integer". The keywords are compiled into a trie so matching takes the
+// same time no matter how many there are (unlike trying each with Peek
+// in turn). On failure an untyped (0) Error is pushed at the start
+// position (see Scanner.Revert for reporting a specific type instead).
+func KeywordSet(words ...string) ScanFunc {
+	root := &kwnode{}
+	for _, w := range words {
+		if w == "" {
+			continue
+		}
+		n := root
+		for _, r := range w {
+			if n.next == nil {
+				n.next = map[rune]*kwnode{}
+			}
+			c, has := n.next[r]
+			if !has {
+				c = &kwnode{}
+				n.next[r] = c
+			}
+			n = c
+		}
+		n.end = true
+	}
+
+	return func(s Scanner, buf *[]rune) bool {
+		m := s.Mark()
+		var runes []rune
+		var found int
+		last := m
+		n := root
+		for n.next != nil && s.Scan() {
+			if n = n.next[s.Rune()]; n == nil {
+				break
+			}
+			runes = append(runes, s.Rune())
+			if n.end {
+				c := s.Mark()
+				if !s.Scan() || !isWordRune(s.Rune()) {
+					last, found = c, len(runes)
+				}
+				s.Goto(c)
+			}
+		}
+		if found == 0 {
+			s.Goto(m)
+			return s.Expected(0)
+		}
+		s.Goto(last)
+		if l, is := s.(lastMatcher); is {
+			l.SetLastMatch(m.E, last.E)
+		}
+		if buf != nil {
+			*buf = append(*buf, runes[:found]...)
+		}
+		return true
+	}
+}
+
+// lastMatcher is implemented by scanners that record the last match
+// (see scanner.S.LastMatch).
+type lastMatcher interface{ SetLastMatch(b, e int) }
+
+type kwnode struct {
+	next map[rune]*kwnode
+	end  bool
+}
+
+func isWordRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
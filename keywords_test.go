@@ -0,0 +1,32 @@
+package pegn_test
+
+import (
+	"fmt"
+
+	"github.com/rwxrob/pegn"
+	"github.com/rwxrob/pegn/scanner"
+)
+
+func ExampleKeywordSet() {
+
+	kw := pegn.KeywordSet(`if`, `in`, `int`, `interface`, `for`)
+
+	for _, in := range []string{`int x`, `in(`, `interface{}`, `integer`, `for`, `fork`} {
+		s := scanner.New(in)
+		buf := []rune{}
+		fmt.Printf("%-13q %-5v %q\n", in, kw(s, &buf), string(buf))
+	}
+
+	s := scanner.New(`iffy`)
+	kw(s, nil)
+	fmt.Println(s.Errors())
+
+	// Output:
+	// "int x"       true  "int"
+	// "in("         true  "in"
+	// "interface{}" true  "interface"
+	// "integer"     false ""
+	// "for"         true  "for"
+	// "fork"        false ""
+	// &[expecting type 0 at '\x00' 0-0]
+}
@@ -0,0 +1,168 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+/*
+Package lang is the catalog of messages (see Catalog) used when
+reporting errors to end users so that errors (along with the rule
+descriptions of model.Rule.Desc, see rule.Describe) are written in the
+language of the user. The language (Lang) is negotiated from the
+environment when the program starts and can be set to any other
+explicitly (see Negotiate). Messages missing from the catalog for
+a language are written in English.
+*/
+package lang
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/rwxrob/pegn/model"
+)
+
+// Catalog is a set of messages (fmt formats) by key in every language
+// available.
+type Catalog map[string]model.LangMap
+
+// Default is the catalog of every message reported by this module.
+// Add a language by adding its messages for every key.
+var Default = Catalog{
+	`expecting`: {
+		`en`: `expecting %v at %v`,
+		`de`: `erwartet %v bei %v`,
+		`es`: `se esperaba %v en %v`,
+		`fr`: `%v attendu à %v`,
+	},
+	`position`: {
+		`en`: `line %v, column %v`,
+		`de`: `Zeile %v, Spalte %v`,
+		`es`: `línea %v, columna %v`,
+		`fr`: `ligne %v, colonne %v`,
+	},
+	`type`: {
+		`en`: `type %v`,
+		`de`: `Typ %v`,
+		`es`: `tipo %v`,
+		`fr`: `type %v`,
+	},
+	`or`: {
+		`en`: `or`,
+		`de`: `oder`,
+		`es`: `o`,
+		`fr`: `ou`,
+	},
+	`example`: {
+		`en`: `e.g. %q`,
+		`de`: `z. B. %q`,
+		`es`: `p. ej. %q`,
+		`fr`: `p. ex. %q`,
+	},
+}
+
+// Lang is the language of all messages (see Sprintf) and rule
+// descriptions. It is negotiated from the environment (see FromEnv)
+// when the program starts and may be set to any language (ex: de,
+// pt_BR) explicitly, usually with Negotiate.
+var Lang = FromEnv()
+
+// Langs returns the languages of the catalog (those with every message)
+// in sorted order.
+func (c Catalog) Langs() []string {
+	count := map[string]int{}
+	for _, m := range c {
+		for l := range m {
+			count[l]++
+		}
+	}
+	var langs []string
+	for l, n := range count {
+		if n == len(c) {
+			langs = append(langs, l)
+		}
+	}
+	sort.Strings(langs)
+	return langs
+}
+
+// Sprintf returns the message of the key in the language (see
+// model.LangMap.Get) formatted with the arguments. The key itself is
+// used if not in the catalog.
+func (c Catalog) Sprintf(lang, key string, args ...any) string {
+	format := c[key].Get(lang)
+	if format == "" {
+		format = key
+	}
+	return fmt.Sprintf(format, args...)
+}
+
+// Sprintf calls Default.Sprintf with Lang.
+func Sprintf(key string, args ...any) string { return Default.Sprintf(Lang, key, args...) }
+
+// Or returns the items joined as a list of choices in Lang (a, b, or c).
+func Or(items ...string) string {
+	or := Sprintf(`or`)
+	switch n := len(items); {
+	case n == 0:
+		return ""
+	case n == 1:
+		return items[0]
+	case n == 2:
+		return items[0] + ` ` + or + ` ` + items[1]
+	}
+	last := len(items) - 1
+	list := strings.Join(items[:last], `, `)
+	if or == Default[`or`][`en`] { // English (or no translation)
+		list += `,`
+	}
+	return list + ` ` + or + ` ` + items[last]
+}
+
+// Negotiate returns the first of the preferred languages (most
+// preferred first) available from the Default catalog (or of which the
+// language without the region is) and English (en) if none are. Each
+// may be a locale (de_DE.UTF-8), an IETF language tag (pt-BR), or
+// a list of either (de:fr or de-CH, fr;q=0.8 as in Accept-Language).
+func Negotiate(prefs ...string) string {
+	have := map[string]bool{}
+	for _, l := range Default.Langs() {
+		have[l] = true
+	}
+	for _, p := range prefs {
+		for _, l := range strings.FieldsFunc(p, func(r rune) bool { return r == ':' || r == ',' }) {
+			l = normalize(l)
+			if have[l] || have[base(l)] {
+				return l
+			}
+		}
+	}
+	return `en`
+}
+
+// FromEnv negotiates (see Negotiate) the language from the LANGUAGE,
+// LC_ALL, LC_MESSAGES, and LANG environment variables in that order.
+func FromEnv() string {
+	var prefs []string
+	for _, k := range []string{`LANGUAGE`, `LC_ALL`, `LC_MESSAGES`, `LANG`} {
+		if v := os.Getenv(k); v != "" {
+			prefs = append(prefs, v)
+		}
+	}
+	return Negotiate(prefs...)
+}
+
+// normalize returns the language of a locale or language tag with any
+// encoding, modifier, or weight removed and the region separated with
+// an underscore (pt_BR).
+func normalize(l string) string {
+	l, _, _ = strings.Cut(strings.TrimSpace(l), `;`)
+	l, _, _ = strings.Cut(l, `.`)
+	l, _, _ = strings.Cut(l, `@`)
+	return strings.ReplaceAll(l, `-`, `_`)
+}
+
+// base returns the language without the region (pt_BR is pt).
+func base(l string) string {
+	b, _, _ := strings.Cut(l, `_`)
+	return b
+}
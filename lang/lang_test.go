@@ -0,0 +1,35 @@
+package lang_test
+
+import (
+	"fmt"
+
+	"github.com/rwxrob/pegn/lang"
+)
+
+func ExampleNegotiate() {
+	fmt.Println(lang.Default.Langs())
+	fmt.Println(lang.Negotiate(`de_DE.UTF-8`))
+	fmt.Println(lang.Negotiate(`ja_JP.UTF-8`, `fr-CA, en;q=0.8`))
+	fmt.Println(lang.Negotiate(`ja:pt_BR`))
+	fmt.Println(lang.Negotiate())
+	// Output:
+	// [de en es fr]
+	// de_DE
+	// fr_CA
+	// en
+	// en
+}
+
+func ExampleSprintf() {
+	for _, l := range []string{`en`, `de_AT`, `es`, `ja`} {
+		lang.Lang = l
+		fmt.Println(lang.Sprintf(`position`, 2, 7), `|`, lang.Or(`Key`, `Val`, `Sep`))
+	}
+	fmt.Println(lang.Sprintf(`no such key %v`, 1))
+	// Output:
+	// line 2, column 7 | Key, Val, or Sep
+	// Zeile 2, Spalte 7 | Key, Val oder Sep
+	// línea 2, columna 7 | Key, Val o Sep
+	// line 2, column 7 | Key, Val, or Sep
+	// no such key 1
+}
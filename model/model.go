@@ -5,7 +5,14 @@ Package model provides the data model and schema files associated with the PEGN
 */
 package model
 
-import _ "embed"
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
 
 //go:embed pegn.yaml
 var YAML string
@@ -13,10 +20,71 @@ var YAML string
 //go:embed pegn.pegn
 var PEGN string
 
+//go:embed classes.pegn
+var Classes string
+
+//go:embed tokens.pegn
+var Tokens string
+
 // LangMap is a map that contains strings associated with a specific
 // language identifier.
 type LangMap map[string]string
 
+// Langs returns the language identifiers in sorted order. Always use
+// it (rather than ranging over the map) when producing output so that
+// the output is the same every time.
+func (m LangMap) Langs() []string {
+	langs := make([]string, 0, len(m))
+	for k := range m {
+		langs = append(langs, k)
+	}
+	sort.Strings(langs)
+	return langs
+}
+
+// Get returns the string for the language (ex: en, en_US, pt-BR)
+// falling back to the language without its region (en), then to
+// English (en), and finally to the empty string.
+func (m LangMap) Get(lang string) string {
+	if v, has := m[lang]; has {
+		return v
+	}
+	if i := strings.IndexAny(lang, `_-`); i > 0 {
+		if v, has := m[lang[:i]]; has {
+			return v
+		}
+	}
+	return m[`en`]
+}
+
+// Rule types corresponding to the PEGN case conventions.
+const (
+	RuleType  = iota // RuleName (Mixed)
+	TokenType        // TOKEN_NAME (CAPS)
+	ClassType        // class_name (lower)
+)
+
+// TypeOf returns the rule type (RuleType, TokenType, ClassType) implied
+// by the case of the name alone.
+func TypeOf(name string) int {
+	var upper, lower bool
+	for _, r := range name {
+		switch {
+		case r >= 'A' && r <= 'Z':
+			upper = true
+		case r >= 'a' && r <= 'z':
+			lower = true
+		}
+	}
+	switch {
+	case upper && !lower:
+		return TokenType
+	case lower && !upper:
+		return ClassType
+	}
+	return RuleType
+}
+
 // Rule structs bring together all the required parts in a way that can
 // also be marshalled as JSON (see docs). All PEGN rules have a unique
 // integer ID and type (0 rule, 1 token, and 2 class) that
@@ -24,11 +92,66 @@ type LangMap map[string]string
 // rules must ever have the same case-insensitive name. Rules
 // often have their descriptions (Desc) omitted until needed when they
 // can be dynamically loaded based on the languages needed.  The rest of
-// the properties are language agnostic.
+// the properties are language agnostic. A rule is deprecated if
+// Deprecated is not empty and is an alias if AliasOf is not empty, which
+// allows grammars to rename and retire rules without breaking existing
+// documents or code referring to them by name. Valid and Invalid are
+// examples of what the rule must and must not match completely which
+// double as tests of the rule and as hints in error messages.
 type Rule struct {
 	ID   int     `json:"id,omitempty"`   // uniq type identifier
 	Name string  `json:"name,omitempty"` // RuleName, TokenName, ClassName
 	Type int     `json:"type"`           // 0 rule, 1 token, 2, class
 	PEGN string  `json:"pegn,omitempty"` // specific PEGN notation
 	Desc LangMap `json:"desc,omitempty"` // human-friendly descriptions
+
+	Deprecated string `json:"deprecated,omitempty"` // why and what instead
+	AliasOf    string `json:"aliasof,omitempty"`    // rule this is another name for
+
+	Valid   []string `json:"valid,omitempty"`   // examples that must match
+	Invalid []string `json:"invalid,omitempty"` // examples that must not match
+}
+
+// MarshalText fulfills encoding.TextMarshaler by returning the PEGN
+// notation of the rule. This allows rules to be used naturally as
+// values in YAML, TOML, and command line flags.
+func (r Rule) MarshalText() ([]byte, error) { return []byte(r.PEGN), nil }
+
+// UnmarshalText fulfills encoding.TextUnmarshaler by setting the PEGN
+// notation along with the Name and Type implied by it. Any ID and Desc
+// are left unchanged. Returns an error if the text is not a single
+// PEGN definition (Name <- ...).
+func (r *Rule) UnmarshalText(text []byte) error {
+	pegn := strings.TrimSpace(string(text))
+	f := strings.Fields(pegn)
+	if len(f) < 3 || (f[1] != `<-` && f[1] != `<--`) {
+		return fmt.Errorf(`not a PEGN definition: %q`, pegn)
+	}
+	r.Name = f[0]
+	r.Type = TypeOf(r.Name)
+	r.PEGN = pegn
+	return nil
+}
+
+// rule prevents recursion into MarshalJSON and away from MarshalText
+type rule Rule
+
+// MarshalJSON fulfills json.Marshaler so that the JSON form of a Rule
+// remains an object rather than the string from MarshalText. Like
+// ast.Node, HTML characters (common in PEGN) are not escaped.
+func (r Rule) MarshalJSON() ([]byte, error) {
+	buf := new(bytes.Buffer)
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(rule(r)); err != nil {
+		return nil, err
+	}
+	byt := buf.Bytes()
+	return byt[:len(byt)-1], nil
+}
+
+// UnmarshalJSON fulfills json.Unmarshaler for the same reason as
+// MarshalJSON.
+func (r *Rule) UnmarshalJSON(b []byte) error {
+	return json.Unmarshal(b, (*rule)(r))
 }
@@ -1,5 +1,11 @@
 package model_test
 
+import (
+	"fmt"
+
+	"github.com/rwxrob/pegn/model"
+)
+
 /*
 
 func ExampleYAML() {
@@ -14,3 +20,54 @@ func ExamplePEGN() {
 	// some
 }
 */
+
+func ExampleTypeOf() {
+	fmt.Println(model.TypeOf(`RuleName`))
+	fmt.Println(model.TypeOf(`TOKEN_NAME`))
+	fmt.Println(model.TypeOf(`class_name`))
+	// Output:
+	// 0
+	// 1
+	// 2
+}
+
+func ExampleRule_MarshalText() {
+
+	r := new(model.Rule)
+	err := r.UnmarshalText([]byte(`MajorVer <-- digit+`))
+	fmt.Println(err)
+
+	text, _ := r.MarshalText()
+	fmt.Println(string(text))
+
+	byt, _ := r.MarshalJSON()
+	fmt.Println(string(byt))
+
+	fmt.Println(r.UnmarshalText([]byte(`MajorVer digit+`)))
+
+	// Output:
+	// <nil>
+	// MajorVer <-- digit+
+	// {"name":"MajorVer","type":0,"pegn":"MajorVer <-- digit+"}
+	// not a PEGN definition: "MajorVer digit+"
+}
+
+func ExampleLangMap_Langs() {
+	m := model.LangMap{`fr`: `règle`, `en`: `rule`, `de`: `Regel`}
+	for _, l := range m.Langs() {
+		fmt.Println(l, m[l])
+	}
+	// Output:
+	// de Regel
+	// en rule
+	// fr règle
+}
+
+func ExampleLangMap_Get() {
+	m := model.LangMap{`fr`: `règle`, `en`: `rule`, `pt_BR`: `regra`}
+	fmt.Println(m.Get(`fr`), m.Get(`fr_CA`), m.Get(`pt-PT`), m.Get(`pt_BR`))
+	fmt.Printf("%q\n", model.LangMap{}.Get(`en`))
+	// Output:
+	// règle règle rule regra
+	// ""
+}
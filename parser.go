@@ -0,0 +1,16 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+package pegn
+
+// ParserFor returns the standard ParseFunc for a rule with nothing but
+// a value: scan into a new buffer (with room for capHint runes so that
+// most never grow) and return a node of the rule type with the runes
+// buffered as its value (or nil, with the error pushed by scan, if it
+// fails). See ParserSized for rules with captures of widely varying
+// size.
+//
+//	var Parse_Version = pegn.ParserFor(Version, Scan_Version, 16)
+func ParserFor(t int, scan ScanFunc, capHint int) ParseFunc {
+	return ParserSized(t, scan, FixedCap(capHint))
+}
@@ -0,0 +1,22 @@
+package pegn_test
+
+import (
+	"fmt"
+
+	"github.com/rwxrob/pegn"
+	"github.com/rwxrob/pegn/scanner"
+)
+
+func ExampleParserFor() {
+	const Keyword = 1
+	parse := pegn.ParserFor(Keyword, pegn.KeywordSet(`int`, `string`), 8)
+
+	s := scanner.New(`string x`)
+	n := parse(s)
+	fmt.Println(n, n.B, n.E)
+	fmt.Println(parse(s))
+
+	// Output:
+	// {"T":1,"V":"string"} 0 6
+	// <nil>
+}
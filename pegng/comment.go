@@ -0,0 +1,157 @@
+package pegng
+
+import (
+	"github.com/rwxrob/pegn"
+	"github.com/rwxrob/pegn/ast"
+)
+
+// Comment describes line comments (Prefix to end of line), block
+// comments (Open to Close), or both so that they can be scanned (see
+// Scan) alone or skipped along with white space (see Skip). Block
+// comments may optionally be Nested (as in Rust, Swift, and Haskell).
+type Comment struct {
+	T      int    // type for errors and nodes
+	Prefix string // begins a line comment (ex: //) or empty for none
+	Open   string // begins a block comment (ex: /*) or empty for none
+	Close  string // ends a block comment (ex: */)
+	Nested bool   // allow blocks within blocks
+}
+
+// Scan fulfills pegn.ScanFunc buffering only the text of the comment
+// (without Prefix, Open, or Close). A line comment never includes the
+// line ending (which is left to be scanned next) but may end at the end
+// of data. A block comment that is never closed fails.
+func (c Comment) Scan(s pegn.Scanner, buf *[]rune) bool {
+	m := s.Mark()
+	var n int
+	if buf != nil {
+		n = len(*buf)
+	}
+
+	switch {
+
+	case c.Prefix != "" && s.Peek(c.Prefix):
+		skip(s, c.Prefix)
+		for !s.Peek("\n") && !s.Peek("\r\n") && s.Scan() {
+			if buf != nil {
+				*buf = append(*buf, s.Rune())
+			}
+		}
+		return true
+
+	case c.Open != "" && s.Peek(c.Open):
+		skip(s, c.Open)
+		depth := 1
+		for {
+			switch {
+			case s.Peek(c.Close):
+				depth--
+				if depth == 0 {
+					skip(s, c.Close)
+					return true
+				}
+				c.add(s, buf, c.Close)
+			case c.Nested && s.Peek(c.Open):
+				depth++
+				c.add(s, buf, c.Open)
+			case s.Scan():
+				if buf != nil {
+					*buf = append(*buf, s.Rune())
+				}
+			default:
+				if buf != nil {
+					*buf = (*buf)[:n]
+				}
+				return s.Revert(m, c.T)
+			}
+		}
+	}
+
+	return s.Revert(m, c.T)
+}
+
+// add scans past the delimiter buffering it as part of the text.
+func (c Comment) add(s pegn.Scanner, buf *[]rune, delim string) {
+	skip(s, delim)
+	if buf != nil {
+		*buf = append(*buf, []rune(delim)...)
+	}
+}
+
+// skip scans past the string (already known to be next).
+func skip(s pegn.Scanner, a string) {
+	for range a {
+		s.Scan()
+	}
+}
+
+// Parse returns a node with the text of the comment as its value.
+func (c Comment) Parse(s pegn.Scanner) *ast.Node {
+	return pegn.ParserFor(c.T, c.Scan, 32)(s)
+}
+
+// Skip returns a pegn.ScanFunc that scans past any number of matches
+// of the given scan functions in any order (usually white space and
+// comments) and never fails. Nothing is buffered and errors pushed by
+// the scan functions while trying are removed. Use it between the
+// elements of rules wherever a grammar allows white space.
+//
+//	ws := pegng.Skip(pegng.Scan_ws, pegng.Scan_CComment)
+func Skip(fns ...pegn.ScanFunc) pegn.ScanFunc {
+	return func(s pegn.Scanner, buf *[]rune) bool {
+		errs := s.Errors()
+		n := len(*errs)
+		for more := true; more; {
+			more = false
+			for _, fn := range fns {
+				b := s.RuneE()
+				if fn(s, nil) && s.RuneE() > b {
+					more = true
+				}
+			}
+		}
+		*errs = (*errs)[:n]
+		return true
+	}
+}
+
+// ---------------------------- HashComment ---------------------------
+
+var hashcomment = Comment{T: HashComment, Prefix: `#`}
+
+// Scan_HashComment scans a line comment beginning with # (as in shell,
+// Python, YAML, TOML, and PEGN itself).
+func Scan_HashComment(s pegn.Scanner, buf *[]rune) bool {
+	return hashcomment.Scan(s, buf)
+}
+
+// Parse_HashComment returns a node with the text of the comment.
+func Parse_HashComment(s pegn.Scanner) *ast.Node { return hashcomment.Parse(s) }
+
+// ----------------------------- CComment -----------------------------
+
+var ccomment = Comment{T: CComment, Prefix: `//`, Open: `/*`, Close: `*/`}
+
+// Scan_CComment scans a line (//) or block (/* */) comment that does
+// not nest (as in C, Go, Java, and JavaScript).
+func Scan_CComment(s pegn.Scanner, buf *[]rune) bool {
+	return ccomment.Scan(s, buf)
+}
+
+// Parse_CComment returns a node with the text of the comment.
+func Parse_CComment(s pegn.Scanner) *ast.Node { return ccomment.Parse(s) }
+
+// --------------------------- NestedComment --------------------------
+
+var nestedcomment = Comment{
+	T: NestedComment, Prefix: `//`, Open: `/*`, Close: `*/`, Nested: true,
+}
+
+// Scan_NestedComment is the same as Scan_CComment but block comments
+// nest (as in Rust, Swift, and Scala).
+func Scan_NestedComment(s pegn.Scanner, buf *[]rune) bool {
+	return nestedcomment.Scan(s, buf)
+}
+
+// Parse_NestedComment returns a node with the text of the comment.
+func Parse_NestedComment(s pegn.Scanner) *ast.Node { return nestedcomment.Parse(s) }
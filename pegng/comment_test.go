@@ -0,0 +1,62 @@
+package pegng_test
+
+import (
+	"fmt"
+
+	"github.com/rwxrob/pegn/pegng"
+	"github.com/rwxrob/pegn/scanner"
+)
+
+func ExampleComment() {
+
+	// Pascal and ML style
+	c := pegng.Comment{T: 1, Open: `(*`, Close: `*)`, Nested: true}
+
+	fmt.Println(c.Parse(scanner.New(`(* a (* b *) c *)d`)))
+	fmt.Println(c.Parse(scanner.New(`(* open (* *)`)))
+
+	// Output:
+	// {"T":1,"V":" a (* b *) c "}
+	// <nil>
+}
+
+func Example_scanCComment() {
+
+	s := scanner.New("/* a /* b */ c */\n// line\nx")
+	buf := []rune{}
+	fmt.Println(pegng.Scan_CComment(s, &buf))
+	fmt.Printf("%q\n", string(buf))
+	s.Print()
+
+	s = scanner.New("// line\r\nx")
+	fmt.Println(pegng.Parse_CComment(s))
+	fmt.Println(pegng.Parse_NestedComment(scanner.New(`/* a /* b */ c */`)))
+	fmt.Println(pegng.Parse_HashComment(scanner.New(`# end`)))
+
+	// Output:
+	// true
+	// " a /* b "
+	// '/' 11-12 " c */\n// l"
+	// {"T":-15,"V":" line"}
+	// {"T":-16,"V":" a /* b */ c "}
+	// {"T":-14,"V":" end"}
+}
+
+func ExampleSkip() {
+
+	ws := pegng.Skip(pegng.Scan_ws, pegng.Scan_CComment)
+
+	s := scanner.New(" // one\n  /* two */\tx")
+	fmt.Println(ws(s, nil))
+	s.Print()
+	fmt.Println(ws(s, nil))
+	s.Print()
+	fmt.Println(s.Errors())
+
+	// Output:
+	// true
+	// '\t' 19-20 "x"
+	// true
+	// '\t' 19-20 "x"
+	// &[]
+}
@@ -0,0 +1,235 @@
+package pegng
+
+import (
+	"unicode/utf8"
+
+	"github.com/rwxrob/pegn"
+	"github.com/rwxrob/pegn/ast"
+)
+
+// ------------------------------ helpers -----------------------------
+
+// hexval returns the value of the hexadecimal digit (either case) or -1.
+func hexval(r rune) int {
+	switch {
+	case '0' <= r && r <= '9':
+		return int(r - '0')
+	case 'a' <= r && r <= 'f':
+		return int(r-'a') + 10
+	case 'A' <= r && r <= 'F':
+		return int(r-'A') + 10
+	}
+	return -1
+}
+
+// scanhex scans exactly n hexadecimal digits returning their value.
+func scanhex(s pegn.Scanner, n int) (rune, bool) {
+	var v rune
+	for i := 0; i < n; i++ {
+		if !s.Scan() {
+			return 0, false
+		}
+		d := hexval(s.Rune())
+		if d < 0 {
+			return 0, false
+		}
+		v = v<<4 | rune(d)
+	}
+	return v, true
+}
+
+// parseDecoded wraps a decoding ScanFunc into a node with the decoded
+// rune as its value.
+func parseDecoded(s pegn.Scanner, t int, scan pegn.ScanFunc) *ast.Node {
+	return pegn.ParserFor(t, scan, 1)(s)
+}
+
+// ------------------------------ Entity ------------------------------
+
+// Entities maps the names of the HTML character references recognized
+// by Scan_Entity to the runes they represent. Only the most common are
+// included. Add to it (before scanning) to recognize more.
+var Entities = map[string]rune{
+	`amp`: '&', `lt`: '<', `gt`: '>', `quot`: '"', `apos`: '\'',
+	`nbsp`: '\u00A0', `iexcl`: '¡', `cent`: '¢', `pound`: '£',
+	`curren`: '¤', `yen`: '¥', `brvbar`: '¦', `sect`: '§', `uml`: '¨',
+	`copy`: '©', `ordf`: 'ª', `laquo`: '«', `not`: '¬', `shy`: '\u00AD',
+	`reg`: '®', `macr`: '¯', `deg`: '°', `plusmn`: '±', `sup2`: '²',
+	`sup3`: '³', `acute`: '´', `micro`: 'µ', `para`: '¶', `middot`: '·',
+	`cedil`: '¸', `sup1`: '¹', `ordm`: 'º', `raquo`: '»', `frac14`: '¼',
+	`frac12`: '½', `frac34`: '¾', `iquest`: '¿', `times`: '×',
+	`divide`: '÷', `ndash`: '–', `mdash`: '—', `lsquo`: '‘',
+	`rsquo`: '’', `sbquo`: '‚', `ldquo`: '“', `rdquo`: '”', `bdquo`: '„',
+	`dagger`: '†', `Dagger`: '‡', `bull`: '•', `hellip`: '…',
+	`permil`: '‰', `prime`: '′', `Prime`: '″', `lsaquo`: '‹',
+	`rsaquo`: '›', `euro`: '€', `trade`: '™', `larr`: '←', `uarr`: '↑',
+	`rarr`: '→', `darr`: '↓', `harr`: '↔', `minus`: '−', `ne`: '≠',
+	`le`: '≤', `ge`: '≥', `infin`: '∞', `ensp`: '\u2002',
+	`emsp`: '\u2003', `thinsp`: '\u2009', `zwnj`: '\u200C',
+	`zwj`: '\u200D', `lrm`: '\u200E', `rlm`: '\u200F',
+}
+
+// Scan_Entity scans an HTML character reference, either named
+// (&amp;) from Entities or numeric in decimal (&#38;) or hexadecimal
+// (&#x26;), and buffers the single rune it represents. The trailing
+// semicolon is required.
+//
+//	Entity  <-- '&' (name / '#' ([xX] hexdig+ / digit+)) ';'
+func Scan_Entity(s pegn.Scanner, buf *[]rune) bool {
+	m := s.Mark()
+	if !s.Peek(`&`) {
+		return s.Revert(m, Entity)
+	}
+	s.Scan()
+	if !s.Scan() {
+		return s.Revert(m, Entity)
+	}
+
+	var r rune
+	if s.Rune() == '#' {
+		base, n := rune(10), 0
+		if s.Peek(`x`) || s.Peek(`X`) {
+			s.Scan()
+			base = 16
+		}
+		for !s.Peek(`;`) {
+			if !s.Scan() || n > 7 {
+				return s.Revert(m, Entity)
+			}
+			d := hexval(s.Rune())
+			if d < 0 || rune(d) >= base {
+				return s.Revert(m, Entity)
+			}
+			r = r*base + rune(d)
+			n++
+		}
+		if n == 0 || !utf8.ValidRune(r) {
+			return s.Revert(m, Entity)
+		}
+	} else {
+		name := []rune{s.Rune()}
+		for !s.Peek(`;`) && len(name) < 32 {
+			if !s.Scan() {
+				return s.Revert(m, Entity)
+			}
+			name = append(name, s.Rune())
+		}
+		var has bool
+		if r, has = Entities[string(name)]; !has {
+			return s.Revert(m, Entity)
+		}
+	}
+
+	if !s.Peek(`;`) {
+		return s.Revert(m, Entity)
+	}
+	s.Scan()
+	if buf != nil {
+		*buf = append(*buf, r)
+	}
+	return true
+}
+
+// Parse_Entity returns a node with the decoded rune as its value.
+func Parse_Entity(s pegn.Scanner) *ast.Node {
+	return parseDecoded(s, Entity, Scan_Entity)
+}
+
+// ------------------------------ UEscape -----------------------------
+
+// Scan_UEscape scans a unicode escape of exactly four hexadecimal
+// digits (\u00e9) and buffers the rune it represents. As in JSON and
+// JavaScript, runes beyond the basic multilingual plane must be given
+// as a surrogate pair (\uD83D\uDE00) which is combined into a single
+// rune. Unpaired surrogates are never valid.
+//
+//	UEscape  <-- '\u' hexdig{4} ('\u' hexdig{4})?
+func Scan_UEscape(s pegn.Scanner, buf *[]rune) bool {
+	m := s.Mark()
+	if !s.Peek(`\u`) {
+		return s.Revert(m, UEscape)
+	}
+	s.Scan()
+	s.Scan()
+	r, ok := scanuhex(s, '\\')
+	if !ok {
+		return s.Revert(m, UEscape)
+	}
+	if buf != nil {
+		*buf = append(*buf, r)
+	}
+	return true
+}
+
+// scanuhex scans the four hexadecimal digits following a unicode
+// escape (esc + 'u') along with the second half of a surrogate pair
+// (if needed) returning the rune.
+func scanuhex(s pegn.Scanner, esc rune) (rune, bool) {
+	r, ok := scanhex(s, 4)
+	if !ok {
+		return 0, false
+	}
+	switch {
+	case 0xDC00 <= r && r <= 0xDFFF:
+		return 0, false
+	case 0xD800 <= r && r <= 0xDBFF:
+		if !s.Peek(string(esc) + `u`) {
+			return 0, false
+		}
+		s.Scan()
+		s.Scan()
+		lo, ok := scanhex(s, 4)
+		if !ok || lo < 0xDC00 || lo > 0xDFFF {
+			return 0, false
+		}
+		r = 0x10000 + (r-0xD800)<<10 + (lo - 0xDC00)
+	}
+	return r, true
+}
+
+// Parse_UEscape returns a node with the decoded rune as its value.
+func Parse_UEscape(s pegn.Scanner) *ast.Node {
+	return parseDecoded(s, UEscape, Scan_UEscape)
+}
+
+// ----------------------------- PctEncoded ---------------------------
+
+// Scan_PctEncoded scans enough percent-encoded bytes (%C3%A9) to make
+// up a single UTF-8 encoded rune (1-4 bytes) and buffers that rune.
+// Hexadecimal digits may be either case. Bytes that are not valid UTF-8
+// fail to match.
+//
+//	PctEncoded  <-- ('%' hexdig{2}){1,4}
+func Scan_PctEncoded(s pegn.Scanner, buf *[]rune) bool {
+	m := s.Mark()
+	var b [utf8.UTFMax]byte
+	var n int
+	for n < len(b) {
+		if !s.Peek(`%`) {
+			return s.Revert(m, PctEncoded)
+		}
+		s.Scan()
+		v, ok := scanhex(s, 2)
+		if !ok {
+			return s.Revert(m, PctEncoded)
+		}
+		b[n] = byte(v)
+		n++
+		if utf8.FullRune(b[:n]) {
+			break
+		}
+	}
+	r, size := utf8.DecodeRune(b[:n])
+	if r == utf8.RuneError && size <= 1 {
+		return s.Revert(m, PctEncoded)
+	}
+	if buf != nil {
+		*buf = append(*buf, r)
+	}
+	return true
+}
+
+// Parse_PctEncoded returns a node with the decoded rune as its value.
+func Parse_PctEncoded(s pegn.Scanner) *ast.Node {
+	return parseDecoded(s, PctEncoded, Scan_PctEncoded)
+}
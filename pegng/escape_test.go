@@ -0,0 +1,87 @@
+package pegng_test
+
+import (
+	"fmt"
+
+	"github.com/rwxrob/pegn/pegng"
+	"github.com/rwxrob/pegn/scanner"
+)
+
+func Example_scanEntity() {
+
+	for _, in := range []string{
+		`&amp;`, `&eacute;`, `&#233;`, `&#xE9;`, `&copy; 2022`, `&amp`, `&#x;`,
+	} {
+		s := scanner.New(in)
+		buf := []rune{}
+		ok := pegng.Scan_Entity(s, &buf)
+		fmt.Printf("%-12q %-5v %q %v\n", in, ok, string(buf), s.RuneE())
+	}
+
+	pegng.Entities[`eacute`] = 'é'
+	s := scanner.New(`&eacute;`)
+	fmt.Println(pegng.Parse_Entity(s))
+
+	// Output:
+	// "&amp;"      true  "&" 5
+	// "&eacute;"   false "" 0
+	// "&#233;"     true  "é" 6
+	// "&#xE9;"     true  "é" 6
+	// "&copy; 2022" true  "©" 6
+	// "&amp"       false "" 0
+	// "&#x;"       false "" 0
+	// {"T":-2,"V":"é"}
+}
+
+func Example_scanUEscape() {
+
+	for _, in := range []string{
+		`\u00e9`, `\u00E9!`, `\uD83D\uDE00`, `\uD83D`, `\uDE00`, `\u0e9`,
+	} {
+		s := scanner.New(in)
+		buf := []rune{}
+		ok := pegng.Scan_UEscape(s, &buf)
+		fmt.Printf("%-15q %-5v %q %v\n", in, ok, string(buf), s.RuneE())
+	}
+
+	s := scanner.New(`\u0041`)
+	fmt.Println(pegng.Parse_UEscape(s))
+
+	// Output:
+	// "\\u00e9"       true  "é" 6
+	// "\\u00E9!"      true  "é" 6
+	// "\\uD83D\\uDE00" true  "😀" 12
+	// "\\uD83D"       false "" 0
+	// "\\uDE00"       false "" 0
+	// "\\u0e9"        false "" 0
+	// {"T":-3,"V":"A"}
+}
+
+func Example_scanPctEncoded() {
+
+	for _, in := range []string{
+		`%20`, `%c3%A9`, `%F0%9F%98%80`, `%C3`, `%FF`, `%2`,
+	} {
+		s := scanner.New(in)
+		buf := []rune{}
+		ok := pegng.Scan_PctEncoded(s, &buf)
+		fmt.Printf("%-14q %-5v %q %v\n", in, ok, string(buf), s.RuneE())
+	}
+
+	s := scanner.New(`%41%42`)
+	fmt.Println(pegng.Parse_PctEncoded(s))
+	fmt.Println(pegng.Parse_PctEncoded(s))
+	s = scanner.New(`nope`)
+	fmt.Println(pegng.Parse_PctEncoded(s), s.Errors())
+
+	// Output:
+	// "%20"          true  " " 3
+	// "%c3%A9"       true  "é" 6
+	// "%F0%9F%98%80" true  "😀" 12
+	// "%C3"          false "" 0
+	// "%FF"          false "" 0
+	// "%2"           false "" 0
+	// {"T":-4,"V":"A"}
+	// {"T":-4,"V":"B"}
+	// <nil> &[expecting type -4 at '\x00' 0-0]
+}
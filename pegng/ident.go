@@ -0,0 +1,132 @@
+package pegng
+
+import (
+	"unicode"
+
+	"github.com/rwxrob/pegn"
+	"github.com/rwxrob/pegn/ast"
+)
+
+// Identifier describes an identifier as a first rune followed by any
+// number of rest runes. If Sep is set, single separators are also
+// allowed between rest runes (never first, last, or doubled). See
+// NewIdentifier and the Scan_*Ident functions.
+type Identifier struct {
+	T     int            // type for errors and nodes
+	First pegn.ClassFunc // class of first rune
+	Rest  pegn.ClassFunc // class of every rune after the first
+	Sep   rune           // separator between rest runes or 0 for none
+}
+
+// NewIdentifier returns an Identifier of the type with the given
+// classes for the first and remaining runes.
+func NewIdentifier(t int, first, rest pegn.ClassFunc) Identifier {
+	return Identifier{T: t, First: first, Rest: rest}
+}
+
+// Scan fulfills pegn.ScanFunc buffering the identifier.
+func (id Identifier) Scan(s pegn.Scanner, buf *[]rune) bool {
+	m := s.Mark()
+	if !s.Scan() || !id.First(s.Rune()) {
+		return s.Revert(m, id.T)
+	}
+	runes := []rune{s.Rune()}
+	for {
+		c := s.Mark()
+		sep := id.Sep != 0 && s.Peek(string(id.Sep))
+		if sep {
+			s.Scan()
+		}
+		if !s.Scan() || !id.Rest(s.Rune()) {
+			s.Goto(c)
+			break
+		}
+		if sep {
+			runes = append(runes, id.Sep)
+		}
+		runes = append(runes, s.Rune())
+	}
+	if buf != nil {
+		*buf = append(*buf, runes...)
+	}
+	return true
+}
+
+// Parse returns a node with the identifier as its value.
+func (id Identifier) Parse(s pegn.Scanner) *ast.Node {
+	return pegn.ParserFor(id.T, id.Scan, 16)(s)
+}
+
+// ------------------------------ XIdent ------------------------------
+
+// Is_xidstart approximates the Unicode XID_Start property (letters,
+// letter numbers, and Other_ID_Start) with the underscore added as is
+// customary in programming languages.
+var Is_xidstart = func(r rune) bool {
+	return r == '_' || unicode.In(r, unicode.L, unicode.Nl, unicode.Other_ID_Start) &&
+		!unicode.Is(unicode.Pattern_Syntax, r)
+}
+
+// Is_xidcontinue approximates the Unicode XID_Continue property
+// (XID_Start plus combining marks, decimal digits, connector
+// punctuation, and Other_ID_Continue).
+var Is_xidcontinue = func(r rune) bool {
+	return Is_xidstart(r) ||
+		unicode.In(r, unicode.Mn, unicode.Mc, unicode.Nd, unicode.Pc,
+			unicode.Other_ID_Continue) && !unicode.Is(unicode.Pattern_Syntax, r)
+}
+
+var xident = NewIdentifier(XIdent, Is_xidstart, Is_xidcontinue)
+
+// Scan_XIdent scans a Unicode identifier (UAX #31) such as those of
+// Go, Rust, Python, and JavaScript (café, _x1, 变量).
+func Scan_XIdent(s pegn.Scanner, buf *[]rune) bool { return xident.Scan(s, buf) }
+
+// Parse_XIdent returns a node with the identifier as its value.
+func Parse_XIdent(s pegn.Scanner) *ast.Node { return xident.Parse(s) }
+
+// ------------------------------- Ident ------------------------------
+
+var ident = NewIdentifier(Ident,
+	func(r rune) bool { return r == '_' || 'a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' },
+	func(r rune) bool {
+		return r == '_' || 'a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' || '0' <= r && r <= '9'
+	},
+)
+
+// Scan_Ident scans a traditional ASCII identifier.
+//
+//	Ident  <-- (alpha / '_') (alphanum / '_')*
+func Scan_Ident(s pegn.Scanner, buf *[]rune) bool { return ident.Scan(s, buf) }
+
+// Parse_Ident returns a node with the identifier as its value.
+func Parse_Ident(s pegn.Scanner) *ast.Node { return ident.Parse(s) }
+
+// ---------------------------- KebabIdent ----------------------------
+
+func isLower(r rune) bool      { return 'a' <= r && r <= 'z' }
+func isLowerDigit(r rune) bool { return isLower(r) || '0' <= r && r <= '9' }
+
+var kebab = Identifier{T: KebabIdent, First: isLower, Rest: isLowerDigit, Sep: '-'}
+
+// Scan_KebabIdent scans a lowercase identifier with words separated by
+// single dashes (as in command names and CSS).
+//
+//	KebabIdent  <-- lower (lower / digit)* ('-' (lower / digit)+)*
+func Scan_KebabIdent(s pegn.Scanner, buf *[]rune) bool { return kebab.Scan(s, buf) }
+
+// Parse_KebabIdent returns a node with the identifier as its value.
+func Parse_KebabIdent(s pegn.Scanner) *ast.Node { return kebab.Parse(s) }
+
+// ---------------------------- SnakeIdent ----------------------------
+
+var snake = Identifier{T: SnakeIdent, First: isLower, Rest: isLowerDigit, Sep: '_'}
+
+// Scan_SnakeIdent scans a lowercase identifier with words separated by
+// single underscores.
+//
+//	SnakeIdent  <-- lower (lower / digit)* ('_' (lower / digit)+)*
+func Scan_SnakeIdent(s pegn.Scanner, buf *[]rune) bool { return snake.Scan(s, buf) }
+
+// Parse_SnakeIdent returns a node with the identifier as its value.
+func Parse_SnakeIdent(s pegn.Scanner) *ast.Node { return snake.Parse(s) }
@@ -0,0 +1,64 @@
+package pegng_test
+
+import (
+	"fmt"
+	"unicode"
+
+	"github.com/rwxrob/pegn/pegng"
+	"github.com/rwxrob/pegn/scanner"
+)
+
+func ExampleIdentifier() {
+
+	// Lisp-like symbols
+	sym := pegng.NewIdentifier(1,
+		unicode.IsLetter,
+		func(r rune) bool { return unicode.IsLetter(r) || r == '?' || r == '!' },
+	)
+	sym.Sep = '-'
+
+	for _, in := range []string{`null?`, `set-car!`, `a--b`, `end-`, `-x`} {
+		s := scanner.New(in)
+		buf := []rune{}
+		fmt.Printf("%-10q %-5v %q\n", in, sym.Scan(s, &buf), string(buf))
+	}
+
+	// Output:
+	// "null?"    true  "null?"
+	// "set-car!" true  "set-car!"
+	// "a--b"     true  "a"
+	// "end-"     true  "end"
+	// "-x"       false ""
+}
+
+func Example_scanXIdent() {
+	for _, in := range []string{`café_1`, `_x`, `变量 = 1`, `1st`, `a·b`} {
+		fmt.Println(pegng.Parse_XIdent(scanner.New(in)))
+	}
+	// Output:
+	// {"T":-10,"V":"café_1"}
+	// {"T":-10,"V":"_x"}
+	// {"T":-10,"V":"变量"}
+	// <nil>
+	// {"T":-10,"V":"a·b"}
+}
+
+func Example_scanIdent() {
+	s := scanner.New(`_foo42.bar`)
+	buf := []rune{}
+	fmt.Println(pegng.Scan_Ident(s, &buf), string(buf))
+	fmt.Println(pegng.Parse_Ident(scanner.New(`café`)))
+	// Output:
+	// true _foo42
+	// {"T":-11,"V":"caf"}
+}
+
+func Example_scanKebabIdent() {
+	fmt.Println(pegng.Parse_KebabIdent(scanner.New(`go-to-2nd-page_x`)))
+	fmt.Println(pegng.Parse_KebabIdent(scanner.New(`Go`)))
+	fmt.Println(pegng.Parse_SnakeIdent(scanner.New(`max_len-1`)))
+	// Output:
+	// {"T":-12,"V":"go-to-2nd-page"}
+	// <nil>
+	// {"T":-13,"V":"max_len"}
+}
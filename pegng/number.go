@@ -0,0 +1,246 @@
+package pegng
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/rwxrob/pegn"
+	"github.com/rwxrob/pegn/ast"
+	"github.com/rwxrob/pegn/curs"
+)
+
+// Number describes which number literals are allowed so that they can
+// be scanned (see Scan) and read into typed values (see Read). With
+// everything allowed the literals are those of modern programming
+// languages (Go, Rust, Python, JavaScript):
+//
+//	Number   <-- sign? (Radix / Decimal)
+//	Radix    <-  '0' ([xX] ('_'? hexdig)+ / [oO] ('_'? octdig)+
+//	                / [bB] ('_'? bindig)+)
+//	Decimal  <-  Digits ('.' Digits)? ([eE] sign? Digits)?
+//	Digits   <-  digit ('_'? digit)*
+//
+// Leading zeros are always decimal (never octal as in C) and a radix
+// prefix without any digits after it fails to match.
+type Number struct {
+	T          int  // type for errors and nodes
+	Sign       bool // allow leading + or -
+	Radix      bool // allow 0x, 0o, and 0b prefixes
+	Float      bool // allow fraction and exponent
+	Underscore bool // allow single underscores between digits
+}
+
+// Scan fulfills pegn.ScanFunc buffering the literal exactly as written.
+func (n Number) Scan(s pegn.Scanner, buf *[]rune) bool {
+	m := s.Mark()
+	var lit []rune
+	if n.Sign && (s.Peek(`+`) || s.Peek(`-`)) {
+		s.Scan()
+		lit = append(lit, s.Rune())
+	}
+
+	if n.Radix && s.Peek(`0`) {
+		s.Scan()
+		if c, base := n.radix(s); base > 0 {
+			lit = append(lit, '0', c)
+			if !n.digits(s, &lit, base) {
+				return s.Revert(m, n.T)
+			}
+			if buf != nil {
+				*buf = append(*buf, lit...)
+			}
+			return true
+		}
+		s.Goto(m)
+		if len(lit) > 0 {
+			s.Scan()
+		}
+	}
+
+	if !n.digits(s, &lit, 10) {
+		return s.Revert(m, n.T)
+	}
+
+	if n.Float {
+		if s.Peek(`.`) {
+			c := s.Mark()
+			s.Scan()
+			frac := []rune{'.'}
+			if n.digits(s, &frac, 10) {
+				lit = append(lit, frac...)
+			} else {
+				s.Goto(c)
+			}
+		}
+		if s.Peek(`e`) || s.Peek(`E`) {
+			c := s.Mark()
+			s.Scan()
+			exp := []rune{s.Rune()}
+			if s.Peek(`+`) || s.Peek(`-`) {
+				s.Scan()
+				exp = append(exp, s.Rune())
+			}
+			if n.digits(s, &exp, 10) {
+				lit = append(lit, exp...)
+			} else {
+				s.Goto(c)
+			}
+		}
+	}
+
+	if buf != nil {
+		*buf = append(*buf, lit...)
+	}
+	return true
+}
+
+// radix scans the rune after a leading zero returning it and the base
+// it indicates (or 0 if not a radix prefix).
+func (n Number) radix(s pegn.Scanner) (rune, int) {
+	for _, p := range []struct {
+		c    string
+		base int
+	}{{`x`, 16}, {`X`, 16}, {`o`, 8}, {`O`, 8}, {`b`, 2}, {`B`, 2}} {
+		if s.Peek(p.c) {
+			s.Scan()
+			return s.Rune(), p.base
+		}
+	}
+	return 0, 0
+}
+
+// digits scans one or more digits of the base (with underscores
+// between them if allowed) appending them to lit. Digits following
+// a radix prefix may also begin with an underscore (as in Go).
+func (n Number) digits(s pegn.Scanner, lit *[]rune, base int) bool {
+	var count int
+	for {
+		c := s.Mark()
+		under := n.Underscore && s.Peek(`_`) && (count > 0 || base != 10)
+		if under {
+			s.Scan()
+		}
+		if !s.Scan() {
+			s.Goto(c)
+			break
+		}
+		d := hexval(s.Rune())
+		if d < 0 || d >= base {
+			s.Goto(c)
+			break
+		}
+		if under {
+			*lit = append(*lit, '_')
+		}
+		*lit = append(*lit, s.Rune())
+		count++
+	}
+	return count > 0
+}
+
+// Parse returns a node with the literal as its value.
+func (n Number) Parse(s pegn.Scanner) *ast.Node {
+	return pegn.ParserFor(n.T, n.Scan, 8)(s)
+}
+
+// Read scans a literal and returns its value as an int64 (when it has
+// no fraction or exponent) or float64. Literals that do not fit fail
+// (as if they had not matched) pushing an error at their end.
+func (n Number) Read(s pegn.Scanner) (any, bool) {
+	m := s.Mark()
+	buf := make([]rune, 0, 8)
+	if !n.Scan(s, &buf) {
+		return nil, false
+	}
+	lit := strings.ReplaceAll(string(buf), `_`, ``)
+
+	digits, neg := lit, false
+	if digits[0] == '+' || digits[0] == '-' {
+		neg = digits[0] == '-'
+		digits = digits[1:]
+	}
+
+	if len(digits) > 2 && digits[0] == '0' {
+		base := map[byte]int{'x': 16, 'X': 16, 'o': 8, 'O': 8, 'b': 2, 'B': 2}[digits[1]]
+		if base > 0 {
+			v, err := strconv.ParseUint(digits[2:], base, 64)
+			return n.integer(s, m, v, neg, err)
+		}
+	}
+
+	if strings.ContainsAny(digits, `.eE`) {
+		v, err := strconv.ParseFloat(lit, 64)
+		if err != nil {
+			s.Expected(n.T)
+			s.Goto(m)
+			return nil, false
+		}
+		return v, true
+	}
+
+	v, err := strconv.ParseUint(digits, 10, 64)
+	return n.integer(s, m, v, neg, err)
+}
+
+func (n Number) integer(s pegn.Scanner, m curs.R, v uint64, neg bool, err error) (any, bool) {
+	limit := uint64(1<<63 - 1)
+	if neg {
+		limit++
+	}
+	if err != nil || v > limit {
+		s.Expected(n.T)
+		s.Goto(m)
+		return nil, false
+	}
+	if neg {
+		return -int64(v), true
+	}
+	return int64(v), true
+}
+
+// ------------------------------ Integer -----------------------------
+
+var integer = Number{T: Integer, Sign: true, Radix: true, Underscore: true}
+
+// Scan_Integer scans a signed integer in decimal, hexadecimal (0x),
+// octal (0o), or binary (0b) with optional underscores between digits.
+func Scan_Integer(s pegn.Scanner, buf *[]rune) bool {
+	return integer.Scan(s, buf)
+}
+
+// Parse_Integer returns a node with the literal as its value.
+func Parse_Integer(s pegn.Scanner) *ast.Node { return integer.Parse(s) }
+
+// Read_Integer returns the value of the integer literal.
+func Read_Integer(s pegn.Scanner) (int64, bool) {
+	v, ok := integer.Read(s)
+	if !ok {
+		return 0, false
+	}
+	return v.(int64), true
+}
+
+// ------------------------------- Float ------------------------------
+
+var float = Number{T: Float, Sign: true, Float: true, Underscore: true}
+
+// Scan_Float scans a signed decimal number with optional fraction and
+// exponent and underscores between digits (1, 1.5, -1_000.25e-3).
+func Scan_Float(s pegn.Scanner, buf *[]rune) bool {
+	return float.Scan(s, buf)
+}
+
+// Parse_Float returns a node with the literal as its value.
+func Parse_Float(s pegn.Scanner) *ast.Node { return float.Parse(s) }
+
+// Read_Float returns the value of the literal (even if an integer).
+func Read_Float(s pegn.Scanner) (float64, bool) {
+	v, ok := float.Read(s)
+	if !ok {
+		return 0, false
+	}
+	if i, is := v.(int64); is {
+		return float64(i), true
+	}
+	return v.(float64), true
+}
@@ -0,0 +1,70 @@
+package pegng_test
+
+import (
+	"fmt"
+
+	"github.com/rwxrob/pegn/pegng"
+	"github.com/rwxrob/pegn/scanner"
+)
+
+func ExampleNumber() {
+
+	n := pegng.Number{T: 1, Radix: true, Float: true, Underscore: true}
+
+	for _, in := range []string{
+		`42`, `0755`, `0x_FF`, `0b1010`, `0o17`, `1_000_000`, `3.14`,
+		`6.02e23`, `1e-3`, `1.`, `1__0`, `_1`, `-1`, `0x`, `0xFFFFFFFFFFFFFFFFF`,
+	} {
+		s := scanner.New(in)
+		v, ok := n.Read(s)
+		fmt.Printf("%-22q %-5v %T %v\n", in, ok, v, v)
+	}
+
+	// Output:
+	// "42"                   true  int64 42
+	// "0755"                 true  int64 755
+	// "0x_FF"                true  int64 255
+	// "0b1010"               true  int64 10
+	// "0o17"                 true  int64 15
+	// "1_000_000"            true  int64 1000000
+	// "3.14"                 true  float64 3.14
+	// "6.02e23"              true  float64 6.02e+23
+	// "1e-3"                 true  float64 0.001
+	// "1."                   true  int64 1
+	// "1__0"                 true  int64 1
+	// "_1"                   false <nil> <nil>
+	// "-1"                   false <nil> <nil>
+	// "0x"                   false <nil> <nil>
+	// "0xFFFFFFFFFFFFFFFFF"  false <nil> <nil>
+}
+
+func Example_scanInteger() {
+
+	s := scanner.New(`-0x8000_0000_0000_0000+12`)
+	buf := []rune{}
+	fmt.Println(pegng.Scan_Integer(s, &buf), string(buf))
+	s.Print()
+	fmt.Println(pegng.Read_Integer(s))
+	fmt.Println(pegng.Parse_Integer(scanner.New(`0b_1`)))
+
+	// Output:
+	// true -0x8000_0000_0000_0000
+	// '0' 21-22 "+12"
+	// 12 true
+	// {"T":-8,"V":"0b_1"}
+}
+
+func Example_readFloat() {
+
+	for _, in := range []string{`-1_000.25e-3`, `7`, `.5`} {
+		s := scanner.New(in)
+		fmt.Println(pegng.Read_Float(s))
+	}
+	fmt.Println(pegng.Parse_Float(scanner.New(`1.5E+2x`)))
+
+	// Output:
+	// -1.00025 true
+	// 7 true
+	// 0 false
+	// {"T":-9,"V":"1.5E+2"}
+}
@@ -0,0 +1,93 @@
+package pegng
+
+import (
+	"github.com/rwxrob/pegn"
+	"github.com/rwxrob/pegn/ast"
+)
+
+// Operators scans the longest of a fixed set of operators (such as
+// "<=", "<-", "=>", and "::=") using a trie compiled by NewOperators so
+// that the time taken depends only on the length of the operator and
+// never on how many there are.
+type Operators struct {
+	T    int // type for errors and nodes
+	root *opnode
+}
+
+type opnode struct {
+	next map[rune]*opnode
+	end  bool // an operator ends here
+}
+
+// NewOperators compiles the operators (in any order, duplicates and
+// empty strings ignored) into a new Operators of the given type.
+func NewOperators(t int, ops ...string) *Operators {
+	o := &Operators{T: t, root: &opnode{}}
+	for _, op := range ops {
+		if op == "" {
+			continue
+		}
+		n := o.root
+		for _, r := range op {
+			if n.next == nil {
+				n.next = map[rune]*opnode{}
+			}
+			c, has := n.next[r]
+			if !has {
+				c = &opnode{}
+				n.next[r] = c
+			}
+			n = c
+		}
+		n.end = true
+	}
+	return o
+}
+
+// Scan fulfills pegn.ScanFunc buffering the longest operator found.
+func (o *Operators) Scan(s pegn.Scanner, buf *[]rune) bool {
+	m := s.Mark()
+	last := m
+	var found []rune
+	var runes []rune
+	n := o.root
+	for n.next != nil && s.Scan() {
+		if n = n.next[s.Rune()]; n == nil {
+			break
+		}
+		runes = append(runes, s.Rune())
+		if n.end {
+			last = s.Mark()
+			found = runes
+		}
+	}
+	if found == nil {
+		return s.Revert(m, o.T)
+	}
+	s.Goto(last)
+	if buf != nil {
+		*buf = append(*buf, found...)
+	}
+	return true
+}
+
+// Parse returns a node with the operator as its value.
+func (o *Operators) Parse(s pegn.Scanner) *ast.Node {
+	return pegn.ParserFor(o.T, o.Scan, 4)(s)
+}
+
+// --------------------------- PEGNOperator ---------------------------
+
+var pegnops = NewOperators(PEGNOperator,
+	`<--`, `<-`, `/`, `!`, `&`, `?`, `*`, `+`, `(`, `)`, `[`, `]`, `{`, `}`,
+	`-`, `,`, `<`, `>`, `.`,
+)
+
+// Scan_PEGNOperator scans any operator or punctuation of PEGN itself
+// (with <-- before <- and such).
+func Scan_PEGNOperator(s pegn.Scanner, buf *[]rune) bool {
+	return pegnops.Scan(s, buf)
+}
+
+// Parse_PEGNOperator returns a node with the operator as its value.
+func Parse_PEGNOperator(s pegn.Scanner) *ast.Node { return pegnops.Parse(s) }
@@ -0,0 +1,40 @@
+package pegng_test
+
+import (
+	"fmt"
+
+	"github.com/rwxrob/pegn/pegng"
+	"github.com/rwxrob/pegn/scanner"
+)
+
+func ExampleOperators() {
+
+	ops := pegng.NewOperators(1, `=`, `==`, `===`, `<`, `<=`, `=>`, `::=`)
+
+	s := scanner.New(`===<==>::=:`)
+	var found []string
+	for {
+		buf := []rune{}
+		if !ops.Scan(s, &buf) {
+			break
+		}
+		found = append(found, string(buf))
+	}
+	fmt.Printf("%q\n", found)
+	s.Print()
+	fmt.Println(s.Errors())
+
+	// Output:
+	// ["===" "<=" "=>" "::="]
+	// '=' 9-10 ":"
+	// &[expecting type 1 at ':' 10-11]
+}
+
+func Example_scanPEGNOperator() {
+	s := scanner.New(`<--<-`)
+	fmt.Println(pegng.Parse_PEGNOperator(s))
+	fmt.Println(pegng.Parse_PEGNOperator(s))
+	// Output:
+	// {"T":-17,"V":"<--"}
+	// {"T":-17,"V":"<-"}
+}
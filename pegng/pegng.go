@@ -2,7 +2,6 @@ package pegng
 
 import (
 	"github.com/rwxrob/pegn"
-	"github.com/rwxrob/pegn/ast"
 	"github.com/rwxrob/pegn/scanner"
 )
 
@@ -13,6 +12,22 @@ var Scanner = scanner.New()
 const (
 	Untyped int = -iota
 	C_ws
+	Entity
+	UEscape
+	PctEncoded
+	DQString
+	SQString
+	BTString
+	Integer
+	Float
+	XIdent
+	Ident
+	KebabIdent
+	SnakeIdent
+	HashComment
+	CComment
+	NestedComment
+	PEGNOperator
 )
 
 /*
@@ -108,13 +123,7 @@ func Scan_ws(s pegn.Scanner, buf *[]rune) bool {
 	return s.Revert(m, C_ws)
 }
 
-func Parse_ws(s pegn.Scanner) *ast.Node {
-	buf := make([]rune, 0, 1)
-	if !Scan_ws(s, &buf) {
-		return nil
-	}
-	return &ast.Node{T: C_ws, V: string(buf)}
-}
+var Parse_ws = pegn.ParserFor(C_ws, Scan_ws, 1)
 
 /*
 var (
@@ -46,7 +46,7 @@ func ExampleScan_ws() {
 
 }
 
-func ExampleParse_ws() {
+func Example_parseWs() {
 
 	s := scanner.New(`1 `)
 
@@ -54,14 +54,15 @@ func ExampleParse_ws() {
 	s.Print()
 	s.Scan()
 	s.Print()
-	fmt.Println(pegng.Parse_ws(s))
+	n := pegng.Parse_ws(s)
+	fmt.Println(n, len(n.Errors()))
 	s.Print()
 
 	// Output:
 	// <nil>
 	// '\x00' 0-0 "1 "
 	// '1' 0-1 " "
-	// {"T":-1,"V":" "}
+	// {"T":-1,"V":" "} 0
 	// ' ' 1-2 ""
 
 }
@@ -0,0 +1,145 @@
+package pegng
+
+import (
+	"github.com/rwxrob/pegn"
+	"github.com/rwxrob/pegn/ast"
+)
+
+// Quoted describes one of the many forms of quoted strings so that it
+// can be scanned (see Scan) with the quotes removed and escapes
+// replaced leaving only the value itself in the buffer. The escape
+// rune followed by either Quote or Escape always stands for that rune.
+// When Escape and Quote are the same rune (as in SQL) a doubled quote
+// stands for a single quote. See Scan_DQString, Scan_SQString, and
+// Scan_BTString for the most common forms.
+type Quoted struct {
+	T         int           // type for errors and nodes
+	Quote     rune          // opening and closing rune
+	Escape    rune          // escape rune or 0 for none
+	Escapes   map[rune]rune // runes allowed after Escape to their values
+	Unicode   bool          // allow Escape + u + hexdig{4} (see UEscape)
+	Multiline bool          // allow line feed and carriage return
+}
+
+// CEscapes are the escapes of C (and Go, JSON, JavaScript, and many
+// more) other than the numeric forms.
+var CEscapes = map[rune]rune{
+	'a': '\a', 'b': '\b', 'f': '\f', 'n': '\n', 'r': '\r', 't': '\t',
+	'v': '\v', '0': 0, '/': '/', '\'': '\'', '"': '"', '\\': '\\',
+}
+
+// Scan fulfills pegn.ScanFunc buffering only the unescaped value
+// between the quotes. On failure nothing is buffered and the error
+// (of type T) points to the offending rune.
+func (q Quoted) Scan(s pegn.Scanner, buf *[]rune) bool {
+	m := s.Mark()
+	var n int
+	if buf != nil {
+		n = len(*buf)
+	}
+	fail := func() bool {
+		if buf != nil {
+			*buf = (*buf)[:n]
+		}
+		s.Expected(q.T)
+		s.Goto(m)
+		return false
+	}
+
+	if !s.Peek(string(q.Quote)) {
+		return fail()
+	}
+	s.Scan()
+
+	for {
+		if !s.Scan() {
+			return fail()
+		}
+		r := s.Rune()
+
+		switch {
+
+		case r == q.Quote && q.Escape == q.Quote:
+			if !s.Peek(string(q.Quote)) {
+				return true
+			}
+			s.Scan()
+
+		case r == q.Quote:
+			return true
+
+		case r == q.Escape && q.Escape != 0:
+			if !s.Scan() {
+				return fail()
+			}
+			e := s.Rune()
+			switch v, has := q.Escapes[e]; {
+			case has:
+				r = v
+			case e == q.Quote || e == q.Escape:
+				r = e
+			case e == 'u' && q.Unicode:
+				var ok bool
+				if r, ok = scanuhex(s, q.Escape); !ok {
+					return fail()
+				}
+			default:
+				return fail()
+			}
+
+		case (r == '\n' || r == '\r') && !q.Multiline:
+			return fail()
+		}
+
+		if buf != nil {
+			*buf = append(*buf, r)
+		}
+	}
+}
+
+// Parse returns a node (of type T) with the unescaped value.
+func (q Quoted) Parse(s pegn.Scanner) *ast.Node {
+	return pegn.ParserFor(q.T, q.Scan, 16)(s)
+}
+
+// ------------------------------ DQString ----------------------------
+
+var dqstring = Quoted{
+	T: DQString, Quote: '"', Escape: '\\', Escapes: CEscapes, Unicode: true,
+}
+
+// Scan_DQString scans a double quoted string on a single line with
+// CEscapes and unicode escapes (as in JSON and Go interpreted strings).
+func Scan_DQString(s pegn.Scanner, buf *[]rune) bool {
+	return dqstring.Scan(s, buf)
+}
+
+// Parse_DQString returns a node with the unescaped value.
+func Parse_DQString(s pegn.Scanner) *ast.Node { return dqstring.Parse(s) }
+
+// ------------------------------ SQString ----------------------------
+
+var sqstring = Quoted{T: SQString, Quote: '\'', Escape: '\'', Multiline: true}
+
+// Scan_SQString scans a single quoted string that may span lines and
+// within which two single quotes in a row stand for one (as in SQL,
+// YAML, and PowerShell).
+func Scan_SQString(s pegn.Scanner, buf *[]rune) bool {
+	return sqstring.Scan(s, buf)
+}
+
+// Parse_SQString returns a node with the unescaped value.
+func Parse_SQString(s pegn.Scanner) *ast.Node { return sqstring.Parse(s) }
+
+// ------------------------------ BTString ----------------------------
+
+var btstring = Quoted{T: BTString, Quote: '`', Multiline: true}
+
+// Scan_BTString scans a backtick quoted string without any escapes
+// that may span lines (as in Go raw strings).
+func Scan_BTString(s pegn.Scanner, buf *[]rune) bool {
+	return btstring.Scan(s, buf)
+}
+
+// Parse_BTString returns a node with the value.
+func Parse_BTString(s pegn.Scanner) *ast.Node { return btstring.Parse(s) }
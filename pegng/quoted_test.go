@@ -0,0 +1,68 @@
+package pegng_test
+
+import (
+	"fmt"
+
+	"github.com/rwxrob/pegn/pegng"
+	"github.com/rwxrob/pegn/scanner"
+)
+
+func ExampleQuoted() {
+
+	// shell-like with only a few escapes allowed
+	q := pegng.Quoted{
+		T:       1,
+		Quote:   '"',
+		Escape:  '\\',
+		Escapes: map[rune]rune{'n': '\n', '$': '$'},
+	}
+
+	for _, in := range []string{
+		`"costs \$5\n"`, `"say \"hi\""`, `"\t"`, `"open`, "\"two\nlines\"",
+	} {
+		s := scanner.New(in)
+		buf := []rune{}
+		fmt.Printf("%-5v %q\n", q.Scan(s, &buf), string(buf))
+	}
+
+	// Output:
+	// true  "costs $5\n"
+	// true  "say \"hi\""
+	// false ""
+	// false ""
+	// false ""
+}
+
+func Example_scanDQString() {
+
+	s := scanner.New(`"caf\u00e9\t\uD83D\uDE00" rest`)
+	buf := []rune{}
+	fmt.Println(pegng.Scan_DQString(s, &buf))
+	fmt.Printf("%q\n", string(buf))
+	s.Print()
+
+	s = scanner.New(`"bad \q"`)
+	fmt.Println(pegng.Scan_DQString(s, nil))
+	fmt.Println(s.Errors())
+
+	// Output:
+	// true
+	// "café\t😀"
+	// '"' 24-25 " rest"
+	// false
+	// &[expecting type -5 at 'q' 6-7]
+}
+
+func Example_scanSQString() {
+	s := scanner.New("'it''s\nfine' ok")
+	fmt.Println(pegng.Parse_SQString(s))
+	// Output:
+	// {"T":-6,"V":"it's\nfine"}
+}
+
+func Example_scanBTString() {
+	s := scanner.New("`C:\\path\\n`")
+	fmt.Println(pegng.Parse_BTString(s))
+	// Output:
+	// {"T":-7,"V":"C:\\path\\n"}
+}
@@ -0,0 +1,280 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+package spec
+
+import (
+	"github.com/rwxrob/pegn"
+	"github.com/rwxrob/pegn/ast"
+)
+
+// recorder records the spans of node rules (<--) while scanning so that
+// the node tree can be created from them once the scan succeeds.
+type recorder struct {
+	pegn.Scanner
+	spans []span
+	depth int
+}
+
+// span is the range of bytes matched by a node rule at a given depth of
+// nested node rules.
+type span struct {
+	t     int
+	b, e  int
+	depth int
+}
+
+// parse scans the rule of type t and returns the node tree of every
+// node rule matched within it. The root node is always of type t (even
+// if not a node rule itself). Nodes without any nodes under them have
+// the text matched as their value. Returns nil on failure with the
+// error pushed as by the Scan_* function.
+func parse(t int, scan pegn.ScanFunc, s pegn.Scanner) *ast.Node {
+	r := &recorder{Scanner: s}
+	b := s.RuneE()
+	if !scan(r, nil) {
+		return nil
+	}
+	spans := r.spans
+	if len(spans) == 0 || spans[0].t != t || spans[0].b != b {
+		spans = append([]span{{t: t, b: b, e: s.RuneE(), depth: -1}}, spans...)
+	}
+	var off int
+	if o, is := s.(interface{ Offset() int }); is {
+		off = o.Offset()
+	}
+	n, _ := tree(*s.Bytes(), off, spans)
+	return n
+}
+
+func tree(buf []byte, off int, spans []span) (*ast.Node, []span) {
+	top := spans[0]
+	n := &ast.Node{T: top.t, B: top.b, E: top.e}
+	spans = spans[1:]
+	for len(spans) > 0 && spans[0].depth > top.depth {
+		var u *ast.Node
+		u, spans = tree(buf, off, spans)
+		u.P = n
+		n.Append(u)
+	}
+	if n.Count == 0 {
+		n.V = string(buf[top.b-off : top.e-off])
+	}
+	return n, spans
+}
+
+// Parse_Spec returns the node tree of Spec (see Scan_Spec).
+func Parse_Spec(s pegn.Scanner) *ast.Node { return parse(Spec, Scan_Spec, s) }
+
+// Parse_Meta returns the node tree of Meta (see Scan_Meta).
+func Parse_Meta(s pegn.Scanner) *ast.Node { return parse(Meta, Scan_Meta, s) }
+
+// Parse_Rules returns the node tree of Rules (see Scan_Rules).
+func Parse_Rules(s pegn.Scanner) *ast.Node { return parse(Rules, Scan_Rules, s) }
+
+// Parse_Ident returns the node tree of Ident (see Scan_Ident).
+func Parse_Ident(s pegn.Scanner) *ast.Node { return parse(Ident, Scan_Ident, s) }
+
+// Parse_Home returns the node tree of Home (see Scan_Home).
+func Parse_Home(s pegn.Scanner) *ast.Node { return parse(Home, Scan_Home, s) }
+
+// Parse_Path returns the node tree of Path (see Scan_Path).
+func Parse_Path(s pegn.Scanner) *ast.Node { return parse(Path, Scan_Path, s) }
+
+// Parse_Copyright returns the node tree of Copyright (see Scan_Copyright).
+func Parse_Copyright(s pegn.Scanner) *ast.Node { return parse(Copyright, Scan_Copyright, s) }
+
+// Parse_License returns the node tree of License (see Scan_License).
+func Parse_License(s pegn.Scanner) *ast.Node { return parse(License, Scan_License, s) }
+
+// Parse_SPDXID returns the node tree of SPDXID (see Scan_SPDXID).
+func Parse_SPDXID(s pegn.Scanner) *ast.Node { return parse(SPDXID, Scan_SPDXID, s) }
+
+// Parse_Include returns the node tree of Include (see Scan_Include).
+func Parse_Include(s pegn.Scanner) *ast.Node { return parse(Include, Scan_Include, s) }
+
+// Parse_Comment returns the node tree of Comment (see Scan_Comment).
+func Parse_Comment(s pegn.Scanner) *ast.Node { return parse(Comment, Scan_Comment, s) }
+
+// Parse_BlankLine returns the node tree of BlankLine (see Scan_BlankLine).
+func Parse_BlankLine(s pegn.Scanner) *ast.Node { return parse(BlankLine, Scan_BlankLine, s) }
+
+// Parse_EndLine returns the node tree of EndLine (see Scan_EndLine).
+func Parse_EndLine(s pegn.Scanner) *ast.Node { return parse(EndLine, Scan_EndLine, s) }
+
+// Parse_ComEndLine returns the node tree of ComEndLine (see Scan_ComEndLine).
+func Parse_ComEndLine(s pegn.Scanner) *ast.Node { return parse(ComEndLine, Scan_ComEndLine, s) }
+
+// Parse_Spacing returns the node tree of Spacing (see Scan_Spacing).
+func Parse_Spacing(s pegn.Scanner) *ast.Node { return parse(Spacing, Scan_Spacing, s) }
+
+// Parse_Name returns the node tree of Name (see Scan_Name).
+func Parse_Name(s pegn.Scanner) *ast.Node { return parse(Name, Scan_Name, s) }
+
+// Parse_TokenDef returns the node tree of TokenDef (see Scan_TokenDef).
+func Parse_TokenDef(s pegn.Scanner) *ast.Node { return parse(TokenDef, Scan_TokenDef, s) }
+
+// Parse_ClassDef returns the node tree of ClassDef (see Scan_ClassDef).
+func Parse_ClassDef(s pegn.Scanner) *ast.Node { return parse(ClassDef, Scan_ClassDef, s) }
+
+// Parse_RuleDef returns the node tree of RuleDef (see Scan_RuleDef).
+func Parse_RuleDef(s pegn.Scanner) *ast.Node { return parse(RuleDef, Scan_RuleDef, s) }
+
+// Parse_TokenVal returns the node tree of TokenVal (see Scan_TokenVal).
+func Parse_TokenVal(s pegn.Scanner) *ast.Node { return parse(TokenVal, Scan_TokenVal, s) }
+
+// Parse_MajorVer returns the node tree of MajorVer (see Scan_MajorVer).
+func Parse_MajorVer(s pegn.Scanner) *ast.Node { return parse(MajorVer, Scan_MajorVer, s) }
+
+// Parse_MinorVer returns the node tree of MinorVer (see Scan_MinorVer).
+func Parse_MinorVer(s pegn.Scanner) *ast.Node { return parse(MinorVer, Scan_MinorVer, s) }
+
+// Parse_PatchVer returns the node tree of PatchVer (see Scan_PatchVer).
+func Parse_PatchVer(s pegn.Scanner) *ast.Node { return parse(PatchVer, Scan_PatchVer, s) }
+
+// Parse_PreVer returns the node tree of PreVer (see Scan_PreVer).
+func Parse_PreVer(s pegn.Scanner) *ast.Node { return parse(PreVer, Scan_PreVer, s) }
+
+// Parse_RuleId returns the node tree of RuleId (see Scan_RuleId).
+func Parse_RuleId(s pegn.Scanner) *ast.Node { return parse(RuleId, Scan_RuleId, s) }
+
+// Parse_ClassId returns the node tree of ClassId (see Scan_ClassId).
+func Parse_ClassId(s pegn.Scanner) *ast.Node { return parse(ClassId, Scan_ClassId, s) }
+
+// Parse_TokenId returns the node tree of TokenId (see Scan_TokenId).
+func Parse_TokenId(s pegn.Scanner) *ast.Node { return parse(TokenId, Scan_TokenId, s) }
+
+// Parse_ResClassId returns the node tree of ResClassId (see Scan_ResClassId).
+func Parse_ResClassId(s pegn.Scanner) *ast.Node { return parse(ResClassId, Scan_ResClassId, s) }
+
+// Parse_ResTokenId returns the node tree of ResTokenId (see Scan_ResTokenId).
+func Parse_ResTokenId(s pegn.Scanner) *ast.Node { return parse(ResTokenId, Scan_ResTokenId, s) }
+
+// Parse_Expression returns the node tree of Expression (see Scan_Expression).
+func Parse_Expression(s pegn.Scanner) *ast.Node { return parse(Expression, Scan_Expression, s) }
+
+// Parse_ClassExpr returns the node tree of ClassExpr (see Scan_ClassExpr).
+func Parse_ClassExpr(s pegn.Scanner) *ast.Node { return parse(ClassExpr, Scan_ClassExpr, s) }
+
+// Parse_Simple returns the node tree of Simple (see Scan_Simple).
+func Parse_Simple(s pegn.Scanner) *ast.Node { return parse(Simple, Scan_Simple, s) }
+
+// Parse_Sequence returns the node tree of Sequence (see Scan_Sequence).
+func Parse_Sequence(s pegn.Scanner) *ast.Node { return parse(Sequence, Scan_Sequence, s) }
+
+// Parse_Rule returns the node tree of Rule (see Scan_Rule).
+func Parse_Rule(s pegn.Scanner) *ast.Node { return parse(Rule, Scan_Rule, s) }
+
+// Parse_Plain returns the node tree of Plain (see Scan_Plain).
+func Parse_Plain(s pegn.Scanner) *ast.Node { return parse(Plain, Scan_Plain, s) }
+
+// Parse_PosLook returns the node tree of PosLook (see Scan_PosLook).
+func Parse_PosLook(s pegn.Scanner) *ast.Node { return parse(PosLook, Scan_PosLook, s) }
+
+// Parse_NegLook returns the node tree of NegLook (see Scan_NegLook).
+func Parse_NegLook(s pegn.Scanner) *ast.Node { return parse(NegLook, Scan_NegLook, s) }
+
+// Parse_Primary returns the node tree of Primary (see Scan_Primary).
+func Parse_Primary(s pegn.Scanner) *ast.Node { return parse(Primary, Scan_Primary, s) }
+
+// Parse_Quant returns the node tree of Quant (see Scan_Quant).
+func Parse_Quant(s pegn.Scanner) *ast.Node { return parse(Quant, Scan_Quant, s) }
+
+// Parse_Optional returns the node tree of Optional (see Scan_Optional).
+func Parse_Optional(s pegn.Scanner) *ast.Node { return parse(Optional, Scan_Optional, s) }
+
+// Parse_MinZero returns the node tree of MinZero (see Scan_MinZero).
+func Parse_MinZero(s pegn.Scanner) *ast.Node { return parse(MinZero, Scan_MinZero, s) }
+
+// Parse_MinOne returns the node tree of MinOne (see Scan_MinOne).
+func Parse_MinOne(s pegn.Scanner) *ast.Node { return parse(MinOne, Scan_MinOne, s) }
+
+// Parse_MinMax returns the node tree of MinMax (see Scan_MinMax).
+func Parse_MinMax(s pegn.Scanner) *ast.Node { return parse(MinMax, Scan_MinMax, s) }
+
+// Parse_Min returns the node tree of Min (see Scan_Min).
+func Parse_Min(s pegn.Scanner) *ast.Node { return parse(Min, Scan_Min, s) }
+
+// Parse_Max returns the node tree of Max (see Scan_Max).
+func Parse_Max(s pegn.Scanner) *ast.Node { return parse(Max, Scan_Max, s) }
+
+// Parse_Amount returns the node tree of Amount (see Scan_Amount).
+func Parse_Amount(s pegn.Scanner) *ast.Node { return parse(Amount, Scan_Amount, s) }
+
+// Parse_Count returns the node tree of Count (see Scan_Count).
+func Parse_Count(s pegn.Scanner) *ast.Node { return parse(Count, Scan_Count, s) }
+
+// Parse_Range returns the node tree of Range (see Scan_Range).
+func Parse_Range(s pegn.Scanner) *ast.Node { return parse(Range, Scan_Range, s) }
+
+// Parse_UniRange returns the node tree of UniRange (see Scan_UniRange).
+func Parse_UniRange(s pegn.Scanner) *ast.Node { return parse(UniRange, Scan_UniRange, s) }
+
+// Parse_AlphaRange returns the node tree of AlphaRange (see Scan_AlphaRange).
+func Parse_AlphaRange(s pegn.Scanner) *ast.Node { return parse(AlphaRange, Scan_AlphaRange, s) }
+
+// Parse_IntRange returns the node tree of IntRange (see Scan_IntRange).
+func Parse_IntRange(s pegn.Scanner) *ast.Node { return parse(IntRange, Scan_IntRange, s) }
+
+// Parse_BinRange returns the node tree of BinRange (see Scan_BinRange).
+func Parse_BinRange(s pegn.Scanner) *ast.Node { return parse(BinRange, Scan_BinRange, s) }
+
+// Parse_HexRange returns the node tree of HexRange (see Scan_HexRange).
+func Parse_HexRange(s pegn.Scanner) *ast.Node { return parse(HexRange, Scan_HexRange, s) }
+
+// Parse_OctRange returns the node tree of OctRange (see Scan_OctRange).
+func Parse_OctRange(s pegn.Scanner) *ast.Node { return parse(OctRange, Scan_OctRange, s) }
+
+// Parse_IsoDate returns the node tree of IsoDate (see Scan_IsoDate).
+func Parse_IsoDate(s pegn.Scanner) *ast.Node { return parse(IsoDate, Scan_IsoDate, s) }
+
+// Parse_Date returns the node tree of Date (see Scan_Date).
+func Parse_Date(s pegn.Scanner) *ast.Node { return parse(Date, Scan_Date, s) }
+
+// Parse_Time returns the node tree of Time (see Scan_Time).
+func Parse_Time(s pegn.Scanner) *ast.Node { return parse(Time, Scan_Time, s) }
+
+// Parse_Year returns the node tree of Year (see Scan_Year).
+func Parse_Year(s pegn.Scanner) *ast.Node { return parse(Year, Scan_Year, s) }
+
+// Parse_Month returns the node tree of Month (see Scan_Month).
+func Parse_Month(s pegn.Scanner) *ast.Node { return parse(Month, Scan_Month, s) }
+
+// Parse_Day returns the node tree of Day (see Scan_Day).
+func Parse_Day(s pegn.Scanner) *ast.Node { return parse(Day, Scan_Day, s) }
+
+// Parse_Hour returns the node tree of Hour (see Scan_Hour).
+func Parse_Hour(s pegn.Scanner) *ast.Node { return parse(Hour, Scan_Hour, s) }
+
+// Parse_Minute returns the node tree of Minute (see Scan_Minute).
+func Parse_Minute(s pegn.Scanner) *ast.Node { return parse(Minute, Scan_Minute, s) }
+
+// Parse_Second returns the node tree of Second (see Scan_Second).
+func Parse_Second(s pegn.Scanner) *ast.Node { return parse(Second, Scan_Second, s) }
+
+// Parse_Field returns the node tree of Field (see Scan_Field).
+func Parse_Field(s pegn.Scanner) *ast.Node { return parse(Field, Scan_Field, s) }
+
+// Parse_String returns the node tree of String (see Scan_String).
+func Parse_String(s pegn.Scanner) *ast.Node { return parse(String, Scan_String, s) }
+
+// Parse_Letter returns the node tree of Letter (see Scan_Letter).
+func Parse_Letter(s pegn.Scanner) *ast.Node { return parse(Letter, Scan_Letter, s) }
+
+// Parse_Unicode returns the node tree of Unicode (see Scan_Unicode).
+func Parse_Unicode(s pegn.Scanner) *ast.Node { return parse(Unicode, Scan_Unicode, s) }
+
+// Parse_Integer returns the node tree of Integer (see Scan_Integer).
+func Parse_Integer(s pegn.Scanner) *ast.Node { return parse(Integer, Scan_Integer, s) }
+
+// Parse_Binary returns the node tree of Binary (see Scan_Binary).
+func Parse_Binary(s pegn.Scanner) *ast.Node { return parse(Binary, Scan_Binary, s) }
+
+// Parse_Hexadec returns the node tree of Hexadec (see Scan_Hexadec).
+func Parse_Hexadec(s pegn.Scanner) *ast.Node { return parse(Hexadec, Scan_Hexadec, s) }
+
+// Parse_Octal returns the node tree of Octal (see Scan_Octal).
+func Parse_Octal(s pegn.Scanner) *ast.Node { return parse(Octal, Scan_Octal, s) }
+
+// Parse_EndPara returns the node tree of EndPara (see Scan_EndPara).
+func Parse_EndPara(s pegn.Scanner) *ast.Node { return parse(EndPara, Scan_EndPara, s) }
@@ -0,0 +1,548 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+package spec
+
+import "github.com/rwxrob/pegn"
+
+// -------------------------------- Spec ------------------------------
+
+// Scan_Spec scans an entire PEGN grammar.
+//
+//	Spec       <-- Meta? Rules
+func Scan_Spec(s pegn.Scanner, buf *[]rune) bool { return scanSpec(s, buf) }
+
+// Scan_Meta scans the meta header lines.
+//
+//	Meta       <-- '# ' Ident SP+ Home EndLine Copyright? License? Include*
+func Scan_Meta(s pegn.Scanner, buf *[]rune) bool { return scanMeta(s, buf) }
+
+// Scan_Rules scans every line after the meta header.
+//
+//	Rules      <-- (BlankLine / Comment / TokenDef / ClassDef / RuleDef)+
+func Scan_Rules(s pegn.Scanner, buf *[]rune) bool { return scanRules(s, buf) }
+
+// Scan_Ident scans the name of the grammar.
+//
+//	Ident      <-- upper{2,12}
+func Scan_Ident(s pegn.Scanner, buf *[]rune) bool { return scanIdent(s, buf) }
+
+// Scan_Home scans the home of the grammar.
+//
+//	Home       <-- Path
+func Scan_Home(s pegn.Scanner, buf *[]rune) bool { return scanHome(s, buf) }
+
+// Scan_Path scans anything up to white space.
+//
+//	Path       <-- (!ws unipoint)+
+func Scan_Path(s pegn.Scanner, buf *[]rune) bool { return scanPath(s, buf) }
+
+// Scan_Copyright scans the copyright header line.
+//
+//	Copyright  <-- '# Copyright ' (!EndLine unipoint)+ EndLine
+func Scan_Copyright(s pegn.Scanner, buf *[]rune) bool { return scanCopyright(s, buf) }
+
+// Scan_License scans the license header line.
+//
+//	License    <-- '# SPDX-License-Identifier: ' SPDXID EndLine
+func Scan_License(s pegn.Scanner, buf *[]rune) bool { return scanLicense(s, buf) }
+
+// Scan_SPDXID scans a license identifier (undefined in the draft).
+//
+//	SPDXID     <-- (!ws unipoint)+
+func Scan_SPDXID(s pegn.Scanner, buf *[]rune) bool { return scanSPDXID(s, buf) }
+
+// Scan_Include scans an include header line.
+//
+//	Include    <-- '# Include ' Path EndLine
+func Scan_Include(s pegn.Scanner, buf *[]rune) bool { return scanInclude(s, buf) }
+
+// Scan_Comment scans a comment line.
+//
+//	Comment    <-- '#' (!EndLine unipoint)* EndLine
+func Scan_Comment(s pegn.Scanner, buf *[]rune) bool { return scanComment(s, buf) }
+
+// Scan_BlankLine scans a line with nothing but spaces (undefined in the
+// draft).
+//
+//	BlankLine  <-- SP* EndLine
+func Scan_BlankLine(s pegn.Scanner, buf *[]rune) bool { return scanBlankLine(s, buf) }
+
+// Scan_EndLine scans a line ending (undefined in the draft).
+//
+//	EndLine     <- LF / CRLF / CR
+func Scan_EndLine(s pegn.Scanner, buf *[]rune) bool { return scanEndLine(s, buf) }
+
+// Scan_ComEndLine scans the end of a definition line with an optional
+// comment (undefined in the draft).
+//
+//	ComEndLine  <- SP* ('# ' (!EndLine unipoint)*)? EndLine
+func Scan_ComEndLine(s pegn.Scanner, buf *[]rune) bool { return scanComEndLine(s, buf) }
+
+// Scan_Spacing scans between the elements of a definition continued on
+// an indented line.
+//
+//	Spacing     <- ComEndLine? SP+
+func Scan_Spacing(s pegn.Scanner, buf *[]rune) bool { return scanSpacing(s, buf) }
+
+// ---------------------------- definitions ---------------------------
+
+// Scan_Name scans the name of any definition.
+//
+//	Name        <- RuleId / ClassId / TokenId
+func Scan_Name(s pegn.Scanner, buf *[]rune) bool { return scanName(s, buf) }
+
+// Scan_TokenDef scans a token definition (TokenName in the draft is
+// TokenId and TokenId is added to TokenVal for tokens such as CRLF).
+//
+//	TokenDef   <-- TokenId SP+ '<-' SP+ TokenVal (Spacing TokenVal)* ComEndLine
+func Scan_TokenDef(s pegn.Scanner, buf *[]rune) bool { return scanTokenDef(s, buf) }
+
+// Scan_ClassDef scans a class definition (ClassName in the draft is
+// ClassId).
+//
+//	ClassDef   <-- ClassId SP+ '<-' SP+ ClassExpr ComEndLine
+func Scan_ClassDef(s pegn.Scanner, buf *[]rune) bool { return scanClassDef(s, buf) }
+
+// Scan_RuleDef scans a rule definition (RuleName in the draft is
+// RuleId).
+//
+//	RuleDef    <-- RuleId SP+ ('<--' / '<-') SP+ Expression ComEndLine
+func Scan_RuleDef(s pegn.Scanner, buf *[]rune) bool { return scanRuleDef(s, buf) }
+
+// Scan_TokenVal scans a single value of a token.
+//
+//	TokenVal    <- Unicode / Binary / Hexadec / Octal / TokenId / SQ String SQ
+func Scan_TokenVal(s pegn.Scanner, buf *[]rune) bool { return scanTokenVal(s, buf) }
+
+// ------------------------------ versions ----------------------------
+
+// Scan_MajorVer scans the major version number.
+//
+//	MajorVer   <-- digit+
+func Scan_MajorVer(s pegn.Scanner, buf *[]rune) bool { return scanMajorVer(s, buf) }
+
+// Scan_MinorVer scans the minor version number.
+//
+//	MinorVer   <-- digit+
+func Scan_MinorVer(s pegn.Scanner, buf *[]rune) bool { return scanMinorVer(s, buf) }
+
+// Scan_PatchVer scans the patch version number.
+//
+//	PatchVer   <-- digit+
+func Scan_PatchVer(s pegn.Scanner, buf *[]rune) bool { return scanPatchVer(s, buf) }
+
+// Scan_PreVer scans the pre-release part of a version.
+//
+//	PreVer     <-- (word / DASH)+ ('.' (word / DASH)+)*
+func Scan_PreVer(s pegn.Scanner, buf *[]rune) bool { return scanPreVer(s, buf) }
+
+// -------------------------------- ids -------------------------------
+
+// Scan_RuleId scans the name of a rule.
+//
+//	RuleId     <-- (upper lower+)+
+func Scan_RuleId(s pegn.Scanner, buf *[]rune) bool { return scanRuleId(s, buf) }
+
+// Scan_ClassId scans the name of a class. Only the second alternative
+// is tried since it matches every reserved name as well and a longer
+// name beginning with one (alphax) is then never cut short.
+//
+//	ClassId    <-- ResClassId / lower (lower / UNDER lower)+
+func Scan_ClassId(s pegn.Scanner, buf *[]rune) bool { return scanClassId(s, buf) }
+
+// Scan_TokenId scans the name of a token (see ClassId for the order).
+//
+//	TokenId    <-- ResTokenId / upper (upper / UNDER upper)+
+func Scan_TokenId(s pegn.Scanner, buf *[]rune) bool { return scanTokenId(s, buf) }
+
+// Scan_ResClassId scans a class name reserved by the specification.
+//
+//	ResClassId <-- 'alphanum' / 'alpha' / 'any' / ... / 'unipoint'
+func Scan_ResClassId(s pegn.Scanner, buf *[]rune) bool { return scanResClassId(s, buf) }
+
+// Scan_ResTokenId scans a token name reserved by the specification.
+//
+//	ResTokenId <-- 'TAB' / 'CRLF' / 'CR' / ... / 'ENDOFDATA'
+func Scan_ResTokenId(s pegn.Scanner, buf *[]rune) bool { return scanResTokenId(s, buf) }
+
+// ---------------------------- expressions ---------------------------
+
+// Scan_Expression scans the expression of a rule.
+//
+//	Expression <-- Sequence (Spacing '/' SP+ Sequence)*
+func Scan_Expression(s pegn.Scanner, buf *[]rune) bool { return scanExpression(s, buf) }
+
+// Scan_ClassExpr scans the expression of a class.
+//
+//	ClassExpr  <-- Simple (Spacing '/' SP+ Simple)*
+func Scan_ClassExpr(s pegn.Scanner, buf *[]rune) bool { return scanClassExpr(s, buf) }
+
+// Scan_Simple scans anything allowed in a class expression.
+//
+//	Simple      <- Unicode / Binary / Hexadec / Octal
+//	             / ClassId / TokenId / Range / SQ String SQ
+func Scan_Simple(s pegn.Scanner, buf *[]rune) bool { return scanSimple(s, buf) }
+
+// Scan_Sequence scans one alternative of an expression.
+//
+//	Sequence   <-- Rule (Spacing Rule)*
+func Scan_Sequence(s pegn.Scanner, buf *[]rune) bool { return scanSequence(s, buf) }
+
+// Scan_Rule scans one element of a sequence.
+//
+//	Rule        <- PosLook / NegLook / Plain
+func Scan_Rule(s pegn.Scanner, buf *[]rune) bool { return scanRule(s, buf) }
+
+// Scan_Plain scans a primary with an optional quantifier.
+//
+//	Plain      <-- Primary Quant?
+func Scan_Plain(s pegn.Scanner, buf *[]rune) bool { return scanPlain(s, buf) }
+
+// Scan_PosLook scans a positive lookahead.
+//
+//	PosLook    <-- '&' Primary Quant?
+func Scan_PosLook(s pegn.Scanner, buf *[]rune) bool { return scanPosLook(s, buf) }
+
+// Scan_NegLook scans a negative lookahead.
+//
+//	NegLook    <-- '!' Primary Quant?
+func Scan_NegLook(s pegn.Scanner, buf *[]rune) bool { return scanNegLook(s, buf) }
+
+// Scan_Primary scans a simple, a reference to a rule (CheckId in the
+// draft is RuleId), or a group.
+//
+//	Primary     <- Simple / RuleId / '(' Expression ')'
+func Scan_Primary(s pegn.Scanner, buf *[]rune) bool { return scanPrimary(s, buf) }
+
+// ------------------------------- quants -----------------------------
+
+// Scan_Quant scans any quantifier.
+//
+//	Quant       <- Optional / MinZero / MinOne / MinMax / Amount
+func Scan_Quant(s pegn.Scanner, buf *[]rune) bool { return scanQuant(s, buf) }
+
+// Scan_Optional scans the optional quantifier.
+//
+//	Optional   <-- '?'
+func Scan_Optional(s pegn.Scanner, buf *[]rune) bool { return scanOptional(s, buf) }
+
+// Scan_MinZero scans the zero or more quantifier.
+//
+//	MinZero    <-- '*'
+func Scan_MinZero(s pegn.Scanner, buf *[]rune) bool { return scanMinZero(s, buf) }
+
+// Scan_MinOne scans the one or more quantifier.
+//
+//	MinOne     <-- '+'
+func Scan_MinOne(s pegn.Scanner, buf *[]rune) bool { return scanMinOne(s, buf) }
+
+// Scan_MinMax scans a quantifier with a minimum and optional maximum.
+//
+//	MinMax     <-- '{' Min ',' Max? '}'
+func Scan_MinMax(s pegn.Scanner, buf *[]rune) bool { return scanMinMax(s, buf) }
+
+// Scan_Min scans the minimum of MinMax.
+//
+//	Min        <-- digit+
+func Scan_Min(s pegn.Scanner, buf *[]rune) bool { return scanMin(s, buf) }
+
+// Scan_Max scans the maximum of MinMax.
+//
+//	Max        <-- digit+
+func Scan_Max(s pegn.Scanner, buf *[]rune) bool { return scanMax(s, buf) }
+
+// Scan_Amount scans a quantifier with an exact count.
+//
+//	Amount      <- '{' Count '}'
+func Scan_Amount(s pegn.Scanner, buf *[]rune) bool { return scanAmount(s, buf) }
+
+// Scan_Count scans the count of Amount.
+//
+//	Count      <-- digit+
+func Scan_Count(s pegn.Scanner, buf *[]rune) bool { return scanCount(s, buf) }
+
+// ------------------------------- ranges -----------------------------
+
+// Scan_Range scans any range.
+//
+//	Range       <- AlphaRange / IntRange / UniRange
+//	             / BinRange / HexRange / OctRange
+func Scan_Range(s pegn.Scanner, buf *[]rune) bool { return scanRange(s, buf) }
+
+// Scan_UniRange scans a range of Unicode code points ([u0000-u10FFFF]).
+//
+//	UniRange   <-- '[' Unicode '-' Unicode ']'
+func Scan_UniRange(s pegn.Scanner, buf *[]rune) bool { return scanUniRange(s, buf) }
+
+// Scan_AlphaRange scans a range of letters ([a-m] [A-Z]).
+//
+//	AlphaRange <-- '[' Letter '-' Letter ']'
+func Scan_AlphaRange(s pegn.Scanner, buf *[]rune) bool { return scanAlphaRange(s, buf) }
+
+// Scan_IntRange scans a range of integers ([0-108] [0-9]).
+//
+//	IntRange   <-- '[' Integer '-' Integer ']'
+func Scan_IntRange(s pegn.Scanner, buf *[]rune) bool { return scanIntRange(s, buf) }
+
+// Scan_BinRange scans a range of binary values ([b101-b1111]).
+//
+//	BinRange   <-- '[' Binary '-' Binary ']'
+func Scan_BinRange(s pegn.Scanner, buf *[]rune) bool { return scanBinRange(s, buf) }
+
+// Scan_HexRange scans a range of hexadecimal values ([x20-x2F]).
+//
+//	HexRange   <-- '[' Hexadec '-' Hexadec ']'
+func Scan_HexRange(s pegn.Scanner, buf *[]rune) bool { return scanHexRange(s, buf) }
+
+// Scan_OctRange scans a range of octal values ([o20-o37]).
+//
+//	OctRange   <-- '[' Octal '-' Octal ']'
+func Scan_OctRange(s pegn.Scanner, buf *[]rune) bool { return scanOctRange(s, buf) }
+
+// -------------------------------- dates -----------------------------
+
+// Scan_IsoDate scans a date and time which must always be in UTC.
+//
+//	IsoDate    <-- Date 'T' Time
+func Scan_IsoDate(s pegn.Scanner, buf *[]rune) bool { return scanIsoDate(s, buf) }
+
+// Scan_Date scans a date.
+//
+//	Date       <-- Year '-' Month '-' Day
+func Scan_Date(s pegn.Scanner, buf *[]rune) bool { return scanDate(s, buf) }
+
+// Scan_Time scans a time in UTC.
+//
+//	Time       <-- Hour ':' Minute ':' Second 'Z'
+func Scan_Time(s pegn.Scanner, buf *[]rune) bool { return scanTime(s, buf) }
+
+// Scan_Year scans a year.
+//
+//	Year       <-- digit{4}
+func Scan_Year(s pegn.Scanner, buf *[]rune) bool { return scanYear(s, buf) }
+
+// Scan_Month scans a month.
+//
+//	Month      <-- ('0' [0-9]) / ('1' [0-2])
+func Scan_Month(s pegn.Scanner, buf *[]rune) bool { return scanMonth(s, buf) }
+
+// Scan_Day scans a day of the month.
+//
+//	Day        <-- ([0-2] [0-9]) / ('3' [0-1])
+func Scan_Day(s pegn.Scanner, buf *[]rune) bool { return scanDay(s, buf) }
+
+// Scan_Hour scans an hour.
+//
+//	Hour       <-- ([0-1] [0-9]) / ('2' [0-3])
+func Scan_Hour(s pegn.Scanner, buf *[]rune) bool { return scanHour(s, buf) }
+
+// Scan_Minute scans a minute.
+//
+//	Minute     <-- [0-5] [0-9]
+func Scan_Minute(s pegn.Scanner, buf *[]rune) bool { return scanMinute(s, buf) }
+
+// Scan_Second scans a second.
+//
+//	Second     <-- [0-5] [0-9]
+func Scan_Second(s pegn.Scanner, buf *[]rune) bool { return scanSecond(s, buf) }
+
+// ------------------------------- values -----------------------------
+
+// Scan_Field scans printable runes.
+//
+//	Field      <-- uprint+
+func Scan_Field(s pegn.Scanner, buf *[]rune) bool { return scanField(s, buf) }
+
+// Scan_String scans the inside of a single quoted string.
+//
+//	String     <-- quotable+
+func Scan_String(s pegn.Scanner, buf *[]rune) bool { return scanString(s, buf) }
+
+// Scan_Letter scans a single letter.
+//
+//	Letter     <-- alpha
+func Scan_Letter(s pegn.Scanner, buf *[]rune) bool { return scanLetter(s, buf) }
+
+// Scan_Unicode scans a Unicode code point (u00AD, u10FFFF).
+//
+//	Unicode    <-- 'u' ('10' uphex{4} / uphex{4,5})
+func Scan_Unicode(s pegn.Scanner, buf *[]rune) bool { return scanUnicode(s, buf) }
+
+// Scan_Integer scans a decimal integer.
+//
+//	Integer    <-- digit+
+func Scan_Integer(s pegn.Scanner, buf *[]rune) bool { return scanInteger(s, buf) }
+
+// Scan_Binary scans a binary value (b1 is b00000001).
+//
+//	Binary     <-- 'b' bindig+
+func Scan_Binary(s pegn.Scanner, buf *[]rune) bool { return scanBinary(s, buf) }
+
+// Scan_Hexadec scans a hexadecimal value.
+//
+//	Hexadec    <-- 'x' uphex+
+func Scan_Hexadec(s pegn.Scanner, buf *[]rune) bool { return scanHexadec(s, buf) }
+
+// Scan_Octal scans an octal value.
+//
+//	Octal      <-- 'o' octdig+
+func Scan_Octal(s pegn.Scanner, buf *[]rune) bool { return scanOctal(s, buf) }
+
+// Scan_EndPara scans the end of a paragraph.
+//
+//	EndPara    <-- ws* (!. / EndLine !. / EndLine{2})
+func Scan_EndPara(s pegn.Scanner, buf *[]rune) bool { return scanEndPara(s, buf) }
+
+// ------------------------------- rules ------------------------------
+
+var resClassIds = []string{
+	`alphanum`, `alpha`, `any`, `bindig`, `control`, `digit`, `hexdig`,
+	`lowerhex`, `lower`, `octdig`, `punct`, `quotable`, `sign`, `uphex`,
+	`upper`, `visible`, `ws`, `alnum`, `ascii`, `blank`, `cntrl`,
+	`graph`, `print`, `space`, `word`, `xdigit`, `unipoint`,
+}
+
+var resTokenIds = []string{
+	`TAB`, `CRLF`, `CR`, `LFAT`, `SP`, `VT`, `FF`, `NOT`, `BANG`, `DQ`,
+	`HASH`, `DOLLAR`, `PERCENT`, `AND`, `SQ`, `LPAREN`, `RPAREN`, `STAR`,
+	`PLUS`, `COMMA`, `DASH`, `MINUS`, `DOT`, `SLASH`, `COLON`, `SEMI`,
+	`LT`, `EQ`, `GT`, `QUERY`, `QUESTION`, `AT`, `LBRAKT`, `BKSLASH`,
+	`RBRAKT`, `CARET`, `UNDER`, `BKTICK`, `LCURLY`, `LBRACE`, `BAR`,
+	`PIPE`, `RCURLY`, `RBRACE`, `TILDE`, `UNKNOWN`, `REPLACE`, `MAXRUNE`,
+	`MAXASCII`, `MAXLATIN`, `LARROWF`, `RARROWF`, `LLARROW`, `RLARROW`,
+	`LARROW`, `LF`, `RARROW`, `RFAT`, `WALRUS`, `ENDOFDATA`,
+}
+
+func lits(a []string) pegn.ScanFunc {
+	x := make([]pegn.ScanFunc, len(a))
+	for i, v := range a {
+		x[i] = lit(v)
+	}
+	return alt(x...)
+}
+
+// rest of line (not including the line ending)
+var text = star(seq(not(Scan_EndLine), unipoint))
+
+// scanExpression is assigned by init since rules refer back to it.
+var scanExpression pegn.ScanFunc
+
+func init() {
+	scanExpression = node(Expression, seq(Scan_Sequence,
+		star(seq(Scan_Spacing, lit(`/`), some(sp), Scan_Sequence))))
+}
+
+var (
+	scanSpec = node(Spec, seq(opt(Scan_Meta), Scan_Rules))
+	scanMeta = node(Meta, seq(lit(`# `), Scan_Ident, some(sp), Scan_Home,
+		Scan_EndLine, opt(Scan_Copyright), opt(Scan_License),
+		star(Scan_Include)))
+	scanRules = node(Rules, some(alt(Scan_BlankLine, Scan_Comment,
+		Scan_TokenDef, Scan_ClassDef, Scan_RuleDef)))
+	scanIdent     = node(Ident, many(2, 12, upper))
+	scanHome      = node(Home, Scan_Path)
+	scanPath      = node(Path, some(seq(not(ws), unipoint)))
+	scanCopyright = node(Copyright, seq(lit(`# Copyright `),
+		some(seq(not(Scan_EndLine), unipoint)), Scan_EndLine))
+	scanLicense = node(License, seq(lit(`# SPDX-License-Identifier: `),
+		Scan_SPDXID, Scan_EndLine))
+	scanSPDXID     = node(SPDXID, some(seq(not(ws), unipoint)))
+	scanInclude    = node(Include, seq(lit(`# Include `), Scan_Path, Scan_EndLine))
+	scanComment    = node(Comment, seq(lit(`#`), text, Scan_EndLine))
+	scanBlankLine  = node(BlankLine, seq(star(sp), Scan_EndLine))
+	scanEndLine    = rule(EndLine, alt(lit("\n"), lit("\r\n"), lit("\r")))
+	scanComEndLine = rule(ComEndLine, seq(star(sp),
+		opt(seq(lit(`# `), text)), Scan_EndLine))
+	scanSpacing = rule(Spacing, seq(opt(Scan_ComEndLine), some(sp)))
+
+	scanName     = rule(Name, alt(Scan_RuleId, Scan_ClassId, Scan_TokenId))
+	scanTokenDef = node(TokenDef, seq(Scan_TokenId, some(sp), lit(`<-`),
+		some(sp), Scan_TokenVal, star(seq(Scan_Spacing, Scan_TokenVal)),
+		Scan_ComEndLine))
+	scanClassDef = node(ClassDef, seq(Scan_ClassId, some(sp), lit(`<-`),
+		some(sp), Scan_ClassExpr, Scan_ComEndLine))
+	scanRuleDef = node(RuleDef, seq(Scan_RuleId, some(sp),
+		alt(lit(`<--`), lit(`<-`)), some(sp), Scan_Expression,
+		Scan_ComEndLine))
+	scanTokenVal = rule(TokenVal, alt(Scan_Unicode, Scan_Binary, Scan_Hexadec,
+		Scan_Octal, Scan_TokenId, seq(sq, Scan_String, sq)))
+
+	scanMajorVer = node(MajorVer, some(digit))
+	scanMinorVer = node(MinorVer, some(digit))
+	scanPatchVer = node(PatchVer, some(digit))
+	scanPreVer   = node(PreVer, seq(some(alt(word, dash)),
+		star(seq(lit(`.`), some(alt(word, dash))))))
+
+	scanRuleId     = node(RuleId, some(seq(upper, some(lower))))
+	scanClassId    = node(ClassId, seq(lower, some(alt(lower, seq(under, lower)))))
+	scanTokenId    = node(TokenId, seq(upper, some(alt(upper, seq(under, upper)))))
+	scanResClassId = node(ResClassId, lits(resClassIds))
+	scanResTokenId = node(ResTokenId, lits(resTokenIds))
+
+	scanClassExpr = node(ClassExpr, seq(Scan_Simple,
+		star(seq(Scan_Spacing, lit(`/`), some(sp), Scan_Simple))))
+	scanSimple = rule(Simple, alt(Scan_Unicode, Scan_Binary, Scan_Hexadec,
+		Scan_Octal, Scan_ClassId, Scan_TokenId, Scan_Range,
+		seq(sq, Scan_String, sq)))
+	scanSequence = node(Sequence, seq(Scan_Rule, star(seq(Scan_Spacing, Scan_Rule))))
+	scanRule     = rule(Rule, alt(Scan_PosLook, Scan_NegLook, Scan_Plain))
+	scanPlain    = node(Plain, seq(Scan_Primary, opt(Scan_Quant)))
+	scanPosLook  = node(PosLook, seq(lit(`&`), Scan_Primary, opt(Scan_Quant)))
+	scanNegLook  = node(NegLook, seq(lit(`!`), Scan_Primary, opt(Scan_Quant)))
+	scanPrimary  = rule(Primary, alt(Scan_Simple, Scan_RuleId,
+		seq(lit(`(`), Scan_Expression, lit(`)`))))
+
+	scanQuant = rule(Quant, alt(Scan_Optional, Scan_MinZero, Scan_MinOne,
+		Scan_MinMax, Scan_Amount))
+	scanOptional = node(Optional, lit(`?`))
+	scanMinZero  = node(MinZero, lit(`*`))
+	scanMinOne   = node(MinOne, lit(`+`))
+	scanMinMax   = node(MinMax, seq(lit(`{`), Scan_Min, lit(`,`), opt(Scan_Max), lit(`}`)))
+	scanMin      = node(Min, some(digit))
+	scanMax      = node(Max, some(digit))
+	scanAmount   = rule(Amount, seq(lit(`{`), Scan_Count, lit(`}`)))
+	scanCount    = node(Count, some(digit))
+
+	scanRange = rule(Range, alt(Scan_AlphaRange, Scan_IntRange, Scan_UniRange,
+		Scan_BinRange, Scan_HexRange, Scan_OctRange))
+	scanUniRange   = node(UniRange, between(Scan_Unicode))
+	scanAlphaRange = node(AlphaRange, between(Scan_Letter))
+	scanIntRange   = node(IntRange, between(Scan_Integer))
+	scanBinRange   = node(BinRange, between(Scan_Binary))
+	scanHexRange   = node(HexRange, between(Scan_Hexadec))
+	scanOctRange   = node(OctRange, between(Scan_Octal))
+
+	scanIsoDate = node(IsoDate, seq(Scan_Date, lit(`T`), Scan_Time))
+	scanDate    = node(Date, seq(Scan_Year, lit(`-`), Scan_Month, lit(`-`), Scan_Day))
+	scanTime    = node(Time, seq(Scan_Hour, lit(`:`), Scan_Minute, lit(`:`),
+		Scan_Second, lit(`Z`)))
+	scanYear   = node(Year, many(4, 4, digit))
+	scanMonth  = node(Month, alt(seq(lit(`0`), digit), seq(lit(`1`), digits('0', '2'))))
+	scanDay    = node(Day, alt(seq(digits('0', '2'), digit), seq(lit(`3`), digits('0', '1'))))
+	scanHour   = node(Hour, alt(seq(digits('0', '1'), digit), seq(lit(`2`), digits('0', '3'))))
+	scanMinute = node(Minute, seq(digits('0', '5'), digit))
+	scanSecond = node(Second, seq(digits('0', '5'), digit))
+
+	scanField   = node(Field, some(uprint))
+	scanString  = node(String, some(quotable))
+	scanLetter  = node(Letter, alpha)
+	scanUnicode = node(Unicode, seq(lit(`u`),
+		alt(seq(lit(`10`), many(4, 4, uphex)), many(4, 5, uphex))))
+	scanInteger = node(Integer, some(digit))
+	scanBinary  = node(Binary, seq(lit(`b`), some(bindig)))
+	scanHexadec = node(Hexadec, seq(lit(`x`), some(uphex)))
+	scanOctal   = node(Octal, seq(lit(`o`), some(octdig)))
+	scanEndPara = node(EndPara, seq(star(ws), alt(end,
+		seq(Scan_EndLine, end), many(2, 2, Scan_EndLine))))
+)
+
+// between matches a range of x ('[' x '-' x ']').
+func between(x pegn.ScanFunc) pegn.ScanFunc {
+	return seq(lit(`[`), x, lit(`-`), x, lit(`]`))
+}
+
+// digits matches a single digit from lo to hi.
+func digits(lo, hi rune) pegn.ScanFunc {
+	return is(func(r rune) bool { return lo <= r && r <= hi })
+}
@@ -0,0 +1,325 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+/*
+Package spec contains a pegn.ScanFunc for every rule of the PEGN
+specification (pegn.dev/spec/2023-01) so that PEGN grammars (including
+the tokens and classes of the specification itself) can be scanned end
+to end with any pegn.Scanner. Each Scan_* function is named after the
+rule it scans and pushes an error with the type (rule ID) of the same
+name when it fails. Each Parse_* function returns the node tree of the
+rule with a node for every node rule (<--) matched within it so that
+entire PEGN documents can be parsed into ast.Node trees.
+
+The rules follow model.PEGN as closely as possible. Where the draft
+refers to rules it never defines the following are used (and noted
+in the documentation of each):
+
+	RuleName, CheckId  ->  RuleId
+	ClassName          ->  ClassId
+	TokenName          ->  TokenId
+	rune               ->  unipoint
+	SPDXID, EndLine, ComEndLine, BlankLine
+
+Every rule that can match a line (Comment, TokenDef, ClassDef, RuleDef)
+includes the line ending so that Rules can be a simple repetition.
+*/
+package spec
+
+import (
+	"unicode"
+
+	"github.com/rwxrob/pegn"
+	"github.com/rwxrob/pegn/curs"
+)
+
+// NEVER REMOVE FROM LIST!
+// Append to list only (even if deprecated or not supported)
+const (
+	Untyped int = iota
+	Spec
+	Meta
+	Rules
+	Ident
+	Home
+	Path
+	Copyright
+	License
+	SPDXID
+	Include
+	Comment
+	BlankLine
+	EndLine
+	ComEndLine
+	Spacing
+	Name
+	TokenDef
+	ClassDef
+	RuleDef
+	TokenVal
+	MajorVer
+	MinorVer
+	PatchVer
+	PreVer
+	RuleId
+	ClassId
+	TokenId
+	ResClassId
+	ResTokenId
+	Expression
+	ClassExpr
+	Simple
+	Sequence
+	Rule
+	Plain
+	PosLook
+	NegLook
+	Primary
+	Quant
+	Optional
+	MinZero
+	MinOne
+	MinMax
+	Min
+	Max
+	Amount
+	Count
+	Range
+	UniRange
+	AlphaRange
+	IntRange
+	BinRange
+	HexRange
+	OctRange
+	IsoDate
+	Date
+	Time
+	Year
+	Month
+	Day
+	Hour
+	Minute
+	Second
+	Field
+	String
+	Letter
+	Unicode
+	Integer
+	Binary
+	Hexadec
+	Octal
+	EndPara
+)
+
+// ------------------------------ helpers -----------------------------
+
+// The helpers build the expression of a rule from others exactly as
+// the PEGN expressions of the same name. Unlike the Scan_* functions
+// they never push errors (see rule) but do restore the position and
+// buffer when they fail.
+
+// rule returns a ScanFunc for the rule of type t matching the
+// expression. Errors pushed while trying are dropped and exactly one
+// of type t is pushed if it fails.
+func rule(t int, x pegn.ScanFunc) pegn.ScanFunc {
+	return func(s pegn.Scanner, buf *[]rune) bool {
+		errs := s.Errors()
+		n := len(*errs)
+		st := save(s, buf)
+		ok := x(s, buf)
+		*errs = (*errs)[:n]
+		if !ok {
+			restore(s, buf, st)
+			return s.Expected(t)
+		}
+		return true
+	}
+}
+
+// node is the same as rule for rules that produce nodes (<--) which
+// are recorded when parsing (see the Parse_* functions).
+func node(t int, x pegn.ScanFunc) pegn.ScanFunc {
+	x = rule(t, x)
+	return func(s pegn.Scanner, buf *[]rune) bool {
+		r, is := s.(*recorder)
+		if !is {
+			return x(s, buf)
+		}
+		i := len(r.spans)
+		r.spans = append(r.spans, span{t: t, b: s.RuneE(), depth: r.depth})
+		r.depth++
+		ok := x(s, buf)
+		r.depth--
+		if !ok {
+			r.spans = r.spans[:i]
+			return false
+		}
+		r.spans[i].e = s.RuneE()
+		return true
+	}
+}
+
+// state is everything restored when an expression fails.
+type state struct {
+	c     curs.R
+	n     int // length of buffer
+	spans int // length of spans recorded (see Parse)
+}
+
+func save(s pegn.Scanner, buf *[]rune) state {
+	st := state{c: s.Mark()}
+	if buf != nil {
+		st.n = len(*buf)
+	}
+	if r, is := s.(*recorder); is {
+		st.spans = len(r.spans)
+	}
+	return st
+}
+
+func restore(s pegn.Scanner, buf *[]rune, st state) {
+	s.Goto(st.c)
+	if buf != nil {
+		*buf = (*buf)[:st.n]
+	}
+	if r, is := s.(*recorder); is {
+		r.spans = r.spans[:st.spans]
+	}
+}
+
+func lit(a string) pegn.ScanFunc {
+	runes := []rune(a)
+	return func(s pegn.Scanner, buf *[]rune) bool {
+		if !s.Peek(a) {
+			return false
+		}
+		for range runes {
+			s.Scan()
+		}
+		if buf != nil {
+			*buf = append(*buf, runes...)
+		}
+		return true
+	}
+}
+
+func is(f pegn.ClassFunc) pegn.ScanFunc {
+	return func(s pegn.Scanner, buf *[]rune) bool {
+		m := s.Mark()
+		if !s.Scan() || !f(s.Rune()) {
+			s.Goto(m)
+			return false
+		}
+		if buf != nil {
+			*buf = append(*buf, s.Rune())
+		}
+		return true
+	}
+}
+
+func seq(x ...pegn.ScanFunc) pegn.ScanFunc {
+	return func(s pegn.Scanner, buf *[]rune) bool {
+		st := save(s, buf)
+		for _, f := range x {
+			if !f(s, buf) {
+				restore(s, buf, st)
+				return false
+			}
+		}
+		return true
+	}
+}
+
+func alt(x ...pegn.ScanFunc) pegn.ScanFunc {
+	return func(s pegn.Scanner, buf *[]rune) bool {
+		for _, f := range x {
+			if f(s, buf) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// many matches x at least min and at most max (unlimited if negative)
+// times stopping early if x matches without advancing.
+func many(min, max int, x pegn.ScanFunc) pegn.ScanFunc {
+	return func(s pegn.Scanner, buf *[]rune) bool {
+		st := save(s, buf)
+		var count int
+		for max < 0 || count < max {
+			e := s.RuneE()
+			if !x(s, buf) {
+				break
+			}
+			count++
+			if s.RuneE() == e {
+				break
+			}
+		}
+		if count < min {
+			restore(s, buf, st)
+			return false
+		}
+		return true
+	}
+}
+
+func opt(x pegn.ScanFunc) pegn.ScanFunc  { return many(0, 1, x) }
+func some(x pegn.ScanFunc) pegn.ScanFunc { return many(1, -1, x) }
+func star(x pegn.ScanFunc) pegn.ScanFunc { return many(0, -1, x) }
+
+func not(x pegn.ScanFunc) pegn.ScanFunc {
+	return func(s pegn.Scanner, buf *[]rune) bool {
+		st := save(s, nil)
+		ok := x(s, nil)
+		restore(s, nil, st)
+		return !ok
+	}
+}
+
+// end matches only at the end of the data (!. in PEGN).
+func end(s pegn.Scanner, buf *[]rune) bool { return s.Finished() }
+
+// ------------------------------ classes -----------------------------
+
+func isUpper(r rune) bool    { return 'A' <= r && r <= 'Z' }
+func isLower(r rune) bool    { return 'a' <= r && r <= 'z' }
+func isDigit(r rune) bool    { return '0' <= r && r <= '9' }
+func isAlpha(r rune) bool    { return isUpper(r) || isLower(r) }
+func isAlphanum(r rune) bool { return isAlpha(r) || isDigit(r) }
+func isUphex(r rune) bool    { return isDigit(r) || 'A' <= r && r <= 'F' }
+func isBindig(r rune) bool   { return r == '0' || r == '1' }
+func isOctdig(r rune) bool   { return '0' <= r && r <= '7' }
+func isWord(r rune) bool     { return isAlphanum(r) || r == '_' }
+func isWs(r rune) bool       { return r == ' ' || r == '\t' || r == '\n' || r == '\r' }
+func isUnipoint(r rune) bool { return true }
+
+// isQuotable is any visible ASCII or space except single quote.
+func isQuotable(r rune) bool { return ' ' <= r && r <= '~' && r != '\'' }
+
+// isUprint is the PEGN uprint class (letters, marks, numbers,
+// punctuation, and symbols).
+func isUprint(r rune) bool {
+	return unicode.In(r, unicode.L, unicode.M, unicode.N, unicode.P, unicode.S)
+}
+
+var (
+	upper    = is(isUpper)
+	lower    = is(isLower)
+	digit    = is(isDigit)
+	alpha    = is(isAlpha)
+	uphex    = is(isUphex)
+	bindig   = is(isBindig)
+	octdig   = is(isOctdig)
+	word     = is(isWord)
+	ws       = is(isWs)
+	unipoint = is(isUnipoint)
+	quotable = is(isQuotable)
+	uprint   = is(isUprint)
+
+	sp    = lit(` `)
+	under = lit(`_`)
+	dash  = lit(`-`)
+	sq    = lit(`'`)
+)
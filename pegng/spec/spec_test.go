@@ -0,0 +1,87 @@
+package spec_test
+
+import (
+	"fmt"
+
+	"github.com/rwxrob/pegn/ast"
+	"github.com/rwxrob/pegn/model"
+	"github.com/rwxrob/pegn/pegng/spec"
+	"github.com/rwxrob/pegn/scanner"
+)
+
+func Example_scanSpec() {
+
+	for _, src := range []string{model.Tokens, model.Classes} {
+		s := scanner.New(src)
+		fmt.Println(spec.Scan_Spec(s, nil), s.Finished())
+	}
+
+	s := scanner.New(`# EXAMPLE example.com/ex
+# Copyright 2023 Someone
+# SPDX-License-Identifier: Apache-2.0
+
+Greeting <-- Hello SP+ Name? '!'
+Hello     <- ('hello' / 'hi'){1,2}
+             / 'hey' # short
+Name     <-- upper lower+ !digit
+`)
+	fmt.Println(spec.Scan_Spec(s, nil), s.Finished())
+
+	// Output:
+	// true true
+	// true true
+	// true true
+}
+
+func Example_scanRuleDef() {
+
+	s := scanner.New("Sum <-- Num ('+' Num)*\n")
+	buf := []rune{}
+	fmt.Println(spec.Scan_RuleDef(s, &buf), s.Finished())
+	fmt.Printf("%q\n", string(buf))
+
+	s = scanner.New("Sum <-- Num ('+' Num\n")
+	fmt.Println(spec.Scan_RuleDef(s, nil), s.Mark())
+	fmt.Println(s.Errors())
+
+	// Output:
+	// true true
+	// "Sum <-- Num ('+' Num)*\n"
+	// false '\x00' 0-0
+	// &[expecting type 19 at '\x00' 0-0]
+}
+
+func Example_parseRuleDef() {
+
+	s := scanner.New("Sum <-- Num ('+' Num)*\n")
+	spec.Parse_RuleDef(s).Print()
+
+	// Output:
+	// {"T":19,"N":[{"T":25,"V":"Sum"},{"T":30,"N":[{"T":33,"N":[{"T":35,"N":[{"T":25,"V":"Num"}]},{"T":35,"N":[{"T":30,"N":[{"T":33,"N":[{"T":35,"N":[{"T":65,"V":"+"}]},{"T":35,"N":[{"T":25,"V":"Num"}]}]}]},{"T":41,"V":"*"}]}]}]}]}
+}
+
+func Example_parseSpec() {
+
+	s := scanner.New("# Doc\nTAB <- x09 # tab\nblank <- SP / TAB\n")
+	spec.Parse_Spec(s).Print()
+
+	// Output:
+	// {"T":1,"N":[{"T":3,"N":[{"T":11,"V":"# Doc\n"},{"T":17,"N":[{"T":27,"V":"TAB"},{"T":70,"V":"x09"}]},{"T":18,"N":[{"T":26,"V":"blank"},{"T":31,"N":[{"T":27,"V":"SP"},{"T":27,"V":"TAB"}]}]}]}]}
+}
+
+func Example_parsePositions() {
+
+	s := scanner.New("Sum <-- Num ('+' Num)*\n")
+	spec.Parse_RuleDef(s).WalkDeepPre(func(n *ast.Node) {
+		if n.Count == 0 {
+			fmt.Println(n.T, n.B, n.E, n.V == string(s.Buf[n.B:n.E]))
+		}
+	})
+
+	// Output:
+	// 25 0 3 true
+	// 25 8 11 true
+	// 65 14 15 true
+	// 25 17 20 true
+	// 41 21 22 true
+}
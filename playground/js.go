@@ -0,0 +1,31 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build js && wasm
+
+package playground
+
+import "syscall/js"
+
+// Register sets the global pegn object of the page to one with
+// compile, parse, and trace functions calling Compile, Parse, and
+// TraceJSON (missing arguments are empty) and returning JSON.
+func Register() {
+	arg := func(args []js.Value, i int) string {
+		if i < len(args) && args[i].Type() == js.TypeString {
+			return args[i].String()
+		}
+		return ``
+	}
+	js.Global().Set(`pegn`, js.ValueOf(map[string]any{
+		`compile`: js.FuncOf(func(this js.Value, args []js.Value) any {
+			return Compile(arg(args, 0)).JSON()
+		}),
+		`parse`: js.FuncOf(func(this js.Value, args []js.Value) any {
+			return Parse(arg(args, 0), arg(args, 1), arg(args, 2)).JSON()
+		}),
+		`trace`: js.FuncOf(func(this js.Value, args []js.Value) any {
+			return TraceJSON(arg(args, 0), arg(args, 1), arg(args, 2))
+		}),
+	}))
+}
@@ -0,0 +1,163 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+/*
+Package playground is everything a browser playground needs to compile
+PEGN, parse input with it, and show how the input was matched (step by
+step) running this very implementation compiled to WebAssembly. Each
+function takes nothing but strings and returns results that are plain
+JSON so that the JavaScript side has nothing to convert. Everything is
+run within the limits of Service (see package sandbox) so that no
+grammar typed into the page can hang it.
+
+The bindings (see Register) are only built for js/wasm:
+
+	GOOS=js GOARCH=wasm go build -o pegn.wasm ./playground/wasm
+
+After loading pegn.wasm (with the wasm_exec.js shipped with Go) the
+global pegn object has compile(src), parse(src, rule, input), and
+trace(src, rule, input) each returning the JSON of a Result.
+*/
+package playground
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/rwxrob/pegn/ast"
+	"github.com/rwxrob/pegn/gr"
+	"github.com/rwxrob/pegn/sandbox"
+	"github.com/rwxrob/pegn/scanner"
+)
+
+// Service runs every request within its limits (see sandbox.Limits).
+var Service = sandbox.New(sandbox.DefaultLimits)
+
+// MaxSteps is the most steps kept by TraceJSON (the rest are dropped
+// and the Result is Truncated).
+var MaxSteps = 10000
+
+// Result is what every function returns. Only the fields that apply
+// are set.
+type Result struct {
+	Error     string   `json:"error,omitempty"`     // why it failed (if it did)
+	Grammar   string   `json:"grammar,omitempty"`   // canonical PEGN (Compile)
+	Entries   []string `json:"entries,omitempty"`   // entry rules (Compile)
+	Rules     []string `json:"rules,omitempty"`     // every rule in order (Compile)
+	Tree      *Node    `json:"tree,omitempty"`      // parsed (Parse and TraceJSON)
+	Trace     []Step   `json:"trace,omitempty"`     // rules in order tried (TraceJSON)
+	Truncated bool     `json:"truncated,omitempty"` // more than MaxSteps
+}
+
+// JSON returns the result as JSON (without escaping HTML since PEGN is
+// full of arrows).
+func (r Result) JSON() string {
+	var out strings.Builder
+	enc := json.NewEncoder(&out)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(r); err != nil {
+		return Result{Error: err.Error()}.JSON()
+	}
+	return strings.TrimSuffix(out.String(), "\n")
+}
+
+// Node is an ast.Node with the name of its rule rather than its type
+// so that the tree can be shown as is.
+type Node struct {
+	Rule  string  `json:"rule"`
+	Value string  `json:"value,omitempty"`
+	Nodes []*Node `json:"nodes,omitempty"`
+}
+
+// Step is a rule entered or exited while tracing (see TraceJSON).
+type Step struct {
+	Kind  string `json:"kind"`         // enter or exit
+	Rule  string `json:"rule"`         // name of rule
+	Depth int    `json:"depth"`        // rules entered but not yet exited
+	B     int    `json:"b"`            // byte offset rule began at
+	E     int    `json:"e"`            // byte offset of cursor
+	OK    bool   `json:"ok,omitempty"` // rule matched (exit only)
+}
+
+// Compile compiles the PEGN source returning the canonical form of it
+// (see gr.Grammar.String) along with the names of its rules.
+func Compile(src string) Result {
+	g, err := Service.Compile(src)
+	if err != nil {
+		return Result{Error: err.Error()}
+	}
+	res := Result{Grammar: g.String()}
+	for _, r := range g.Entries() {
+		res.Entries = append(res.Entries, r.Name)
+	}
+	for _, r := range g.Rules {
+		res.Rules = append(res.Rules, r.Name)
+	}
+	return res
+}
+
+// Parse parses the input with the named rule (or the first if empty)
+// of the grammar compiled from the source (see sandbox.Service.Parse).
+func Parse(src, rule, input string) Result {
+	n, err := Service.Parse(context.Background(), src, rule, strings.NewReader(input))
+	return result(src, n, err)
+}
+
+// TraceJSON parses like Parse and returns the JSON of the Result with
+// every rule of the grammar entered and exited in order as its Trace
+// (up to MaxSteps).
+func TraceJSON(src, rule, input string) string {
+	var steps []Step
+	var truncated bool
+	var names map[int]string
+	if g, err := Service.Compile(src); err == nil {
+		names = namesOf(g)
+	}
+	fn := func(e scanner.TraceEvent) {
+		if e.Kind != scanner.TraceEnter && e.Kind != scanner.TraceExit {
+			return
+		}
+		if len(steps) >= MaxSteps {
+			truncated = true
+			return
+		}
+		st := Step{Kind: e.Kind.String(), Rule: names[e.T], Depth: e.Depth, B: e.C.E, E: e.C.E}
+		if e.Kind == scanner.TraceExit {
+			st.B, st.OK = e.B, e.OK
+		}
+		steps = append(steps, st)
+	}
+	n, err := Service.Trace(context.Background(), src, rule, strings.NewReader(input), fn)
+	res := result(src, n, err)
+	res.Trace, res.Truncated = steps, truncated
+	return res.JSON()
+}
+
+// result returns the Result of a parse naming the nodes of the tree.
+func result(src string, n *ast.Node, err error) Result {
+	if err != nil {
+		return Result{Error: err.Error()}
+	}
+	g, err := Service.Compile(src) // cached
+	if err != nil {
+		return Result{Error: err.Error()}
+	}
+	return Result{Tree: named(n, namesOf(g))}
+}
+
+func namesOf(g *gr.Grammar) map[int]string {
+	names := map[int]string{}
+	for _, r := range g.Rules {
+		names[r.ID] = r.Name
+	}
+	return names
+}
+
+func named(n *ast.Node, names map[int]string) *Node {
+	u := &Node{Rule: names[n.T], Value: n.V}
+	for _, c := range n.Nodes() {
+		u.Nodes = append(u.Nodes, named(c, names))
+	}
+	return u
+}
@@ -0,0 +1,25 @@
+package playground_test
+
+import (
+	"fmt"
+
+	"github.com/rwxrob/pegn/playground"
+)
+
+func ExampleTraceJSON() {
+
+	sum := `
+Sum <-- Num ('+' Num)*
+Num <-- digit+`
+
+	fmt.Println(playground.Compile(sum).JSON())
+	fmt.Println(playground.Compile(`Sum <- Nope`).JSON())
+	fmt.Println(playground.Parse(sum, ``, `1+22`).JSON())
+	fmt.Println(playground.TraceJSON(sum, ``, `1+`))
+
+	// Output:
+	// {"grammar":"Sum <-- Num ('+' Num)*\nNum <-- digit+\n","entries":["Sum"],"rules":["Sum","Num"]}
+	// {"error":"line 1: undefined: Nope"}
+	// {"tree":{"rule":"Sum","nodes":[{"rule":"Num","value":"1"},{"rule":"Num","value":"22"}]}}
+	// {"tree":{"rule":"Sum","nodes":[{"rule":"Num","value":"1"}]},"trace":[{"kind":"enter","rule":"Sum","depth":0,"b":0,"e":0},{"kind":"enter","rule":"Num","depth":1,"b":0,"e":0},{"kind":"exit","rule":"Num","depth":1,"b":0,"e":1,"ok":true},{"kind":"enter","rule":"Num","depth":1,"b":2,"e":2},{"kind":"exit","rule":"Num","depth":1,"b":2,"e":2},{"kind":"exit","rule":"Sum","depth":0,"b":0,"e":1,"ok":true}]}
+}
@@ -0,0 +1,16 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build js && wasm
+
+// Command wasm is the playground (see package playground) compiled to
+// WebAssembly. It registers the global pegn object and then waits for
+// the page to call it.
+package main
+
+import "github.com/rwxrob/pegn/playground"
+
+func main() {
+	playground.Register()
+	select {}
+}
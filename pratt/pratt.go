@@ -0,0 +1,207 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+/*
+Package pratt provides a Pratt (top down operator precedence) parser
+for the expression parts of a grammar. Expressing a dozen levels of
+precedence as PEG rules means a rule for every level and a trip
+through every one of them for every operand. A Pratt parser needs
+only an operator table and tries each operator once. The Parser
+has the same Scan and Parse methods as everything in pegng and works directly on
+the same pegn.Scanner as everything else so the rest of the grammar
+stays PEG (see gr.Grammar.Delegates for handing an interpreted rule
+to a Parser).
+*/
+package pratt
+
+import (
+	"unicode"
+
+	"github.com/rwxrob/pegn"
+	"github.com/rwxrob/pegn/ast"
+	"github.com/rwxrob/pegn/pegng"
+)
+
+// Op is a single operator of a Table.
+type Op struct {
+	T     int    // type of the node created for the operation
+	Op    string // operator as written (ex: +, **, not)
+	Prec  int    // binding power (higher binds tighter)
+	Right bool   // right associative (infix only)
+}
+
+// Table contains every operator by where it is written in relation to
+// its operands. The same operator may be both Prefix and Infix (ex: -).
+// Operators ending with a letter or digit (ex: and, not) only match
+// when not followed by another letter, digit, or underscore.
+type Table struct {
+	Prefix  []Op // before the operand (ex: -x, not x)
+	Infix   []Op // between operands (ex: x + y)
+	Postfix []Op // after the operand (ex: x!)
+}
+
+// Parser parses expressions of operands and the operators of a Table
+// into node trees with operators as nodes and their operands under them
+// (one for Prefix and Postfix, two for Infix). The zero value is not
+// usable. Use New.
+type Parser struct {
+	T       int           // type for errors
+	Operand ParseFunc     // parses a single operand (ex: number, (Expr))
+	Skip    pegn.ScanFunc // scanned before operands and operators (optional)
+
+	prefix, infix, postfix opset
+}
+
+// ParseFunc parses a single node (such as the Parse_ functions of
+// pegng) returning nil if not found.
+type ParseFunc func(s pegn.Scanner) *ast.Node
+
+type opset struct {
+	ops *pegng.Operators
+	by  map[string]Op
+}
+
+func newOpset(list []Op) opset {
+	set := opset{by: map[string]Op{}}
+	syms := make([]string, 0, len(list))
+	for _, op := range list {
+		set.by[op.Op] = op
+		syms = append(syms, op.Op)
+	}
+	set.ops = pegng.NewOperators(0, syms...)
+	return set
+}
+
+// New returns a Parser of the given type for the operators of the
+// table with operands parsed by operand. The operand function is free
+// to call Parse of the returned Parser (ex: for parenthesized
+// expressions).
+func New(t int, table Table, operand ParseFunc) *Parser {
+	return &Parser{
+		T:       t,
+		Operand: operand,
+		prefix:  newOpset(table.Prefix),
+		infix:   newOpset(table.Infix),
+		postfix: newOpset(table.Postfix),
+	}
+}
+
+// Parse parses the longest expression it
+// can. Anything after that is left to be scanned next (including any
+// infix operator missing an operand after it). Fails (pushing an error
+// of type T) only if no operand is found at all.
+func (p *Parser) Parse(s pegn.Scanner) *ast.Node {
+	m := s.Mark()
+	errs := s.Errors()
+	n := len(*errs)
+	if x := p.expr(s, 0); x != nil {
+		*errs = (*errs)[:n]
+		return x
+	}
+	*errs = (*errs)[:n]
+	s.Revert(m, p.T)
+	return nil
+}
+
+// Scan fulfills pegn.ScanFunc buffering the expression exactly as
+// written (see Parse).
+func (p *Parser) Scan(s pegn.Scanner, buf *[]rune) bool {
+	m := s.Mark()
+	if p.Parse(s) == nil {
+		return false
+	}
+	if buf != nil {
+		*buf = append(*buf, []rune(s.CopyEE(m))...)
+	}
+	return true
+}
+
+// expr parses operations binding at least as tightly as min.
+func (p *Parser) expr(s pegn.Scanner, min int) *ast.Node {
+	m := s.Mark()
+	p.skip(s)
+
+	var left *ast.Node
+	if op, ok := p.op(s, p.prefix); ok {
+		x := p.expr(s, op.Prec)
+		if x == nil {
+			s.Goto(m)
+			return nil
+		}
+		left = node(op.T, x)
+	} else if left = p.Operand(s); left == nil {
+		s.Goto(m)
+		return nil
+	}
+
+	for {
+		b := s.Mark()
+		p.skip(s)
+
+		if op, ok := p.op(s, p.postfix); ok && op.Prec >= min {
+			left = node(op.T, left)
+			continue
+		}
+		s.Goto(b)
+		p.skip(s)
+
+		op, ok := p.op(s, p.infix)
+		if !ok || op.Prec < min {
+			s.Goto(b)
+			return left
+		}
+		next := op.Prec + 1
+		if op.Right {
+			next = op.Prec
+		}
+		right := p.expr(s, next)
+		if right == nil {
+			s.Goto(b)
+			return left
+		}
+		left = node(op.T, left, right)
+	}
+}
+
+func (p *Parser) skip(s pegn.Scanner) {
+	if p.Skip != nil {
+		p.Skip(s, nil)
+	}
+}
+
+// op scans the longest operator of the set (without pushing any error)
+// returning false if there is none.
+func (p *Parser) op(s pegn.Scanner, set opset) (Op, bool) {
+	m := s.Mark()
+	errs := s.Errors()
+	n := len(*errs)
+	buf := make([]rune, 0, 4)
+	if !set.ops.Scan(s, &buf) {
+		*errs = (*errs)[:n]
+		return Op{}, false
+	}
+	if isWord(buf[len(buf)-1]) && followedByWord(s) {
+		s.Goto(m)
+		return Op{}, false
+	}
+	return set.by[string(buf)], true
+}
+
+func isWord(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+func followedByWord(s pegn.Scanner) bool {
+	m := s.Mark()
+	defer s.Goto(m)
+	return s.Scan() && isWord(s.Rune())
+}
+
+func node(t int, under ...*ast.Node) *ast.Node {
+	n := &ast.Node{T: t}
+	for _, u := range under {
+		u.P = n
+		n.Append(u)
+	}
+	return n
+}
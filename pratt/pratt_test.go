@@ -0,0 +1,95 @@
+package pratt_test
+
+import (
+	"fmt"
+
+	"github.com/rwxrob/pegn"
+	"github.com/rwxrob/pegn/ast"
+	"github.com/rwxrob/pegn/pegng"
+	"github.com/rwxrob/pegn/pratt"
+	"github.com/rwxrob/pegn/scanner"
+)
+
+const (
+	Num = iota + 1
+	Add
+	Sub
+	Mul
+	Pow
+	Neg
+	Not
+	Fact
+	And
+)
+
+func ExampleParser() {
+
+	table := pratt.Table{
+		Prefix: []pratt.Op{
+			{T: Neg, Op: `-`, Prec: 50},
+			{T: Not, Op: `not`, Prec: 5},
+		},
+		Infix: []pratt.Op{
+			{T: And, Op: `and`, Prec: 4},
+			{T: Add, Op: `+`, Prec: 10},
+			{T: Sub, Op: `-`, Prec: 10},
+			{T: Mul, Op: `*`, Prec: 20},
+			{T: Pow, Op: `**`, Prec: 30, Right: true},
+		},
+		Postfix: []pratt.Op{
+			{T: Fact, Op: `!`, Prec: 60},
+		},
+	}
+
+	var p *pratt.Parser
+	p = pratt.New(-1, table, func(s pegn.Scanner) *ast.Node {
+		if s.Peek(`(`) {
+			m := s.Mark()
+			s.Scan()
+			n := p.Parse(s)
+			if n == nil || !s.Peek(`)`) {
+				s.Revert(m, -1)
+				return nil
+			}
+			s.Scan()
+			return n
+		}
+		buf := make([]rune, 0, 8)
+		if !pegng.Scan_Integer(s, &buf) {
+			return nil
+		}
+		return &ast.Node{T: Num, V: string(buf)}
+	})
+	p.Skip = pegng.Skip(pegng.Scan_ws)
+
+	for _, in := range []string{
+		`1 + 2 * 3`,
+		`2 ** 3 ** 2`,
+		`-(1 - 2) - 3!`,
+		`not 1 and 2`,
+		`1 + 2 andy`,
+		`1 +`,
+	} {
+		s := scanner.New(in)
+		fmt.Println(p.Parse(s))
+		s.Print()
+	}
+
+	s := scanner.New(`* 2`)
+	fmt.Println(p.Parse(s), s.Errors())
+
+	// Output:
+	// {"T":2,"N":[{"T":1,"V":"1"},{"T":4,"N":[{"T":1,"V":"2"},{"T":1,"V":"3"}]}]}
+	// '3' 8-9 ""
+	// {"T":5,"N":[{"T":1,"V":"2"},{"T":5,"N":[{"T":1,"V":"3"},{"T":1,"V":"2"}]}]}
+	// '2' 10-11 ""
+	// {"T":3,"N":[{"T":6,"N":[{"T":3,"N":[{"T":1,"V":"1"},{"T":1,"V":"2"}]}]},{"T":8,"N":[{"T":1,"V":"3"}]}]}
+	// '!' 12-13 ""
+	// {"T":9,"N":[{"T":7,"N":[{"T":1,"V":"1"}]},{"T":1,"V":"2"}]}
+	// '2' 10-11 ""
+	// {"T":2,"N":[{"T":1,"V":"1"},{"T":1,"V":"2"}]}
+	// '2' 4-5 " andy"
+	// {"T":1,"V":"1"}
+	// '1' 0-1 " +"
+	// <nil> &[expecting type -1 at '\x00' 0-0]
+}
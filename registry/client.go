@@ -0,0 +1,148 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+package registry
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/rwxrob/pegn/gr"
+)
+
+// MaxGrammar is the most bytes of grammar source that will be sent or
+// received.
+var MaxGrammar = 1 << 20
+
+// Client fetches grammars from (and publishes them to) a registry over
+// HTTP using the following requests relative to Base:
+//
+//	GET <name>/@v/list             versions, one per line
+//	GET <name>/@v/<version>.hash   content hash (see Hash)
+//	GET <name>/@v/<version>.pegn   grammar source
+//	PUT <name>/@v/<version>.pegn   publish grammar source
+//
+// Everything fetched is kept in Store (unless its Dir is empty) and
+// never fetched again.
+type Client struct {
+	Base  string       // URL of registry (https://grammars.example.com)
+	Store Store        // where everything fetched is kept
+	HTTP  *http.Client // http.DefaultClient if nil
+}
+
+func (c *Client) url(name, file string) string {
+	return strings.TrimSuffix(c.Base, `/`) + `/` + name + `/@v/` + file
+}
+
+func (c *Client) do(ctx context.Context, method, url string, body []byte) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	hc := c.HTTP
+	if hc == nil {
+		hc = http.DefaultClient
+	}
+	res, err := hc.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	byt, err := io.ReadAll(io.LimitReader(res.Body, int64(MaxGrammar)+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(byt) > MaxGrammar {
+		return nil, fmt.Errorf(`%v %v: response larger than %v bytes`, method, url, MaxGrammar)
+	}
+	switch {
+	case res.StatusCode == http.StatusNotFound:
+		return nil, fmt.Errorf(`%v %v: %w`, method, url, ErrNotFound)
+	case res.StatusCode >= 300:
+		msg := strings.TrimSpace(string(byt))
+		return nil, fmt.Errorf(`%v %v: %v: %v`, method, url, res.Status, msg)
+	}
+	return byt, nil
+}
+
+// Fetch returns the grammar source of the version from Store or from
+// the registry after verifying that its content matches the hash the
+// registry has for it.
+func (c *Client) Fetch(ctx context.Context, r Ref) ([]byte, error) {
+	if err := r.Valid(); err != nil {
+		return nil, err
+	}
+	if c.Store.Dir != `` {
+		if src, err := c.Store.Get(r); err == nil {
+			return src, nil
+		}
+	}
+	hash, err := c.do(ctx, `GET`, c.url(r.Name, r.Version+`.hash`), nil)
+	if err != nil {
+		return nil, err
+	}
+	src, err := c.do(ctx, `GET`, c.url(r.Name, r.Version+`.pegn`), nil)
+	if err != nil {
+		return nil, err
+	}
+	want := strings.TrimSpace(string(hash))
+	if got := Hash(src); got != want {
+		return nil, fmt.Errorf(`%v: hash %v does not match %v`, r, got, want)
+	}
+	if c.Store.Dir != `` {
+		if _, err := c.Store.Put(r, src); err != nil {
+			return nil, err
+		}
+	}
+	return src, nil
+}
+
+// Grammar fetches (see Fetch) and compiles (see gr.Compile) the
+// version.
+func (c *Client) Grammar(ctx context.Context, r Ref) (*gr.Grammar, error) {
+	src, err := c.Fetch(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+	return gr.Compile(string(src))
+}
+
+// Versions returns every version of the named grammar in the registry
+// in order (lowest first).
+func (c *Client) Versions(ctx context.Context, name string) ([]string, error) {
+	if err := (Ref{name, `v0.0.0`}).Valid(); err != nil {
+		return nil, err
+	}
+	byt, err := c.do(ctx, `GET`, c.url(name, `list`), nil)
+	if err != nil {
+		return nil, err
+	}
+	return strings.Fields(string(byt)), nil
+}
+
+// Publish sends the grammar source to the registry as the version
+// (which must compile) and returns its hash.
+func (c *Client) Publish(ctx context.Context, r Ref, src []byte) (string, error) {
+	if err := r.Valid(); err != nil {
+		return ``, err
+	}
+	if len(src) > MaxGrammar {
+		return ``, fmt.Errorf(`%v: larger than %v bytes`, r, MaxGrammar)
+	}
+	if _, err := gr.Compile(string(src)); err != nil {
+		return ``, fmt.Errorf(`%v: %w`, r, err)
+	}
+	byt, err := c.do(ctx, `PUT`, c.url(r.Name, r.Version+`.pegn`), src)
+	if err != nil {
+		return ``, err
+	}
+	hash := Hash(src)
+	if got := strings.TrimSpace(string(byt)); got != hash {
+		return ``, fmt.Errorf(`%v: registry stored %v instead of %v`, r, got, hash)
+	}
+	return hash, nil
+}
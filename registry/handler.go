@@ -0,0 +1,99 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+package registry
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// Handler serves the Store as a registry (see Client for the requests).
+// Publishing (PUT) is refused unless Writable, in which case anyone who
+// can reach the handler can publish, so wrap it with whatever
+// authentication is needed. Mount it with http.StripPrefix when not
+// serving from the root.
+type Handler struct {
+	Store    Store
+	Writable bool
+}
+
+// ServeHTTP fulfills http.Handler.
+func (h Handler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	name, file, found := strings.Cut(strings.TrimPrefix(req.URL.Path, `/`), `/@v/`)
+	if !found {
+		http.NotFound(w, req)
+		return
+	}
+
+	if file == `list` && req.Method == `GET` {
+		list, err := h.Store.Versions(name)
+		if err != nil {
+			h.error(w, req, err)
+			return
+		}
+		fmt.Fprintln(w, strings.Join(list, "\n"))
+		return
+	}
+
+	ver := strings.TrimSuffix(strings.TrimSuffix(file, `.pegn`), `.hash`)
+	r := Ref{name, ver}
+	if err := r.Valid(); err != nil || ver == file {
+		http.NotFound(w, req)
+		return
+	}
+
+	switch {
+	case req.Method == `GET` && strings.HasSuffix(file, `.hash`):
+		hash, err := h.Store.Lookup(r)
+		if err != nil {
+			h.error(w, req, err)
+			return
+		}
+		fmt.Fprintln(w, hash)
+
+	case req.Method == `GET`:
+		src, err := h.Store.Get(r)
+		if err != nil {
+			h.error(w, req, err)
+			return
+		}
+		w.Header().Set(`Content-Type`, `text/plain; charset=utf-8`)
+		w.Write(src)
+
+	case req.Method == `PUT` && strings.HasSuffix(file, `.pegn`) && h.Writable:
+		src, err := io.ReadAll(io.LimitReader(req.Body, int64(MaxGrammar)+1))
+		if err != nil {
+			h.error(w, req, err)
+			return
+		}
+		if len(src) > MaxGrammar {
+			http.Error(w, `grammar too large`, http.StatusRequestEntityTooLarge)
+			return
+		}
+		hash, err := h.Store.Put(r, src)
+		if errors.Is(err, ErrPublished) {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		fmt.Fprintln(w, hash)
+
+	default:
+		http.Error(w, `method not allowed`, http.StatusMethodNotAllowed)
+	}
+}
+
+func (h Handler) error(w http.ResponseWriter, req *http.Request, err error) {
+	if errors.Is(err, ErrNotFound) {
+		http.NotFound(w, req)
+		return
+	}
+	http.Error(w, err.Error(), http.StatusInternalServerError)
+}
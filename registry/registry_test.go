@@ -0,0 +1,171 @@
+package registry_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"os"
+	"sync"
+
+	"github.com/rwxrob/pegn/registry"
+)
+
+func ExampleClient() {
+
+	dir, _ := os.MkdirTemp(``, `registry`)
+	defer os.RemoveAll(dir)
+
+	// the registry itself
+	srv := httptest.NewServer(registry.Handler{
+		Store:    registry.Store{Dir: dir + `/registry`},
+		Writable: true,
+	})
+	defer srv.Close()
+
+	c := registry.Client{
+		Base:  srv.URL,
+		Store: registry.Store{Dir: dir + `/cache`},
+	}
+	ctx := context.Background()
+
+	v1, _ := registry.ParseRef(`rwxrob/greet@v1.0.0`)
+	v2, _ := registry.ParseRef(`rwxrob/greet@v1.1.0-beta.1`)
+
+	fmt.Println(c.Publish(ctx, v1, []byte("Greet <-- 'hi'\n")))
+	fmt.Println(c.Publish(ctx, v2, []byte("Greet <-- 'hi' / 'hello'\n")))
+	_, err := c.Publish(ctx, v1, []byte("Greet <-- 'hey'\n"))
+	fmt.Println(err != nil)
+	_, err = c.Publish(ctx, v1, []byte("Greet <-- Hi\n"))
+	fmt.Println(err)
+
+	fmt.Println(c.Versions(ctx, `rwxrob/greet`))
+	g, err := c.Grammar(ctx, v2)
+	fmt.Println(g.Rule(`Greet`), err)
+	_, err = c.Fetch(ctx, registry.Ref{Name: `rwxrob/greet`, Version: `v2.0.0`})
+	fmt.Println(errors.Is(err, registry.ErrNotFound))
+
+	srv.Close() // fetched versions are kept in the local store
+	src, err := c.Fetch(ctx, v2)
+	fmt.Printf("%q %v\n", src, err)
+
+	// Output:
+	// sha256:85b1056455e2ba4de3c8c5222607e3e183aa73b60d37b16e8db35ec57611603a <nil>
+	// sha256:c9e57e26c755673997fd8a5d4bbc794ba19f45f9b9d564e6290132801f0c5318 <nil>
+	// true
+	// rwxrob/greet@v1.0.0: line 1: undefined: Hi
+	// [v1.0.0 v1.1.0-beta.1] <nil>
+	// Greet <-- 'hi' / 'hello' <nil>
+	// true
+	// "Greet <-- 'hi' / 'hello'\n" <nil>
+}
+
+func ExampleHandler() {
+
+	dir, _ := os.MkdirTemp(``, `registry`)
+	defer os.RemoveAll(dir)
+
+	srv := httptest.NewServer(registry.Handler{Store: registry.Store{Dir: dir}})
+	defer srv.Close()
+
+	c := registry.Client{Base: srv.URL, Store: registry.Store{Dir: dir + `/cache`}}
+	v1, _ := registry.ParseRef(`rwxrob/greet@v1.0.0`)
+	_, err := c.Publish(context.Background(), v1, []byte("Greet <-- 'hi'\n"))
+	fmt.Println(err != nil)
+	_, err = registry.Store{Dir: dir}.Lookup(v1)
+	fmt.Println(errors.Is(err, registry.ErrNotFound))
+
+	// Output:
+	// true
+	// true
+}
+
+func ExampleStore_Put() {
+
+	dir, _ := os.MkdirTemp(``, `registry`)
+	defer os.RemoveAll(dir)
+	st := registry.Store{Dir: dir}
+	v1, _ := registry.ParseRef(`rwxrob/greet@v1.0.0`)
+
+	// many publishing different content for the same version at once
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var stored, refused int
+	start := make(chan struct{})
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			<-start
+			_, err := st.Put(v1, []byte(fmt.Sprintf("Greet <-- 'hi%v'\n", i)))
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err == nil:
+				stored++
+			case errors.Is(err, registry.ErrPublished):
+				refused++
+			}
+		}(i)
+	}
+	close(start)
+	wg.Wait()
+	fmt.Println(stored, refused)
+
+	src, _ := st.Get(v1)
+	_, err := st.Put(v1, src)
+	fmt.Println(err)
+
+	// Output:
+	// 1 99
+	// <nil>
+}
+
+func ExampleStore_Put_collision() {
+
+	dir, _ := os.MkdirTemp(``, `registry`)
+	defer os.RemoveAll(dir)
+	st := registry.Store{Dir: dir}
+	src := []byte("Greet <-- 'hi'\n")
+
+	// a name ending with a version would take the path of that version
+	_, err := st.Put(registry.Ref{Name: `json/v2.0.0`, Version: `v1.0.0`}, src)
+	fmt.Println(err)
+	_, err = st.Put(registry.Ref{Name: `json`, Version: `v2.0.0`}, src)
+	fmt.Println(err)
+	fmt.Println(st.Versions(`json`))
+
+	// Output:
+	// invalid name: "json/v2.0.0" (element is a version)
+	// <nil>
+	// [v2.0.0] <nil>
+}
+
+func ExampleParseRef() {
+	for _, a := range []string{
+		`rwxrob/json@v1.2.3`,
+		`json@v0.1.0-rc.1`,
+		`JSON@v1.0.0`,
+		`json@1.0.0`,
+		`json`,
+		`../json@v1.0.0`,
+		`json/v2.0.0@v1.0.0`,
+		`json/v2@v2.0.0`,
+	} {
+		r, err := registry.ParseRef(a)
+		if err != nil {
+			fmt.Println(err)
+			continue
+		}
+		fmt.Println(r)
+	}
+	// Output:
+	// rwxrob/json@v1.2.3
+	// json@v0.1.0-rc.1
+	// invalid name: "JSON"
+	// invalid version: "1.0.0"
+	// missing @version: "json"
+	// invalid name: "../json"
+	// invalid name: "json/v2.0.0" (element is a version)
+	// json/v2@v2.0.0
+}
@@ -0,0 +1,326 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+/*
+Package registry publishes and fetches PEGN grammars by name and
+version (name@version) much as Go modules are, so that grammars can be
+shared between projects and pinned by their content hashes.
+
+Grammars are kept in a Store, a directory laid out as follows (with
+every grammar source stored once no matter how many versions refer to
+it):
+
+	<dir>/blobs/sha256/<hex>          exact bytes of grammar source
+	<dir>/grammars/<name>/<version>   sha256:<hex> of the source
+
+A Client fetches from (and publishes to) a registry over HTTP keeping
+everything fetched in a local Store so that each version is only ever
+downloaded once. Handler serves a Store as a registry.
+
+Versions are immutable: once published, a version can never refer to
+different content. Every grammar must compile (see gr.Compile) before
+it is stored and its content hash is verified whenever it is read.
+*/
+package registry
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/rwxrob/pegn/gr"
+)
+
+// ErrNotFound is wrapped by errors for grammars or versions that do not
+// exist.
+var ErrNotFound = errors.New(`not found`)
+
+// ErrPublished is wrapped by errors for versions already published with
+// different content.
+var ErrPublished = errors.New(`already published`)
+
+// Ref refers to a single version of a named grammar.
+type Ref struct {
+	Name    string // slash separated lowercase path (rwxrob/json)
+	Version string // semantic version beginning with v (v1.2.3)
+}
+
+// String returns the reference as name@version.
+func (r Ref) String() string { return r.Name + `@` + r.Version }
+
+// ParseRef parses and validates name@version.
+func ParseRef(a string) (Ref, error) {
+	name, ver, found := strings.Cut(a, `@`)
+	if !found {
+		return Ref{}, fmt.Errorf(`missing @version: %q`, a)
+	}
+	r := Ref{name, ver}
+	return r, r.Valid()
+}
+
+// Valid returns an error unless the name is one or more slash separated
+// elements of lowercase letters, digits, dots, dashes, and underscores
+// (not beginning with a dot) and the version is a semantic version
+// beginning with v (v1.2.3 or v1.2.3-pre.1). Name elements that are
+// themselves versions (json/v2.0.0) are invalid since versions are
+// stored within the directory of the name (see package documentation)
+// and would collide with them.
+func (r Ref) Valid() error {
+	if r.Name == `` {
+		return fmt.Errorf(`missing name`)
+	}
+	for _, e := range strings.Split(r.Name, `/`) {
+		if e == `` || e[0] == '.' {
+			return fmt.Errorf(`invalid name: %q`, r.Name)
+		}
+		for _, c := range e {
+			if !('a' <= c && c <= 'z' || '0' <= c && c <= '9' ||
+				c == '.' || c == '-' || c == '_') {
+				return fmt.Errorf(`invalid name: %q`, r.Name)
+			}
+		}
+		if _, ok := semver(e); ok {
+			return fmt.Errorf(`invalid name: %q (element is a version)`, r.Name)
+		}
+	}
+	if _, ok := semver(r.Version); !ok {
+		return fmt.Errorf(`invalid version: %q`, r.Version)
+	}
+	return nil
+}
+
+// semver returns the major, minor, and patch numbers of the version
+// (ignoring any pre-release) and whether it is valid.
+func semver(v string) ([3]int, bool) {
+	var n [3]int
+	if !strings.HasPrefix(v, `v`) {
+		return n, false
+	}
+	v, pre, hasPre := strings.Cut(v[1:], `-`)
+	if hasPre && pre == `` {
+		return n, false
+	}
+	for _, c := range pre {
+		if !('a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' ||
+			'0' <= c && c <= '9' || c == '.' || c == '-') {
+			return n, false
+		}
+	}
+	f := strings.Split(v, `.`)
+	if len(f) != 3 {
+		return n, false
+	}
+	for i, a := range f {
+		if a == `` || len(a) > 1 && a[0] == '0' {
+			return n, false
+		}
+		x, err := strconv.Atoi(a)
+		if err != nil || x < 0 {
+			return n, false
+		}
+		n[i] = x
+	}
+	return n, true
+}
+
+// less orders versions by number with pre-releases before the release
+// and otherwise lexically.
+func less(a, b string) bool {
+	x, _ := semver(a)
+	y, _ := semver(b)
+	if x != y {
+		for i := range x {
+			if x[i] != y[i] {
+				return x[i] < y[i]
+			}
+		}
+	}
+	_, pa, hasA := strings.Cut(a, `-`)
+	_, pb, hasB := strings.Cut(b, `-`)
+	if hasA != hasB {
+		return hasA
+	}
+	return pa < pb
+}
+
+// Hash returns the content hash of grammar source (sha256:<hex>) which
+// is the hash of the exact bytes rather than the canonical form (see
+// gr.Grammar.Hash) so that what was published is exactly what is
+// fetched.
+func Hash(src []byte) string {
+	return fmt.Sprintf(`sha256:%x`, sha256.Sum256(src))
+}
+
+// Store is a directory of grammars (see package documentation).
+type Store struct {
+	Dir string
+}
+
+func (st Store) blob(hash string) (string, error) {
+	hex := strings.TrimPrefix(hash, `sha256:`)
+	if hex == hash || len(hex) != sha256.Size*2 || strings.Trim(hex, `0123456789abcdef`) != `` {
+		return ``, fmt.Errorf(`invalid hash: %q`, hash)
+	}
+	return filepath.Join(st.Dir, `blobs`, `sha256`, hex), nil
+}
+
+func (st Store) index(r Ref) string {
+	return filepath.Join(st.Dir, `grammars`, filepath.FromSlash(r.Name), r.Version)
+}
+
+// Put stores the grammar source as the version returning its hash.
+// Putting exactly the same source again is not an error but different
+// source for a version already stored is, even when put at the same
+// time by another process (see create).
+func (st Store) Put(r Ref, src []byte) (string, error) {
+	if err := r.Valid(); err != nil {
+		return ``, err
+	}
+	if _, err := gr.Compile(string(src)); err != nil {
+		return ``, fmt.Errorf(`%v: %w`, r, err)
+	}
+	hash := Hash(src)
+	if had, err := st.Lookup(r); err == nil {
+		if had != hash {
+			return ``, fmt.Errorf(`%v: %w with %v`, r, ErrPublished, had)
+		}
+		return hash, nil
+	}
+	path, _ := st.blob(hash)
+	if err := write(path, src); err != nil {
+		return ``, err
+	}
+	err := create(st.index(r), []byte(hash+"\n"))
+	if errors.Is(err, os.ErrExist) {
+		had, err := st.Lookup(r)
+		if err != nil {
+			return ``, err
+		}
+		if had != hash {
+			return ``, fmt.Errorf(`%v: %w with %v`, r, ErrPublished, had)
+		}
+		return hash, nil
+	}
+	if err != nil {
+		return ``, err
+	}
+	return hash, nil
+}
+
+// write writes the file (creating any directories) by renaming
+// a temporary file into place so that it is never seen half written.
+func write(path string, data []byte) error {
+	tmp, err := temp(path, data)
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// create writes the file as write does but only if it does not already
+// exist (returning an error wrapping os.ErrExist if it does). The
+// temporary file is linked into place rather than renamed since only
+// linking fails when the file exists, so that of any number of callers
+// creating the same file at once exactly one succeeds.
+func create(path string, data []byte) error {
+	tmp, err := temp(path, data)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp)
+	return os.Link(tmp, path)
+}
+
+// temp writes the data to a new temporary file in the directory of
+// path (creating it if needed) returning its name.
+func temp(path string, data []byte) (string, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return ``, err
+	}
+	f, err := os.CreateTemp(filepath.Dir(path), `.tmp-*`)
+	if err != nil {
+		return ``, err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return ``, err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return ``, err
+	}
+	return f.Name(), nil
+}
+
+// Lookup returns the hash of the version.
+func (st Store) Lookup(r Ref) (string, error) {
+	if err := r.Valid(); err != nil {
+		return ``, err
+	}
+	byt, err := os.ReadFile(st.index(r))
+	if errors.Is(err, os.ErrNotExist) {
+		return ``, fmt.Errorf(`%v: %w`, r, ErrNotFound)
+	}
+	return strings.TrimSpace(string(byt)), err
+}
+
+// Get returns the grammar source of the version after verifying its
+// hash.
+func (st Store) Get(r Ref) ([]byte, error) {
+	hash, err := st.Lookup(r)
+	if err != nil {
+		return nil, err
+	}
+	return st.Blob(hash)
+}
+
+// Blob returns the grammar source with the hash after verifying it.
+func (st Store) Blob(hash string) ([]byte, error) {
+	path, err := st.blob(hash)
+	if err != nil {
+		return nil, err
+	}
+	src, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf(`%v: %w`, hash, ErrNotFound)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if got := Hash(src); got != hash {
+		return nil, fmt.Errorf(`%v: corrupt (hash is %v)`, hash, got)
+	}
+	return src, nil
+}
+
+// Versions returns every version of the named grammar in order (lowest
+// first).
+func (st Store) Versions(name string) ([]string, error) {
+	if err := (Ref{name, `v0.0.0`}).Valid(); err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(filepath.Join(st.Dir, `grammars`, filepath.FromSlash(name)))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf(`%v: %w`, name, ErrNotFound)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var list []string
+	for _, e := range entries {
+		if _, ok := semver(e.Name()); ok && !e.IsDir() {
+			list = append(list, e.Name())
+		}
+	}
+	if len(list) == 0 {
+		return nil, fmt.Errorf(`%v: %w`, name, ErrNotFound)
+	}
+	sort.Slice(list, func(i, j int) bool { return less(list[i], list[j]) })
+	return list, nil
+}
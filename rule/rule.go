@@ -0,0 +1,163 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+/*
+Package rule is a registry of the rules (model.Rule) of every grammar
+package linked into a program so that anything given nothing but a rule
+ID (such as the type of a pegn.Error or ast.Node) can resolve it into
+the name, PEGN, and descriptions of the rule. Grammar packages register
+their rules from init:
+
+	func init() {
+		for _, r := range rules {
+			if err := rule.Register(r); err != nil {
+				panic(err)
+			}
+		}
+	}
+
+Since IDs are only unique within a grammar, programs should link at
+most one grammar package registering rules (or grammars with IDs that
+do not overlap). Any ID other than 0 and those reserved by ast (see
+ast.Reserved) may be registered, including the negative IDs of pegng.
+Grammars loaded while running (such as those read by gr) use Replace
+instead so that loading another (or the same one edited) takes over the
+IDs and names of the one before.
+
+Once registered, every pegn.Error of a rule describes it by name and
+description (see Describe) rather than by ID alone.
+*/
+package rule
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/rwxrob/pegn/ast"
+	"github.com/rwxrob/pegn/lang"
+	"github.com/rwxrob/pegn/model"
+)
+
+// ShowExamples adds the first Valid example of a rule (if it has one)
+// to its description (see Describe).
+var ShowExamples bool
+
+var (
+	mu     sync.RWMutex
+	byid   = map[int]model.Rule{}
+	byname = map[string]int{} // lower case name to ID
+)
+
+// Register adds the rule to the registry. Registering the same rule
+// again (same ID, name, and PEGN) replaces it (with any new
+// descriptions). An error is returned (leaving the registry unchanged)
+// if the rule has no ID (or one reserved by ast) or name or if
+// a different rule has already been registered with the same ID or
+// name (case-insensitive).
+func Register(r model.Rule) error {
+	if err := valid(r); err != nil {
+		return err
+	}
+	key := strings.ToLower(r.Name)
+	mu.Lock()
+	defer mu.Unlock()
+	if d, has := byid[r.ID]; has && (d.Name != r.Name || d.PEGN != r.PEGN) {
+		return fmt.Errorf(`rule ID %v already registered for %v`, r.ID, d.Name)
+	}
+	if id, has := byname[key]; has && id != r.ID {
+		return fmt.Errorf(`rule %v already registered with ID %v`, r.Name, id)
+	}
+	byid[r.ID] = r
+	byname[key] = r.ID
+	return nil
+}
+
+// Replace adds the rule to the registry removing any other rule
+// registered with the same ID or name (case-insensitive). An error is
+// returned (leaving the registry unchanged) only if the rule has no ID
+// (or one reserved by ast) or name.
+func Replace(r model.Rule) error {
+	if err := valid(r); err != nil {
+		return err
+	}
+	key := strings.ToLower(r.Name)
+	mu.Lock()
+	defer mu.Unlock()
+	if d, has := byid[r.ID]; has {
+		delete(byname, strings.ToLower(d.Name))
+	}
+	if id, has := byname[key]; has {
+		delete(byid, id)
+	}
+	byid[r.ID] = r
+	byname[key] = r.ID
+	return nil
+}
+
+func valid(r model.Rule) error {
+	if r.ID == 0 || r.ID <= ast.Reserved || r.Name == "" {
+		return fmt.Errorf(`rule must have ID and name: %v %q`, r.ID, r.Name)
+	}
+	return nil
+}
+
+// ByID returns the rule registered with the ID.
+func ByID(id int) (model.Rule, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+	r, has := byid[id]
+	return r, has
+}
+
+// ByName returns the rule registered with the name (case-insensitive).
+func ByName(name string) (model.Rule, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+	id, has := byname[strings.ToLower(name)]
+	if !has {
+		return model.Rule{}, false
+	}
+	return byid[id], true
+}
+
+// All returns every rule registered in order of ID.
+func All() []model.Rule {
+	mu.RLock()
+	defer mu.RUnlock()
+	list := make([]model.Rule, 0, len(byid))
+	for _, r := range byid {
+		list = append(list, r)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].ID < list[j].ID })
+	return list
+}
+
+// Describe returns the name of the rule registered with the ID followed
+// by its description in lang.Lang (if it has one) in parenthesis:
+//
+//	Field (one or more printable runes except space)
+//
+// With ShowExamples the first Valid example follows the description:
+//
+//	Version (semantic version; e.g. "v1.2.3")
+//
+// False is returned if no rule has been registered with the ID.
+func Describe(id int) (string, bool) {
+	r, has := ByID(id)
+	if !has {
+		return "", false
+	}
+	var in []string
+	if d := r.Desc.Get(lang.Lang); d != "" {
+		in = append(in, d)
+	}
+	if ShowExamples && len(r.Valid) > 0 {
+		in = append(in, lang.Sprintf(`example`, r.Valid[0]))
+	}
+	if len(in) == 0 {
+		return r.Name, true
+	}
+	return r.Name + ` (` + strings.Join(in, `; `) + `)`, true
+}
@@ -0,0 +1,110 @@
+package rule_test
+
+import (
+	"fmt"
+
+	"github.com/rwxrob/pegn/ast"
+	"github.com/rwxrob/pegn/lang"
+	"github.com/rwxrob/pegn/model"
+	"github.com/rwxrob/pegn/rule"
+)
+
+func ExampleRegister() {
+
+	rules := []model.Rule{
+		{ID: 2, Name: `Key`, PEGN: `Key <-- lower+`,
+			Desc: model.LangMap{`en`: `lower case letters`}},
+		{ID: 1, Name: `Pair`, PEGN: `Pair <-- Key '=' Val`},
+		{ID: 3, Name: `Val`, PEGN: `Val <-- digit+`},
+	}
+	for _, r := range rules {
+		fmt.Println(rule.Register(r))
+	}
+
+	fmt.Println(rule.Register(model.Rule{ID: 2, Name: `Other`}))
+	fmt.Println(rule.Register(model.Rule{ID: 4, Name: `key`}))
+	fmt.Println(rule.Register(rules[0]))
+
+	r, has := rule.ByID(2)
+	fmt.Println(r.Name, r.Desc[`en`], has)
+	r, has = rule.ByName(`val`)
+	fmt.Println(r.PEGN, has)
+	_, has = rule.ByID(9)
+	fmt.Println(has)
+
+	for _, r := range rule.All() {
+		fmt.Println(r.ID, r.Name)
+	}
+
+	// Output:
+	// <nil>
+	// <nil>
+	// <nil>
+	// rule ID 2 already registered for Key
+	// rule key already registered with ID 2
+	// <nil>
+	// Key lower case letters true
+	// Val <-- digit+ true
+	// false
+	// 1 Pair
+	// 2 Key
+	// 3 Val
+}
+
+func ExampleDescribe() {
+	lang.Lang = `en`
+
+	rule.Register(model.Rule{ID: 10, Name: `Version`,
+		Desc:  model.LangMap{`en`: `semantic version`},
+		Valid: []string{`v1.2.3`, `1.2.3`}})
+	rule.Register(model.Rule{ID: 11, Name: `Tag`, Valid: []string{`latest`}})
+
+	fmt.Println(rule.Describe(10))
+	rule.ShowExamples = true
+	defer func() { rule.ShowExamples = false }()
+	fmt.Println(rule.Describe(10))
+	fmt.Println(rule.Describe(11))
+	fmt.Println(rule.Describe(12))
+
+	// Output:
+	// Version (semantic version) true
+	// Version (semantic version; e.g. "v1.2.3") true
+	// Tag (e.g. "latest") true
+	//  false
+}
+
+func ExampleRegister_ranges() {
+
+	fmt.Println(rule.Register(model.Rule{ID: -20, Name: `NegRule`}))
+	fmt.Println(rule.Register(model.Rule{ID: 0, Name: `Zero`}))
+	fmt.Println(rule.Register(model.Rule{ID: ast.ErrorNode, Name: `Err`}))
+	fmt.Println(rule.Register(model.Rule{ID: ast.Reserved + 1, Name: `Low`}))
+
+	// Output:
+	// <nil>
+	// rule must have ID and name: 0 "Zero"
+	// rule must have ID and name: -2147483648 "Err"
+	// <nil>
+}
+
+func ExampleReplace() {
+
+	rule.Register(model.Rule{ID: 30, Name: `Word`, PEGN: `Word <-- alpha+`})
+	rule.Register(model.Rule{ID: 31, Name: `Num`, PEGN: `Num <-- digit+`})
+
+	fmt.Println(rule.Replace(model.Rule{ID: 30, Name: `Num`, PEGN: `Num <-- [0-9]+`}))
+	_, has := rule.ByName(`Word`)
+	fmt.Println(has)
+	_, has = rule.ByID(31)
+	fmt.Println(has)
+	r, _ := rule.ByName(`num`)
+	fmt.Println(r.ID, r.PEGN)
+	fmt.Println(rule.Replace(model.Rule{ID: 0, Name: `Num`}))
+
+	// Output:
+	// <nil>
+	// false
+	// false
+	// 30 Num <-- [0-9]+
+	// rule must have ID and name: 0 "Num"
+}
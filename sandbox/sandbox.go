@@ -0,0 +1,267 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+/*
+Package sandbox runs grammars supplied by users (playgrounds,
+multi-tenant validators, and other services receiving PEGN with every
+request) within limits so that no single request can use more than its
+share of memory or time. A Service bundles the limits with a cache of
+compiled grammars (see gr.Cache) and a pool of scanners reused from one
+request to the next.
+
+Limits are enforced by counting every rune scanned (or peeked) as
+a step. Once the steps or time run out every scan fails so that even
+grammars that backtrack exponentially finish quickly with an error that
+wraps ErrLimit (or the error of the context). Rules nested too deeply
+(A <- '(' A ')' / 'x' with a million parentheses) fail the same way
+before they can exhaust the Go stack, which would crash the process. Grammars are compiled
+(see gr.Compile) so that rules referring to undefined rules are never
+run. Left recursive rules are run as the interpreter does (see
+gr.Grammar.ScanRule) within the same limits.
+*/
+package sandbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rwxrob/pegn"
+	"github.com/rwxrob/pegn/ast"
+	"github.com/rwxrob/pegn/gr"
+	"github.com/rwxrob/pegn/scanner"
+)
+
+// ErrLimit is wrapped by every error returned because a limit was
+// reached.
+var ErrLimit = errors.New(`limit exceeded`)
+
+// Limits bound the resources of every request. Zero means no limit.
+type Limits struct {
+	Grammar  int           // most bytes of PEGN source
+	Rules    int           // most rules in a grammar
+	Grammars int           // most compiled grammars cached
+	Input    int           // most bytes of input
+	Steps    int           // most runes scanned or peeked
+	Depth    int           // most rules nested within one another
+	Time     time.Duration // most time for a single scan or parse
+	Errors   int           // most errors kept (see scanner.S.SetErrCap)
+}
+
+// DefaultLimits are generous enough for any reasonable grammar and
+// document while keeping every request under a second and a few
+// megabytes.
+var DefaultLimits = Limits{
+	Grammar:  64 << 10,
+	Rules:    1000,
+	Grammars: 1000,
+	Input:    1 << 20,
+	Steps:    10_000_000,
+	Depth:    5000,
+	Time:     time.Second,
+	Errors:   100,
+}
+
+// Service compiles and runs grammars within its Limits. It is safe
+// for concurrent use and must not be copied. Create with New.
+type Service struct {
+	Limits Limits
+	cache  gr.Cache
+	pool   sync.Pool
+}
+
+// New returns a Service with the limits.
+func New(l Limits) *Service {
+	sv := &Service{Limits: l}
+	sv.cache.Max = l.Grammars
+	sv.pool.New = func() any { return &limited{S: new(scanner.S)} }
+	return sv
+}
+
+// Compile returns the grammar compiled from the source (from the cache
+// if compiled before) or an error if it cannot be compiled or is
+// larger than the limits allow.
+func (sv *Service) Compile(src string) (*gr.Grammar, error) {
+	if max := sv.Limits.Grammar; max > 0 && len(src) > max {
+		return nil, fmt.Errorf(`%w: grammar larger than %v bytes`, ErrLimit, max)
+	}
+	g, err := sv.cache.Compile(src)
+	if err != nil {
+		return nil, err
+	}
+	if max := sv.Limits.Rules; max > 0 && len(g.Rules) > max {
+		return nil, fmt.Errorf(`%w: grammar has more than %v rules`, ErrLimit, max)
+	}
+	return g, nil
+}
+
+// Scan matches the entire input against the named rule (or the first
+// if empty) of the grammar compiled from the source and returns nil
+// only if it matches completely.
+func (sv *Service) Scan(ctx context.Context, src, rule string, input io.Reader) error {
+	return sv.run(ctx, src, rule, input, func(g *gr.Grammar, rule string, s *limited) error {
+		if !g.ScanRule(rule, s, nil) {
+			return s.failed()
+		}
+		if !s.Finished() {
+			s.S.Scan()
+			return fmt.Errorf(`%v: unexpected %q after %v`, s.Pos().Short(), s.Rune(), rule)
+		}
+		return nil
+	})
+}
+
+// Parse parses the input with the named rule (or the first if empty)
+// of the grammar compiled from the source (see gr.Grammar.ParseRule).
+// Unlike Scan, the input need not match completely.
+func (sv *Service) Parse(ctx context.Context, src, rule string, input io.Reader) (*ast.Node, error) {
+	var n *ast.Node
+	err := sv.run(ctx, src, rule, input, func(g *gr.Grammar, rule string, s *limited) error {
+		if n = g.ParseRule(rule, s); n == nil {
+			return s.failed()
+		}
+		return nil
+	})
+	return n, err
+}
+
+// Trace parses like Parse calling fn with every step of the scan (see
+// scanner.TraceEvent) as it happens so that playgrounds can show how
+// the input was matched. Steps count toward the limits the same.
+func (sv *Service) Trace(ctx context.Context, src, rule string, input io.Reader, fn func(e scanner.TraceEvent)) (*ast.Node, error) {
+	var n *ast.Node
+	err := sv.run(ctx, src, rule, input, func(g *gr.Grammar, rule string, s *limited) error {
+		s.S.TraceFunc = fn
+		s.S.TraceOn()
+		defer func() { s.S.TraceFunc = nil; s.S.TraceOff() }()
+		if n = g.ParseRule(rule, s); n == nil {
+			return s.failed()
+		}
+		return nil
+	})
+	return n, err
+}
+
+// run compiles the grammar, reads the input into a pooled scanner, and
+// calls fn with both (with the name of the first rule if none given).
+func (sv *Service) run(ctx context.Context, src, rule string, input io.Reader,
+	fn func(g *gr.Grammar, rule string, s *limited) error) error {
+
+	g, err := sv.Compile(src)
+	if err != nil {
+		return err
+	}
+	if rule == `` && len(g.Rules) > 0 {
+		rule = g.Rules[0].Name
+	}
+	if g.Lookup(rule) == nil {
+		return fmt.Errorf(`unknown rule: %q`, rule)
+	}
+
+	if sv.Limits.Time > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, sv.Limits.Time)
+		defer cancel()
+	}
+	s := sv.pool.Get().(*limited)
+	defer sv.pool.Put(s)
+	if err := s.reset(ctx, sv.Limits, input); err != nil {
+		return err
+	}
+	return fn(g, rule, s)
+}
+
+// limited is a scanner that fails every Scan and Peek once out of
+// steps or time. The buffer is kept for the next request.
+type limited struct {
+	*scanner.S
+	data  []byte
+	ctx   context.Context
+	steps int
+	max   int
+	depth int   // most rules nested
+	err   error // why stopped (nil if not)
+}
+
+// checkEvery is the number of steps between checks of the context.
+const checkEvery = 1024
+
+func (l *limited) step() bool {
+	if l.err != nil {
+		return false
+	}
+	l.steps++
+	if l.max > 0 && l.steps > l.max {
+		l.err = fmt.Errorf(`%w: more than %v steps`, ErrLimit, l.max)
+		return false
+	}
+	if l.steps%checkEvery == 0 {
+		if err := l.ctx.Err(); err != nil {
+			l.err = err
+			return false
+		}
+	}
+	return true
+}
+
+// CheckDepth fulfills the interface the gr interpreter uses to limit
+// how deeply rules are nested.
+func (l *limited) CheckDepth(depth int) error {
+	if l.err == nil && l.depth > 0 && depth > l.depth {
+		l.err = fmt.Errorf(`%w: rules nested more than %v deep`, ErrLimit, l.depth)
+	}
+	return l.err
+}
+
+func (l *limited) Scan() bool         { return l.step() && l.S.Scan() }
+func (l *limited) Peek(a string) bool { return l.step() && l.S.Peek(a) }
+
+// reset prepares the scanner for a new request reading all of the
+// input into the buffer kept from the last.
+func (l *limited) reset(ctx context.Context, lim Limits, input io.Reader) error {
+	l.ctx, l.steps, l.max, l.depth, l.err = ctx, 0, lim.Steps, lim.Depth, nil
+	if lim.Input > 0 {
+		input = io.LimitReader(input, int64(lim.Input)+1)
+	}
+	l.data = l.data[:0]
+	for {
+		if len(l.data) == cap(l.data) {
+			l.data = append(l.data, 0)[:len(l.data)]
+		}
+		n, err := input.Read(l.data[len(l.data):cap(l.data)])
+		l.data = l.data[:len(l.data)+n]
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if lim.Input > 0 && len(l.data) > lim.Input {
+		return fmt.Errorf(`%w: input larger than %v bytes`, ErrLimit, lim.Input)
+	}
+	*l.S.Errors() = (*l.S.Errors())[:0]
+	l.S.SetErrCap(lim.Errors/2, lim.Errors-lim.Errors/2)
+	return l.S.Buffer(l.data)
+}
+
+// failed returns the reason the scan stopped or the last error pushed
+// with its position in the input.
+func (l *limited) failed() error {
+	if l.err != nil {
+		return l.err
+	}
+	errs := *l.S.Errors()
+	if len(errs) == 0 {
+		return errors.New(`failed to match`)
+	}
+	err := errs[len(errs)-1]
+	if !errors.As(err, new(pegn.Error)) {
+		return err
+	}
+	return errors.New(strings.TrimSpace(l.S.FormatErr(err)))
+}
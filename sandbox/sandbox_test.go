@@ -0,0 +1,71 @@
+package sandbox_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rwxrob/pegn/sandbox"
+)
+
+func ExampleService() {
+
+	sv := sandbox.New(sandbox.DefaultLimits)
+	ctx := context.Background()
+
+	sum := `
+Sum <-- Num ('+' Num)*
+Num <-- digit+`
+
+	n, err := sv.Parse(ctx, sum, ``, strings.NewReader(`1+22`))
+	fmt.Println(n, err)
+	fmt.Println(sv.Scan(ctx, sum, ``, strings.NewReader(`1+22`)))
+	fmt.Println(sv.Scan(ctx, sum, ``, strings.NewReader(`1+22-3`)))
+	fmt.Println(sv.Scan(ctx, sum, `Nope`, strings.NewReader(`1`)))
+
+	// backtracks exponentially without ever matching
+	slow := `
+Slow <- A !.
+A    <- 'a' A 'b' / 'a' A 'c' / 'a' A 'd' / 'a'`
+
+	l := sandbox.DefaultLimits
+	l.Steps = 100_000
+	sv = sandbox.New(l)
+	in := strings.Repeat(`a`, 40) + `x`
+	err = sv.Scan(ctx, slow, ``, strings.NewReader(in))
+	fmt.Println(err, errors.Is(err, sandbox.ErrLimit))
+
+	l.Input = 10
+	sv = sandbox.New(l)
+	fmt.Println(sv.Scan(ctx, sum, ``, strings.NewReader(`1+2+3+4+5+6`)))
+
+	// Output:
+	// {"T":1,"N":[{"T":2,"V":"1"},{"T":2,"V":"22"}]} <nil>
+	// <nil>
+	// 1:5: unexpected '-' after Sum
+	// unknown rule: "Nope"
+	// limit exceeded: more than 100000 steps true
+	// limit exceeded: input larger than 10 bytes
+}
+
+func ExampleLimits_depth() {
+
+	l := sandbox.DefaultLimits
+	l.Time = 60 * time.Second
+	sv := sandbox.New(l)
+	ctx := context.Background()
+
+	nest := `A <- '(' A ')' / 'x'`
+
+	fmt.Println(sv.Scan(ctx, nest, ``, strings.NewReader(`((x))`)))
+
+	in := strings.Repeat(`(`, 1_000_000)
+	err := sv.Scan(ctx, nest, ``, strings.NewReader(in))
+	fmt.Println(err, errors.Is(err, sandbox.ErrLimit))
+
+	// Output:
+	// <nil>
+	// limit exceeded: rules nested more than 5000 deep true
+}
@@ -0,0 +1,50 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+package scanner
+
+import "github.com/rwxrob/pegn/curs"
+
+// SetBookmark marks the current position (see Mark) with the name so
+// that rules scanning in several stages can return to (GotoBookmark) or
+// copy from (CopySince) landmarks such as the start of the line or
+// statement without passing cursors through every function in between.
+// Setting the same name again moves the bookmark. Bookmarks are removed
+// by Buffer (and Open).
+func (s *S) SetBookmark(name string) {
+	if s.bookmarks == nil {
+		s.bookmarks = map[string]curs.R{}
+	}
+	s.bookmarks[name] = s.Mark()
+}
+
+// Bookmark returns the cursor of the named bookmark (see SetBookmark)
+// and false if there is none.
+func (s *S) Bookmark(name string) (curs.R, bool) {
+	c, has := s.bookmarks[name]
+	return c, has
+}
+
+// GotoBookmark moves to the named bookmark (see SetBookmark) returning
+// false (without moving) if there is none. Like Goto, it panics if
+// streaming and the bookmark is no longer within the window.
+func (s *S) GotoBookmark(name string) bool {
+	c, has := s.bookmarks[name]
+	if has {
+		s.Goto(c)
+	}
+	return has
+}
+
+// CopySince returns everything scanned since the named bookmark (see
+// SetBookmark and CopyEE) or an empty string if there is none.
+func (s *S) CopySince(name string) string {
+	c, has := s.bookmarks[name]
+	if !has {
+		return ""
+	}
+	return s.CopyEE(c)
+}
+
+// DeleteBookmark removes the named bookmark (if any).
+func (s *S) DeleteBookmark(name string) { delete(s.bookmarks, name) }
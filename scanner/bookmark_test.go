@@ -0,0 +1,36 @@
+package scanner_test
+
+import (
+	"fmt"
+
+	"github.com/rwxrob/pegn/scanner"
+)
+
+func ExampleS_SetBookmark() {
+
+	s := scanner.New("let x = 1;\nlet y = 2;")
+	for !s.Finished() {
+		s.SetBookmark(`stmt`)
+		for s.Scan() && s.Rune() != ';' {
+			if s.Rune() == '=' {
+				s.SetBookmark(`value`)
+			}
+		}
+		fmt.Printf("%q %q\n", s.CopySince(`stmt`), s.CopySince(`value`))
+		s.Scan() // newline
+	}
+
+	fmt.Println(s.GotoBookmark(`stmt`), s.CopySince(`stmt`) == "")
+	s.DeleteBookmark(`stmt`)
+	fmt.Println(s.GotoBookmark(`stmt`))
+	s.Buffer(`new`)
+	_, has := s.Bookmark(`value`)
+	fmt.Println(has)
+
+	// Output:
+	// "let x = 1;" " 1;"
+	// "let y = 2;" " 2;"
+	// true true
+	// false
+	// false
+}
@@ -0,0 +1,51 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+package scanner
+
+import (
+	"unicode/utf8"
+
+	"github.com/rwxrob/pegn/curs"
+)
+
+// Capture sets the buffer that runes are being captured into (the buf
+// of the ScanFunc being called) so that Replace and Inject can change
+// it and returns the buffer set before (so that it can be set back).
+// pegn.ParserFor (and ParserSized) set it for the duration of every
+// parse. Passing nil stops any changes.
+func (s *S) Capture(buf *[]rune) *[]rune {
+	old := s.capture
+	s.capture = buf
+	return old
+}
+
+// Replace replaces everything captured since the cursor (see Mark) with
+// the string (the => operator of PEGN). Since only what was scanned is
+// known, every rune scanned since must have been captured one for one.
+func (s *S) Replace(from curs.R, with string) {
+	if s.capture == nil {
+		return
+	}
+	out := *s.capture
+	b := from.E - s.off
+	if b < 0 {
+		b = 0
+	}
+	n := 0
+	if b < s.E {
+		n = utf8.RuneCount(s.Buf[b:s.E])
+	}
+	if n > len(out) {
+		n = len(out)
+	}
+	*s.capture = append(out[:len(out)-n], []rune(with)...)
+}
+
+// Inject adds the string to what has been captured without scanning
+// anything (the ^ operator of PEGN).
+func (s *S) Inject(a string) {
+	if s.capture != nil {
+		*s.capture = append(*s.capture, []rune(a)...)
+	}
+}
@@ -0,0 +1,31 @@
+package scanner_test
+
+import (
+	"fmt"
+
+	"github.com/rwxrob/pegn"
+	"github.com/rwxrob/pegn/scanner"
+)
+
+func ExampleS_Replace() {
+	const Path = 1
+
+	// Path <-- ^'/' ('\' => '/' / unipoint)*
+	scan := func(s pegn.Scanner, buf *[]rune) bool {
+		ss := s.(*scanner.S)
+		ss.Inject(`/`)
+		for m := s.Mark(); s.Scan(); m = s.Mark() {
+			*buf = append(*buf, s.Rune())
+			if s.Rune() == '\\' {
+				ss.Replace(m, `/`)
+			}
+		}
+		return true
+	}
+	parse := pegn.ParserFor(Path, scan, 16)
+
+	fmt.Println(parse(scanner.New(`usr\local\bin`)))
+
+	// Output:
+	// {"T":1,"V":"/usr/local/bin"}
+}
@@ -0,0 +1,55 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+package scanner
+
+import "github.com/rwxrob/pegn"
+
+// Checkpoint returns the current cursor, depth of the error stack, and
+// number of errors dropped (see SetErrCap) fulfilling
+// pegn.ScannerCheckpoint.
+func (s *S) Checkpoint() pegn.Checkpoint {
+	return pegn.Checkpoint{C: s.Mark(), Errs: len(s.errors), Dropped: s.dropped}
+}
+
+// Restore moves to the cursor of the checkpoint (see Goto) and drops
+// every error pushed since it was taken fulfilling
+// pegn.ScannerCheckpoint. Memoized results (see EnableMemo) and the
+// farthest failure (see EnableFarthest) are kept since both are still
+// true after backtracking. If the error stack is bounded (see
+// SetErrCap) only the errors pushed since that have not already been
+// dropped are removed. Errors from before that were dropped to make
+// room for them cannot be brought back and are still counted as
+// dropped.
+func (s *S) Restore(c pegn.Checkpoint) {
+	s.Goto(c.C)
+	n := len(s.errors) + s.dropped - c.Errs - c.Dropped // pushed since
+	if n <= 0 {
+		return
+	}
+	if s.dropped > 0 {
+		n = s.kept(c.Errs+c.Dropped, n)
+	}
+	if n > len(s.errors) {
+		n = len(s.errors)
+	}
+	s.errors = s.errors[:len(s.errors)-n]
+	if s.dropped > 0 {
+		s.dropped = c.Errs + c.Dropped - len(s.errors)
+	}
+}
+
+// kept returns how many of the last n errors pushed (with before
+// pushed ahead of them) are still on the bounded error stack (see
+// SetErrCap): those among the first errors kept plus those among the
+// last.
+func (s *S) kept(before, n int) int {
+	var k int
+	if before < s.errfirst {
+		k = s.errfirst - before
+	}
+	if n < s.errlast {
+		return k + n
+	}
+	return k + s.errlast
+}
@@ -0,0 +1,50 @@
+package scanner_test
+
+import (
+	"fmt"
+
+	"github.com/rwxrob/pegn/scanner"
+)
+
+func ExampleS_Checkpoint() {
+
+	s := scanner.New(`int x`)
+	s.Expected(1) // from before
+
+	cp := s.Checkpoint()
+	if !s.Peek(`integer`) {
+		s.Scan()
+		s.Expected(2)
+		s.Expected(3)
+	}
+	fmt.Println(len(*s.Errors()), s.Mark())
+
+	s.Restore(cp)
+	fmt.Println(len(*s.Errors()), s.Mark())
+
+	// Output:
+	// 3 'i' 0-1
+	// 1 '\x00' 0-0
+}
+
+func ExampleS_Restore_dropped() {
+
+	s := scanner.New(`int x`)
+	s.SetErrCap(2, 2)
+	s.Expected(1)
+	s.Expected(2)
+	s.Expected(3) // from before
+
+	cp := s.Checkpoint()
+	s.Expected(4)
+	s.Expected(5)
+	s.Expected(6) // dropping 3 and 4 to make room
+	fmt.Println(*s.Errors(), s.Dropped())
+
+	s.Restore(cp)
+	fmt.Println(*s.Errors(), s.Dropped())
+
+	// Output:
+	// [expecting type 1 at '\x00' 0-0 expecting type 2 at '\x00' 0-0 expecting type 5 at '\x00' 0-0 expecting type 6 at '\x00' 0-0] 2
+	// [expecting type 1 at '\x00' 0-0 expecting type 2 at '\x00' 0-0] 1
+}
@@ -0,0 +1,74 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+package scanner
+
+import "fmt"
+
+// SetErrCap bounds the error stack (see ErrPush) so that it keeps only
+// the first errors pushed and the last errors pushed, counting the rest
+// in between as dropped (see Dropped). This keeps memory from growing
+// without end during lenient scans of huge, noisy input where most
+// errors are never looked at anyway. The first errors usually explain
+// the problem and the last are where the scan gave up. Setting both to
+// zero (the default) removes the bound. The count of errors dropped
+// starts again from zero.
+//
+// The stack is always kept in the order pushed so that Errors can be
+// used as usual. Once full, the oldest of the last errors is dropped
+// with each push, which is cheap for the small limits this is meant
+// for. Note that truncating the stack (a common way to discard the
+// errors of an alternative that was tried) cannot bring back errors
+// that have been dropped.
+func (s *S) SetErrCap(first, last int) {
+	if first < 0 {
+		first = 0
+	}
+	if last < 0 {
+		last = 0
+	}
+	s.errfirst, s.errlast = first, last
+	s.dropped = 0
+	s.trimErrors()
+}
+
+// ErrCap returns the limits set by SetErrCap.
+func (s *S) ErrCap() (first, last int) { return s.errfirst, s.errlast }
+
+// Dropped returns the number of errors dropped from the error stack
+// since it was bounded (see SetErrCap).
+func (s *S) Dropped() int { return s.dropped }
+
+// ErrPush pushes the error onto the error stack dropping an earlier one
+// if the stack is bounded and full (see SetErrCap). The farthest failure
+// is also updated if tracked (see EnableFarthest).
+func (s *S) ErrPush(e error) {
+	if s.far != nil {
+		s.farthest(e)
+	}
+	s.errors = append(s.errors, e)
+	s.trimErrors()
+	if s.tracing() {
+		s.traceErr(e)
+	}
+}
+
+func (s *S) trimErrors() {
+	max := s.errfirst + s.errlast
+	if max == 0 || len(s.errors) <= max {
+		return
+	}
+	n := len(s.errors) - max
+	copy(s.errors[s.errfirst:], s.errors[s.errfirst+n:])
+	for i := max; i < len(s.errors); i++ {
+		s.errors[i] = nil
+	}
+	s.errors = s.errors[:max]
+	s.dropped += n
+}
+
+// droppedMsg is added by Error between the first and last errors kept
+// when any have been dropped (see SetErrCap).
+func (s *S) droppedMsg() string {
+	return fmt.Sprintf("... %v errors dropped ...\n", s.dropped)
+}
@@ -0,0 +1,29 @@
+package scanner_test
+
+import (
+	"fmt"
+
+	"github.com/rwxrob/pegn/scanner"
+)
+
+func ExampleS_SetErrCap() {
+
+	s := scanner.New(`some noisy input`)
+	s.SetErrFmtFunc(scanner.DefaultErrFmtFunc)
+	s.SetErrCap(2, 3)
+
+	for i := 1; i <= 100; i++ {
+		s.ErrPush(fmt.Errorf(`error %v`, i))
+	}
+	fmt.Println(len(*s.Errors()), s.Dropped())
+	fmt.Print(s.Error())
+
+	// Output:
+	// 5 95
+	// error 1
+	// error 2
+	// ... 95 errors dropped ...
+	// error 98
+	// error 99
+	// error 100
+}
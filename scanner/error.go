@@ -1,6 +1,10 @@
 package scanner
 
-import "fmt"
+import (
+	"errors"
+
+	"github.com/rwxrob/pegn"
+)
 
 var DefaultErrorMessage = `failed to scan`
 
@@ -8,10 +12,49 @@ type Error struct {
 	P   int      // can be left blank if Pos is defined
 	Pos Position // can be left blank, Report will populate
 	Msg string
+	Sev pegn.Severity // error (default), warning, or info
 }
 
+// Severity returns Sev (see pegn.SeverityOf).
+func (e Error) Severity() pegn.Severity { return e.Sev }
+
+// Error fulfills the error interface with the short form of the
+// position (see Position.Short) followed by the message (or the message
+// alone if the position is not known).
 func (e Error) Error() string {
-	return fmt.Sprintf("%v at %v", e.Msg, e.Pos)
+	if e.Pos.Line == 0 {
+		return e.Msg
+	}
+	return e.Pos.Short() + ": " + e.Msg
+}
+
+// ErrReport returns every error on the stack (see Errors) as an Error
+// with the message and severity (see pegn.SeverityOf) of the error and,
+// for every pegn.Error (or error
+// wrapping one), the Position of its cursor. Positions are only resolved
+// now (rather than when pushed) and with a single pass through the
+// buffer for all of them (see Positions).
+func (s S) ErrReport() []Error {
+	list := make([]Error, len(s.errors))
+	var offs []int
+	var which []int
+	for i, err := range s.errors {
+		list[i].Msg = err.Error()
+		list[i].Sev = pegn.SeverityOf(err)
+		var pe pegn.Error
+		if errors.As(err, &pe) {
+			list[i].P = pe.C.E
+			offs = append(offs, pe.C.E)
+			which = append(which, i)
+		}
+	}
+	for i, p := range s.Positions(offs...) {
+		if p.Line == 0 { // nothing scanned yet
+			p = Position{Name: s.Name, Line: 1 + s.lines, LByte: 1, LRune: 1}
+		}
+		list[which[i]].Pos = p
+	}
+	return list
 }
 
 /*
@@ -0,0 +1,84 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+package scanner
+
+import (
+	"errors"
+
+	"github.com/rwxrob/pegn"
+	"github.com/rwxrob/pegn/curs"
+	"github.com/rwxrob/pegn/lang"
+	"github.com/rwxrob/pegn/rule"
+)
+
+// FarthestErr is the farthest failure of a scan (see EnableFarthest)
+// with every rule ID expected there (in order first pushed).
+type FarthestErr struct {
+	C curs.R // cursor of the errors pushed farthest into the buffer
+	T []int  // rule IDs expected at C (never empty)
+}
+
+// Error fulfills the error interface in the same form as pegn.Error
+// with every rule expected (described by name if registered, see
+// rule.Describe) in the language of the user (see lang.Lang).
+func (e FarthestErr) Error() string {
+	names := make([]string, len(e.T))
+	for i, t := range e.T {
+		if d, has := rule.Describe(t); has {
+			names[i] = d
+			continue
+		}
+		names[i] = lang.Sprintf(`type`, t)
+	}
+	return lang.Sprintf(`expecting`, lang.Or(names...), e.C)
+}
+
+// Unwrap returns the pegn.Error of the first rule expected so that it
+// can be located the same way (see FormatErr).
+func (e FarthestErr) Unwrap() error { return pegn.Error{T: e.T[0], C: e.C} }
+
+// EnableFarthest turns on tracking of the farthest failure across every
+// error pushed (see ErrPush) so that it can be reported (see
+// FarthestError) even after the errors of the alternatives that failed
+// have been dropped from the error stack. PEG parsers backtrack so much
+// that the last error pushed is rarely where the input is actually
+// wrong but the farthest position any rule reached almost always is.
+// Only errors (see pegn.SeverityOf) that are (or wrap) a pegn.Error are
+// tracked. Tracking starts again by Buffer (and Open) or by enabling
+// again.
+func (s *S) EnableFarthest() { s.far = new(FarthestErr) }
+
+// DisableFarthest turns off tracking of the farthest failure.
+func (s *S) DisableFarthest() { s.far = nil }
+
+// FarthestError returns the farthest failure since tracking was enabled
+// (see EnableFarthest) or nil if not tracking or nothing has failed.
+func (s *S) FarthestError() error {
+	if s.far == nil || len(s.far.T) == 0 {
+		return nil
+	}
+	e := *s.far
+	e.T = append([]int(nil), e.T...)
+	return e
+}
+
+// farthest records the error if it is the farthest yet.
+func (s *S) farthest(err error) {
+	var pe pegn.Error
+	if !errors.As(err, &pe) || pegn.SeverityOf(err) != pegn.SevError {
+		return
+	}
+	f := s.far
+	switch {
+	case len(f.T) == 0 || pe.C.E > f.C.E:
+		f.C, f.T = pe.C, append(f.T[:0], pe.T)
+	case pe.C.E == f.C.E:
+		for _, t := range f.T {
+			if t == pe.T {
+				return
+			}
+		}
+		f.T = append(f.T, pe.T)
+	}
+}
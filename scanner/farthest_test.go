@@ -0,0 +1,54 @@
+package scanner_test
+
+import (
+	"fmt"
+
+	"github.com/rwxrob/pegn"
+	"github.com/rwxrob/pegn/lang"
+	"github.com/rwxrob/pegn/scanner"
+)
+
+func ExampleS_FarthestError() {
+
+	lang.Lang = `en`
+
+	s := scanner.New(`let x = 1;`)
+	s.EnableFarthest()
+	fmt.Println(s.FarthestError())
+
+	// pretend three alternatives failed, two at the farthest position
+	m := s.Mark()
+	s.Scan()
+	s.Scan()
+	s.Expected(3)
+	s.Goto(m)
+	s.Scan()
+	s.Scan()
+	s.Expected(5)
+	s.Expected(3)
+	s.Goto(m)
+	s.Expected(7)
+
+	// and dropped all the errors of the alternatives
+	*s.Errors() = (*s.Errors())[:0]
+	s.Expected(1)
+
+	err := s.FarthestError()
+	fmt.Println(err)
+	fmt.Print(s.FormatErr(err))
+	fmt.Println(err.(scanner.FarthestErr).T)
+
+	s.Buffer(`other`)
+	fmt.Println(s.FarthestError())
+	s.DisableFarthest()
+	s.ErrPush(pegn.Error{T: 2})
+	fmt.Println(s.FarthestError())
+
+	// Output:
+	// <nil>
+	// expecting type 3 or type 5 at 'e' 1-2
+	// 1:3: expecting type 3 or type 5 at 'e' 1-2
+	// [3 5]
+	// <nil>
+	// <nil>
+}
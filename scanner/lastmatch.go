@@ -0,0 +1,34 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+package scanner
+
+// LastMatch returns the beginning and end (byte offsets like RuneE) of
+// the last successful match of anything that records it: ScanWhile,
+// ScanWhileASCII, Memo, pegn.KeywordSet, every rule matched by the
+// grammar interpreter (see gr.Grammar.ScanRule), and any ScanFunc
+// calling SetLastMatch. This allows ParseFuncs to take the text matched
+// (see CopyLastMatch) by the ScanFunc they call without marking the
+// position before it. Both are 0 if nothing has matched since Buffer
+// (or Open).
+//
+//	if !Scan_Version(s, nil) {
+//		return nil
+//	}
+//	return &ast.Node{T: Version, V: s.CopyLastMatch()}
+func (s *S) LastMatch() (b, e int) { return s.last[0], s.last[1] }
+
+// SetLastMatch sets the span returned by LastMatch. ScanFuncs call it
+// on success with the RuneE before and after matching.
+func (s *S) SetLastMatch(b, e int) { s.last = [2]int{b, e} }
+
+// CopyLastMatch returns the text of the last match (see LastMatch) or
+// an empty string if it is no longer within the buffer (see
+// NewStreaming).
+func (s *S) CopyLastMatch() string {
+	b, e := s.last[0]-s.off, s.last[1]-s.off
+	if b < 0 || e > len(s.Buf) || b > e {
+		return ""
+	}
+	return string(s.Buf[b:e])
+}
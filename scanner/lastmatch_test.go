@@ -0,0 +1,30 @@
+package scanner_test
+
+import (
+	"fmt"
+	"unicode"
+
+	"github.com/rwxrob/pegn"
+	"github.com/rwxrob/pegn/scanner"
+)
+
+func ExampleS_LastMatch() {
+
+	s := scanner.New(`  hello int x`)
+	fmt.Println(s.LastMatch())
+
+	s.ScanWhile(unicode.IsSpace)
+	s.ScanWhile(unicode.IsLetter)
+	fmt.Println(s.LastMatch())
+	fmt.Printf("%q\n", s.CopyLastMatch())
+
+	s.ScanWhile(unicode.IsSpace)
+	pegn.KeywordSet(`int`, `string`)(s, nil)
+	fmt.Printf("%q\n", s.CopyLastMatch())
+
+	// Output:
+	// 0 0
+	// 2 7
+	// "hello"
+	// "int"
+}
@@ -0,0 +1,130 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+package scanner
+
+import (
+	"unsafe"
+
+	"github.com/rwxrob/pegn"
+	"github.com/rwxrob/pegn/curs"
+)
+
+// memokey identifies the result of a rule at a position.
+type memokey struct {
+	id  int // rule ID
+	pos int // byte offset (RuneE) scanned from
+}
+
+// result is everything needed to replay a ScanFunc exactly as it
+// happened the first time.
+type result struct {
+	ok    bool
+	end   curs.R  // position after (unchanged on failure)
+	runes []rune  // buffered on success
+	errs  []error // pushed on failure
+}
+
+// MemoStats report how well memoization (see EnableMemo) is working.
+type MemoStats struct {
+	Hits    int // results replayed from the memo
+	Misses  int // results scanned and added to the memo
+	Entries int // results currently in the memo
+}
+
+// Ratio returns the portion (0 to 1) of memoized scans that were hits.
+func (m MemoStats) Ratio() float64 {
+	if m.Hits+m.Misses == 0 {
+		return 0
+	}
+	return float64(m.Hits) / float64(m.Hits+m.Misses)
+}
+
+// EnableMemo turns on memoization (packrat parsing) of every ScanFunc
+// wrapped with Memo so that grammars that backtrack heavily never scan
+// the same rule at the same position more than once trading memory for
+// (sometimes dramatically) less time. The memo is emptied by Buffer
+// (and Open). Enabling again also empties the memo and resets stats.
+func (s *S) EnableMemo() {
+	s.memo = map[memokey]result{}
+	s.memostats = MemoStats{}
+}
+
+// DisableMemo turns off memoization dropping the memo and stats.
+func (s *S) DisableMemo() {
+	s.memo = nil
+	s.memostats = MemoStats{}
+}
+
+// MemoStats returns the current memoization stats (see EnableMemo).
+func (s *S) MemoStats() MemoStats {
+	st := s.memostats
+	st.Entries = len(s.memo)
+	return st
+}
+
+// memoEntry is the number of bytes of every memo entry (without its
+// runes and errors).
+const memoEntry = int(unsafe.Sizeof(memokey{}) + unsafe.Sizeof(result{}))
+
+// MemoBytes returns an estimate of the bytes of memory held by the memo
+// (see EnableMemo): every key and result along with the runes and
+// errors (interface only) of each. The overhead of the map itself is
+// not included. Use with MemoStats to decide if memoization is worth
+// the memory for a given grammar and input.
+func (s *S) MemoBytes() int {
+	var size int
+	for _, r := range s.memo {
+		size += memoEntry + 4*len(r.runes) + 16*len(r.errs)
+	}
+	return size
+}
+
+// Memo returns a ScanFunc that memoizes the results of fn by rule ID
+// and position when used with an S that has memoization enabled (see
+// EnableMemo). Otherwise, fn is simply called. The result of fn must
+// depend only on the position it begins scanning from (which is true
+// of any ScanFunc for a PEG rule). While memoizing, fn is always given
+// a buffer so that the runes can be replayed.
+//
+//	var Scan_Expr = scanner.Memo(Expr, scan_Expr)
+func Memo(id int, fn pegn.ScanFunc) pegn.ScanFunc {
+	return func(p pegn.Scanner, buf *[]rune) bool {
+		s, is := p.(*S)
+		if !is || s.memo == nil {
+			return fn(p, buf)
+		}
+		key := memokey{id, s.E + s.off}
+		if r, has := s.memo[key]; has {
+			s.memostats.Hits++
+			if !r.ok {
+				for _, e := range r.errs {
+					s.ErrPush(e)
+				}
+				return false
+			}
+			s.Goto(r.end)
+			s.last = [2]int{key.pos, s.E + s.off}
+			if buf != nil {
+				*buf = append(*buf, r.runes...)
+			}
+			return true
+		}
+		s.memostats.Misses++
+		n := len(s.errors)
+		runes := []rune{}
+		ok := fn(s, &runes)
+		r := result{ok: ok, end: s.Mark()}
+		if ok {
+			s.last = [2]int{key.pos, s.E + s.off}
+			r.runes = runes
+			if buf != nil {
+				*buf = append(*buf, runes...)
+			}
+		} else if len(s.errors) > n {
+			r.errs = append([]error{}, s.errors[n:]...)
+		}
+		s.memo[key] = r
+		return ok
+	}
+}
@@ -0,0 +1,69 @@
+package scanner_test
+
+import (
+	"fmt"
+
+	"github.com/rwxrob/pegn"
+	"github.com/rwxrob/pegn/scanner"
+)
+
+func ExampleMemo() {
+
+	// Sum <- Num '+' Sum / Num '-' Sum / Num
+	// Num <- [0-9]+
+
+	var calls int
+	var Num, Sum pegn.ScanFunc
+
+	Num = scanner.Memo(1, func(s pegn.Scanner, buf *[]rune) bool {
+		calls++
+		m := s.Mark()
+		var n int
+		for s.Scan() {
+			if s.Rune() < '0' || s.Rune() > '9' {
+				s.Goto(m)
+				break
+			}
+			if buf != nil {
+				*buf = append(*buf, s.Rune())
+			}
+			m = s.Mark()
+			n++
+		}
+		if n == 0 {
+			return s.Expected(1)
+		}
+		return true
+	})
+
+	Sum = func(s pegn.Scanner, buf *[]rune) bool {
+		for _, op := range []string{`+`, `-`} {
+			m := s.Mark()
+			if Num(s, buf) && s.Peek(op) {
+				s.Scan()
+				if Sum(s, buf) {
+					return true
+				}
+			}
+			s.Goto(m)
+		}
+		return Num(s, buf)
+	}
+
+	s := scanner.New(`1-22-333`)
+	Sum(s, nil)
+	fmt.Println(calls, s.Finished())
+
+	calls = 0
+	s.Buffer(`1-22-333`)
+	s.EnableMemo()
+	Sum(s, nil)
+	st := s.MemoStats()
+	fmt.Println(calls, s.Finished(), st, st.Ratio())
+	fmt.Println(s.MemoBytes() > 0)
+
+	// Output:
+	// 7 true
+	// 3 true {4 3 3} 0.5714285714285714
+	// true
+}
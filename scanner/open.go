@@ -0,0 +1,50 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+package scanner
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// HTTPClient is used by Open to get http and https URLs.
+var HTTPClient = http.DefaultClient
+
+// Open reads everything from the path and passes it to Buffer setting
+// Name to the path. The path "-" is standard input and any path
+// beginning with http:// or https:// is a URL (which fails unless the
+// status is 2xx, see HTTPClient). Anything else is a local file.
+// Fulfills pegn.Scanner.
+func (s *S) Open(path string) error {
+	var in io.Reader
+	switch {
+	case path == `-`:
+		in = os.Stdin
+	case strings.HasPrefix(path, `http://`), strings.HasPrefix(path, `https://`):
+		res, err := HTTPClient.Get(path)
+		if err != nil {
+			return err
+		}
+		defer res.Body.Close()
+		if res.StatusCode < 200 || res.StatusCode > 299 {
+			return fmt.Errorf(`open %v: %v`, path, res.Status)
+		}
+		in = res.Body
+	default:
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		in = f
+	}
+	if err := s.Buffer(in); err != nil {
+		return err
+	}
+	s.Name = path
+	return nil
+}
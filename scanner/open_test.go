@@ -0,0 +1,34 @@
+package scanner_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/rwxrob/pegn/scanner"
+)
+
+func ExampleS_Open_url() {
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != `/greet.txt` {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, `hello`)
+	}))
+	defer srv.Close()
+
+	s := scanner.New(`old`)
+	s.Scan()
+	fmt.Println(s.Open(srv.URL + `/greet.txt`))
+	s.Scan()
+	fmt.Printf("%q %v\n", s.Rune(), s.Name == srv.URL+`/greet.txt`)
+
+	fmt.Println(s.Open(srv.URL+`/missing`) != nil)
+
+	// Output:
+	// <nil>
+	// 'h' true
+	// true
+}
@@ -0,0 +1,110 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+package scanner
+
+// Patterns is a set of byte patterns compiled into an Aho-Corasick
+// automaton so that the first of them to occur can be found in a single
+// pass over the data no matter how many there are. Create with
+// NewPatterns and use with S.PeekAny (or Find directly).
+type Patterns struct {
+	list   []string
+	max    int        // length of longest pattern
+	next   [][256]int // goto function (complete, never negative)
+	output [][]int    // patterns (indexes) ending at each state
+}
+
+// NewPatterns compiles the patterns keeping their order so that the
+// indexes returned refer to them. Empty patterns never match.
+func NewPatterns(patterns ...string) *Patterns {
+	p := &Patterns{list: patterns}
+	p.next = append(p.next, [256]int{})
+	p.output = append(p.output, nil)
+
+	// trie of all patterns (state 0 is root)
+	for i, pat := range patterns {
+		if pat == "" {
+			continue
+		}
+		if len(pat) > p.max {
+			p.max = len(pat)
+		}
+		st := 0
+		for j := 0; j < len(pat); j++ {
+			if p.next[st][pat[j]] == 0 {
+				p.next = append(p.next, [256]int{})
+				p.output = append(p.output, nil)
+				p.next[st][pat[j]] = len(p.next) - 1
+			}
+			st = p.next[st][pat[j]]
+		}
+		p.output[st] = append(p.output[st], i)
+	}
+
+	// failure links breadth first turning the trie into a complete
+	// automaton (missing transitions follow the failure link)
+	fail := make([]int, len(p.next))
+	var queue []int
+	for c := 0; c < 256; c++ {
+		if st := p.next[0][c]; st != 0 {
+			queue = append(queue, st)
+		}
+	}
+	for len(queue) > 0 {
+		st := queue[0]
+		queue = queue[1:]
+		p.output[st] = append(p.output[st], p.output[fail[st]]...)
+		for c := 0; c < 256; c++ {
+			n := p.next[st][c]
+			if n == 0 {
+				p.next[st][c] = p.next[fail[st]][c]
+				continue
+			}
+			fail[n] = p.next[fail[st]][c]
+			queue = append(queue, n)
+		}
+	}
+
+	return p
+}
+
+// Find returns the index of the pattern that begins first in b and the
+// offset where it begins. When more than one begins at the same offset
+// the longest (then the first given) is returned. The index is -1 if
+// none are found.
+func (p *Patterns) Find(b []byte) (index, offset int) {
+	index, offset = -1, -1
+	st := 0
+	for j := 0; j < len(b); j++ {
+		if index >= 0 && j-p.max >= offset {
+			break
+		}
+		st = p.next[st][b[j]]
+		for _, i := range p.output[st] {
+			start := j - len(p.list[i]) + 1
+			switch {
+			case index < 0, start < offset:
+			case start == offset && len(p.list[i]) > len(p.list[index]):
+			case start == offset && len(p.list[i]) == len(p.list[index]) && i < index:
+			default:
+				continue
+			}
+			index, offset = i, start
+		}
+	}
+	return
+}
+
+// PeekAny returns the index of whichever of the patterns occurs next
+// (begins first) anywhere in the rest of the buffer without advancing
+// the scanner (see Patterns.Find). This is useful for detecting which
+// of many possible terminators (end fences, closing tags) ends the
+// content being scanned. The index is -1 and false is returned if none
+// occur.
+func (s *S) PeekAny(p *Patterns) (int, bool) {
+	if s.src != nil {
+		s.fill(p.max)
+	}
+	i, _ := p.Find(s.Buf[s.E:])
+	return i, i >= 0
+}
@@ -0,0 +1,40 @@
+package scanner_test
+
+import (
+	"fmt"
+
+	"github.com/rwxrob/pegn/scanner"
+)
+
+func ExampleS_PeekAny() {
+
+	ends := scanner.NewPatterns("```", `</pre>`, `</p>`, "\n\n")
+
+	s := scanner.New("some <b>text</b>\n</pre> and </p>\n\n")
+	i, ok := s.PeekAny(ends)
+	fmt.Println(i, ok)
+
+	s = scanner.New(`no ending here`)
+	fmt.Println(s.PeekAny(ends))
+
+	// Output:
+	// 1 true
+	// -1 false
+}
+
+func ExamplePatterns_Find() {
+
+	p := scanner.NewPatterns(`bcd`, `abcde`, `he`, `she`, `hers`, `c`)
+
+	for _, in := range []string{`xabcdef`, `ushers`, `xxc`, `zzz`, `abcdx`} {
+		i, at := p.Find([]byte(in))
+		fmt.Println(in, i, at)
+	}
+
+	// Output:
+	// xabcdef 1 1
+	// ushers 3 1
+	// xxc 5 2
+	// zzz -1 -1
+	// abcdx 0 1
+}
@@ -0,0 +1,126 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+package scanner
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rwxrob/pegn/rule"
+)
+
+// RuleProfile is what was collected about a single rule while profiling
+// (see EnableProfile).
+type RuleProfile struct {
+	T         int           `json:"type"`           // rule ID
+	Name      string        `json:"rule,omitempty"` // if registered (see rule.ByID)
+	Calls     int           `json:"calls"`          // times entered
+	Fails     int           `json:"fails"`          // times exited without matching
+	Backtrack int           `json:"backtrack"`      // bytes given back by Revert
+	Time      time.Duration `json:"time"`           // total wall time within (ns)
+	Self      time.Duration `json:"self"`           // of Time not within other rules (ns)
+}
+
+// Profile is every rule profiled (see EnableProfile) with those taking
+// the most time first. It is written as JSON with encoding/json.
+type Profile []RuleProfile
+
+// String returns the profile as a table with a line for every rule.
+func (p Profile) String() string {
+	var out strings.Builder
+	fmt.Fprintf(&out, "%-16v %8v %8v %10v %12v %12v\n", `RULE`, `CALLS`, `FAILS`, `BACKTRACK`, `TIME`, `SELF`)
+	for _, r := range p {
+		name := r.Name
+		if name == "" {
+			name = fmt.Sprint(r.T)
+		}
+		fmt.Fprintf(&out, "%-16v %8v %8v %10v %12v %12v\n", name, r.Calls, r.Fails, r.Backtrack, r.Time, r.Self)
+	}
+	return out.String()
+}
+
+// profiler collects a RuleProfile for every rule entered (see
+// TraceEnter) keeping a frame for each rule still being scanned.
+type profiler struct {
+	rules map[int]*RuleProfile
+	began []frame
+}
+
+// frame is when a rule being scanned began and how much of the time
+// since was spent within the rules it called.
+type frame struct {
+	at    time.Time
+	inner time.Duration
+}
+
+func (p *profiler) get(t int) *RuleProfile {
+	r, has := p.rules[t]
+	if !has {
+		r = &RuleProfile{T: t}
+		p.rules[t] = r
+	}
+	return r
+}
+
+// EnableProfile turns on profiling of every rule wrapped with
+// pegn.Traced (or otherwise calling TraceEnter and TraceExit) counting
+// how often each was called and failed, how many bytes were given back
+// by Revert with its ID, and the total wall time spent within it (which
+// includes the rules it calls and counts recursive calls more than
+// once) as well as the self time not spent within any other rule so
+// that the rules that dominate the time of a scan can be found (see
+// Profile). Tracing need not be on. Profiling starts again
+// by Buffer (and Open) or by enabling again.
+func (s *S) EnableProfile() { s.prof = &profiler{rules: map[int]*RuleProfile{}} }
+
+// DisableProfile turns off profiling dropping everything collected.
+func (s *S) DisableProfile() { s.prof = nil }
+
+// Profile returns everything collected since profiling was enabled (see
+// EnableProfile) with the rules that took the most time first (or nil
+// if not profiling).
+func (s *S) Profile() Profile {
+	if s.prof == nil {
+		return nil
+	}
+	p := make(Profile, 0, len(s.prof.rules))
+	for _, r := range s.prof.rules {
+		c := *r
+		if d, has := rule.ByID(c.T); has {
+			c.Name = d.Name
+		}
+		p = append(p, c)
+	}
+	sort.Slice(p, func(i, j int) bool {
+		if p[i].Time != p[j].Time {
+			return p[i].Time > p[j].Time
+		}
+		return p[i].T < p[j].T
+	})
+	return p
+}
+
+func (p *profiler) enter(t int) {
+	p.get(t).Calls++
+	p.began = append(p.began, frame{at: time.Now()})
+}
+
+func (p *profiler) exit(t int, ok bool) {
+	r := p.get(t)
+	if !ok {
+		r.Fails++
+	}
+	if n := len(p.began); n > 0 {
+		f := p.began[n-1]
+		d := time.Since(f.at)
+		r.Time += d
+		r.Self += d - f.inner
+		p.began = p.began[:n-1]
+		if n > 1 {
+			p.began[n-2].inner += d
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package scanner_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/rwxrob/pegn"
+	"github.com/rwxrob/pegn/scanner"
+)
+
+func ExampleS_Profile() {
+	const (
+		Digits = iota + 1
+		Number
+	)
+
+	digits := pegn.Traced(Digits, func(s pegn.Scanner, buf *[]rune) bool {
+		b := s.RuneE()
+		for m := s.Mark(); s.Scan(); m = s.Mark() {
+			if s.Rune() < '0' || s.Rune() > '9' {
+				s.Goto(m)
+				break
+			}
+		}
+		return s.RuneE() > b || s.Expected(Digits)
+	})
+	number := pegn.Traced(Number, func(s pegn.Scanner, buf *[]rune) bool {
+		m := s.Mark()
+		if !digits(s, buf) {
+			return false
+		}
+		if !s.Peek(`.`) {
+			return s.Revert(m, Number)
+		}
+		s.Scan()
+		return digits(s, buf)
+	})
+
+	s := scanner.New(`42.1 123x`)
+	s.EnableProfile()
+	number(s, nil)
+	s.Scan()
+	number(s, nil)
+
+	p := s.Profile()
+	sort.Slice(p, func(i, j int) bool { return p[i].T < p[j].T })
+	for i := range p {
+		if p[i].Self > p[i].Time {
+			fmt.Println(`self more than total`)
+		}
+		p[i].Time, p[i].Self = 0, 0 // varies
+	}
+	fmt.Print(p)
+	byt, _ := json.Marshal(p[1])
+	fmt.Println(string(byt))
+
+	// Output:
+	// RULE                CALLS    FAILS  BACKTRACK         TIME         SELF
+	// 1                       3        0          0           0s           0s
+	// 2                       2        1          3           0s           0s
+	// {"type":2,"calls":2,"fails":1,"backtrack":3,"time":0,"self":0}
+}
+
+func ExampleS_Profile_streaming() {
+
+	defer func(n int) { scanner.StreamChunk = n }(scanner.StreamChunk)
+	scanner.StreamChunk = 16
+
+	s := scanner.NewStreaming(strings.NewReader(strings.Repeat(`a`, 1000)), 8)
+	s.EnableProfile()
+	for i := 0; i < 900; i++ {
+		s.Scan()
+	}
+	m := s.Mark()
+	for i := 0; i < 5; i++ {
+		s.Scan()
+	}
+	s.Revert(m, 1)
+
+	fmt.Println(s.Offset() > 0, s.RuneE(), s.Profile()[0].Backtrack)
+
+	// Output:
+	// true 900 5
+}
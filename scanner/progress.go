@@ -0,0 +1,40 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+package scanner
+
+// progress is when to call the hook set with OnProgress next.
+type progress struct {
+	every int
+	next  int
+	fn    func(pos int, pct float64)
+}
+
+// OnProgress sets a hook called every time the scan first reaches
+// another multiple of every bytes (see Stats.Max) with the byte offset
+// reached and the percentage of the input that is (or -1 if the size
+// is not known because still streaming, see NewStreaming) so that
+// parses of huge inputs can show progress or check that they are still
+// alive. Moving back (backtracking) never calls it again for the same
+// bytes. A nil fn (or every less than 1) removes the hook. It is kept
+// by Buffer (and Open) which start counting again.
+func (s *S) OnProgress(every int, fn func(pos int, pct float64)) {
+	if fn == nil || every < 1 {
+		s.prog = nil
+		return
+	}
+	s.prog = &progress{every: every, next: every, fn: fn}
+}
+
+// report calls the progress hook (see OnProgress) with the byte offset
+// reached (pos) for every multiple passed.
+func (p *progress) report(s *S, pos int) {
+	pct := -1.0
+	if s.src == nil && len(s.Buf) > 0 {
+		pct = float64(pos) * 100 / float64(len(s.Buf)+s.off)
+	}
+	for pos >= p.next {
+		p.next += p.every
+	}
+	p.fn(pos, pct)
+}
@@ -0,0 +1,28 @@
+package scanner_test
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rwxrob/pegn/scanner"
+)
+
+func ExampleS_OnProgress() {
+	s := scanner.New(strings.Repeat(`x`, 100))
+	s.OnProgress(30, func(pos int, pct float64) {
+		fmt.Printf("%v %.0f%%\n", pos, pct)
+	})
+	for i := 0; i < 40; i++ {
+		s.Scan()
+	}
+	m := s.Mark()
+	for s.Scan() {
+	}
+	s.Goto(m) // not again
+	for s.Scan() {
+	}
+	// Output:
+	// 30 30%
+	// 60 60%
+	// 90 90%
+}
@@ -5,16 +5,17 @@ package scanner
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"io"
 	"log"
-	"os"
 	"regexp"
 	"text/template"
 	"unicode/utf8"
 
 	"github.com/rwxrob/pegn"
 	"github.com/rwxrob/pegn/curs"
+	"github.com/rwxrob/pegn/lang"
 )
 
 // Trace sets the trace for everything that uses this package. Use
@@ -24,22 +25,53 @@ var Trace int
 // S (to avoid stuttering) implements a buffered data, non-linear,
 // rune-centric, scanner with regular expression support
 type S struct {
-	Buf        []byte             // full buffer for lookahead or behind
-	R          rune               // last decoded/scanned rune, maybe >1byte
-	B          int                // index pointing beginning of R
-	E          int                // index pointing to end (after) R
-	Template   *template.Template // for Report()
-	NewLine    []string           // []string{"\r\n","\n"} by default
-	Trace      int                // non-zero activates tracing
-	ErrFmtFunc func(e error) string
+	Buf        []byte               // full buffer for lookahead or behind
+	R          rune                 // last decoded/scanned rune, maybe >1byte
+	B          int                  // index pointing beginning of R
+	E          int                  // index pointing to end (after) R
+	Template   *template.Template   // for Report()
+	NewLine    []string             // []string{"\r\n","\n"} by default
+	Trace      int                  // non-zero activates tracing
+	TraceFunc  func(e TraceEvent)   // nil to log (see TraceEvent)
+	ErrFmtFunc func(e error) string // nil for FormatErr
+	Name       string               // name of file opened (see Open)
 
 	viewlen int // length of bytes to show in preview
 	errors  []error
 	maxerr  int
+
+	errfirst int // errors kept from beginning (see SetErrCap)
+	errlast  int // errors kept from end (see SetErrCap)
+	dropped  int // errors dropped between them
+
+	far *FarthestErr // nil unless EnableFarthest
+
+	memo      map[memokey]result // nil unless EnableMemo
+	memostats MemoStats
+
+	bookmarks map[string]curs.R // see SetBookmark
+	last      [2]int            // see LastMatch
+	depth     int               // rules entered (see TraceEnter)
+	prof      *profiler         // nil unless EnableProfile
+	capture   *[]rune           // see Capture
+	stats     Stats             // see Stats
+	prog      *progress         // nil unless OnProgress
+
+	ReadErr error     // from reading stream (see NewStreaming)
+	src     io.Reader // nil unless streaming (or stream ended)
+	window  int       // bytes kept before B when streaming
+	keep    int       // offset never dropped from when streaming (see Keep) or -1
+	off     int       // offset of Buf[0] from beginning of stream
+	lines   int       // lines dropped from beginning of stream
+	runes   int       // runes dropped from beginning of stream
+	col     [2]int    // bytes and runes of line before Buf[0] (see SubScanner)
 }
 
 var ViewLenDefault = 10 // default length of preview window
 
+// DefaultErrFmtFunc formats each error without a position (anything
+// not a pegn.Error) on its own line exactly as it is (see FormatErr).
+// Set it with SetErrFmtFunc to format all errors without positions.
 var DefaultErrFmtFunc = func(e error) string { return fmt.Sprintf("%v\n", e) }
 
 // New is a high-level scanner constructor and initializer that takes
@@ -47,7 +79,6 @@ var DefaultErrFmtFunc = func(e error) string { return fmt.Sprintf("%v\n", e) }
 // Invalid arguments will fail (not fatal) with log output.
 func New(args ...any) *S {
 	s := new(S)
-	s.ErrFmtFunc = DefaultErrFmtFunc
 	switch len(args) {
 	case 2:
 		if c, ok := args[1].(curs.R); ok {
@@ -64,27 +95,92 @@ func (s *S) SetViewLen(a int) { s.viewlen = a }
 func (s *S) SetMaxErr(i int)  { s.maxerr = i }
 func (s *S) Bytes() *[]byte   { return &s.Buf }
 func (s *S) Rune() rune       { return s.R }
-func (s *S) RuneB() int       { return s.B }
-func (s *S) RuneE() int       { return s.E }
-func (s *S) Mark() curs.R     { return curs.R{&s.Buf, s.R, s.B, s.E} }
-func (s *S) Goto(c curs.R)    { s.R, s.B, s.E = c.R, c.B, c.E }
+func (s *S) RuneB() int       { return s.B + s.off }
+func (s *S) RuneE() int       { return s.E + s.off }
+func (s *S) Mark() curs.R     { return curs.R{&s.Buf, s.R, s.B + s.off, s.E + s.off} }
 func (s *S) ViewLen() int     { return s.viewlen }
 func (s *S) TraceOff()        { s.Trace = 0 }
 func (s *S) TraceOn()         { s.Trace++ }
 
+// Goto moves to the cursor (see Mark). If streaming (see NewStreaming)
+// and the cursor is no longer within the window the stream is ended
+// instead (see ErrWindow).
+func (s *S) Goto(c curs.R) {
+	if c.B < s.off {
+		s.lost(c)
+		return
+	}
+	if c.E-s.off < s.E {
+		s.stats.Resets++
+	}
+	s.R, s.B, s.E = c.R, c.B-s.off, c.E-s.off
+}
+
 func (s *S) SetErrFmtFunc(fn func(e error) string) { s.ErrFmtFunc = fn }
 
 func (s *S) Errors() *[]error { return &s.errors }
-func (s *S) ErrPush(e error)  { s.errors = append(s.errors, e) }
 
+// Error combines all the errors into a single string with each
+// formatted by ErrFmtFunc (or FormatErr if not set). A line with the
+// number dropped is added where any were dropped (see SetErrCap).
 func (s *S) Error() string {
+	format := s.ErrFmtFunc
+	if format == nil {
+		format = s.FormatErr
+	}
 	var buf string
-	for _, e := range s.errors {
-		buf += s.ErrFmtFunc(e)
+	for i, e := range s.errors {
+		if s.dropped > 0 && i == s.errfirst {
+			buf += s.droppedMsg()
+		}
+		buf += format(e)
+	}
+	if s.dropped > 0 && len(s.errors) <= s.errfirst {
+		buf += s.droppedMsg()
 	}
 	return buf
 }
 
+// FormatErr formats a single error on its own line. A pegn.Error (or
+// any error wrapping one) is prefixed with the short form (see
+// Position.Short) of the position of the rune following its cursor
+// (the one that was not expected) so that it can be located from
+// editors and logs:
+//
+//	grammar.pegn:12:7: expecting type 3 at 'x' 220-221
+//
+// Anything less severe than an error (see pegn.SeverityOf) has the
+// severity added after the position (grammar.pegn:12:7: warning: ...).
+// A pegn.Error itself is described with the line and column kept by
+// the scanner (see pegn.Error.At) which, unlike those of the cursor
+// alone, are right even when streaming (see NewStreaming).
+func (s *S) FormatErr(e error) string {
+	var pe pegn.Error
+	if errors.As(e, &pe) {
+		line, col := s.next(pe.C)
+		pos := Position{Name: s.Name, Line: line, LRune: col}.Short() + ": "
+		if sev := pegn.SeverityOf(e); sev != pegn.SevError {
+			pos += sev.String() + ": "
+		}
+		msg := e.Error()
+		if _, is := e.(pegn.Error); is {
+			msg = pe.At(lang.Sprintf(`position`, line, col))
+		}
+		return pos + msg + "\n"
+	}
+	return DefaultErrFmtFunc(e)
+}
+
+// next returns the line and column (both beginning with 1) of the rune
+// following the cursor.
+func (s *S) next(c curs.R) (line, col int) {
+	p := s.Positions(c.E)[0]
+	if p.Line == 0 {
+		return 1 + s.lines, 1 + s.col[1]
+	}
+	return p.Line, p.LRune + 1
+}
+
 func (s *S) ErrPop() error {
 	l := len(s.errors)
 	if l == 0 {
@@ -95,51 +191,55 @@ func (s *S) ErrPop() error {
 	return e
 }
 
+// text returns the bytes of the buffer as a string, without copying
+// them if built with the pegn_unsafe tag (see str) unless streaming
+// since sliding the window (see NewStreaming) rewrites the buffer.
+func (s *S) text(b []byte) string {
+	if s.src != nil {
+		return string(b)
+	}
+	return str(b)
+}
+
 // CopyEE returns copy (n,m] fulfilling pegn.Scanner interface.
 func (s *S) CopyEE(m curs.R) string {
+	m.B, m.E = m.B-s.off, m.E-s.off
 	if m.B <= s.B {
-		return string(s.Buf[m.E:s.E])
+		return s.text(s.Buf[m.E:s.E])
 	}
-	return string(s.Buf[s.E:m.E])
+	return s.text(s.Buf[s.E:m.E])
 }
 
 // CopyBB returns copy [n,m] fulfilling pegn.Scanner interface.
 func (s *S) CopyBE(m curs.R) string {
+	m.B, m.E = m.B-s.off, m.E-s.off
 	if m.B <= s.B {
-		return string(s.Buf[m.B:s.E])
+		return s.text(s.Buf[m.B:s.E])
 	}
-	return string(s.Buf[s.B:m.E])
+	return s.text(s.Buf[s.B:m.E])
 }
 
 // CopyBB returns copy [n,m) fulfilling pegn.Scanner interface.
 func (s *S) CopyBB(m curs.R) string {
+	m.B, m.E = m.B-s.off, m.E-s.off
 	if m.B <= s.B {
-		return string(s.Buf[m.B:s.B])
+		return s.text(s.Buf[m.B:s.B])
 	}
-	return string(s.Buf[s.B:m.B])
+	return s.text(s.Buf[s.B:m.B])
 }
 
 // CopyEB returns copy (n,m) fulfilling pegn.Scanner interface.
 func (s *S) CopyEB(m curs.R) string {
+	m.B, m.E = m.B-s.off, m.E-s.off
 	if m.B <= s.B {
-		return string(s.Buf[m.E:s.B])
-	}
-	return string(s.Buf[s.E:m.B])
-}
-
-// Open opens the file at path and passes it to Buffer. Fulfills
-// pegn.Scanner.
-func (s *S) Open(path string) error {
-	f, err := os.Open(path)
-	defer f.Close()
-	if err != nil {
-		return err
+		return s.text(s.Buf[m.E:s.B])
 	}
-	return s.Buffer(f)
+	return s.text(s.Buf[s.E:m.B])
 }
 
 // Buffer sets the internal bytes buffer (Buf) and resets the existing
-// cursor values to their initial state (null, 0,0). This is useful when
+// cursor values to their initial state (null, 0,0) and Name to empty
+// (see Open). This is useful when
 // testing in order to buffer strings as well as content from any
 // io.Reader, []byte, []rune, or string. Fulfills pegn.Scanner.
 func (s *S) Buffer(b any) error {
@@ -160,6 +260,25 @@ func (s *S) Buffer(b any) error {
 	s.R = '\x00'
 	s.B = 0
 	s.E = 0
+	s.Name = ""
+	s.src, s.ReadErr = nil, nil
+	s.off, s.lines, s.runes, s.col = 0, 0, 0, [2]int{}
+	if s.far != nil {
+		s.far = new(FarthestErr)
+	}
+	if s.memo != nil {
+		s.memo = map[memokey]result{}
+	}
+	if s.prof != nil {
+		s.EnableProfile()
+	}
+	s.bookmarks = nil
+	s.last = [2]int{}
+	s.depth = 0
+	s.stats = Stats{}
+	if s.prog != nil {
+		s.prog.next = s.prog.every
+	}
 	return nil
 }
 
@@ -173,8 +292,14 @@ func (s *S) Expected(ruleid int) bool {
 
 // Revert is a shortcut for Expected + Goto.
 func (s *S) Revert(m curs.R, ruleid int) bool {
+	if s.prof != nil && m.E < s.E+s.off {
+		s.prof.get(ruleid).Backtrack += s.E + s.off - m.E
+	}
 	s.Expected(ruleid)
 	s.Goto(m)
+	if s.tracing() {
+		s.trace(TraceEvent{Kind: TraceRevert, T: ruleid})
+	}
 	return false
 }
 
@@ -188,9 +313,10 @@ type ScannerErrors interface {
 */
 
 const DefaultTemplate = `
-{{- if .Errors -}}
-	{{- range .Errors -}}
-		error: {{.}}
+{{- if .ErrReport -}}
+	{{- range $i, $e := .ErrReport -}}
+		{{- if $i }}{{ "\n" }}{{ end -}}
+		{{- $e.Sev}}: {{$e}}
 	{{- end -}}
 {{- else -}}
 	{{- .Pos -}}
@@ -213,12 +339,13 @@ func init() {
 // within a give text file. Note that all values begin with 1 and not
 // 0.
 type Position struct {
-	Rune    rune // rune at this location
-	BufByte int  // byte offset in file
-	BufRune int  // rune offset in file
-	Line    int  // line offset
-	LByte   int  // line column byte offset
-	LRune   int  // line column rune offset
+	Name    string // name of file (see S.Open) or empty
+	Rune    rune   // rune at this location
+	BufByte int    // byte offset in file
+	BufRune int    // rune offset in file
+	Line    int    // line offset
+	LByte   int    // line column byte offset
+	LRune   int    // line column rune offset
 }
 
 // String fulfills the fmt.Stringer interface by printing
@@ -239,6 +366,24 @@ func (p Position) String() string {
 	return s
 }
 
+// Short returns the position in the form used by compilers and
+// understood by most editors and continuous integration tools
+// (name.pegn:12:7). The line and column (in runes) begin with 1. The
+// name is omitted (12:7) if not known.
+func (p Position) Short() string {
+	line, col := p.Line, p.LRune
+	if line < 1 {
+		line = 1
+	}
+	if col < 1 {
+		col = 1
+	}
+	if p.Name == "" {
+		return fmt.Sprintf(`%v:%v`, line, col)
+	}
+	return fmt.Sprintf(`%v:%v:%v`, p.Name, line, col)
+}
+
 // Print prints the cursor itself in String form. See String.
 func (p Position) Print() { fmt.Println(p.String()) }
 
@@ -248,7 +393,7 @@ func (p Position) Log() { log.Println(p.String()) }
 // Pos returns a human-friendly Position for the current location.
 // When multiple positions are needed use Positions instead.
 
-func (s S) Pos() Position { return s.Positions(s.E)[0] }
+func (s S) Pos() Position { return s.Positions(s.E + s.off)[0] }
 
 // Positions returns human-friendly Position information (which can easily
 // be used to populate a text/template) for each raw byte offset (s.E).
@@ -268,7 +413,7 @@ func (s S) Positions(p ...int) []Position {
 		s.NewLine = []string{"\r\n", "\n"}
 	}
 
-	_rune, line, lbyte, lrune := 1, 1, 1, 1
+	_rune, line, lbyte, lrune := 1+s.runes, 1+s.lines, 1+s.col[0], 1+s.col[1]
 	_s := S{Buf: s.Buf}
 	//_s.Trace++
 
@@ -286,10 +431,11 @@ func (s S) Positions(p ...int) []Position {
 		}
 
 		for i, v := range p {
-			if _s.E == v {
+			if _s.E+s.off == v {
 				pos[i] = Position{
+					Name:    s.Name,
 					Rune:    _s.R,
-					BufByte: _s.E,
+					BufByte: v,
 					BufRune: _rune,
 					Line:    line,
 					LByte:   lbyte,
@@ -298,7 +444,7 @@ func (s S) Positions(p ...int) []Position {
 			}
 		}
 
-		rlen := len([]byte(string(s.R)))
+		rlen := len([]byte(string(_s.R)))
 		lbyte += rlen
 		lrune++
 		_rune++
@@ -320,7 +466,7 @@ func (s S) String() string {
 		end = len(s.Buf)
 	}
 	return fmt.Sprintf("%v %q",
-		curs.R{&s.Buf, s.R, s.B, s.E}, s.Buf[s.E:end])
+		curs.R{&s.Buf, s.R, s.B + s.off, s.E + s.off}, s.Buf[s.E:end])
 }
 
 // Print is shorthand for fmt.Println(s).
@@ -335,6 +481,10 @@ func (s S) Log() { log.Println(s) }
 // decoded since most runes (ASCII) will usually be under this number.
 func (s *S) Scan() bool {
 
+	if s.src != nil {
+		s.fill(utf8.UTFMax)
+	}
+
 	if s.E >= len(s.Buf) {
 		return false
 	}
@@ -351,19 +501,37 @@ func (s *S) Scan() bool {
 	s.B = s.E
 	s.E += ln
 	s.R = r
+	s.scanned(1)
 
 	if s.Trace > 0 || Trace > 0 {
-		s.Log()
+		s.trace(TraceEvent{Kind: TraceRune})
 	}
 
 	return true
 }
 
+// scanned counts n runes just scanned (ending at E) in the Stats and
+// reports progress (see OnProgress) if the farthest position reached
+// has passed the next interval. Every way of scanning forward (but not
+// Goto) must call it.
+func (s *S) scanned(n int) {
+	s.stats.Runes += n
+	if s.E+s.off > s.stats.Max {
+		s.stats.Max = s.E + s.off
+		if s.prog != nil && s.stats.Max >= s.prog.next {
+			s.prog.report(s, s.stats.Max)
+		}
+	}
+}
+
 // Peek returns true if the passed string matches from current position
 // in the buffer (s.B) forward. Returns false if the string
 // would go beyond the length of buffer (len(s.Buf)). Peek does not
 // advance the Scanner.
 func (s *S) Peek(a string) bool {
+	if s.src != nil {
+		s.fill(len(a))
+	}
 	if len(a)+s.E > len(s.Buf) {
 		return false
 	}
@@ -374,18 +542,27 @@ func (s *S) Peek(a string) bool {
 }
 
 // Finished returns true if scanner has nothing more to scan.
-func (s *S) Finished() bool { return s.E == len(s.Buf) }
+func (s *S) Finished() bool {
+	if s.src != nil {
+		s.fill(1)
+	}
+	return s.E == len(s.Buf)
+}
 
 // Beginning returns true if and only if the scanner is currently
 // pointing to the beginning of the buffer without anything scanned at
 // all.
-func (s *S) Beginning() bool { return s.E == 0 }
+func (s *S) Beginning() bool { return s.E+s.off == 0 }
 
 // Is returns true if the passed string matches the last scanned rune
 // and the runes ahead matching the length of the string.  Returns false
 // if the string would go beyond the length of buffer (len(s.Buf)).
 func (s *S) Is(a string) bool {
 
+	if s.src != nil {
+		s.fill(len(a))
+	}
+
 	if len(a)+s.B > len(s.Buf) {
 		return false
 	}
@@ -438,13 +615,13 @@ func (s *S) Match(re *regexp.Regexp) int {
 }
 
 // Report will fill in the s.Template (or scan.Template if not set) and
-// log it to standard error. See the log package for removing prefixes
+// log it to standard error with the position of every error resolved
+// (see ErrReport). See the log package for removing prefixes
 // and such. The DefaultTemplate is compiled at init() and assigned to
 // the scan.Template global package variable. To silence reports
 // developers may use the log package or simply ensure that both
 // s.Template and scan.Template are nil.
 func (s S) Report() {
-	// TODO expand the s.Errors if no s.Position on first
 	tmpl := s.Template
 	if s.Template == nil {
 		tmpl = Template
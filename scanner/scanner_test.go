@@ -1,11 +1,17 @@
 package scanner_test
 
 import (
+	"errors"
 	"fmt"
 	"log"
 	"os"
+	"path/filepath"
 	"regexp"
+	"strings"
 
+	"github.com/rwxrob/pegn"
+	"github.com/rwxrob/pegn/model"
+	"github.com/rwxrob/pegn/rule"
 	"github.com/rwxrob/pegn/scanner"
 )
 
@@ -19,7 +25,7 @@ func ExampleS_init() {
 
 }
 
-func ExampleS_package_Trace() {
+func ExampleS_packageTrace() {
 
 	// take over stderr just for this test
 	defer log.SetFlags(log.Flags())
@@ -285,3 +291,75 @@ func ExampleMark() {
 	// '\x00' 0-0 "foo"
 
 }
+
+func ExamplePosition_Short() {
+
+	dir, _ := os.MkdirTemp("", `scanner`)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, `some.pegn`)
+	os.WriteFile(path, []byte("Some <- 'thing'\nElse <- !x"), 0600)
+
+	s := scanner.New()
+	s.Open(path)
+	for s.Scan() && s.Rune() != '!' {
+	}
+	fmt.Println(strings.TrimPrefix(s.Pos().Short(), dir+string(filepath.Separator)))
+	s.Expected(2)
+	fmt.Print(strings.TrimPrefix(s.Error(), dir+string(filepath.Separator)))
+
+	s = scanner.New("one\ntwo")
+	s.Scan()
+	s.Expected(1)
+	s.ErrPush(errors.New(`no position`))
+	fmt.Print(s.Error())
+
+	// Output:
+	// some.pegn:2:9
+	// some.pegn:2:10: expecting type 2 at '!' 24-25
+	// 1:2: expecting type 1 at 'o' 0-1
+	// no position
+}
+
+func ExampleS_FormatErr() {
+
+	rule.Register(model.Rule{ID: 9002, Name: `Val`, PEGN: `Val <-- digit+`})
+
+	s := scanner.New(`ab=x`)
+	s.Scan()
+	s.Scan()
+	s.Scan()
+	fmt.Print(s.FormatErr(pegn.Error{T: 9002, C: s.Mark()}))
+
+	// Output:
+	// 1:4: expecting Val at line 1, column 4
+}
+
+func ExampleS_ErrReport() {
+
+	defer log.SetFlags(log.Flags())
+	defer log.SetOutput(os.Stderr)
+	log.SetOutput(os.Stdout)
+	log.SetFlags(0)
+
+	s := scanner.New("one line\nand another")
+	s.Name = `some.txt`
+	s.Expected(1)
+	for i := 0; i < 12; i++ {
+		s.Scan()
+	}
+	s.Expected(2)
+	s.ErrPush(fmt.Errorf(`no position`))
+
+	for _, e := range s.ErrReport() {
+		fmt.Println(e.P, e.Pos.Line, e.Pos.LRune, e)
+	}
+	s.Report()
+
+	// Output:
+	// 0 1 1 some.txt:1:1: expecting type 1 at '\x00' 0-0
+	// 12 2 3 some.txt:2:3: expecting type 2 at 'd' 11-12
+	// 0 0 0 no position
+	// error: some.txt:1:1: expecting type 1 at '\x00' 0-0
+	// error: some.txt:2:3: expecting type 2 at 'd' 11-12
+	// error: no position
+}
@@ -0,0 +1,100 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+/*
+Package scantest captures what a single pegn.ScanFunc did to a scanner
+(see Run) so that failing tests of grammars and hand-written scan
+functions can say exactly what happened rather than just that something
+went wrong (much like net/http/httptest).
+
+	d := scantest.Run(s, Scan_Version)
+	if !d.OK {
+		t.Errorf("Scan_Version failed:\n%v", d)
+	}
+*/
+package scantest
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rwxrob/pegn"
+	"github.com/rwxrob/pegn/curs"
+)
+
+// Diff is the difference between the state of a scanner before and
+// after a ScanFunc was called.
+type Diff struct {
+	OK       bool    // returned by the ScanFunc
+	Before   curs.R  // position before
+	After    curs.R  // position after
+	Consumed string  // input between Before and After
+	Buffered string  // runes added to the buffer
+	Errors   []error // pushed onto the error stack
+	Dropped  int     // errors removed from the stack (never negative)
+}
+
+// Run calls fn with the scanner and a new buffer and returns what it
+// changed. Errors already on the stack are left as they are.
+func Run(s pegn.Scanner, fn pegn.ScanFunc) Diff {
+	before := s.Mark()
+	n := len(*s.Errors())
+	var buf []rune
+	d := Diff{Before: before}
+	d.OK = fn(s, &buf)
+	d.After = s.Mark()
+	d.Buffered = string(buf)
+	if d.After.E != before.E {
+		d.Consumed = s.CopyEE(before)
+	}
+	errs := *s.Errors()
+	switch {
+	case len(errs) > n:
+		d.Errors = append(d.Errors, errs[n:]...)
+	case len(errs) < n:
+		d.Dropped = n - len(errs)
+	}
+	return d
+}
+
+// Moved returns the number of bytes the cursor moved (negative if
+// backward).
+func (d Diff) Moved() int { return d.After.E - d.Before.E }
+
+// String renders the difference as a few lines, one per change,
+// leaving out what did not change:
+//
+//	failed
+//	cursor: '\x00' 0-0 -> '.' 3-4 (+4 bytes)
+//	consumed: "v1.2"
+//	errors: +1
+//	  expecting Num (one or more digits) at line 1, column 5
+func (d Diff) String() string {
+	var out strings.Builder
+	if d.OK {
+		out.WriteString("ok\n")
+	} else {
+		out.WriteString("failed\n")
+	}
+	if m := d.Moved(); m != 0 {
+		fmt.Fprintf(&out, "cursor: %v -> %v (%+d bytes)\n", d.Before, d.After, m)
+	} else {
+		fmt.Fprintf(&out, "cursor: %v (unchanged)\n", d.Before)
+	}
+	if d.Consumed != "" {
+		fmt.Fprintf(&out, "consumed: %q\n", d.Consumed)
+	}
+	if d.Buffered != "" {
+		fmt.Fprintf(&out, "buffered: %q\n", d.Buffered)
+	}
+	if len(d.Errors) > 0 {
+		fmt.Fprintf(&out, "errors: +%v\n", len(d.Errors))
+		for _, e := range d.Errors {
+			fmt.Fprintf(&out, "  %v\n", e)
+		}
+	}
+	if d.Dropped > 0 {
+		fmt.Fprintf(&out, "errors: -%v\n", d.Dropped)
+	}
+	return strings.TrimSuffix(out.String(), "\n")
+}
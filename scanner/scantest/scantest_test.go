@@ -0,0 +1,45 @@
+package scantest_test
+
+import (
+	"fmt"
+
+	"github.com/rwxrob/pegn"
+	"github.com/rwxrob/pegn/scanner"
+	"github.com/rwxrob/pegn/scanner/scantest"
+)
+
+func ExampleRun() {
+
+	// Digits <- digit+
+	digits := func(s pegn.Scanner, buf *[]rune) bool {
+		m := s.Mark()
+		var n int
+		for s.Scan() {
+			if s.Rune() < '0' || s.Rune() > '9' {
+				break
+			}
+			*buf = append(*buf, s.Rune())
+			m = s.Mark()
+			n++
+		}
+		s.Goto(m)
+		if n == 0 {
+			return s.Expected(1)
+		}
+		return true
+	}
+
+	s := scanner.New(`123abc`)
+	fmt.Println(scantest.Run(s, digits))
+	fmt.Println(scantest.Run(s, digits))
+
+	// Output:
+	// ok
+	// cursor: '\x00' 0-0 -> '3' 2-3 (+3 bytes)
+	// consumed: "123"
+	// buffered: "123"
+	// failed
+	// cursor: '3' 2-3 (unchanged)
+	// errors: +1
+	//   expecting type 1 at '3' 2-3
+}
@@ -0,0 +1,22 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+package scanner
+
+import (
+	"github.com/rwxrob/pegn/ast"
+	"github.com/rwxrob/pegn/curs"
+)
+
+// Span sets the byte range of the node (ast.Node.B and E) to everything
+// scanned since the cursor (see Mark) and returns it so that hand
+// written ParseFuncs can record where their nodes came from in one
+// line.
+//
+//	m := s.Mark()
+//	...
+//	return s.Span(&ast.Node{T: Fence, V: string(buf)}, m)
+func (s *S) Span(n *ast.Node, from curs.R) *ast.Node {
+	n.B, n.E = from.E, s.E+s.off
+	return n
+}
@@ -0,0 +1,21 @@
+package scanner_test
+
+import (
+	"fmt"
+
+	"github.com/rwxrob/pegn/ast"
+	"github.com/rwxrob/pegn/scanner"
+)
+
+func ExampleS_Span() {
+	s := scanner.New("key=value")
+	for s.Scan() && s.Rune() != '=' {
+	}
+	m := s.Mark()
+	for s.Scan() {
+	}
+	n := s.Span(&ast.Node{T: 1, V: "value"}, m)
+	fmt.Println(n.B, n.E, string(s.Buf[n.B:n.E]))
+	// Output:
+	// 4 9 value
+}
@@ -0,0 +1,24 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+package scanner
+
+import "fmt"
+
+// Stats are counters kept by every scanner (see S.Stats) cheap enough
+// to never turn off. Comparing Runes to Max shows how much is scanned
+// again after backtracking and Max alone (compared to the size of the
+// input) is enough to report the progress of long scans.
+type Stats struct {
+	Runes  int `json:"runes"`  // scanned (including those scanned again)
+	Max    int `json:"max"`    // farthest byte offset reached
+	Resets int `json:"resets"` // times moved back (see Goto and Revert)
+}
+
+// String returns the stats as a single line.
+func (st Stats) String() string {
+	return fmt.Sprintf(`runes=%v max=%v resets=%v`, st.Runes, st.Max, st.Resets)
+}
+
+// Stats returns the counters since the last Buffer (or Open).
+func (s *S) Stats() Stats { return s.stats }
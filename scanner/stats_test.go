@@ -0,0 +1,24 @@
+package scanner_test
+
+import (
+	"fmt"
+
+	"github.com/rwxrob/pegn/scanner"
+)
+
+func ExampleS_Stats() {
+	s := scanner.New(`abcdef`)
+	s.Scan()
+	m := s.Mark()
+	s.Scan()
+	s.Scan()
+	s.Revert(m, 1)
+	for s.Scan() {
+	}
+	fmt.Println(s.Stats())
+	s.Buffer(`again`)
+	fmt.Println(s.Stats().Runes)
+	// Output:
+	// runes=8 max=6 resets=1
+	// 0
+}
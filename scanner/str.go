@@ -0,0 +1,10 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build !pegn_unsafe
+
+package scanner
+
+// str returns a copy of the bytes as a string. Build with the
+// pegn_unsafe tag to avoid the copy (see str_unsafe.go).
+func str(b []byte) string { return string(b) }
@@ -0,0 +1,20 @@
+package scanner_test
+
+import (
+	"fmt"
+
+	"github.com/rwxrob/pegn/scanner"
+)
+
+// works the same with or without the pegn_unsafe build tag
+func ExampleS_CopyBE() {
+	s := scanner.New(`some thing`)
+	s.Scan()
+	m := s.Mark()
+	s.Scan()
+	s.Scan()
+	s.Scan()
+	fmt.Printf("%q %q %q %q\n", s.CopyBE(m), s.CopyEE(m), s.CopyBB(m), s.CopyEB(m))
+	// Output:
+	// "some" "ome" "som" "om"
+}
@@ -0,0 +1,23 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build pegn_unsafe
+
+package scanner
+
+import "unsafe"
+
+// str returns the bytes as a string without copying them. This is
+// enabled only with the pegn_unsafe build tag for performance-sensitive
+// applications that copy a lot of the buffer (CopyEE and such). The
+// strings returned share memory with the buffer and therefore change
+// if the buffer is modified (directly or with Buffer or Open) while they
+// are still in use. This includes bytes passed to Buffer that are later
+// reused for other input, as pools of buffers (such as that of
+// sandbox.Service) do, so keep no such strings beyond a single scan.
+// Streaming scanners (see NewStreaming) always copy since sliding the
+// window rewrites the buffer. Never enable unless the buffer is never
+// changed once scanning begins.
+//
+//	go build -tags pegn_unsafe
+func str(b []byte) string { return *(*string)(unsafe.Pointer(&b)) }
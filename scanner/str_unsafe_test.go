@@ -0,0 +1,43 @@
+//go:build pegn_unsafe
+
+package scanner_test
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rwxrob/pegn/scanner"
+)
+
+func ExampleS_CopyBE_unsafe() {
+
+	// strings share the bytes passed to Buffer (as pools reuse them)
+	buf := []byte(`some thing`)
+	s := scanner.New(buf)
+	m := s.Mark()
+	s.Scan()
+	s.Scan()
+	some := s.CopyEE(m)
+	copy(buf, `else`)
+	fmt.Println(some)
+
+	// but never those of streaming scanners since the window slides
+	defer func(n int) { scanner.StreamChunk = n }(scanner.StreamChunk)
+	scanner.StreamChunk = 16
+	in := strings.Repeat(`abcdefg`, 10000)
+	s = scanner.NewStreaming(strings.NewReader(in), 8)
+	for i := 0; i < 5000; i++ {
+		s.Scan()
+	}
+	m = s.Mark()
+	s.Scan()
+	s.Scan()
+	two := s.CopyEE(m)
+	for s.Scan() {
+	}
+	fmt.Println(two, two == in[5000:5002], s.Offset() > 5000)
+
+	// Output:
+	// el
+	// cd true true
+}
@@ -0,0 +1,154 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+package scanner
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"unicode/utf8"
+
+	"github.com/rwxrob/pegn/curs"
+)
+
+// StreamChunk is the minimum number of bytes read from a stream at a
+// time (see NewStreaming).
+var StreamChunk = 64 << 10
+
+// NewStreaming returns a new scanner that reads from r only as needed
+// and keeps at most window bytes before the beginning of the last rune
+// scanned (plus whatever has been read ahead) rather than reading
+// everything into Buf first as Buffer does. This allows multi-gigabyte
+// inputs (logs, data dumps) to be scanned in bounded memory so long as
+// no rule ever backtracks (see Goto) more than window bytes. The window
+// is not bounded by the marks still in use (since nothing says when
+// a mark is no longer needed) so the window must be larger than the
+// most any rule backtracks, which is usually the longest alternative
+// that can fail (see ErrWindow), unless what is needed is kept (see
+// Keep) as it is while parsing each record with gr.Grammar.ParseEach.
+//
+// All positions (Mark, RuneB, RuneE, Positions, errors) remain offsets
+// from the beginning of the stream while Buf (and Bytes) hold only the
+// bytes currently retained beginning at Offset. Regular expressions
+// (Match, PeekMatch) and patterns (PeekAny) only see what has been
+// read so far. Any error reading (other than io.EOF) ends the stream
+// and is kept in ReadErr.
+func NewStreaming(r io.Reader, window int) *S {
+	s := new(S)
+	s.src = r
+	s.window = window
+	s.keep = -1
+	return s
+}
+
+// Keep makes sure nothing from the offset on is dropped when streaming
+// (see NewStreaming) no matter how far beyond the window it is until
+// Keep is called again (with -1 to keep nothing more than the window).
+// Returns the offset kept before so that Keep can be nested:
+//
+//	defer s.Keep(s.Keep(s.RuneE()))
+//
+// This is how node values and actions (which need everything matched
+// by a rule) can be longer than the window. Keep does nothing unless
+// streaming.
+func (s *S) Keep(off int) int {
+	prev := s.keep
+	s.keep = off
+	return prev
+}
+
+// ErrWindow is wrapped by the ReadErr of a streaming scanner asked to
+// Goto a cursor that is no longer within its window. Since the input
+// needed is gone, the stream is ended then and there (as for any error
+// reading) so that every rule scanning further fails. The error is
+// also pushed onto the error stack so that it is reported along with
+// the failure. Rules that can still match without scanning (!. and
+// such) may succeed nonetheless, so always check ReadErr when done.
+var ErrWindow = errors.New(`before streaming window`)
+
+// lost ends the stream (see ErrWindow) since the cursor is before the
+// window.
+func (s *S) lost(c curs.R) {
+	err := fmt.Errorf(`scanner: goto %v %w at %v`, c.B, ErrWindow, s.off)
+	if s.ReadErr == nil {
+		s.ReadErr = err
+	}
+	s.ErrPush(err)
+	s.src = nil
+	s.Buf = s.Buf[:s.E]
+}
+
+// Offset returns the offset of the first byte of Buf from the beginning
+// of the input, which is always 0 unless streaming (see NewStreaming).
+func (s *S) Offset() int { return s.off }
+
+// fill reads from the stream until at least n bytes after E are
+// buffered returning false if the stream ends first.
+func (s *S) fill(n int) bool {
+	for len(s.Buf)-s.E < n {
+		if s.src == nil {
+			return false
+		}
+		s.slide()
+		if cap(s.Buf)-len(s.Buf) < StreamChunk {
+			buf := make([]byte, len(s.Buf), 2*cap(s.Buf)+StreamChunk)
+			copy(buf, s.Buf)
+			s.Buf = buf
+		}
+		c, err := s.src.Read(s.Buf[len(s.Buf):cap(s.Buf)])
+		s.Buf = s.Buf[:len(s.Buf)+c]
+		if err != nil {
+			if err != io.EOF {
+				s.ReadErr = err
+			}
+			s.src = nil
+		}
+	}
+	return true
+}
+
+// more reads more of the stream (if any) returning true if anything
+// was read.
+func (s *S) more() bool {
+	if s.src == nil {
+		return false
+	}
+	return s.fill(len(s.Buf) - s.E + 1)
+}
+
+// slide drops everything more than window bytes before B (and before
+// anything kept, see Keep) but only once that is at least half of what
+// is buffered to keep copying to a minimum, keeping count of the lines,
+// runes, and columns dropped for Positions (as SubScanner does).
+func (s *S) slide() {
+	drop := s.B - s.window
+	if s.keep >= 0 && drop > s.keep-s.off {
+		drop = s.keep - s.off
+	}
+	if drop <= 0 || drop < len(s.Buf)/2 {
+		return
+	}
+	for drop > 0 && !utf8.RuneStart(s.Buf[drop]) {
+		drop--
+	}
+	gone := s.Buf[:drop]
+	s.lines += bytes.Count(gone, []byte{'\n'})
+	s.runes += utf8.RuneCount(gone)
+	line := gone
+	if i := bytes.LastIndexByte(gone, '\n'); i >= 0 {
+		line = gone[i+1:]
+		s.col = [2]int{}
+	}
+	s.col[0] += len(line)
+	s.col[1] += utf8.RuneCount(line)
+	n := copy(s.Buf, s.Buf[drop:])
+	s.Buf = s.Buf[:n]
+	s.B -= drop
+	s.E -= drop
+	s.off += drop
+	if s.memo != nil {
+		s.memo = map[memokey]result{}
+	}
+}
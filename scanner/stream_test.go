@@ -0,0 +1,127 @@
+package scanner_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/rwxrob/pegn/model"
+	"github.com/rwxrob/pegn/rule"
+	"github.com/rwxrob/pegn/scanner"
+)
+
+func ExampleNewStreaming() {
+
+	defer func(n int) { scanner.StreamChunk = n }(scanner.StreamChunk)
+	scanner.StreamChunk = 16
+
+	// 1000 lines of "line NNN\n" (9000 bytes)
+	var lines strings.Builder
+	for i := 0; i < 1000; i++ {
+		fmt.Fprintf(&lines, "line %03d\n", i)
+	}
+
+	s := scanner.NewStreaming(strings.NewReader(lines.String()), 32)
+	digits := scanner.NewASCIISet(func(r rune) bool { return r >= '0' && r <= '9' })
+	var count int
+	for !s.Finished() {
+		if !s.Peek(`line `) {
+			break
+		}
+		for range `line ` {
+			s.Scan()
+		}
+		count += s.ScanWhileASCII(digits)
+		s.Scan()
+	}
+	fmt.Println(count, s.RuneE(), s.Offset() > 8000, len(s.Buf) < 128)
+	fmt.Println(s.Positions(8995)[0].Short())
+
+	m := s.Mark()
+	s.Goto(m)
+	fmt.Println(s.ReadErr)
+
+	// Output:
+	// 3000 9000 true true
+	// 1000:4
+	// <nil>
+}
+
+func ExampleErrWindow() {
+
+	defer func(n int) { scanner.StreamChunk = n }(scanner.StreamChunk)
+	scanner.StreamChunk = 16
+
+	// A <- 'a'* 'b' / 'a'* 'c' backtracking more than the window
+	in := strings.Repeat(`a`, 1000) + `c`
+	s := scanner.NewStreaming(strings.NewReader(in), 32)
+	m := s.Mark()
+	for s.Scan() && s.Rune() == 'a' {
+	}
+	s.Goto(m)
+
+	fmt.Println(errors.Is(s.ReadErr, scanner.ErrWindow), s.Scan(), s.Finished())
+	fmt.Println(s.ReadErr)
+	fmt.Print(s.Error())
+
+	// Output:
+	// true false true
+	// scanner: goto 0 before streaming window at 965
+	// scanner: goto 0 before streaming window at 965
+}
+
+func ExampleS_Keep() {
+
+	defer func(n int) { scanner.StreamChunk = n }(scanner.StreamChunk)
+	scanner.StreamChunk = 16
+
+	// the same backtracking as ErrWindow but keeping what is needed
+	in := strings.Repeat(`a`, 1000) + `c`
+	s := scanner.NewStreaming(strings.NewReader(in), 32)
+	m := s.Mark()
+	prev := s.Keep(m.E)
+	for s.Scan() && s.Rune() == 'a' {
+	}
+	s.Goto(m)
+	fmt.Println(s.ReadErr, s.Offset(), prev)
+
+	s.Keep(prev)
+	for s.Scan() && s.Rune() == 'a' {
+	}
+	fmt.Println(string(s.Rune()), s.RuneE())
+
+	// Output:
+	// <nil> 0 -1
+	// c 1001
+}
+
+func ExampleNewStreaming_positions() {
+
+	defer func(n int) { scanner.StreamChunk = n }(scanner.StreamChunk)
+	scanner.StreamChunk = 16
+
+	rule.Register(model.Rule{ID: 9001, Name: `End`, PEGN: `End <-- 'Y'`})
+
+	in := "first\n" + strings.Repeat(`a`, 200) + `éX`
+	a := scanner.NewASCIISet(func(r rune) bool { return r == 'a' })
+	for _, s := range []*scanner.S{
+		scanner.New(in),
+		scanner.NewStreaming(strings.NewReader(in), 8),
+	} {
+		s.Scan()
+		s.ScanWhileASCII(scanner.NewASCIISet(func(r rune) bool { return r != '\n' }))
+		s.Scan()
+		s.ScanWhileASCII(a)
+		s.Scan()
+		s.Expected(1)
+		s.Scan()
+		s.Expected(9001)
+		fmt.Print(s.Error())
+	}
+
+	// Output:
+	// 2:202: expecting type 1 at 'é' 206-208
+	// 2:203: expecting End at line 2, column 203
+	// 2:202: expecting type 1 at 'é' 206-208
+	// 2:203: expecting End at line 2, column 203
+}
@@ -0,0 +1,50 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+package scanner
+
+import (
+	"bytes"
+	"fmt"
+	"unicode/utf8"
+)
+
+// SubScanner returns a new scanner of nothing but the bytes from b up
+// to e (offsets as returned by RuneE) sharing the buffer rather than
+// copying it. Every offset, cursor, error, and Position of the new
+// scanner is that of the same byte within this one so that island
+// grammars (front matter, embedded code blocks) can be scanned by
+// different rules entirely while reporting where they are within the
+// whole document. The new scanner has the same Name, NewLine,
+// Template, ErrFmtFunc, view length, and error limit but none of the
+// errors, tracing, memo, or other state of this one and never scans
+// or moves outside the range. Panics if the range is not within the
+// buffer.
+func (s *S) SubScanner(b, e int) *S {
+	if b < s.off || e < b || e > s.off+len(s.Buf) {
+		panic(fmt.Sprintf(`scanner: sub scanner %v-%v outside of %v-%v`,
+			b, e, s.off, s.off+len(s.Buf)))
+	}
+	before := s.Buf[:b-s.off]
+	sub := &S{
+		Buf:        s.Buf[b-s.off : e-s.off : e-s.off],
+		Template:   s.Template,
+		NewLine:    s.NewLine,
+		ErrFmtFunc: s.ErrFmtFunc,
+		Name:       s.Name,
+		viewlen:    s.viewlen,
+		maxerr:     s.maxerr,
+		off:        b,
+		lines:      s.lines + bytes.Count(before, []byte{'\n'}),
+		runes:      s.runes + utf8.RuneCount(before),
+	}
+	line := before
+	if i := bytes.LastIndexByte(before, '\n'); i >= 0 {
+		line = before[i+1:]
+	} else {
+		sub.col = s.col
+	}
+	sub.col[0] += len(line)
+	sub.col[1] += utf8.RuneCount(line)
+	return sub
+}
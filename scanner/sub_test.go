@@ -0,0 +1,36 @@
+package scanner_test
+
+import (
+	"fmt"
+
+	"github.com/rwxrob/pegn/scanner"
+)
+
+func ExampleS_SubScanner() {
+
+	s := scanner.New("---\ntitle: ✓ x\n---\nbody")
+	for i := 0; i < 4; i++ {
+		s.Scan()
+	}
+	b := s.RuneE()
+	for s.Scan() && s.Rune() != '\n' {
+	}
+	front := s.SubScanner(b, s.RuneB())
+
+	for i := 0; i < 7; i++ {
+		front.Scan()
+	}
+	fmt.Println(front.Mark(), front.Pos())
+	front.Scan()
+	fmt.Println(front.Mark(), front.Pos())
+	fmt.Println(front.Scan(), front.Scan(), front.Scan(), front.Finished())
+
+	// same positions in the whole document
+	fmt.Println(s.Positions(11, 14))
+
+	// Output:
+	// ' ' 10-11 U+0020 ' ' 2,7-7 (11-11)
+	// '✓' 11-14 U+2713 '✓' 2,8-8 (12-14)
+	// true true false true
+	// [U+0020 ' ' 2,7-7 (11-11) U+2713 '✓' 2,8-8 (12-14)]
+}
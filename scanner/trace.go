@@ -0,0 +1,125 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+package scanner
+
+import (
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/rwxrob/pegn"
+	"github.com/rwxrob/pegn/curs"
+)
+
+// TraceKind is the kind of a TraceEvent.
+type TraceKind int
+
+const (
+	TraceRune   TraceKind = iota // rune scanned (see Scan and ScanWhile)
+	TraceEnter                   // rule entered (see TraceEnter)
+	TraceExit                    // rule exited (see TraceExit)
+	TraceRevert                  // moved back with an error (see Revert)
+	TraceError                   // error pushed (see ErrPush)
+)
+
+func (k TraceKind) String() string {
+	switch k {
+	case TraceRune:
+		return `rune`
+	case TraceEnter:
+		return `enter`
+	case TraceExit:
+		return `exit`
+	case TraceRevert:
+		return `revert`
+	case TraceError:
+		return `error`
+	}
+	return fmt.Sprintf(`TraceKind(%d)`, int(k))
+}
+
+// TraceEvent is a single step of a scan passed to the TraceFunc of the
+// scanner while tracing (see TraceOn) so that tools can build
+// visualizations (trees, flame graphs, HTML) of how input was scanned.
+type TraceEvent struct {
+	Kind  TraceKind
+	C     curs.R // cursor after the event
+	T     int    // rule ID (all but TraceRune, 0 if unknown)
+	B     int    // byte offset the rule began at (TraceExit)
+	OK    bool   // rule matched (TraceExit)
+	Err   error  // pushed (TraceError)
+	Depth int    // rules entered but not yet exited (before TraceEnter)
+}
+
+// String returns the event as a single line indented by its depth.
+func (e TraceEvent) String() string {
+	indent := fmt.Sprintf(`%*s`, 2*e.Depth, ``)
+	switch e.Kind {
+	case TraceRune:
+		return fmt.Sprintf(`%v%v %v`, indent, e.Kind, e.C)
+	case TraceExit:
+		ok := `failed`
+		if e.OK {
+			ok = `ok`
+		}
+		return fmt.Sprintf(`%v%v %v %v %v-%v`, indent, e.Kind, e.T, ok, e.B, e.C.E)
+	case TraceError:
+		return fmt.Sprintf(`%v%v %v`, indent, e.Kind, e.Err)
+	}
+	return fmt.Sprintf(`%v%v %v %v`, indent, e.Kind, e.T, e.C)
+}
+
+// tracing returns true if the scanner (or package) has tracing on.
+func (s *S) tracing() bool { return s.Trace > 0 || Trace > 0 }
+
+// trace passes the event to TraceFunc or logs it (runes as Log always
+// has) if there is none.
+func (s *S) trace(e TraceEvent) {
+	e.C, e.Depth = s.Mark(), s.depth
+	if s.TraceFunc != nil {
+		s.TraceFunc(e)
+		return
+	}
+	if e.Kind == TraceRune {
+		s.Log()
+		return
+	}
+	log.Println(e)
+}
+
+// TraceEnter records entering the rule (see Traced in package pegn)
+// while tracing or profiling (see EnableProfile).
+func (s *S) TraceEnter(t int) {
+	if s.prof != nil {
+		s.prof.enter(t)
+	}
+	if !s.tracing() {
+		return
+	}
+	s.trace(TraceEvent{Kind: TraceEnter, T: t})
+	s.depth++
+}
+
+// TraceExit records leaving the rule entered (see TraceEnter) at byte
+// offset b with the result while tracing or profiling.
+func (s *S) TraceExit(t, b int, ok bool) {
+	if s.prof != nil {
+		s.prof.exit(t, ok)
+	}
+	if !s.tracing() {
+		return
+	}
+	if s.depth > 0 {
+		s.depth--
+	}
+	s.trace(TraceEvent{Kind: TraceExit, T: t, B: b, OK: ok})
+}
+
+// traceErr records the error pushed (with the rule ID of the
+// pegn.Error it is or wraps) while tracing.
+func (s *S) traceErr(err error) {
+	var e pegn.Error
+	errors.As(err, &e)
+	s.trace(TraceEvent{Kind: TraceError, T: e.T, Err: err})
+}
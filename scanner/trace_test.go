@@ -0,0 +1,33 @@
+package scanner_test
+
+import (
+	"fmt"
+
+	"github.com/rwxrob/pegn/scanner"
+)
+
+func ExampleTraceEvent() {
+
+	s := scanner.New(`ab`)
+	s.TraceFunc = func(e scanner.TraceEvent) { fmt.Println(e) }
+	s.TraceOn()
+
+	s.TraceEnter(1)
+	s.Scan()
+	s.TraceEnter(2)
+	m := s.Mark()
+	s.Scan()
+	s.Revert(m, 2)
+	s.TraceExit(2, m.E, false)
+	s.TraceExit(1, 0, true)
+
+	// Output:
+	// enter 1 '\x00' 0-0
+	//   rune 'a' 0-1
+	//   enter 2 'a' 0-1
+	//     rune 'b' 1-2
+	//     error expecting type 2 at 'b' 1-2
+	//     revert 2 'a' 0-1
+	//   exit 2 failed 1-1
+	// exit 1 ok 0-1
+}
@@ -0,0 +1,169 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+package scanner
+
+import (
+	"encoding/binary"
+	"unicode/utf8"
+
+	"github.com/rwxrob/pegn"
+)
+
+// ScanWhile scans runes for as long as they are in the class returning
+// the number of runes scanned (which may be 0). ASCII runes are passed
+// to the class directly without decoding. For long runs of a purely
+// ASCII class (white space, digits) prefer ScanWhileASCII which is
+// several times faster.
+func (s *S) ScanWhile(is pegn.ClassFunc) int {
+	var n int
+	start := s.E + s.off
+	for s.E < len(s.Buf) || s.more() {
+		ln := 1
+		r := rune(s.Buf[s.E])
+		if r >= utf8.RuneSelf {
+			if s.src != nil {
+				s.fill(utf8.UTFMax)
+			}
+			r, ln = utf8.DecodeRune(s.Buf[s.E:])
+		}
+		if !is(r) {
+			break
+		}
+		s.B, s.E, s.R = s.E, s.E+ln, r
+		s.scanned(1)
+		n++
+	}
+	if n > 0 {
+		s.last = [2]int{start, s.E + s.off}
+	}
+	if n > 0 && s.tracing() {
+		s.trace(TraceEvent{Kind: TraceRune})
+	}
+	return n
+}
+
+// ASCIISet is a set of ASCII bytes used to scan runs of them quickly
+// (see ScanWhileASCII). Bytes 128 and above are never members.
+type ASCIISet struct {
+	has    [256]bool // lookup table for every byte
+	n      int       // ranges (0 if more than maxRanges)
+	lo, hi [maxRanges]uint64
+}
+
+// maxRanges is the most ranges of bytes in an ASCIISet checked a word
+// at a time. Every range costs four operations per word so sets with
+// more (such as vowels) are faster checked eight table lookups at
+// a time.
+const maxRanges = 4
+
+// Words with every byte the same.
+const (
+	ones  = 0x0101010101010101
+	highs = 0x8080808080808080
+)
+
+// NewASCIISet returns a set of the ASCII runes (0-127) in the class.
+// If the set is made of no more than four ranges of runes (digits,
+// white space, letters, identifiers) it is checked a word at a time.
+func NewASCIISet(is pegn.ClassFunc) *ASCIISet {
+	set := new(ASCIISet)
+	for r := rune(0); r < utf8.RuneSelf; r++ {
+		set.has[r] = is(r)
+	}
+	for r := 0; r < utf8.RuneSelf; r++ {
+		if !set.has[r] || r > 0 && set.has[r-1] {
+			continue
+		}
+		if set.n == maxRanges {
+			set.n = 0
+			break
+		}
+		end := r
+		for end+1 < utf8.RuneSelf && set.has[end+1] {
+			end++
+		}
+		// adding these sets the high bit of every byte of a word that is
+		// at least r (lo) and leaves it clear if at most end (hi) so long
+		// as every byte is ASCII (see words)
+		set.lo[set.n] = ones * uint64(128-r)
+		set.hi[set.n] = ones * uint64(127-end)
+		set.n++
+	}
+	return set
+}
+
+// words returns the number of bytes (a multiple of eight) from the
+// beginning of b that are in the set checking a 64-bit word (eight
+// bytes) at a time. The bytes of a word are only in the set if all are
+// ASCII (high bit clear) and, for every byte, one of the ranges of the
+// set has the high bit of the byte plus lo set and plus hi clear. Since
+// no byte plus either is ever more than 255 there are never carries
+// from one byte to the next. Ranges not used are zero which never sets
+// the high bit of an ASCII byte.
+func (set *ASCIISet) words(b []byte) int {
+	n, lo, hi := set.n, set.lo, set.hi
+	var i int
+	for ; len(b) >= 8; b = b[8:] {
+		w := binary.LittleEndian.Uint64(b)
+		if w&highs != 0 {
+			break
+		}
+		in := (w + lo[0]) &^ (w + hi[0])
+		if n > 1 {
+			in |= (w + lo[1]) &^ (w + hi[1])
+			if n > 2 {
+				in |= (w+lo[2])&^(w+hi[2]) | (w+lo[3])&^(w+hi[3])
+			}
+		}
+		if in&highs != highs {
+			break
+		}
+		i += 8
+	}
+	return i
+}
+
+// ScanWhileASCII scans bytes for as long as they are in the set
+// returning the number scanned (which is also the number of runes). It
+// works directly on the bytes of the buffer checking a 64-bit word at
+// a time for most sets (see NewASCIISet) and is the fastest way to scan
+// over long runs of white space, digits, and such (see the
+// BenchmarkS_ScanWhileASCII benchmarks: about 3 GB/s for digits).
+func (s *S) ScanWhileASCII(set *ASCIISet) int {
+	var n int
+	start := s.E + s.off
+	for {
+		b := s.Buf
+		i := s.E
+		if set.n > 0 {
+			i += set.words(b[i:])
+		} else {
+			t := &set.has
+			for i+8 <= len(b) &&
+				t[b[i]] && t[b[i+1]] && t[b[i+2]] && t[b[i+3]] &&
+				t[b[i+4]] && t[b[i+5]] && t[b[i+6]] && t[b[i+7]] {
+				i += 8
+			}
+		}
+		for i < len(b) && set.has[b[i]] {
+			i++
+		}
+		if i > s.E {
+			m := i - s.E
+			s.B, s.E, s.R = i-1, i, rune(b[i-1])
+			s.scanned(m)
+			n += m
+		}
+		if i < len(b) || !s.more() {
+			break
+		}
+	}
+	if n > 0 {
+		s.last = [2]int{start, s.E + s.off}
+	}
+	if n > 0 && s.tracing() {
+		s.trace(TraceEvent{Kind: TraceRune})
+	}
+	return n
+}
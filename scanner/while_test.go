@@ -0,0 +1,139 @@
+package scanner_test
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand"
+	"strings"
+	"testing"
+	"unicode"
+
+	"github.com/rwxrob/pegn/scanner"
+)
+
+func ExampleS_ScanWhile() {
+	s := scanner.New(`héllo wörld`)
+	fmt.Println(s.ScanWhile(unicode.IsLetter))
+	s.Print()
+	fmt.Println(s.ScanWhile(unicode.IsLetter))
+	s.Print()
+	// Output:
+	// 5
+	// 'o' 5-6 " wörld"
+	// 0
+	// 'o' 5-6 " wörld"
+}
+
+func ExampleS_ScanWhileASCII() {
+	digits := scanner.NewASCIISet(unicode.IsDigit)
+	s := scanner.New(`1234567890123, 42`)
+	fmt.Println(s.ScanWhileASCII(digits))
+	s.Print()
+	// Output:
+	// 13
+	// '3' 12-13 ", 42"
+}
+
+// the word at a time check agrees with checking every byte
+func ExampleNewASCIISet() {
+	classes := []func(r rune) bool{
+		unicode.IsDigit, unicode.IsSpace, unicode.IsLetter, unicode.IsPunct,
+		func(r rune) bool { return r == '_' || r < 128 && unicode.IsLetter(r) || unicode.IsDigit(r) },
+		func(r rune) bool { return r != '\n' },
+		func(r rune) bool { return r == 0 || r == 127 },
+		func(r rune) bool { return true },
+		func(r rune) bool { return false },
+	}
+	alpha := []byte("09azAZ_ \t\n\x00\x7f!~é")
+	rnd := rand.New(rand.NewSource(1))
+	var wrong int
+	for _, is := range classes {
+		set := scanner.NewASCIISet(is)
+		for i := 0; i < 2000; i++ {
+			in := make([]byte, rnd.Intn(40))
+			for j := range in {
+				in[j] = alpha[rnd.Intn(len(alpha))]
+			}
+			var want int
+			for want < len(in) && in[want] < 128 && is(rune(in[want])) {
+				want++
+			}
+			if scanner.New(in).ScanWhileASCII(set) != want {
+				wrong++
+			}
+		}
+	}
+	fmt.Println(wrong)
+	// Output:
+	// 0
+}
+
+func Example_scanWhileStats() {
+	digits := scanner.NewASCIISet(unicode.IsDigit)
+	s := scanner.New(`héllo 1234567890123`)
+	s.OnProgress(5, func(pos int, pct float64) {
+		fmt.Printf("%v %.0f%%\n", pos, pct)
+	})
+	s.ScanWhile(unicode.IsLetter)
+	s.Scan()
+	s.ScanWhileASCII(digits)
+	fmt.Println(s.Stats())
+	// Output:
+	// 5 25%
+	// 20 100%
+	// runes=19 max=20 resets=0
+}
+
+var (
+	benchws  = bytes.Repeat([]byte(" \t\n\r"), 1<<18)
+	benchset = scanner.NewASCIISet(unicode.IsSpace)
+)
+
+func BenchmarkS_Scan_ws(b *testing.B) {
+	b.SetBytes(int64(len(benchws)))
+	for i := 0; i < b.N; i++ {
+		s := scanner.New(benchws)
+		for s.Scan() && unicode.IsSpace(s.Rune()) {
+		}
+	}
+}
+
+func BenchmarkS_ScanWhile_ws(b *testing.B) {
+	b.SetBytes(int64(len(benchws)))
+	for i := 0; i < b.N; i++ {
+		s := scanner.New(benchws)
+		s.ScanWhile(unicode.IsSpace)
+	}
+}
+
+var (
+	benchdigits   = bytes.Repeat([]byte("0123456789"), 1<<17)
+	benchdigitset = scanner.NewASCIISet(unicode.IsDigit)
+	benchvowelset = scanner.NewASCIISet(func(r rune) bool { return strings.ContainsRune(`aeiou`, r) })
+	benchvowels   = bytes.Repeat([]byte("aeiou"), 1<<18)
+)
+
+func BenchmarkS_ScanWhileASCII_digits(b *testing.B) {
+	b.SetBytes(int64(len(benchdigits)))
+	for i := 0; i < b.N; i++ {
+		s := scanner.New(benchdigits)
+		s.ScanWhileASCII(benchdigitset)
+	}
+}
+
+// vowels are too many ranges to be checked a word at a time
+func BenchmarkS_ScanWhileASCII_vowels(b *testing.B) {
+	b.SetBytes(int64(len(benchvowels)))
+	for i := 0; i < b.N; i++ {
+		s := scanner.New(benchvowels)
+		s.ScanWhileASCII(benchvowelset)
+	}
+}
+
+func BenchmarkS_ScanWhileASCII_ws(b *testing.B) {
+	b.SetBytes(int64(len(benchws)))
+	for i := 0; i < b.N; i++ {
+		s := scanner.New(benchws)
+		s.ScanWhileASCII(benchset)
+	}
+}
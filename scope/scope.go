@@ -0,0 +1,100 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+/*
+Package scope provides nested scopes of symbols (symbol tables) for
+use by semantic actions and passes over parse output (see eval) since
+every language-like grammar needs them eventually. Each scope links to
+the one it is within so that a name declared in an inner scope shadows
+the same name in the outer ones. Pushing a scope never changes the
+outer scope so a scope can be passed down a tree (as an inherited
+attribute) and simply dropped when done.
+*/
+package scope
+
+import "fmt"
+
+// S (to avoid stuttering) is a single scope of symbols with values of
+// type T. The zero value is not usable. Use New.
+type S[T any] struct {
+	Up    *S[T] // scope this one is within (nil if outermost)
+	Depth int   // number of scopes this one is within
+
+	syms  map[string]T
+	names []string // in order of declaration
+}
+
+// New returns a new outermost scope.
+func New[T any]() *S[T] { return &S[T]{syms: map[string]T{}} }
+
+// Push returns a new scope within this one.
+func (s *S[T]) Push() *S[T] {
+	return &S[T]{Up: s, Depth: s.Depth + 1, syms: map[string]T{}}
+}
+
+// Pop returns the scope this one is within (nil if outermost).
+func (s *S[T]) Pop() *S[T] { return s.Up }
+
+// Declare adds the symbol to this scope returning an error if it has
+// already been declared in this scope (but not if it was declared in
+// an outer scope, which it then shadows).
+func (s *S[T]) Declare(name string, v T) error {
+	if _, has := s.syms[name]; has {
+		return fmt.Errorf(`already declared in this scope: %q`, name)
+	}
+	s.syms[name] = v
+	s.names = append(s.names, name)
+	return nil
+}
+
+// Resolve returns the value of the symbol from the innermost scope that
+// declares it along with that scope. The scope is nil if not found.
+func (s *S[T]) Resolve(name string) (T, *S[T]) {
+	for c := s; c != nil; c = c.Up {
+		if v, has := c.syms[name]; has {
+			return v, c
+		}
+	}
+	var zero T
+	return zero, nil
+}
+
+// Lookup returns the value of the symbol from the innermost scope that
+// declares it and false if no scope does.
+func (s *S[T]) Lookup(name string) (T, bool) {
+	v, c := s.Resolve(name)
+	return v, c != nil
+}
+
+// Local returns the value of the symbol only if declared in this scope.
+func (s *S[T]) Local(name string) (T, bool) {
+	v, has := s.syms[name]
+	return v, has
+}
+
+// Set changes the value of the symbol in the innermost scope that
+// declares it returning false if no scope does.
+func (s *S[T]) Set(name string, v T) bool {
+	_, c := s.Resolve(name)
+	if c == nil {
+		return false
+	}
+	c.syms[name] = v
+	return true
+}
+
+// Shadows returns true if the symbol is declared in this scope and
+// also in one it is within.
+func (s *S[T]) Shadows(name string) bool {
+	if _, has := s.syms[name]; !has || s.Up == nil {
+		return false
+	}
+	_, c := s.Up.Resolve(name)
+	return c != nil
+}
+
+// Names returns the names declared in this scope only in the order
+// they were declared.
+func (s *S[T]) Names() []string {
+	return append([]string{}, s.names...)
+}
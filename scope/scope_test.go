@@ -0,0 +1,45 @@
+package scope_test
+
+import (
+	"fmt"
+
+	"github.com/rwxrob/pegn/scope"
+)
+
+func ExampleS() {
+
+	global := scope.New[string]()
+	global.Declare(`x`, `int`)
+	global.Declare(`f`, `func`)
+
+	fn := global.Push()
+	fn.Declare(`x`, `string`)
+	fn.Declare(`y`, `bool`)
+	fmt.Println(fn.Declare(`y`, `int`))
+
+	fmt.Println(fn.Lookup(`x`))
+	fmt.Println(global.Lookup(`x`))
+	fmt.Println(fn.Lookup(`f`))
+	fmt.Println(fn.Lookup(`z`))
+	fmt.Println(fn.Shadows(`x`), fn.Shadows(`y`))
+
+	_, where := fn.Resolve(`f`)
+	fmt.Println(where == global, fn.Depth, where.Depth)
+
+	fmt.Println(fn.Set(`f`, `method`), global.Names())
+	fmt.Println(global.Lookup(`f`))
+
+	fmt.Println(fn.Pop() == global, global.Pop())
+
+	// Output:
+	// already declared in this scope: "y"
+	// string true
+	// int true
+	// func true
+	//  false
+	// true false
+	// true 1 0
+	// true [x f]
+	// method true
+	// true <nil>
+}
@@ -0,0 +1,58 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+package pegn
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Severity is the level of a diagnostic pushed onto the error stack of
+// a Scanner. Only errors (the zero value) mean that a scan failed.
+// Warnings and informational diagnostics (ex: from lenient parses or
+// lint-style rules) are pushed by scans that succeed so that they can be
+// reported the same way as errors.
+type Severity int
+
+const (
+	SevError Severity = iota
+	SevWarning
+	SevInfo
+)
+
+var sevNames = []string{`error`, `warning`, `info`}
+
+// String fulfills fmt.Stringer with the lower case name of the severity
+// (error, warning, info).
+func (v Severity) String() string {
+	if v < 0 || int(v) >= len(sevNames) {
+		return fmt.Sprintf(`severity(%d)`, int(v))
+	}
+	return sevNames[v]
+}
+
+// MarshalText fulfills encoding.TextMarshaler with the String form so
+// that severities are names in JSON and YAML.
+func (v Severity) MarshalText() ([]byte, error) { return []byte(v.String()), nil }
+
+// UnmarshalText fulfills encoding.TextUnmarshaler from the String form.
+func (v *Severity) UnmarshalText(text []byte) error {
+	for i, n := range sevNames {
+		if n == string(text) {
+			*v = Severity(i)
+			return nil
+		}
+	}
+	return fmt.Errorf(`unknown severity: %q`, text)
+}
+
+// SeverityOf returns the Severity of the error (or of the first error
+// it wraps) with a Severity method and SevError for any other error.
+func SeverityOf(err error) Severity {
+	var s interface{ Severity() Severity }
+	if errors.As(err, &s) {
+		return s.Severity()
+	}
+	return SevError
+}
@@ -0,0 +1,34 @@
+package pegn_test
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/rwxrob/pegn"
+)
+
+type lint struct{ msg string }
+
+func (l lint) Error() string           { return l.msg }
+func (l lint) Severity() pegn.Severity { return pegn.SevWarning }
+
+func ExampleSeverityOf() {
+
+	fmt.Println(pegn.SeverityOf(pegn.Error{T: 1}))
+	fmt.Println(pegn.SeverityOf(lint{`trailing space`}))
+	fmt.Println(pegn.SeverityOf(fmt.Errorf(`line 2: %w`, lint{`tab`})))
+
+	byt, _ := json.Marshal([]pegn.Severity{pegn.SevError, pegn.SevInfo})
+	fmt.Println(string(byt))
+	var v pegn.Severity
+	fmt.Println(json.Unmarshal([]byte(`"warning"`), &v), v)
+	fmt.Println(v.UnmarshalText([]byte(`fatal`)))
+
+	// Output:
+	// error
+	// warning
+	// warning
+	// ["error","info"]
+	// <nil> warning
+	// unknown severity: "fatal"
+}
@@ -0,0 +1,112 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+package pegn
+
+import (
+	"math/bits"
+	"sync/atomic"
+
+	"github.com/rwxrob/pegn/ast"
+)
+
+// Sizer decides the initial capacity (in runes) of the capture buffer
+// of a rule (see ParserSized) from the sizes captured so far so that
+// rules capturing a lot (code fences, long fields) do not grow their
+// buffers again and again while those capturing little do not waste
+// memory. Implementations must be safe for concurrent use.
+type Sizer interface {
+	Cap() int      // capacity for the next buffer
+	Observe(n int) // runes captured into the last buffer
+}
+
+// FixedCap is a Sizer that always returns the same capacity (see
+// ParserFor).
+type FixedCap int
+
+func (c FixedCap) Cap() int    { return int(c) }
+func (c FixedCap) Observe(int) {}
+
+// AdaptiveCap is a Sizer that learns the distribution of the sizes
+// captured (in powers of two) and returns the smallest power of two
+// larger than the Percentile of them so that only the largest few
+// captures ever grow their buffer. Until Warmup sizes have been
+// observed Start is returned. The zero value is ready to use (with
+// a Percentile of 0.9 and Warmup of 16). Do not copy once used.
+type AdaptiveCap struct {
+	// first for 64-bit alignment of atomics (see sync/atomic)
+	counts [33]int64 // by bits.Len of size
+	total  int64
+	cap    int64 // last calculated
+
+	Start      int     // capacity until warm
+	Percentile float64 // portion of captures that fit (0 to 1)
+	Warmup     int     // sizes observed before adapting
+}
+
+// Cap returns the capacity covering the Percentile of the sizes
+// observed.
+func (a *AdaptiveCap) Cap() int {
+	warm := int64(a.Warmup)
+	if warm <= 0 {
+		warm = 16
+	}
+	if atomic.LoadInt64(&a.total) < warm {
+		return a.Start
+	}
+	return int(atomic.LoadInt64(&a.cap))
+}
+
+// Observe records the size captured recalculating the capacity.
+func (a *AdaptiveCap) Observe(n int) {
+	if n < 0 {
+		return
+	}
+	i := bits.Len(uint(n))
+	if i >= len(a.counts) {
+		i = len(a.counts) - 1
+	}
+	atomic.AddInt64(&a.counts[i], 1)
+	total := atomic.AddInt64(&a.total, 1)
+
+	p := a.Percentile
+	if p <= 0 || p > 1 {
+		p = 0.9
+	}
+	need := int64(float64(total)*p + 0.5)
+	var sum int64
+	for i := range a.counts {
+		sum += atomic.LoadInt64(&a.counts[i])
+		if sum >= need {
+			atomic.StoreInt64(&a.cap, int64(1)<<i)
+			return
+		}
+	}
+}
+
+// ParserSized is the same as ParserFor but with the capacity of every
+// buffer from the Sizer which is told the size of every successful
+// capture. Scanners that can (see scanner.S.Capture) are given the
+// buffer so that scan can change what is captured as it goes.
+//
+//	var fences pegn.AdaptiveCap
+//	var Parse_Fence = pegn.ParserSized(Fence, Scan_Fence, &fences)
+func ParserSized(t int, scan ScanFunc, sz Sizer) ParseFunc {
+	return func(s Scanner) *ast.Node {
+		buf := make([]rune, 0, sz.Cap())
+		if c, is := s.(capturer); is {
+			old := c.Capture(&buf)
+			defer c.Capture(old)
+		}
+		b := s.RuneE()
+		if !scan(s, &buf) {
+			return nil
+		}
+		sz.Observe(len(buf))
+		return &ast.Node{T: t, V: string(buf), B: b, E: s.RuneE()}
+	}
+}
+
+// capturer is implemented by scanners that can change what is captured
+// (see scanner.S.Capture).
+type capturer interface{ Capture(buf *[]rune) *[]rune }
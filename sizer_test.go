@@ -0,0 +1,48 @@
+package pegn_test
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rwxrob/pegn"
+	"github.com/rwxrob/pegn/scanner"
+)
+
+func ExampleParserSized() {
+
+	const Line = 1
+	scanLine := func(s pegn.Scanner, buf *[]rune) bool {
+		for s.Scan() && s.Rune() != '\n' {
+			*buf = append(*buf, s.Rune())
+		}
+		return true
+	}
+
+	sizes := &pegn.AdaptiveCap{Start: 8, Warmup: 4}
+	parse := pegn.ParserSized(Line, scanLine, sizes)
+
+	var lines []string
+	for i := 0; i < 10; i++ {
+		lines = append(lines, strings.Repeat(`x`, 20+i))
+	}
+	lines = append(lines, strings.Repeat(`y`, 500))
+	s := scanner.New(strings.Join(lines, "\n"))
+
+	for i := 0; i < 3; i++ {
+		parse(s)
+		fmt.Println(sizes.Cap())
+	}
+	for !s.Finished() {
+		parse(s)
+	}
+	fmt.Println(sizes.Cap())
+
+	fmt.Println(pegn.FixedCap(4).Cap())
+
+	// Output:
+	// 8
+	// 8
+	// 8
+	// 32
+	// 4
+}
@@ -0,0 +1,227 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+/*
+Package togo converts ast.Node trees into Go syntax trees (go/ast) so
+that small languages can be transpiled to Go and written out with
+go/format rather than by printing Go source by hand. What each node
+type becomes is declared with a Rule for each node type (see Mapping)
+and the common cases (identifiers, literals, operators, calls) are
+provided as ready-made rules.
+*/
+package togo
+
+import (
+	"errors"
+	"fmt"
+	goast "go/ast"
+	"go/format"
+	"go/token"
+	"strings"
+
+	"github.com/rwxrob/pegn/ast"
+)
+
+// Rule declares what nodes of a given type become. Each function
+// is given the Mapping so that it can convert the nodes under it. Only
+// the functions for the contexts in which a node type is expected
+// (expression, statement, declaration) are needed.
+//
+// Expr returns the Go expression for the node.
+//
+// Stmt returns the Go statements for the node. When nil, the node must
+// have an Expr that is then used as an expression statement.
+//
+// Decl returns the top-level Go declarations for the node.
+type Rule struct {
+	Expr func(m Mapping, n *ast.Node) (goast.Expr, error)
+	Stmt func(m Mapping, n *ast.Node) ([]goast.Stmt, error)
+	Decl func(m Mapping, n *ast.Node) ([]goast.Decl, error)
+}
+
+// Mapping is the set of Rules by node type (ast.Node.T).
+type Mapping map[int]Rule
+
+// Error is returned when a node could not be converted. Only the
+// innermost node that failed is reported.
+type Error struct {
+	T   int   // type of node
+	Err error // reason
+}
+
+// Error fulfills the error interface.
+func (e *Error) Error() string {
+	return fmt.Sprintf(`togo: node type %v: %v`, e.T, e.Err)
+}
+
+// Unwrap returns Err.
+func (e *Error) Unwrap() error { return e.Err }
+
+func wrap(n *ast.Node, err error) error {
+	var e *Error
+	if errors.As(err, &e) {
+		return err
+	}
+	return &Error{T: n.T, Err: err}
+}
+
+// Expr returns the Go expression for the node.
+func (m Mapping) Expr(n *ast.Node) (goast.Expr, error) {
+	r := m[n.T]
+	if r.Expr == nil {
+		return nil, &Error{T: n.T, Err: fmt.Errorf(`not an expression`)}
+	}
+	x, err := r.Expr(m, n)
+	if err != nil {
+		return nil, wrap(n, err)
+	}
+	return x, nil
+}
+
+// Exprs returns the Go expressions for each node in order.
+func (m Mapping) Exprs(nodes ...*ast.Node) ([]goast.Expr, error) {
+	list := make([]goast.Expr, 0, len(nodes))
+	for _, n := range nodes {
+		x, err := m.Expr(n)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, x)
+	}
+	return list, nil
+}
+
+// Stmts returns the Go statements for each node in order.
+func (m Mapping) Stmts(nodes ...*ast.Node) ([]goast.Stmt, error) {
+	var list []goast.Stmt
+	for _, n := range nodes {
+		r := m[n.T]
+		if r.Stmt == nil {
+			x, err := m.Expr(n)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, &goast.ExprStmt{X: x})
+			continue
+		}
+		stmts, err := r.Stmt(m, n)
+		if err != nil {
+			return nil, wrap(n, err)
+		}
+		list = append(list, stmts...)
+	}
+	return list, nil
+}
+
+// Decls returns the Go declarations for each node in order.
+func (m Mapping) Decls(nodes ...*ast.Node) ([]goast.Decl, error) {
+	var list []goast.Decl
+	for _, n := range nodes {
+		r := m[n.T]
+		if r.Decl == nil {
+			return nil, &Error{T: n.T, Err: fmt.Errorf(`not a declaration`)}
+		}
+		decls, err := r.Decl(m, n)
+		if err != nil {
+			return nil, wrap(n, err)
+		}
+		list = append(list, decls...)
+	}
+	return list, nil
+}
+
+// File returns a Go file for the package (pkg) with the declarations
+// of every node under n.
+func (m Mapping) File(pkg string, n *ast.Node) (*goast.File, error) {
+	decls, err := m.Decls(n.Nodes()...)
+	if err != nil {
+		return nil, err
+	}
+	return &goast.File{Name: goast.NewIdent(pkg), Decls: decls}, nil
+}
+
+// Format returns the Go source (see go/format) for any Go syntax tree
+// node (ex: *ast.File, ast.Expr, []ast.Stmt).
+func Format(node any) (string, error) {
+	var out strings.Builder
+	if err := format.Node(&out, token.NewFileSet(), node); err != nil {
+		return "", err
+	}
+	return out.String(), nil
+}
+
+// ------------------------------- rules ------------------------------
+
+// Ident is an Expr rule for a node with an identifier as its value.
+func Ident(m Mapping, n *ast.Node) (goast.Expr, error) {
+	if !token.IsIdentifier(n.V) {
+		return nil, fmt.Errorf(`invalid identifier: %q`, n.V)
+	}
+	return goast.NewIdent(n.V), nil
+}
+
+// Lit returns an Expr rule for a node with a Go literal of the given
+// kind (token.INT, token.FLOAT, token.STRING, ...) as its value.
+func Lit(kind token.Token) func(m Mapping, n *ast.Node) (goast.Expr, error) {
+	return func(m Mapping, n *ast.Node) (goast.Expr, error) {
+		return &goast.BasicLit{Kind: kind, Value: n.V}, nil
+	}
+}
+
+// Unary returns an Expr rule for a node with one node under it.
+func Unary(op token.Token) func(m Mapping, n *ast.Node) (goast.Expr, error) {
+	return func(m Mapping, n *ast.Node) (goast.Expr, error) {
+		x, err := operands(m, n, 1)
+		if err != nil {
+			return nil, err
+		}
+		return &goast.UnaryExpr{Op: op, X: x[0]}, nil
+	}
+}
+
+// Binary returns an Expr rule for a node with two nodes under it
+// (ex: from pratt.Parser). Parenthesis are added wherever needed when
+// formatted.
+func Binary(op token.Token) func(m Mapping, n *ast.Node) (goast.Expr, error) {
+	return func(m Mapping, n *ast.Node) (goast.Expr, error) {
+		x, err := operands(m, n, 2)
+		if err != nil {
+			return nil, err
+		}
+		return &goast.BinaryExpr{X: paren(x[0], op, false), Op: op, Y: paren(x[1], op, true)}, nil
+	}
+}
+
+// Call returns an Expr rule calling the named function with the nodes
+// under the node as arguments.
+func Call(fn string) func(m Mapping, n *ast.Node) (goast.Expr, error) {
+	return func(m Mapping, n *ast.Node) (goast.Expr, error) {
+		args, err := m.Exprs(n.Nodes()...)
+		if err != nil {
+			return nil, err
+		}
+		return &goast.CallExpr{Fun: goast.NewIdent(fn), Args: args}, nil
+	}
+}
+
+func operands(m Mapping, n *ast.Node, count int) ([]goast.Expr, error) {
+	if n.Count != count {
+		return nil, fmt.Errorf(`expected %v nodes under but found %v`, count, n.Count)
+	}
+	return m.Exprs(n.Nodes()...)
+}
+
+// paren wraps binary operands binding less tightly than op (which
+// go/format never does on its own). Right operands binding just as
+// tightly are wrapped as well since Go operators are left associative.
+func paren(x goast.Expr, op token.Token, right bool) goast.Expr {
+	b, is := x.(*goast.BinaryExpr)
+	if !is {
+		return x
+	}
+	p, q := b.Op.Precedence(), op.Precedence()
+	if p < q || right && p == q {
+		return &goast.ParenExpr{X: x}
+	}
+	return x
+}
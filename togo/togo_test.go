@@ -0,0 +1,93 @@
+package togo_test
+
+import (
+	"fmt"
+	goast "go/ast"
+	"go/token"
+
+	"github.com/rwxrob/pegn/ast"
+	"github.com/rwxrob/pegn/togo"
+)
+
+const (
+	Program = iota + 1
+	Let
+	Print
+	Name
+	Num
+	Add
+	Sub
+	Mul
+)
+
+func ExampleMapping() {
+
+	// let x = 1 - (2 - 3) * 4
+	// print x
+	prog := &ast.Node{T: Program}
+	let := prog.Add(Let, "")
+	let.Add(Name, `x`)
+	sub := let.Add(Sub, "")
+	sub.Add(Num, `1`)
+	mul := sub.Add(Mul, "")
+	inner := mul.Add(Sub, "")
+	inner.Add(Num, `2`)
+	inner.Add(Num, `3`)
+	mul.Add(Num, `4`)
+	prog.Add(Print, "").Add(Name, `x`)
+
+	m := togo.Mapping{
+		Name:  {Expr: togo.Ident},
+		Num:   {Expr: togo.Lit(token.INT)},
+		Add:   {Expr: togo.Binary(token.ADD)},
+		Sub:   {Expr: togo.Binary(token.SUB)},
+		Mul:   {Expr: togo.Binary(token.MUL)},
+		Print: {Expr: togo.Call(`println`)},
+		Let: {Stmt: func(m togo.Mapping, n *ast.Node) ([]goast.Stmt, error) {
+			x, err := m.Exprs(n.Nodes()...)
+			if err != nil {
+				return nil, err
+			}
+			return []goast.Stmt{&goast.AssignStmt{
+				Lhs: x[:1], Tok: token.DEFINE, Rhs: x[1:],
+			}}, nil
+		}},
+	}
+
+	m[Program] = togo.Rule{
+		Decl: func(m togo.Mapping, n *ast.Node) ([]goast.Decl, error) {
+			body, err := m.Stmts(n.Nodes()...)
+			if err != nil {
+				return nil, err
+			}
+			return []goast.Decl{&goast.FuncDecl{
+				Name: goast.NewIdent(`main`),
+				Type: &goast.FuncType{Params: &goast.FieldList{}},
+				Body: &goast.BlockStmt{List: body},
+			}}, nil
+		},
+	}
+
+	root := &ast.Node{}
+	root.Append(prog)
+	f, err := m.File(`main`, root)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	src, _ := togo.Format(f)
+	fmt.Print(src)
+
+	let.Add(Name, `2x`)
+	_, err = m.File(`main`, root)
+	fmt.Println(err)
+
+	// Output:
+	// package main
+	//
+	// func main() {
+	// 	x := 1 - (2-3)*4
+	// 	println(x)
+	// }
+	// togo: node type 4: invalid identifier: "2x"
+}
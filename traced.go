@@ -0,0 +1,34 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+package pegn
+
+// Traced returns a ScanFunc calling fn within the rule of the given ID
+// so that scanners able to trace (see scanner.S.TraceEnter) report
+// entering it, and leaving it with the range of bytes consumed and
+// whether it matched, indented by how deeply the rule is nested within
+// others (like the classic trace of a PEG parser). Scanners that
+// cannot trace (or are not tracing) simply call fn. Wrap every scan
+// function of a grammar while developing it:
+//
+//	var Scan_Sum = pegn.Traced(Sum, scan_Sum)
+func Traced(ruleID int, fn ScanFunc) ScanFunc {
+	return func(s Scanner, buf *[]rune) bool {
+		t, is := s.(tracer)
+		if !is {
+			return fn(s, buf)
+		}
+		b := s.RuneE()
+		t.TraceEnter(ruleID)
+		ok := fn(s, buf)
+		t.TraceExit(ruleID, b, ok)
+		return ok
+	}
+}
+
+// tracer is implemented by scanners that trace rules (see
+// scanner.S.TraceEnter).
+type tracer interface {
+	TraceEnter(t int)
+	TraceExit(t, b int, ok bool)
+}
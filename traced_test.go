@@ -0,0 +1,49 @@
+package pegn_test
+
+import (
+	"fmt"
+
+	"github.com/rwxrob/pegn"
+	"github.com/rwxrob/pegn/scanner"
+)
+
+func ExampleTraced() {
+	const (
+		Word = iota + 1
+		Words
+	)
+
+	var scanWord pegn.ScanFunc = pegn.Traced(Word, pegn.KeywordSet(`hi`, `there`))
+	scanWords := pegn.Traced(Words, func(s pegn.Scanner, buf *[]rune) bool {
+		if !scanWord(s, buf) {
+			return false
+		}
+		for s.Peek(` `) {
+			s.Scan()
+			if !scanWord(s, buf) {
+				return false
+			}
+		}
+		return true
+	})
+
+	s := scanner.New(`hi there you`)
+	s.TraceFunc = func(e scanner.TraceEvent) {
+		if e.Kind == scanner.TraceEnter || e.Kind == scanner.TraceExit {
+			fmt.Println(e)
+		}
+	}
+	s.TraceOn()
+	fmt.Println(scanWords(s, nil))
+
+	// Output:
+	// enter 2 '\x00' 0-0
+	//   enter 1 '\x00' 0-0
+	//   exit 1 ok 0-2
+	//   enter 1 ' ' 2-3
+	//   exit 1 ok 3-8
+	//   enter 1 ' ' 8-9
+	//   exit 1 failed 9-9
+	// exit 2 failed 0-9
+	// false
+}
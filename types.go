@@ -12,9 +12,11 @@ package pegn
 
 import (
 	"fmt"
-	"go/ast"
 
+	"github.com/rwxrob/pegn/ast"
 	"github.com/rwxrob/pegn/curs"
+	"github.com/rwxrob/pegn/lang"
+	"github.com/rwxrob/pegn/rule"
 )
 
 // ScanFunc uses the Scanner to scan for a rule and optionally populate
@@ -103,9 +105,11 @@ type ClassFunc func(r rune) bool
 // following sample implementation default JSON marshaling tags:
 //
 //     type node struct {
-//       T int     `json:"t"`           // type (rule id)
-//       V string  `json:"v,omitempty"` // value (if leaf)
-//       N []*node `json:"n,omitempty"` // nodes under (if over/parent)
+//       T   int     `json:"T"`             // type (rule id)
+//       V   string  `json:"V,omitempty"`   // value (if leaf)
+//       Tag string  `json:"Tag,omitempty"` // name of capture (if any)
+//       Err string  `json:"Err,omitempty"` // why not parsed (if error node)
+//       N   []*node `json:"N,omitempty"`   // nodes under (if over/parent)
 //     }
 //
 // All implementations must fail and return an error if there is both
@@ -115,7 +119,7 @@ type ClassFunc func(r rune) bool
 // between applications using this node tree format. Expensive JSON
 // schema validation is not needed and discouraged. Consider an
 // intermediary struct to hold the values before outputting them as
-// a string.
+// a string. See CheckNode for verifying an implementation conforms.
 //
 // UnmarshalJSON(b []byte) error
 //
@@ -143,6 +147,7 @@ type Scanner interface {
 	ScannerRangeCopy
 	ScannerObservability
 	ScannerErrors
+	ScannerCheckpoint
 }
 
 // A Scanner implements a buffered rune scanner and must employ design
@@ -174,7 +179,9 @@ type Scanner interface {
 //
 // Open(path string) error
 //
-// Must open the path and pass it to Buffer.
+// Must open the path and pass it to Buffer resetting the cursor. The
+// path "-" must be standard input. Implementations should also accept
+// http and https URLs so that tools can scan remote documents.
 //
 // Scan() bool
 //
@@ -360,6 +367,32 @@ type ScannerErrors interface {
 	Error() string                        // combine Errors() into single string
 }
 
+// ScannerCheckpoint allows a Scanner to back out of a speculative scan
+// completely. Mark and Goto restore only the cursor leaving behind any
+// errors pushed while trying. Restore returns to the cursor of the
+// Checkpoint and drops every error pushed since. State that remains
+// true no matter where scanning continues from (such as memoized
+// results) is kept.
+//
+//    cp := s.Checkpoint()
+//    if !tryLong(s, buf) {
+//        s.Restore(cp)
+//        return tryShort(s, buf)
+//    }
+//
+type ScannerCheckpoint interface {
+	Checkpoint() Checkpoint
+	Restore(c Checkpoint)
+}
+
+// Checkpoint is the state of a Scanner saved by Checkpoint and returned
+// to by Restore. The position of fields is guaranteed never to change.
+type Checkpoint struct {
+	C       curs.R // cursor (see Mark)
+	Errs    int    // depth of the error stack
+	Dropped int    // errors dropped from a bounded error stack (if any)
+}
+
 // Error wraps the type (T) and current scanner position (C)
 // such that it can be located and displayed with help information by
 // looking up those things from other sources when displayed to the end
@@ -372,8 +405,42 @@ type Error struct {
 }
 
 var DefaultErrFmt = `expecting type %v at %v`
-var DefaultErrFmtFunc = func(e Error) string {
+
+// DefaultErrFmtFunc formats errors of rules that have been registered
+// (see rule.Register) with the description of the rule (see
+// rule.Describe) and the line and column of the error (if known) in the
+// language of the user (see lang.Lang) and all others with
+// DefaultErrFmt (see At).
+var DefaultErrFmtFunc = func(e Error) string { return e.At(where(e.C)) }
+
+// At returns the error as DefaultErrFmtFunc does but with pos as where
+// it is in the input. The line and column worked out from the cursor
+// alone are only right if its buffer holds everything from the
+// beginning of the input so scanners that keep track of them (see
+// scanner.S.FormatErr) pass their own.
+func (e Error) At(pos string) string {
+	if d, has := rule.Describe(e.T); has {
+		return lang.Sprintf(`expecting`, d, pos)
+	}
 	return fmt.Sprintf(DefaultErrFmt, e.T, e.C)
 }
 
+// where returns the line and column (both beginning with 1) of the rune
+// following the cursor (counted from the beginning of its buffer) or
+// the cursor itself if it has no buffer.
+func where(c curs.R) string {
+	if c.Buf == nil || c.E < 0 || c.E > len(*c.Buf) {
+		return c.String()
+	}
+	line, col := 1, 1
+	for _, r := range string((*c.Buf)[:c.E]) {
+		col++
+		if r == '\n' {
+			line++
+			col = 1
+		}
+	}
+	return lang.Sprintf(`position`, line, col)
+}
+
 func (e Error) Error() string { return DefaultErrFmtFunc(e) }
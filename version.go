@@ -0,0 +1,35 @@
+// Copyright 2022 Robert S. Muhlestein.
+// SPDX-License-Identifier: Apache-2.0
+
+package pegn
+
+import "runtime/debug"
+
+// Spec is the version of the PEGN specification implemented.
+const Spec = `2023-01`
+
+// Module is the path of this module as it appears in build info.
+const Module = `github.com/rwxrob/pegn`
+
+// Version returns the version of this module compiled into the current
+// binary (ex: v0.3.0) or "(devel)" if unknown (such as when run from
+// within the module itself or built without module support).
+func Version() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return `(devel)`
+	}
+	if info.Main.Path == Module && info.Main.Version != "" {
+		return info.Main.Version
+	}
+	for _, d := range info.Deps {
+		if d.Path != Module {
+			continue
+		}
+		if d.Replace != nil && d.Replace.Version != "" {
+			return d.Replace.Version
+		}
+		return d.Version
+	}
+	return `(devel)`
+}
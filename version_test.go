@@ -0,0 +1,15 @@
+package pegn_test
+
+import (
+	"fmt"
+
+	"github.com/rwxrob/pegn"
+)
+
+func ExampleVersion() {
+	fmt.Println(pegn.Version())
+	fmt.Println(pegn.Spec)
+	// Output:
+	// (devel)
+	// 2023-01
+}